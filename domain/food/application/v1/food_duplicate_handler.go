@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"errors"
+	"food-api/domain/food/application/v1/response"
+	"food-api/domain/food/domain/model"
+	"food-api/domain/food/domain/service"
+	"food-api/infrastructure/middleware"
+	"github.com/go-chi/chi"
+	"net/http"
+)
+
+// swagger:route GET /foods/{id}/duplicates Food idFoodDuplicatesPath
+//
+// DuplicatesHandler.
+// Response the near-duplicates of a food
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerDuplicatesResponse
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// DuplicatesHandler response the foods of the same user that look like the same dish, only the owner
+// of the food can list them.
+func (ur *FoodRouter) DuplicatesHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	ctx := r.Context()
+	food, err := ur.Repo.GetFoodById(ctx, id, metadata.UserId)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if food.UserID != metadata.UserId {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, errors.New("food not found").Error())
+		return
+	}
+
+	candidates, err := ur.Repo.GetAllFoodByUserId(ctx, food.UserID)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	duplicates := service.FindDuplicates(model.Food{
+		ID:          food.ID,
+		Title:       food.Title,
+		Ingredients: food.Ingredients,
+	}, candidates)
+
+	_ = middleware.JSON(w, r, http.StatusOK, duplicateCandidates(duplicates))
+}
+
+// duplicateConflict is the body of the 409 returned when a new food has near-duplicates.
+func duplicateConflict(duplicates []service.Duplicate) response.DuplicateConflictResponse {
+	conflict := response.DuplicateConflictResponse{
+		Status:       http.StatusConflict,
+		Message:      "the food looks like a duplicate, use force=true to create it anyway",
+		CandidateIDs: make([]string, 0, len(duplicates)),
+		Candidates:   duplicateCandidates(duplicates),
+	}
+
+	for _, duplicate := range duplicates {
+		conflict.CandidateIDs = append(conflict.CandidateIDs, duplicate.Food.ID)
+	}
+
+	return conflict
+}
+
+func duplicateCandidates(duplicates []service.Duplicate) []response.DuplicateCandidateResponse {
+	candidates := make([]response.DuplicateCandidateResponse, 0, len(duplicates))
+
+	for _, duplicate := range duplicates {
+		candidates = append(candidates, response.DuplicateCandidateResponse{
+			ID:              duplicate.Food.ID,
+			Title:           duplicate.Food.Title,
+			Similarity:      duplicate.Similarity,
+			SameIngredients: duplicate.SameIngredients,
+		})
+	}
+
+	return candidates
+}
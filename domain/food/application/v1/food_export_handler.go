@@ -0,0 +1,87 @@
+package v1
+
+import (
+	"fmt"
+	"food-api/domain/food/application/v1/response"
+	"food-api/domain/food/domain/model"
+	"food-api/domain/food/infrastructure/format"
+	"food-api/infrastructure/middleware"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// swagger:route GET /foods/export Food foodExportRequest
+//
+// ExportHandler.
+// Export all the foods of the user
+//
+//     produces:
+//      - text/csv
+//      - application/x-ndjson
+//      - application/ld+json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerFoodExportResponse
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// ExportHandler stream all the foods of the user in CSV, NDJSON or schema.org Recipe JSON-LD.
+func (ur *FoodRouter) ExportHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	fileFormat := strings.ToLower(r.URL.Query().Get("format"))
+	if fileFormat == "" {
+		fileFormat = model.ExportFormatNDJSON
+	}
+
+	writer, err := format.NewWriter(fileFormat, w)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// the headers are sent with the first food, until then an error can still be answered as JSON
+	started := false
+	start := func() {
+		contentType, extension := format.ContentType(fileFormat)
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"foods.%s\"", extension))
+		w.WriteHeader(http.StatusOK)
+		started = true
+	}
+
+	ctx := r.Context()
+	err = ur.Repo.StreamFoodByUserId(ctx, metadata.UserId, func(food response.FoodResponse) error {
+		if !started {
+			start()
+		}
+
+		return writer.Write(food)
+	})
+
+	if err != nil && !started {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err != nil {
+		log.Printf("cannot complete the export of the user %s: %s", metadata.UserId, err.Error())
+		return
+	}
+
+	if !started {
+		start()
+	}
+
+	if err = writer.Close(); err != nil {
+		log.Printf("cannot complete the export of the user %s: %s", metadata.UserId, err.Error())
+	}
+}
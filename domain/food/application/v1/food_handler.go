@@ -1,28 +1,45 @@
 package v1
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"food-api/domain/food/application/v1/response"
 	"food-api/domain/food/domain/model"
 	repoDomain "food-api/domain/food/domain/repository"
+	"food-api/domain/food/domain/service"
 	"food-api/domain/food/infrastructure/persistence"
+	"food-api/infrastructure/auth"
+	authModel "food-api/infrastructure/auth/model"
 	"food-api/infrastructure/database"
 	"food-api/infrastructure/middleware"
 	"github.com/go-chi/chi"
 	"net/http"
+	"strconv"
 	"time"
 )
 
 // FoodRouter
 type FoodRouter struct {
-	Repo repoDomain.FoodRepository
+	Repo         repoDomain.FoodRepository
+	ImportJobs   repoDomain.ImportJobRepository
+	Translations repoDomain.TranslationRepository
+	Preferences  repoDomain.PreferenceRepository
+	Trending     repoDomain.TrendingRepository
+	Scorer       service.Scorer
+	Token        auth.TokenInterface
 }
 
-func NewFoodHandler(db *database.Data) *FoodRouter {
+func NewFoodHandler(db *database.Data, redis *database.RedisService) *FoodRouter {
 	return &FoodRouter{
-		Repo: persistence.NewFoodRepository(db),
+		Repo:         persistence.NewFoodRepository(db),
+		ImportJobs:   persistence.NewImportJobRepository(),
+		Translations: persistence.NewTranslationRepository(db),
+		Preferences:  persistence.NewPreferenceRepository(db),
+		Trending:     persistence.NewTrendingRepository(redis.Client),
+		Scorer:       service.NewScorer(),
+		Token:        auth.NewToken(),
 	}
 }
 
@@ -42,22 +59,15 @@ func NewFoodHandler(db *database.Data) *FoodRouter {
 //		  401: SwaggerErrorMessage
 //		  404: SwaggerErrorMessage
 //
-// GetAllFoodHandler response all the food.
+// GetAllFoodHandler response all the food visible to the user.
 func (ur *FoodRouter) GetAllFoodHandler(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	foods, err := ur.Repo.GetAllFood(ctx)
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
 	if err != nil {
-		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	if foods == nil {
-		_ = middleware.HTTPError(w, r, http.StatusNotFound, errors.New("foods not found").Error())
-		return
-	}
-
-	_ = middleware.JSON(w, r, http.StatusOK, foods)
+	ur.respondAllFood(w, r, metadata.UserId)
 }
 
 
@@ -77,22 +87,44 @@ func (ur *FoodRouter) GetAllFoodHandler(w http.ResponseWriter, r *http.Request)
 //		  401: SwaggerErrorMessage
 //		  404: SwaggerErrorMessage
 //
-// GetOneHandler response one food by id.
+// GetOneHandler response one food by id when it is visible to the user.
 func (ur *FoodRouter) GetOneHandler(w http.ResponseWriter, r *http.Request) {
-	id := chi.URLParam(r, "id")
-	if id == "" {
-		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	ctx := r.Context()
-	userResult, err := ur.Repo.GetFoodById(ctx, id)
+	ur.respondOneFood(w, r, metadata.UserId)
+}
+
+
+// swagger:route GET /foods/by-slug/{slug}  Food slugFoodPath
+//
+// GetBySlugHandler.
+// Response one food by slug
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerFoodResponse
+//        301: description: Moved Permanently, the slug was renamed
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//
+// GetBySlugHandler response one food by slug, an old slug is redirected to the current one.
+func (ur *FoodRouter) GetBySlugHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
 	if err != nil {
-		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	_ = middleware.JSON(w, r, http.StatusOK, userResult)
+	ur.respondFoodBySlug(w, r, metadata.UserId, "/api/v1/foods/by-slug/")
 }
 
 
@@ -112,8 +144,14 @@ func (ur *FoodRouter) GetOneHandler(w http.ResponseWriter, r *http.Request) {
 //		  401: SwaggerErrorMessage
 //		  404: SwaggerErrorMessage
 //
-// GetOneByUserHandler response one food by user id.
+// GetOneByUserHandler response one food by user id when it is visible to the user.
 func (ur *FoodRouter) GetOneByUserHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
 	id := chi.URLParam(r, "id")
 	if id == "" {
 		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
@@ -121,20 +159,20 @@ func (ur *FoodRouter) GetOneByUserHandler(w http.ResponseWriter, r *http.Request
 	}
 
 	ctx := r.Context()
-	userResult, err := ur.Repo.GetFoodByUserId(ctx, id)
+	userResult, err := ur.Repo.GetFoodByUserId(ctx, id, metadata.UserId)
 	if err != nil {
 		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
 		return
 	}
 
-	_ = middleware.JSON(w, r, http.StatusOK, userResult)
+	ur.respondLocalizedFood(w, r, userResult)
 }
 
 
 // swagger:route POST /foods Food foodRequest
 //
 // CreateHandler.
-// Create a new food
+// Create a new food, unless it looks like a duplicate of another food of the user
 //
 //     consumes:
 //     - application/json
@@ -148,15 +186,22 @@ func (ur *FoodRouter) GetOneByUserHandler(w http.ResponseWriter, r *http.Request
 //        201: SwaggerFoodResponse
 //		  400: SwaggerErrorMessage
 //		  401: SwaggerErrorMessage
-//		  409: SwaggerErrorMessage
+//		  409: SwaggerDuplicateConflictResponse
 //		  422: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
 //
-// CreateHandler Create a new food.
+// CreateHandler Create a new food of the user of the token, the near-duplicates are only allowed with force=true.
 func (ur *FoodRouter) CreateHandler(w http.ResponseWriter, r *http.Request) {
 	var now time.Time
 	var food model.Food
 
-	err := json.NewDecoder(r.Body).Decode(&food)
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	err = json.NewDecoder(r.Body).Decode(&food)
 	if err != nil {
 		_ = middleware.HTTPError(w, r, http.StatusBadRequest, err.Error())
 		return
@@ -169,7 +214,24 @@ func (ur *FoodRouter) CreateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The food always belongs to the user of the token, the user_id of the body is ignored
+	food.UserID = metadata.UserId
+
 	ctx := r.Context()
+	force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+	if !force {
+		candidates, err := ur.Repo.GetAllFoodByUserId(ctx, metadata.UserId)
+		if err != nil {
+			_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		if duplicates := service.FindDuplicates(food, candidates); len(duplicates) > 0 {
+			_ = middleware.JSON(w, r, http.StatusConflict, duplicateConflict(duplicates))
+			return
+		}
+	}
+
 	food.CreatedAt = now
 	food.UpdatedAt = now
 
@@ -201,10 +263,12 @@ func (ur *FoodRouter) CreateHandler(w http.ResponseWriter, r *http.Request) {
 //        200: SwaggerFoodResponse
 //		  400: SwaggerErrorMessage
 //		  401: SwaggerErrorMessage
+//		  403: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
 //		  409: SwaggerErrorMessage
 //		  422: SwaggerErrorMessage
 //
-// UpdateHandler update a stored food by id.
+// UpdateHandler update a stored food by id, only the owner or a moderator can update it.
 func (ur *FoodRouter) UpdateHandler(w http.ResponseWriter, r *http.Request) {
 	var now time.Time
 	id := chi.URLParam(r, "id")
@@ -213,8 +277,14 @@ func (ur *FoodRouter) UpdateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
 	var foodUpdate model.Food
-	err := json.NewDecoder(r.Body).Decode(&foodUpdate)
+	err = json.NewDecoder(r.Body).Decode(&foodUpdate)
 	if err != nil {
 		_ = middleware.HTTPError(w, r, http.StatusBadRequest, err.Error())
 		return
@@ -228,20 +298,44 @@ func (ur *FoodRouter) UpdateHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
+	food, err := ur.Repo.GetFoodById(ctx, id, metadata.UserId)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if food.UserID != metadata.UserId && !authModel.HasRole(metadata.Role, authModel.RoleModerator) {
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, errors.New("cannot update the food of another user").Error())
+		return
+	}
+
+	foodUpdate.ID = id
+	foodUpdate.UserID = food.UserID
 	foodUpdate.UpdatedAt = now
 
 	err = ur.Repo.UpdateFood(ctx, id, &foodUpdate)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
 	if err != nil {
 		_ = middleware.HTTPError(w, r, http.StatusConflict, err.Error())
 		return
 	}
 
 	result := response.FoodResponse{
-		ID:          foodUpdate.ID,
-		UserID:      foodUpdate.UserID,
-		Title:       foodUpdate.Title,
-		Description: foodUpdate.Description,
-		FoodImage:   foodUpdate.FoodImage,
+		ID:           foodUpdate.ID,
+		UserID:       foodUpdate.UserID,
+		Title:        foodUpdate.Title,
+		Slug:         foodUpdate.Slug,
+		Description:  foodUpdate.Description,
+		FoodImage:    foodUpdate.FoodImage,
+		Ingredients:  foodUpdate.Ingredients,
+		Instructions: foodUpdate.Instructions,
+		Tags:         foodUpdate.Tags,
+		Visibility:   foodUpdate.Visibility,
+		Language:     foodUpdate.Language,
 	}
 
 	_ = middleware.JSON(w, r, http.StatusOK, result)
@@ -262,9 +356,10 @@ func (ur *FoodRouter) UpdateHandler(w http.ResponseWriter, r *http.Request) {
 //        200: SwaggerSuccessfullyMessage
 //		  400: SwaggerErrorMessage
 //		  401: SwaggerErrorMessage
+//		  403: SwaggerErrorMessage
 //		  404: SwaggerErrorMessage
 //
-// DeleteHandler Remove a food by ID.
+// DeleteHandler Remove a food by ID, only the owner or a moderator can remove it.
 func (ur *FoodRouter) DeleteHandler(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
@@ -272,8 +367,25 @@ func (ur *FoodRouter) DeleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
 	ctx := r.Context()
-	err := ur.Repo.DeleteFood(ctx, id)
+	food, err := ur.Repo.GetFoodById(ctx, id, metadata.UserId)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if food.UserID != metadata.UserId && !authModel.HasRole(metadata.Role, authModel.RoleModerator) {
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, errors.New("cannot remove the food of another user").Error())
+		return
+	}
+
+	err = ur.Repo.DeleteFood(ctx, id)
 	if err != nil {
 		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
 		return
@@ -0,0 +1,446 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"food-api/domain/food/application/v1/response"
+	"food-api/domain/food/domain/model"
+	"food-api/domain/food/infrastructure/format"
+	"food-api/infrastructure/middleware"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// importBatchSize is the number of rows inserted per transaction in partial mode.
+const importBatchSize = 100
+
+// defaultImportAsyncRows is the number of rows from which an import runs as a job.
+const defaultImportAsyncRows = 1000
+
+// defaultImportMaxSize is the largest file that can be imported, 32MB.
+const defaultImportMaxSize = 32 << 20
+
+// defaultImportJobTTL is how long the report of an import job can be read once it finished.
+const defaultImportJobTTL = 24 * time.Hour
+
+// errBodyTooLarge is the message of the error of http.MaxBytesReader when the body exceeds the limit.
+const errBodyTooLarge = "http: request body too large"
+
+// importRow is a valid row waiting to be inserted, index points to its result in the report.
+type importRow struct {
+	index int
+	food  model.Food
+}
+
+// swagger:route POST /foods/import Food foodImportRequest
+//
+// ImportHandler.
+// Import foods from a CSV or NDJSON file
+//
+//     consumes:
+//     - text/csv
+//     - application/x-ndjson
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerImportReportResponse
+//        202: SwaggerImportJobResponse
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  413: SwaggerErrorMessage
+//		  422: SwaggerImportReportResponse
+//		  500: SwaggerErrorMessage
+//
+// ImportHandler import foods from a CSV or NDJSON file of at most FOOD_IMPORT_MAX_SIZE bytes, large files
+// run as an asynchronous job whose report is kept for FOOD_IMPORT_JOB_TTL.
+func (ur *FoodRouter) ImportHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	fileFormat := strings.ToLower(query.Get("format"))
+	if fileFormat == "" {
+		fileFormat = format.FormatFromContentType(r.Header.Get("Content-Type"))
+	}
+
+	mode := strings.ToLower(query.Get("mode"))
+	if mode == "" {
+		mode = model.ImportModeAtomic
+	}
+
+	if mode != model.ImportModeAtomic && mode != model.ImportModePartial {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("mode must be atomic or partial").Error())
+		return
+	}
+
+	defer r.Body.Close()
+	file, err := ioutil.TempFile("", "food-import-*")
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// The file is spooled to disk so a large upload is never held in memory before the job starts
+	path := file.Name()
+	maxSize := importMaxSize()
+	_, err = io.Copy(file, http.MaxBytesReader(w, r.Body, maxSize))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil && err.Error() == errBodyTooLarge {
+		removeImportFile(path)
+		_ = middleware.HTTPError(w, r, http.StatusRequestEntityTooLarge, fmt.Sprintf("the file should be at most %d bytes", maxSize))
+		return
+	}
+
+	if err != nil {
+		removeImportFile(path)
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	total, err := countImportRows(path, fileFormat, importAsyncRows())
+	if err != nil {
+		removeImportFile(path)
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	async, _ := strconv.ParseBool(query.Get("async"))
+	if async || total > importAsyncRows() {
+		job := response.ImportJobResponse{
+			ID:        uuid.New().String(),
+			UserID:    metadata.UserId,
+			Status:    model.ImportStatusPending,
+			CreatedAt: time.Now(),
+		}
+
+		ctx := r.Context()
+		if err := ur.ImportJobs.SaveImportJob(ctx, &job); err != nil {
+			removeImportFile(path)
+			_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		go ur.runImportJob(job, path, fileFormat, mode)
+
+		w.Header().Add("Location", fmt.Sprintf("%s/%s", r.URL.Path, job.ID))
+		_ = middleware.JSON(w, r, http.StatusAccepted, job)
+		return
+	}
+
+	defer removeImportFile(path)
+	rows, report, err := readImportFile(path, fileFormat, metadata.UserId, mode)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ur.importFoods(r.Context(), rows, report)
+
+	status := http.StatusOK
+	if report.Error != "" || (mode == model.ImportModeAtomic && report.Failed > 0) {
+		status = http.StatusUnprocessableEntity
+	}
+
+	_ = middleware.JSON(w, r, status, report)
+}
+
+// swagger:route GET /foods/import/{id}  Food idFoodImportPath
+//
+// GetImportJobHandler.
+// Response the status of an asynchronous import
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerImportJobResponse
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//
+// GetImportJobHandler response the status of an asynchronous import.
+func (ur *FoodRouter) GetImportJobHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	job, err := ur.ImportJobs.GetImportJobById(ctx, id)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if job.UserID != metadata.UserId {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, errors.New("import job not found").Error())
+		return
+	}
+
+	_ = middleware.JSON(w, r, http.StatusOK, job)
+}
+
+// countImportRows counts the rows of the file without keeping them, it stops once there are more than
+// limit rows because the import then runs as a job anyway.
+func countImportRows(path, fileFormat string, limit int) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+
+	defer file.Close()
+	reader, err := format.NewReader(fileFormat, file)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for total <= limit {
+		_, _, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if _, ok := err.(*format.RowError); err != nil && !ok {
+			return 0, err
+		}
+
+		total++
+	}
+
+	if total == 0 {
+		return 0, errors.New("the file does not contain foods")
+	}
+
+	return total, nil
+}
+
+// readImportFile reads and validates the rows of the spooled file.
+func readImportFile(path, fileFormat, userId, mode string) ([]importRow, *response.ImportReportResponse, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer file.Close()
+	reader, err := format.NewReader(fileFormat, file)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return readImportRows(reader, userId, mode)
+}
+
+// removeImportFile deletes the spooled file of an import.
+func removeImportFile(path string) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		log.Printf("cannot remove the import file %s: %s", path, err.Error())
+	}
+}
+
+// readImportRows reads and validates every row, the foods are assigned to the user that imports them.
+func readImportRows(reader format.Reader, userId, mode string) ([]importRow, *response.ImportReportResponse, error) {
+	now := time.Now()
+	report := &response.ImportReportResponse{Mode: mode}
+
+	var rows []importRow
+	for {
+		line, food, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			rowErr, ok := err.(*format.RowError)
+			if !ok {
+				return nil, nil, err
+			}
+
+			report.Rows = append(report.Rows, response.ImportRowResponse{
+				Row:    rowErr.Row,
+				Errors: map[string]string{"invalid_row": rowErr.Err.Error()},
+			})
+			report.Failed++
+			continue
+		}
+
+		food.ID = uuid.New().String()
+		food.UserID = userId
+		food.CreatedAt = now
+		food.UpdatedAt = now
+
+		result := response.ImportRowResponse{Row: line, Title: food.Title}
+		if foodErrors := food.Validate(""); len(foodErrors) > 0 {
+			result.Errors = foodErrors
+			report.Failed++
+		} else {
+			rows = append(rows, importRow{index: len(report.Rows), food: food})
+		}
+
+		report.Rows = append(report.Rows, result)
+	}
+
+	report.Total = len(report.Rows)
+	if report.Total == 0 {
+		return nil, nil, errors.New("the file does not contain foods")
+	}
+
+	return rows, report, nil
+}
+
+// importFoods inserts the valid rows and completes the report. In atomic mode every row is
+// inserted in a single transaction and nothing is saved when a row is invalid, in partial mode
+// the rows are inserted in batches and only the failed ones are discarded.
+func (ur *FoodRouter) importFoods(ctx context.Context, rows []importRow, report *response.ImportReportResponse) {
+	if len(rows) == 0 {
+		return
+	}
+
+	if report.Mode == model.ImportModeAtomic {
+		if report.Failed > 0 {
+			return
+		}
+
+		ur.saveImportBatch(ctx, rows, report, false)
+		return
+	}
+
+	for start := 0; start < len(rows); start += importBatchSize {
+		end := start + importBatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		ur.saveImportBatch(ctx, rows[start:end], report, true)
+	}
+}
+
+func (ur *FoodRouter) saveImportBatch(ctx context.Context, rows []importRow, report *response.ImportReportResponse, partial bool) {
+	foods := make([]model.Food, len(rows))
+	for i := range rows {
+		foods[i] = rows[i].food
+	}
+
+	// when err is not nil the transaction was rolled back and none of the rows were saved
+	rowErrors, err := ur.Repo.SaveFoodBatch(ctx, foods, partial)
+	if err != nil {
+		report.Error = err.Error()
+	}
+
+	for i := range rows {
+		var rowErr error
+		if i < len(rowErrors) {
+			rowErr = rowErrors[i]
+		}
+
+		if rowErr == nil && err != nil && partial {
+			rowErr = err
+		}
+
+		if rowErr != nil {
+			report.Rows[rows[i].index].Errors = map[string]string{"database": rowErr.Error()}
+			report.Failed++
+			continue
+		}
+
+		if err == nil {
+			report.Rows[rows[i].index].ID = foods[i].ID
+			report.Succeeded++
+		}
+	}
+}
+
+// runImportJob reads and imports the spooled file in background and stores the progress of the job,
+// the job is removed once its report expired.
+func (ur *FoodRouter) runImportJob(job response.ImportJobResponse, path, fileFormat, mode string) {
+	ctx := context.Background()
+	defer removeImportFile(path)
+
+	job.Status = model.ImportStatusRunning
+	if err := ur.ImportJobs.SaveImportJob(ctx, &job); err != nil {
+		log.Printf("cannot update import job %s: %s", job.ID, err.Error())
+	}
+
+	rows, report, err := readImportFile(path, fileFormat, job.UserID, mode)
+	if err != nil {
+		report = &response.ImportReportResponse{Mode: mode, Error: err.Error()}
+	} else {
+		ur.importFoods(ctx, rows, report)
+	}
+
+	now := time.Now()
+	job.Report = report
+	job.FinishedAt = &now
+	job.Status = model.ImportStatusCompleted
+	if report.Error != "" || (report.Mode == model.ImportModeAtomic && report.Failed > 0) {
+		job.Status = model.ImportStatusFailed
+	}
+
+	if err := ur.ImportJobs.SaveImportJob(ctx, &job); err != nil {
+		log.Printf("cannot update import job %s: %s", job.ID, err.Error())
+	}
+
+	time.AfterFunc(importJobTTL(), func() {
+		if err := ur.ImportJobs.DeleteImportJob(context.Background(), job.ID); err != nil {
+			log.Printf("cannot remove import job %s: %s", job.ID, err.Error())
+		}
+	})
+}
+
+// importAsyncRows returns the number of rows from which an import runs as a job.
+func importAsyncRows() int {
+	rows, err := strconv.Atoi(os.Getenv("FOOD_IMPORT_ASYNC_ROWS"))
+	if err != nil || rows <= 0 {
+		return defaultImportAsyncRows
+	}
+
+	return rows
+}
+
+// importMaxSize returns the largest file that can be imported in bytes.
+func importMaxSize() int64 {
+	size, err := strconv.ParseInt(os.Getenv("FOOD_IMPORT_MAX_SIZE"), 10, 64)
+	if err != nil || size <= 0 {
+		return defaultImportMaxSize
+	}
+
+	return size
+}
+
+// importJobTTL returns how long the report of an import job can be read once it finished.
+func importJobTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("FOOD_IMPORT_JOB_TTL"))
+	if err != nil || ttl <= 0 {
+		return defaultImportJobTTL
+	}
+
+	return ttl
+}
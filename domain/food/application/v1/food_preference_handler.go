@@ -0,0 +1,171 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"food-api/domain/food/domain/model"
+	"food-api/domain/food/domain/service"
+	"food-api/infrastructure/middleware"
+	"github.com/go-chi/chi"
+	"net/http"
+	"time"
+)
+
+// swagger:route PUT /foods/{id}/favorite Food idFoodFavoritePath
+//
+// FavoriteHandler.
+// Mark a food as a favorite of the user
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerSuccessfullyMessage
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// FavoriteHandler mark a food visible to the user as a favorite, the favorites are used for the recommendations.
+func (ur *FoodRouter) FavoriteHandler(w http.ResponseWriter, r *http.Request) {
+	ur.saveFavorite(w, r, true)
+}
+
+// swagger:route DELETE /foods/{id}/favorite Food idFoodFavoritePath
+//
+// UnfavoriteHandler.
+// Remove a food from the favorites of the user
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        204: SwaggerSuccessfullyMessage
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// UnfavoriteHandler remove a food from the favorites of the user.
+func (ur *FoodRouter) UnfavoriteHandler(w http.ResponseWriter, r *http.Request) {
+	ur.saveFavorite(w, r, false)
+}
+
+// swagger:route PUT /foods/{id}/rating Food foodRatingRequest
+//
+// RatingHandler.
+// Rate a food from 1 to 5
+//
+//     consumes:
+//     - application/json
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerSuccessfullyMessage
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  422: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// RatingHandler add or replace the rating of a food visible to the user, the ratings are used for the recommendations.
+func (ur *FoodRouter) RatingHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	var preference model.FoodPreference
+	err = json.NewDecoder(r.Body).Decode(&preference)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+	preferenceErrors := preference.Validate()
+	if len(preferenceErrors) > 0 {
+		_ = middleware.HTTPErrors(w, r, http.StatusUnprocessableEntity, preferenceErrors)
+		return
+	}
+
+	ctx := r.Context()
+	_, err = ur.Repo.GetFoodById(ctx, id, metadata.UserId)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	preference.UserID = metadata.UserId
+	preference.FoodID = id
+	now := time.Now()
+	preference.CreatedAt = now
+	preference.UpdatedAt = now
+
+	err = ur.Preferences.SaveRating(ctx, &preference)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ur.recordTrending(r, id, service.RatingWeight)
+	_ = middleware.JSONMessages(w, r, http.StatusOK, "Successfully rate food")
+}
+
+// saveFavorite marks or unmarks the food of the id param as a favorite of the user.
+func (ur *FoodRouter) saveFavorite(w http.ResponseWriter, r *http.Request, favorite bool) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	ctx := r.Context()
+	_, err = ur.Repo.GetFoodById(ctx, id, metadata.UserId)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	now := time.Now()
+	err = ur.Preferences.SaveFavorite(ctx, &model.FoodPreference{
+		UserID:    metadata.UserId,
+		FoodID:    id,
+		Favorite:  favorite,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if !favorite {
+		_ = middleware.JSONMessages(w, r, http.StatusNoContent, "Successfully remove favorite food")
+		return
+	}
+
+	ur.recordTrending(r, id, service.FavoriteWeight)
+	_ = middleware.JSONMessages(w, r, http.StatusOK, "Successfully favorite food")
+}
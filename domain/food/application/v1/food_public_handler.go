@@ -0,0 +1,167 @@
+package v1
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"food-api/domain/food/application/v1/response"
+	"food-api/domain/food/domain/service"
+	"food-api/infrastructure/middleware"
+	"github.com/go-chi/chi"
+	"net/http"
+)
+
+// swagger:route GET /public/foods  Public getAllPublicFood
+//
+// GetAllPublicFoodHandler.
+// Response all the public food, no authentication is required
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerAllFoodResponse
+//		  404: SwaggerErrorMessage
+//
+// GetAllPublicFoodHandler response all the public food.
+func (ur *FoodRouter) GetAllPublicFoodHandler(w http.ResponseWriter, r *http.Request) {
+	ur.respondAllFood(w, r, "")
+}
+
+// swagger:route GET /public/foods/{id}  Public idPublicFoodPath
+//
+// GetOnePublicHandler.
+// Response one public food by id, no authentication is required
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerFoodResponse
+//		  400: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//
+// GetOnePublicHandler response one public food by id.
+func (ur *FoodRouter) GetOnePublicHandler(w http.ResponseWriter, r *http.Request) {
+	ur.respondOneFood(w, r, "")
+}
+
+// swagger:route GET /public/foods/by-slug/{slug}  Public slugPublicFoodPath
+//
+// GetPublicBySlugHandler.
+// Response one public food by slug, no authentication is required
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerFoodResponse
+//        301: description: Moved Permanently, the slug was renamed
+//		  400: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//
+// GetPublicBySlugHandler response one public food by slug, an old slug is redirected to the current one.
+func (ur *FoodRouter) GetPublicBySlugHandler(w http.ResponseWriter, r *http.Request) {
+	ur.respondFoodBySlug(w, r, "", "/api/v1/public/foods/by-slug/")
+}
+
+// respondAllFood writes the foods visible to the viewer, an empty viewer only sees the public foods.
+func (ur *FoodRouter) respondAllFood(w http.ResponseWriter, r *http.Request, viewerId string) {
+	ctx := r.Context()
+
+	foods, err := ur.Repo.GetAllFood(ctx, viewerId)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if foods == nil {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, errors.New("foods not found").Error())
+		return
+	}
+
+	if err = ur.localize(w, r, foods); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_ = middleware.JSON(w, r, http.StatusOK, foods)
+}
+
+// respondOneFood writes the food of the id param when it is visible to the viewer.
+func (ur *FoodRouter) respondOneFood(w http.ResponseWriter, r *http.Request, viewerId string) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	ctx := r.Context()
+	foodResult, err := ur.Repo.GetFoodById(ctx, id, viewerId)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	ur.recordTrending(r, foodResult.ID, service.ViewWeight)
+	ur.respondLocalizedFood(w, r, foodResult)
+}
+
+// respondFoodBySlug writes the food of the slug param when it is visible to the viewer,
+// an old slug is redirected to basePath followed by the current slug.
+func (ur *FoodRouter) respondFoodBySlug(w http.ResponseWriter, r *http.Request, viewerId, basePath string) {
+	slug := chi.URLParam(r, "slug")
+	if slug == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get slug").Error())
+		return
+	}
+
+	ctx := r.Context()
+	foodResult, err := ur.Repo.GetFoodBySlug(ctx, slug, viewerId)
+	if err == nil {
+		ur.recordTrending(r, foodResult.ID, service.ViewWeight)
+		ur.respondLocalizedFood(w, r, foodResult)
+		return
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// The old slug is only redirected when the food is visible to the viewer, so it does not reveal the
+	// title of a private food
+	currentSlug, err := ur.Repo.GetSlugByHistory(ctx, slug, viewerId)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("%s%s", basePath, currentSlug), http.StatusMovedPermanently)
+}
+
+// respondLocalizedFood writes the food in the language that best matches the Accept-Language header.
+func (ur *FoodRouter) respondLocalizedFood(w http.ResponseWriter, r *http.Request, food *response.FoodResponse) {
+	foods := []response.FoodResponse{*food}
+	if err := ur.localize(w, r, foods); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if foods[0].Language != "" {
+		w.Header().Set("Content-Language", foods[0].Language)
+	}
+
+	_ = middleware.JSON(w, r, http.StatusOK, foods[0])
+}
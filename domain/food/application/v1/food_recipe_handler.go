@@ -0,0 +1,93 @@
+package v1
+
+import (
+	"fmt"
+	"food-api/domain/food/application/v1/response"
+	"food-api/domain/food/infrastructure/format"
+	"food-api/infrastructure/middleware"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// swagger:route POST /foods/import/recipe Food foodRecipeImportRequest
+//
+// ImportRecipeHandler.
+// Read a schema.org Recipe from an HTML document or JSON-LD payload
+//
+//     consumes:
+//     - text/html
+//     - application/ld+json
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerFoodResponse
+//        201: SwaggerFoodResponse
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  409: SwaggerErrorMessage
+//		  422: SwaggerErrorMessage
+//
+// ImportRecipeHandler maps the schema.org Recipe of the document to a food, it is saved when save is true.
+func (ur *FoodRouter) ImportRecipeHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+	food, err := format.ParseRecipe(r.Header.Get("Content-Type"), body)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	food.UserID = metadata.UserId
+	foodErrors := food.Validate("")
+	if len(foodErrors) > 0 {
+		_ = middleware.HTTPErrors(w, r, http.StatusUnprocessableEntity, foodErrors)
+		return
+	}
+
+	save, _ := strconv.ParseBool(r.URL.Query().Get("save"))
+	if !save {
+		preview := response.FoodResponse{
+			UserID:       food.UserID,
+			Title:        food.Title,
+			Description:  food.Description,
+			FoodImage:    food.FoodImage,
+			Ingredients:  food.Ingredients,
+			Instructions: food.Instructions,
+			Tags:         food.Tags,
+		}
+
+		_ = middleware.JSON(w, r, http.StatusOK, preview)
+		return
+	}
+
+	now := time.Now()
+	food.CreatedAt = now
+	food.UpdatedAt = now
+
+	ctx := r.Context()
+	result, err := ur.Repo.SaveFood(ctx, &food)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusConflict, err.Error())
+		return
+	}
+
+	w.Header().Add("Location", fmt.Sprintf("/api/v1/foods/%s", result.ID))
+	_ = middleware.JSON(w, r, http.StatusCreated, result)
+}
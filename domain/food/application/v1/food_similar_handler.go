@@ -0,0 +1,170 @@
+package v1
+
+import (
+	"errors"
+	"food-api/domain/food/application/v1/response"
+	"food-api/domain/food/domain/service"
+	"food-api/infrastructure/middleware"
+	"github.com/go-chi/chi"
+	"net/http"
+	"strconv"
+)
+
+// Number of foods returned by the similar and recommendations endpoints when the limit is not given, and the maximum.
+const (
+	defaultScoredLimit = 10
+	maxScoredLimit     = 50
+)
+
+// swagger:route GET /foods/{id}/similar Food idFoodSimilarPath
+//
+// SimilarHandler.
+// Response the foods similar to a food
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerScoredFoodResponse
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// SimilarHandler response the foods visible to the user ranked by shared tags, ingredient overlap
+// and text similarity with the food.
+func (ur *FoodRouter) SimilarHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	ctx := r.Context()
+	food, err := ur.Repo.GetFoodById(ctx, id, metadata.UserId)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	candidates, err := ur.Repo.GetAllFood(ctx, metadata.UserId)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ur.respondScoredFood(w, r, service.RankSimilar(ur.Scorer, *food, candidates, scoredLimit(r)))
+}
+
+// swagger:route GET /users/{id}/recommendations Food idUserRecommendationsPath
+//
+// RecommendationsHandler.
+// Response the foods recommended to a user
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerScoredFoodResponse
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  403: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// RecommendationsHandler response the foods visible to the user that are similar to the favorites and the
+// foods rated well by the user, the foods similar to the ones rated badly are pushed down. The foods of
+// the user and the ones already favorite or rated are not recommended. Only the user can see them.
+func (ur *FoodRouter) RecommendationsHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	if id != metadata.UserId {
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, errors.New("cannot get the recommendations of another user").Error())
+		return
+	}
+
+	ctx := r.Context()
+	preferences, err := ur.Preferences.GetPreferencesByUserId(ctx, id)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	candidates, err := ur.Repo.GetAllFood(ctx, id)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	byId := make(map[string]response.FoodResponse, len(candidates))
+	exclude := make(map[string]bool)
+	for _, candidate := range candidates {
+		byId[candidate.ID] = candidate
+		if candidate.UserID == id {
+			exclude[candidate.ID] = true
+		}
+	}
+
+	var seeds []service.Seed
+	for _, preference := range preferences {
+		exclude[preference.FoodID] = true
+		if food, ok := byId[preference.FoodID]; ok {
+			seeds = append(seeds, service.Seed{Food: food, Weight: service.PreferenceWeight(preference.Favorite, preference.Rating)})
+		}
+	}
+
+	ur.respondScoredFood(w, r, service.Recommend(ur.Scorer, seeds, candidates, exclude, scoredLimit(r)))
+}
+
+// respondScoredFood writes the ranked foods in the language that best matches the Accept-Language header.
+func (ur *FoodRouter) respondScoredFood(w http.ResponseWriter, r *http.Request, ranked []response.ScoredFoodResponse) {
+	foods := make([]response.FoodResponse, 0, len(ranked))
+	for _, scored := range ranked {
+		foods = append(foods, scored.FoodResponse)
+	}
+
+	if err := ur.localize(w, r, foods); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	result := make([]response.ScoredFoodResponse, 0, len(ranked))
+	for i, food := range foods {
+		result = append(result, response.ScoredFoodResponse{FoodResponse: food, Score: ranked[i].Score})
+	}
+
+	_ = middleware.JSON(w, r, http.StatusOK, result)
+}
+
+// scoredLimit returns the limit query param, the default when it is missing or invalid.
+func scoredLimit(r *http.Request) int {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit < 1 {
+		return defaultScoredLimit
+	}
+
+	if limit > maxScoredLimit {
+		return maxScoredLimit
+	}
+
+	return limit
+}
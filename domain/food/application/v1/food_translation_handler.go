@@ -0,0 +1,262 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"food-api/domain/food/application/v1/response"
+	"food-api/domain/food/domain/model"
+	"food-api/domain/food/domain/service"
+	"food-api/infrastructure/middleware"
+	"github.com/go-chi/chi"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// swagger:route GET /foods/{id}/translations Food idFoodTranslationsPath
+//
+// TranslationsHandler.
+// Response the translations of a food
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerTranslationsResponse
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// TranslationsHandler response the translations of a food visible to the user.
+func (ur *FoodRouter) TranslationsHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	ctx := r.Context()
+	_, err = ur.Repo.GetFoodById(ctx, id, metadata.UserId)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	translations, err := ur.Translations.GetTranslationsByFoodIds(ctx, []string{id})
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if translations == nil {
+		translations = []response.TranslationResponse{}
+	}
+
+	_ = middleware.JSON(w, r, http.StatusOK, translations)
+}
+
+// swagger:route PUT /foods/{id}/translations/{lang} Food foodTranslationRequest
+//
+// SaveTranslationHandler.
+// Add or edit the translation of a food to a language
+//
+//     consumes:
+//     - application/json
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerTranslationResponse
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  422: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// SaveTranslationHandler add or replace the translation of a food, only the owner of the food can translate it.
+func (ur *FoodRouter) SaveTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	var translation model.FoodTranslation
+	err = json.NewDecoder(r.Body).Decode(&translation)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+	translation.FoodID = id
+	translation.Language = chi.URLParam(r, "lang")
+	translationErrors := translation.Validate()
+	if len(translationErrors) > 0 {
+		_ = middleware.HTTPErrors(w, r, http.StatusUnprocessableEntity, translationErrors)
+		return
+	}
+
+	ctx := r.Context()
+	if !ur.ownFood(w, r, id, metadata.UserId) {
+		return
+	}
+
+	now := time.Now()
+	translation.CreatedAt = now
+	translation.UpdatedAt = now
+
+	result, err := ur.Translations.SaveTranslation(ctx, &translation)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_ = middleware.JSON(w, r, http.StatusOK, result)
+}
+
+// swagger:route DELETE /foods/{id}/translations/{lang} Food langFoodTranslationDeletePath
+//
+// DeleteTranslationHandler.
+// Remove the translation of a food to a language
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        204: SwaggerSuccessfullyMessage
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//
+// DeleteTranslationHandler remove the translation of a food, only the owner of the food can remove it.
+func (ur *FoodRouter) DeleteTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	language, ok := model.NormalizeLanguage(chi.URLParam(r, "lang"))
+	if id == "" || !ok {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id or language").Error())
+		return
+	}
+
+	if !ur.ownFood(w, r, id, metadata.UserId) {
+		return
+	}
+
+	err = ur.Translations.DeleteTranslation(r.Context(), id, language)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	_ = middleware.JSONMessages(w, r, http.StatusNoContent, "Successfully delete translation")
+}
+
+// ownFood writes a 404 and returns false when the food does not exist or does not belong to the user.
+func (ur *FoodRouter) ownFood(w http.ResponseWriter, r *http.Request, id, userId string) bool {
+	food, err := ur.Repo.GetFoodById(r.Context(), id, userId)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return false
+	}
+
+	if food.UserID != userId {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, errors.New("food not found").Error())
+		return false
+	}
+
+	return true
+}
+
+// localize replaces the content of the foods with their translation that best matches the
+// Accept-Language header, a food keeps its own content when it is already in the best language
+// or none of its languages is acceptable. The language of the returned content is set on each food.
+func (ur *FoodRouter) localize(w http.ResponseWriter, r *http.Request, foods []response.FoodResponse) error {
+	w.Header().Add("Vary", "Accept-Language")
+
+	acceptLanguage := r.Header.Get("Accept-Language")
+	if acceptLanguage == "" || len(foods) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(foods))
+	for _, food := range foods {
+		ids = append(ids, food.ID)
+	}
+
+	translations, err := ur.Translations.GetTranslationsByFoodIds(r.Context(), ids)
+	if err != nil {
+		return err
+	}
+
+	byFood := make(map[string]map[string]response.TranslationResponse)
+	for _, translation := range translations {
+		if byFood[translation.FoodID] == nil {
+			byFood[translation.FoodID] = make(map[string]response.TranslationResponse)
+		}
+
+		byFood[translation.FoodID][translation.Language] = translation
+	}
+
+	for i := range foods {
+		foodTranslations := byFood[foods[i].ID]
+		if len(foodTranslations) == 0 {
+			continue
+		}
+
+		languages := make([]string, 0, len(foodTranslations))
+		for language := range foodTranslations {
+			if language != foods[i].Language {
+				languages = append(languages, language)
+			}
+		}
+
+		sort.Strings(languages)
+
+		available := languages
+		if foods[i].Language != "" {
+			available = append([]string{foods[i].Language}, languages...)
+		}
+
+		language := service.NegotiateLanguage(acceptLanguage, available)
+		translation, ok := foodTranslations[language]
+		if !ok || language == foods[i].Language {
+			continue
+		}
+
+		foods[i].Title = translation.Title
+		foods[i].Description = translation.Description
+		if len(translation.Instructions) > 0 {
+			foods[i].Instructions = translation.Instructions
+		}
+
+		foods[i].Language = language
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package v1
+
+import (
+	"errors"
+	"food-api/domain/food/domain/model"
+	"food-api/domain/food/domain/service"
+	"food-api/infrastructure/middleware"
+	"log"
+	"net/http"
+	"time"
+)
+
+// trendingCandidates is the number of scores read to fill a page, some of them may not be visible to the user.
+const trendingCandidates = 100
+
+// Values of the X-Trending-Source header, it tells whether the ranking comes from the Redis counters
+// or from the favorites and ratings of the database because Redis is unavailable.
+const (
+	trendingSourceRedis    = "redis"
+	trendingSourceDatabase = "database"
+)
+
+// swagger:route GET /foods/trending Food trendingFood
+//
+// TrendingHandler.
+// Response the trending foods
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerScoredFoodResponse
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// TrendingHandler response the foods visible to the user with more views, favorites and ratings in the
+// window, the recent activity weighs more. When Redis is unavailable the foods are ranked by the
+// favorites and ratings of the window saved in the database.
+func (ur *FoodRouter) TrendingHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	window := r.URL.Query().Get("window")
+	if window == "" {
+		window = model.TrendingWindowDay
+	}
+
+	if !model.ValidTrendingWindow(window) {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("window must be 24h or 7d").Error())
+		return
+	}
+
+	ctx := r.Context()
+	now := time.Now()
+	source := trendingSourceRedis
+
+	var scores []model.FoodScore
+	if ur.Trending != nil {
+		scores, err = ur.Trending.GetTrending(ctx, window, now, trendingCandidates)
+	}
+
+	if ur.Trending == nil || err != nil {
+		if err != nil {
+			log.Printf("cannot get the trending foods from redis: %s", err.Error())
+		}
+
+		source = trendingSourceDatabase
+		scores, err = ur.Preferences.GetPopularFood(ctx, now.Add(-service.TrendingDuration(window)), trendingCandidates)
+		if err != nil {
+			_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	ids := make([]string, 0, len(scores))
+	for _, score := range scores {
+		ids = append(ids, score.FoodID)
+	}
+
+	foods, err := ur.Repo.GetFoodsByIds(ctx, ids, metadata.UserId)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("X-Trending-Source", source)
+	ur.respondScoredFood(w, r, service.RankTrending(scores, foods, scoredLimit(r)))
+}
+
+// recordTrending adds an event of the food to the trending counters, a failure is only logged so
+// the request does not depend on Redis.
+func (ur *FoodRouter) recordTrending(r *http.Request, foodId string, weight float64) {
+	if ur.Trending == nil {
+		return
+	}
+
+	err := ur.Trending.AddEvent(r.Context(), foodId, weight, time.Now())
+	if err != nil {
+		log.Printf("cannot record the trending event of the food %s: %s", foodId, err.Error())
+	}
+}
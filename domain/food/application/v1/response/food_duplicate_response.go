@@ -0,0 +1,31 @@
+package response
+
+// DuplicateCandidateResponse is a stored food that looks like the same dish.
+type DuplicateCandidateResponse struct {
+	ID              string  `json:"id"`
+	Title           string  `json:"title"`
+	Similarity      float64 `json:"similarity"`
+	SameIngredients bool    `json:"same_ingredients"`
+}
+
+// DuplicateConflictResponse is returned when a food is not created because of its duplicates.
+type DuplicateConflictResponse struct {
+	Status       int                          `json:"status"`
+	Message      string                       `json:"message"`
+	CandidateIDs []string                     `json:"candidate_ids"`
+	Candidates   []DuplicateCandidateResponse `json:"candidates"`
+}
+
+// DuplicateConflictResponse It is the response when the food has near-duplicates
+// swagger:response SwaggerDuplicateConflictResponse
+type SwaggerDuplicateConflictResponse struct {
+	// in: body
+	Body DuplicateConflictResponse
+}
+
+// DuplicateCandidateResponse It is the response of the near-duplicates of a food
+// swagger:response SwaggerDuplicatesResponse
+type SwaggerDuplicatesResponse struct {
+	// in: body
+	Body []DuplicateCandidateResponse
+}
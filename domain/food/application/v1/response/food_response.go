@@ -1,11 +1,17 @@
 package response
 
 type FoodResponse struct {
-	ID          string `json:"id,omitempty"`
-	UserID      string `json:"user_id,omitempty"`
-	Title       string `json:"title,omitempty"`
-	Description string `json:"description,omitempty"`
-	FoodImage   string `json:"food_image,omitempty"`
+	ID           string   `json:"id,omitempty"`
+	UserID       string   `json:"user_id,omitempty"`
+	Title        string   `json:"title,omitempty"`
+	Slug         string   `json:"slug,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	FoodImage    string   `json:"food_image,omitempty"`
+	Ingredients  []string `json:"ingredients,omitempty"`
+	Instructions []string `json:"instructions,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	Visibility   string   `json:"visibility,omitempty"`
+	Language     string   `json:"language,omitempty"`
 }
 
 
@@ -21,4 +27,17 @@ type SwaggerAllFoodResponse struct {
 type SwaggerFoodResponse struct {
 	// in: body
 	Body FoodResponse
-}
\ No newline at end of file
+}
+
+// ScoredFoodResponse is a food ranked by how much it matches a food or the taste of a user.
+type ScoredFoodResponse struct {
+	FoodResponse
+	Score float64 `json:"score"`
+}
+
+// ScoredFoodResponse It is the response of the similar or recommended foods
+// swagger:response SwaggerScoredFoodResponse
+type SwaggerScoredFoodResponse struct {
+	// in: body
+	Body []ScoredFoodResponse
+}
@@ -0,0 +1,52 @@
+package response
+
+import "time"
+
+// ImportRowResponse is the result of importing a single row of the file.
+type ImportRowResponse struct {
+	Row    int               `json:"row"`
+	ID     string            `json:"id,omitempty"`
+	Title  string            `json:"title,omitempty"`
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// ImportReportResponse summarizes the rows of an import.
+type ImportReportResponse struct {
+	Mode      string              `json:"mode"`
+	Total     int                 `json:"total"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Error     string              `json:"error,omitempty"`
+	Rows      []ImportRowResponse `json:"rows"`
+}
+
+// ImportJobResponse is the state of an asynchronous import.
+type ImportJobResponse struct {
+	ID         string                `json:"id"`
+	UserID     string                `json:"user_id"`
+	Status     string                `json:"status"`
+	Report     *ImportReportResponse `json:"report,omitempty"`
+	CreatedAt  time.Time             `json:"created_at"`
+	FinishedAt *time.Time            `json:"finished_at,omitempty"`
+}
+
+// ImportReportResponse It is the response of a synchronous import
+// swagger:response SwaggerImportReportResponse
+type SwaggerImportReportResponse struct {
+	// in: body
+	Body ImportReportResponse
+}
+
+// ImportJobResponse It is the response of an asynchronous import
+// swagger:response SwaggerImportJobResponse
+type SwaggerImportJobResponse struct {
+	// in: body
+	Body ImportJobResponse
+}
+
+// The foods of the user in the requested format
+// swagger:response SwaggerFoodExportResponse
+type SwaggerFoodExportResponse struct {
+	// in: body
+	Body string
+}
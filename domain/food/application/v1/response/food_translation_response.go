@@ -0,0 +1,24 @@
+package response
+
+// TranslationResponse is the content of a food in a language.
+type TranslationResponse struct {
+	FoodID       string   `json:"food_id,omitempty"`
+	Language     string   `json:"language,omitempty"`
+	Title        string   `json:"title,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	Instructions []string `json:"instructions,omitempty"`
+}
+
+// TranslationResponse It is the response of a translation of a food
+// swagger:response SwaggerTranslationResponse
+type SwaggerTranslationResponse struct {
+	// in: body
+	Body TranslationResponse
+}
+
+// TranslationResponse It is the response of the translations of a food
+// swagger:response SwaggerTranslationsResponse
+type SwaggerTranslationsResponse struct {
+	// in: body
+	Body []TranslationResponse
+}
@@ -1,24 +1,49 @@
 package model
 
 import (
+	"fmt"
 	"strings"
 	"time"
 )
 
+// Visibility levels of a food, a food is always visible to its owner.
+const (
+	// VisibilityPrivate foods are only visible to the owner, it is the default.
+	VisibilityPrivate = "private"
+	// VisibilityFollowers foods are visible to the followers of the owner.
+	VisibilityFollowers = "followers"
+	// VisibilityPublic foods are visible to everyone, including anonymous users.
+	VisibilityPublic = "public"
+)
+
+// TagsMaxCount is the maximum number of tags of a food and TagMaxLength the maximum length of a tag.
+const (
+	TagsMaxCount = 20
+	TagMaxLength = 50
+)
+
 // Data of Food
 // swagger:model
 type Food struct {
-	ID          string     `json:"id,omitempty"`
+	ID           string     `json:"id,omitempty"`
 	// Required: true
-	UserID      string     `json:"user_id,omitempty"`
+	UserID       string     `json:"user_id,omitempty"`
 	// Required: true
-	Title       string     `json:"title,omitempty"`
+	Title        string     `json:"title,omitempty"`
 	// Required: true
-	Description string     `json:"description,omitempty"`
-	FoodImage   string     `json:"food_image,omitempty"`
-	CreatedAt   time.Time  `json:"-"`
-	UpdatedAt   time.Time  `json:"-"`
-	DeletedAt   *time.Time `json:"-"`
+	Description  string     `json:"description,omitempty"`
+	FoodImage    string     `json:"food_image,omitempty"`
+	Ingredients  []string   `json:"ingredients,omitempty"`
+	Instructions []string   `json:"instructions,omitempty"`
+	Tags         []string   `json:"tags,omitempty"`
+	// Enum: private,followers,public
+	Visibility   string     `json:"visibility,omitempty"`
+	// Language of the title, description and instructions, e.g. es or en-US
+	Language     string     `json:"language,omitempty"`
+	Slug         string     `json:"-"`
+	CreatedAt    time.Time  `json:"-"`
+	UpdatedAt    time.Time  `json:"-"`
+	DeletedAt    *time.Time `json:"-"`
 }
 
 // Information from food
@@ -39,6 +64,10 @@ type SwaggerFoodRequest struct {
 	// Required: true
 	Authorization string
 
+	// Create the food even if it looks like a duplicate
+	// in: query
+	Force bool `json:"force"`
+
 	//in: body
 	Body Food
 }
@@ -58,10 +87,16 @@ type SwaggerFoodUpdateRequest struct {
 	// in: body
 	Body struct{
 		// Required: true
-		Title       string     `json:"title,omitempty"`
+		Title        string   `json:"title,omitempty"`
 		// Required: true
-		Description string     `json:"description,omitempty"`
-		FoodImage   string     `json:"food_image,omitempty"`
+		Description  string   `json:"description,omitempty"`
+		FoodImage    string   `json:"food_image,omitempty"`
+		Ingredients  []string `json:"ingredients,omitempty"`
+		Instructions []string `json:"instructions,omitempty"`
+		Tags         []string `json:"tags,omitempty"`
+		// Enum: private,followers,public
+		Visibility   string   `json:"visibility,omitempty"`
+		Language     string   `json:"language,omitempty"`
 	}
 }
 
@@ -89,6 +124,44 @@ type SwaggerFoodDeletePathId struct {
 	ID string
 }
 
+// swagger:parameters idFoodDuplicatesPath
+type SwaggerFoodDuplicatesPathId struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	ID string
+}
+
+// swagger:parameters slugFoodPath
+type SwaggerFoodPathSlug struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	Slug string
+}
+
+// swagger:parameters idPublicFoodPath
+type SwaggerPublicFoodPathId struct {
+	// in: path
+	// Required: true
+	ID string
+}
+
+// swagger:parameters slugPublicFoodPath
+type SwaggerPublicFoodPathSlug struct {
+	// in: path
+	// Required: true
+	Slug string
+}
+
 // swagger:parameters idFoodByUserPath
 type SwaggerFoodPathUser struct {
 	// type: apiKey
@@ -123,5 +196,62 @@ func (f *Food) Validate(action string) map[string]string {
 			errorMessages["desc_required"] = "description is required"
 		}
 	}
+
+	if f.Visibility != "" && !ValidVisibility(f.Visibility) {
+		errorMessages["visibility_invalid"] = "visibility must be private, followers or public"
+	}
+
+	tags, ok := NormalizeTags(f.Tags)
+	if !ok {
+		errorMessages["tags_invalid"] = fmt.Sprintf("a food can have up to %d tags of up to %d characters", TagsMaxCount, TagMaxLength)
+	}
+
+	f.Tags = tags
+
+	if f.Language != "" {
+		language, ok := NormalizeLanguage(f.Language)
+		if !ok {
+			errorMessages["language_invalid"] = "language must be a language tag such as es or en-US"
+		}
+
+		f.Language = language
+	}
+
 	return errorMessages
 }
+
+// NormalizeTags returns the tags in lowercase, without surrounding spaces, empty tags or
+// repeated tags, false when there are too many tags or one of them is too long.
+func NormalizeTags(tags []string) ([]string, bool) {
+	if tags == nil {
+		return nil, true
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+		if tag == "" || seen[tag] {
+			continue
+		}
+
+		if len([]rune(tag)) > TagMaxLength {
+			return tags, false
+		}
+
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	return normalized, len(normalized) <= TagsMaxCount
+}
+
+// ValidVisibility reports whether the value is one of the visibility levels.
+func ValidVisibility(visibility string) bool {
+	switch visibility {
+	case VisibilityPrivate, VisibilityFollowers, VisibilityPublic:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,91 @@
+package model
+
+const (
+	// ImportModeAtomic inserts every row in a single transaction, nothing is saved if a row fails.
+	ImportModeAtomic = "atomic"
+	// ImportModePartial saves the valid rows and reports the ones that failed.
+	ImportModePartial = "partial"
+
+	// ImportFormatCSV is a comma separated file with a header row.
+	ImportFormatCSV = "csv"
+	// ImportFormatNDJSON is a file with one food JSON object per line.
+	ImportFormatNDJSON = "ndjson"
+
+	// ExportFormatCSV is a comma separated file with a header row.
+	ExportFormatCSV = "csv"
+	// ExportFormatNDJSON is a file with one food JSON object per line.
+	ExportFormatNDJSON = "ndjson"
+	// ExportFormatJSONLD is a schema.org JSON-LD document with one Recipe per food.
+	ExportFormatJSONLD = "jsonld"
+
+	ImportStatusPending   = "pending"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+)
+
+// Information for the food import
+// swagger:parameters foodImportRequest
+type SwaggerFoodImportRequest struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// csv or ndjson, by default it is taken from the Content-Type
+	// in: query
+	Format string `json:"format"`
+
+	// atomic or partial
+	// in: query
+	Mode string `json:"mode"`
+
+	// run the import as an asynchronous job
+	// in: query
+	Async bool `json:"async"`
+
+	// in: body
+	Body string
+}
+
+// Information for the food export
+// swagger:parameters foodExportRequest
+type SwaggerFoodExportRequest struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// csv, ndjson or jsonld
+	// in: query
+	Format string `json:"format"`
+}
+
+// Information for the recipe import
+// swagger:parameters foodRecipeImportRequest
+type SwaggerFoodRecipeImportRequest struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// save the food instead of returning a preview
+	// in: query
+	Save bool `json:"save"`
+
+	// HTML document or JSON-LD payload
+	// in: body
+	Body string
+}
+
+// swagger:parameters idFoodImportPath
+type SwaggerFoodImportPathId struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	ID string
+}
@@ -0,0 +1,90 @@
+package model
+
+import "time"
+
+// FoodPreference is how a user likes a food, a food can be a favorite and be rated from 1 to 5.
+type FoodPreference struct {
+	UserID    string    `json:"-"`
+	FoodID    string    `json:"food_id,omitempty"`
+	Favorite  bool      `json:"favorite"`
+	// Minimum: 1
+	// Maximum: 5
+	Rating    int       `json:"rating,omitempty"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// swagger:parameters idFoodFavoritePath
+type SwaggerFoodFavoritePathId struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	ID string
+}
+
+// Rating of a food
+// swagger:parameters foodRatingRequest
+type SwaggerFoodRatingRequest struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	ID string
+
+	// in: body
+	Body struct {
+		// Required: true
+		// Minimum: 1
+		// Maximum: 5
+		Rating int `json:"rating"`
+	}
+}
+
+// swagger:parameters idFoodSimilarPath
+type SwaggerFoodSimilarPathId struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	ID string
+
+	// Maximum number of foods, 10 by default
+	// in: query
+	Limit int `json:"limit"`
+}
+
+// swagger:parameters idUserRecommendationsPath
+type SwaggerUserRecommendationsPathId struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	ID string
+
+	// Maximum number of foods, 10 by default
+	// in: query
+	Limit int `json:"limit"`
+}
+
+func (p *FoodPreference) Validate() map[string]string {
+	var errorMessages = make(map[string]string)
+
+	if p.Rating < 1 || p.Rating > 5 {
+		errorMessages["rating_invalid"] = "rating must be from 1 to 5"
+	}
+
+	return errorMessages
+}
@@ -0,0 +1,25 @@
+package model
+
+// SchemaContext is the JSON-LD context of the schema.org vocabulary.
+const SchemaContext = "https://schema.org"
+
+// Recipe is the schema.org Recipe representation of a food
+// https://schema.org/Recipe
+type Recipe struct {
+	Context      string      `json:"@context,omitempty"`
+	Type         string      `json:"@type"`
+	Identifier   string      `json:"identifier,omitempty"`
+	Name         string      `json:"name"`
+	Description  string      `json:"description,omitempty"`
+	Image        string      `json:"image,omitempty"`
+	Ingredients  []string    `json:"recipeIngredient,omitempty"`
+	Instructions []HowToStep `json:"recipeInstructions,omitempty"`
+	Keywords     string      `json:"keywords,omitempty"`
+}
+
+// HowToStep is a single instruction of a recipe
+// https://schema.org/HowToStep
+type HowToStep struct {
+	Type string `json:"@type"`
+	Text string `json:"text"`
+}
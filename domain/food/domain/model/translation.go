@@ -0,0 +1,116 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// LanguageMaxLength is the maximum length of a language tag, e.g. "es-419" or "zh-hant-tw".
+const LanguageMaxLength = 35
+
+// Translation of the content of a food
+// swagger:model
+type FoodTranslation struct {
+	FoodID       string    `json:"-"`
+	Language     string    `json:"-"`
+	// Required: true
+	Title        string    `json:"title,omitempty"`
+	// Required: true
+	Description  string    `json:"description,omitempty"`
+	Instructions []string  `json:"instructions,omitempty"`
+	CreatedAt    time.Time `json:"-"`
+	UpdatedAt    time.Time `json:"-"`
+}
+
+// Translation of a food to add or edit
+// swagger:parameters foodTranslationRequest
+type SwaggerFoodTranslationRequest struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	ID string
+
+	// Language tag of the translation, e.g. es or en-US
+	// in: path
+	// Required: true
+	Lang string
+
+	// in: body
+	Body FoodTranslation
+}
+
+// swagger:parameters idFoodTranslationsPath
+type SwaggerFoodTranslationsPathId struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	ID string
+}
+
+// swagger:parameters langFoodTranslationDeletePath
+type SwaggerFoodTranslationDeletePath struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	ID string
+
+	// in: path
+	// Required: true
+	Lang string
+}
+
+func (t *FoodTranslation) Validate() map[string]string {
+	var errorMessages = make(map[string]string)
+
+	if t.Title == "" || t.Title == "null" {
+		errorMessages["title_required"] = "title is required"
+	}
+
+	if t.Description == "" || t.Description == "null" {
+		errorMessages["desc_required"] = "description is required"
+	}
+
+	language, ok := NormalizeLanguage(t.Language)
+	if !ok {
+		errorMessages["language_invalid"] = "language must be a language tag such as es or en-US"
+	}
+
+	t.Language = language
+
+	return errorMessages
+}
+
+// NormalizeLanguage returns the lowercase version of a language tag such as "es", "en-US" or
+// "es-419", false when it is not a valid tag. The wildcard "*" is not a valid language.
+func NormalizeLanguage(language string) (string, bool) {
+	language = strings.ToLower(strings.TrimSpace(strings.Replace(language, "_", "-", -1)))
+	if language == "" || len(language) > LanguageMaxLength {
+		return "", false
+	}
+
+	for i, subtag := range strings.Split(language, "-") {
+		if len(subtag) == 0 || len(subtag) > 8 || (i == 0 && (len(subtag) < 2 || len(subtag) > 3)) {
+			return "", false
+		}
+
+		for _, r := range subtag {
+			if !(r >= 'a' && r <= 'z') && !(i > 0 && r >= '0' && r <= '9') {
+				return "", false
+			}
+		}
+	}
+
+	return language, true
+}
@@ -0,0 +1,43 @@
+package model
+
+// Windows of the trending foods.
+const (
+	// TrendingWindowDay ranks the activity of the last 24 hours, it is the default.
+	TrendingWindowDay = "24h"
+	// TrendingWindowWeek ranks the activity of the last 7 days.
+	TrendingWindowWeek = "7d"
+)
+
+// FoodScore is the trending score of a food.
+type FoodScore struct {
+	FoodID string
+	Score  float64
+}
+
+// Information for the trending foods
+// swagger:parameters trendingFood
+type SwaggerTrendingFoodRequest struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// Window of the activity, 24h by default
+	// in: query
+	// Enum: 24h,7d
+	Window string `json:"window"`
+
+	// Maximum number of foods, 10 by default
+	// in: query
+	Limit int `json:"limit"`
+}
+
+// ValidTrendingWindow reports whether the value is one of the trending windows.
+func ValidTrendingWindow(window string) bool {
+	switch window {
+	case TrendingWindowDay, TrendingWindowWeek:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"food-api/domain/food/application/v1/response"
+)
+
+type ImportJobRepository interface {
+	SaveImportJob(ctx context.Context, job *response.ImportJobResponse) error
+	GetImportJobById(ctx context.Context, id string) (*response.ImportJobResponse, error)
+	DeleteImportJob(ctx context.Context, id string) error
+}
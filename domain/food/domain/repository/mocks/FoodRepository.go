@@ -4,11 +4,10 @@ package mocks
 
 import (
 	context "context"
+	response "food-api/domain/food/application/v1/response"
 	model "food-api/domain/food/domain/model"
 
 	mock "github.com/stretchr/testify/mock"
-
-	response "food-api/domain/food/application/v1/response"
 )
 
 // FoodRepository is an autogenerated mock type for the FoodRepository type
@@ -30,13 +29,13 @@ func (_m *FoodRepository) DeleteFood(ctx context.Context, id string) error {
 	return r0
 }
 
-// GetAllFood provides a mock function with given fields: ctx
-func (_m *FoodRepository) GetAllFood(ctx context.Context) ([]response.FoodResponse, error) {
-	ret := _m.Called(ctx)
+// GetAllFood provides a mock function with given fields: ctx, viewerId
+func (_m *FoodRepository) GetAllFood(ctx context.Context, viewerId string) ([]response.FoodResponse, error) {
+	ret := _m.Called(ctx, viewerId)
 
 	var r0 []response.FoodResponse
-	if rf, ok := ret.Get(0).(func(context.Context) []response.FoodResponse); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, string) []response.FoodResponse); ok {
+		r0 = rf(ctx, viewerId)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]response.FoodResponse)
@@ -44,8 +43,8 @@ func (_m *FoodRepository) GetAllFood(ctx context.Context) ([]response.FoodRespon
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, viewerId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -53,13 +52,36 @@ func (_m *FoodRepository) GetAllFood(ctx context.Context) ([]response.FoodRespon
 	return r0, r1
 }
 
-// GetFoodById provides a mock function with given fields: ctx, id
-func (_m *FoodRepository) GetFoodById(ctx context.Context, id string) (*response.FoodResponse, error) {
-	ret := _m.Called(ctx, id)
+// GetAllFoodByUserId provides a mock function with given fields: ctx, userId
+func (_m *FoodRepository) GetAllFoodByUserId(ctx context.Context, userId string) ([]response.FoodResponse, error) {
+	ret := _m.Called(ctx, userId)
+
+	var r0 []response.FoodResponse
+	if rf, ok := ret.Get(0).(func(context.Context, string) []response.FoodResponse); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]response.FoodResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFoodById provides a mock function with given fields: ctx, id, viewerId
+func (_m *FoodRepository) GetFoodById(ctx context.Context, id string, viewerId string) (*response.FoodResponse, error) {
+	ret := _m.Called(ctx, id, viewerId)
 
 	var r0 *response.FoodResponse
-	if rf, ok := ret.Get(0).(func(context.Context, string) *response.FoodResponse); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *response.FoodResponse); ok {
+		r0 = rf(ctx, id, viewerId)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*response.FoodResponse)
@@ -67,8 +89,8 @@ func (_m *FoodRepository) GetFoodById(ctx context.Context, id string) (*response
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, id, viewerId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -76,13 +98,13 @@ func (_m *FoodRepository) GetFoodById(ctx context.Context, id string) (*response
 	return r0, r1
 }
 
-// GetFoodByUserId provides a mock function with given fields: ctx, id
-func (_m *FoodRepository) GetFoodByUserId(ctx context.Context, id string) (*response.FoodResponse, error) {
-	ret := _m.Called(ctx, id)
+// GetFoodBySlug provides a mock function with given fields: ctx, slug, viewerId
+func (_m *FoodRepository) GetFoodBySlug(ctx context.Context, slug string, viewerId string) (*response.FoodResponse, error) {
+	ret := _m.Called(ctx, slug, viewerId)
 
 	var r0 *response.FoodResponse
-	if rf, ok := ret.Get(0).(func(context.Context, string) *response.FoodResponse); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *response.FoodResponse); ok {
+		r0 = rf(ctx, slug, viewerId)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*response.FoodResponse)
@@ -90,8 +112,75 @@ func (_m *FoodRepository) GetFoodByUserId(ctx context.Context, id string) (*resp
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, slug, viewerId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFoodByUserId provides a mock function with given fields: ctx, id, viewerId
+func (_m *FoodRepository) GetFoodByUserId(ctx context.Context, id string, viewerId string) (*response.FoodResponse, error) {
+	ret := _m.Called(ctx, id, viewerId)
+
+	var r0 *response.FoodResponse
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *response.FoodResponse); ok {
+		r0 = rf(ctx, id, viewerId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*response.FoodResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, id, viewerId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFoodsByIds provides a mock function with given fields: ctx, ids, viewerId
+func (_m *FoodRepository) GetFoodsByIds(ctx context.Context, ids []string, viewerId string) ([]response.FoodResponse, error) {
+	ret := _m.Called(ctx, ids, viewerId)
+
+	var r0 []response.FoodResponse
+	if rf, ok := ret.Get(0).(func(context.Context, []string, string) []response.FoodResponse); ok {
+		r0 = rf(ctx, ids, viewerId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]response.FoodResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string, string) error); ok {
+		r1 = rf(ctx, ids, viewerId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSlugByHistory provides a mock function with given fields: ctx, slug, viewerId
+func (_m *FoodRepository) GetSlugByHistory(ctx context.Context, slug string, viewerId string) (string, error) {
+	ret := _m.Called(ctx, slug, viewerId)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(ctx, slug, viewerId)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, slug, viewerId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -122,6 +211,43 @@ func (_m *FoodRepository) SaveFood(ctx context.Context, food *model.Food) (*resp
 	return r0, r1
 }
 
+// SaveFoodBatch provides a mock function with given fields: ctx, foods, partial
+func (_m *FoodRepository) SaveFoodBatch(ctx context.Context, foods []model.Food, partial bool) ([]error, error) {
+	ret := _m.Called(ctx, foods, partial)
+
+	var r0 []error
+	if rf, ok := ret.Get(0).(func(context.Context, []model.Food, bool) []error); ok {
+		r0 = rf(ctx, foods, partial)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]error)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []model.Food, bool) error); ok {
+		r1 = rf(ctx, foods, partial)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StreamFoodByUserId provides a mock function with given fields: ctx, userId, fn
+func (_m *FoodRepository) StreamFoodByUserId(ctx context.Context, userId string, fn func(food response.FoodResponse) error) error {
+	ret := _m.Called(ctx, userId, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, func(food response.FoodResponse) error) error); ok {
+		r0 = rf(ctx, userId, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateFood provides a mock function with given fields: ctx, id, food
 func (_m *FoodRepository) UpdateFood(ctx context.Context, id string, food *model.Food) error {
 	ret := _m.Called(ctx, id, food)
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"food-api/domain/food/domain/model"
+	"time"
+)
+
+type PreferenceRepository interface {
+	SaveFavorite(ctx context.Context, preference *model.FoodPreference) error
+	SaveRating(ctx context.Context, preference *model.FoodPreference) error
+	GetPreferencesByUserId(ctx context.Context, userId string) ([]model.FoodPreference, error)
+	GetPopularFood(ctx context.Context, since time.Time, limit int) ([]model.FoodScore, error)
+}
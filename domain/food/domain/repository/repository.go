@@ -8,9 +8,15 @@ import (
 
 type FoodRepository interface {
 	SaveFood(ctx context.Context, food *model.Food) (*response.FoodResponse, error)
-	GetFoodById(ctx context.Context, id string) (*response.FoodResponse, error)
-	GetFoodByUserId(ctx context.Context, id string) (*response.FoodResponse, error)
-	GetAllFood(ctx context.Context) ([]response.FoodResponse, error)
+	SaveFoodBatch(ctx context.Context, foods []model.Food, partial bool) ([]error, error)
+	GetFoodById(ctx context.Context, id, viewerId string) (*response.FoodResponse, error)
+	GetFoodBySlug(ctx context.Context, slug, viewerId string) (*response.FoodResponse, error)
+	GetSlugByHistory(ctx context.Context, slug, viewerId string) (string, error)
+	GetFoodByUserId(ctx context.Context, id, viewerId string) (*response.FoodResponse, error)
+	GetAllFood(ctx context.Context, viewerId string) ([]response.FoodResponse, error)
+	GetFoodsByIds(ctx context.Context, ids []string, viewerId string) ([]response.FoodResponse, error)
+	GetAllFoodByUserId(ctx context.Context, userId string) ([]response.FoodResponse, error)
+	StreamFoodByUserId(ctx context.Context, userId string, fn func(food response.FoodResponse) error) error
 	UpdateFood(ctx context.Context, id string, food *model.Food) error
 	DeleteFood(ctx context.Context, id string) error
 }
\ No newline at end of file
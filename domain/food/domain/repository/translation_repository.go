@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"food-api/domain/food/application/v1/response"
+	"food-api/domain/food/domain/model"
+)
+
+type TranslationRepository interface {
+	SaveTranslation(ctx context.Context, translation *model.FoodTranslation) (*response.TranslationResponse, error)
+	GetTranslationsByFoodIds(ctx context.Context, foodIds []string) ([]response.TranslationResponse, error)
+	DeleteTranslation(ctx context.Context, foodId, language string) error
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"food-api/domain/food/domain/model"
+	"time"
+)
+
+type TrendingRepository interface {
+	AddEvent(ctx context.Context, foodId string, weight float64, at time.Time) error
+	GetTrending(ctx context.Context, window string, now time.Time, limit int) ([]model.FoodScore, error)
+}
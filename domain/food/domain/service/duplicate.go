@@ -0,0 +1,126 @@
+package service
+
+import (
+	"food-api/domain/food/application/v1/response"
+	"food-api/domain/food/domain/model"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// TitleSimilarityThreshold is the trigram similarity from which two titles are considered the same dish.
+const TitleSimilarityThreshold = 0.6
+
+// Duplicate is a stored food that looks like the same dish as the one being checked.
+type Duplicate struct {
+	Food            response.FoodResponse
+	Similarity      float64
+	SameIngredients bool
+}
+
+// FindDuplicates returns the candidates that are near-duplicates of the food, the most similar first.
+// A candidate is a duplicate when its title is similar enough or when both have the same ingredients.
+// The candidate with the same id as the food is skipped.
+func FindDuplicates(food model.Food, candidates []response.FoodResponse) []Duplicate {
+	var duplicates []Duplicate
+
+	for _, candidate := range candidates {
+		if food.ID != "" && candidate.ID == food.ID {
+			continue
+		}
+
+		similarity := TitleSimilarity(food.Title, candidate.Title)
+		sameIngredients := SameIngredients(food.Ingredients, candidate.Ingredients)
+		if similarity < TitleSimilarityThreshold && !sameIngredients {
+			continue
+		}
+
+		duplicates = append(duplicates, Duplicate{
+			Food:            candidate,
+			Similarity:      similarity,
+			SameIngredients: sameIngredients,
+		})
+	}
+
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		return duplicates[i].Similarity > duplicates[j].Similarity
+	})
+
+	return duplicates
+}
+
+// TitleSimilarity returns the trigram similarity of the normalized titles, from 0 to 1.
+// It works like pg_trgm: the shared trigrams divided by the distinct trigrams of both titles.
+func TitleSimilarity(a, b string) float64 {
+	trigramsA := trigrams(NormalizeTitle(a))
+	trigramsB := trigrams(NormalizeTitle(b))
+	if len(trigramsA) == 0 || len(trigramsB) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for trigram := range trigramsA {
+		if _, ok := trigramsB[trigram]; ok {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(trigramsA)+len(trigramsB)-shared)
+}
+
+// SameIngredients reports whether both lists have the same normalized ingredients, ignoring
+// the order and the repeated items. Two empty lists are not considered the same.
+func SameIngredients(a, b []string) bool {
+	setA := ingredientSet(a)
+	setB := ingredientSet(b)
+	if len(setA) == 0 || len(setA) != len(setB) {
+		return false
+	}
+
+	for ingredient := range setA {
+		if _, ok := setB[ingredient]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NormalizeTitle lowercases the title and replaces everything that is not a letter or a digit with a space.
+func NormalizeTitle(title string) string {
+	title = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+
+		return ' '
+	}, title)
+
+	return strings.Join(strings.Fields(title), " ")
+}
+
+// trigrams returns the trigrams of each word, padded with two spaces before and one after.
+func trigrams(text string) map[string]struct{} {
+	result := make(map[string]struct{})
+
+	for _, word := range strings.Fields(text) {
+		runes := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			result[string(runes[i:i+3])] = struct{}{}
+		}
+	}
+
+	return result
+}
+
+func ingredientSet(ingredients []string) map[string]struct{} {
+	set := make(map[string]struct{})
+
+	for _, ingredient := range ingredients {
+		if normalized := NormalizeTitle(ingredient); normalized != "" {
+			set[normalized] = struct{}{}
+		}
+	}
+
+	return set
+}
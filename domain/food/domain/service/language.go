@@ -0,0 +1,107 @@
+package service
+
+import (
+	"food-api/domain/food/domain/model"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// languageRange is a language of the Accept-Language header with its quality.
+type languageRange struct {
+	tag     string
+	quality float64
+}
+
+// NegotiateLanguage returns the language of available that best matches the Accept-Language header,
+// the ranges are tried from the highest quality and for each one an exact match is preferred over a
+// language of the same family, e.g. "es-MX" is served by "es" and "es" by "es-mx". It returns an
+// empty string when none of the available languages is acceptable.
+func NegotiateLanguage(acceptLanguage string, available []string) string {
+	for _, accepted := range parseAcceptLanguage(acceptLanguage) {
+		if accepted.tag == "*" {
+			if len(available) > 0 {
+				return available[0]
+			}
+
+			continue
+		}
+
+		if language := matchLanguage(accepted.tag, available); language != "" {
+			return language
+		}
+	}
+
+	return ""
+}
+
+// matchLanguage returns the available language equal to the tag, otherwise the first one that
+// shares the primary subtag.
+func matchLanguage(tag string, available []string) string {
+	primary := primaryLanguage(tag)
+	family := ""
+
+	for _, language := range available {
+		if language == tag {
+			return language
+		}
+
+		if family == "" && primaryLanguage(language) == primary {
+			family = language
+		}
+	}
+
+	return family
+}
+
+// primaryLanguage returns the first subtag of the language, e.g. "es" for "es-mx".
+func primaryLanguage(language string) string {
+	if i := strings.IndexByte(language, '-'); i >= 0 {
+		return language[:i]
+	}
+
+	return language
+}
+
+// parseAcceptLanguage returns the ranges of the header sorted by quality, the ranges with
+// quality 0 or an invalid tag are discarded.
+func parseAcceptLanguage(acceptLanguage string) []languageRange {
+	var ranges []languageRange
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		params := strings.Split(part, ";")
+
+		tag := strings.TrimSpace(params[0])
+		if tag != "*" {
+			var ok bool
+			if tag, ok = model.NormalizeLanguage(tag); !ok {
+				continue
+			}
+		}
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+
+			value, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+			if err != nil || value < 0 || value > 1 {
+				value = 0
+			}
+
+			quality = value
+		}
+
+		if quality > 0 {
+			ranges = append(ranges, languageRange{tag: tag, quality: quality})
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+
+	return ranges
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"food-api/domain/food/application/v1/response"
+	"sort"
+	"strings"
+)
+
+// MinScore is the score from which a food is similar enough to be returned, it discards the
+// candidates that only share a few trigrams of the text.
+const MinScore = 0.05
+
+// Scorer scores how similar a candidate food is to a target food, from 0 to 1.
+type Scorer interface {
+	Score(target, candidate response.FoodResponse) float64
+}
+
+// WeightedScorer combines the shared tags, the ingredient overlap and the text similarity with the
+// given weights. A signal is only used when the target has data for it, e.g. the tags do not count
+// for a target without tags, and the result is divided by the sum of the weights used.
+type WeightedScorer struct {
+	Tags        float64
+	Ingredients float64
+	Text        float64
+}
+
+// NewScorer returns the scorer used by the API.
+func NewScorer() Scorer {
+	return WeightedScorer{Tags: 0.4, Ingredients: 0.4, Text: 0.2}
+}
+
+// Score implements Scorer.
+func (ws WeightedScorer) Score(target, candidate response.FoodResponse) float64 {
+	var score, weights float64
+
+	if tags := tagSet(target.Tags); len(tags) > 0 && ws.Tags > 0 {
+		score += ws.Tags * jaccard(tags, tagSet(candidate.Tags))
+		weights += ws.Tags
+	}
+
+	if ingredients := ingredientSet(target.Ingredients); len(ingredients) > 0 && ws.Ingredients > 0 {
+		score += ws.Ingredients * jaccard(ingredients, ingredientSet(candidate.Ingredients))
+		weights += ws.Ingredients
+	}
+
+	if ws.Text > 0 {
+		score += ws.Text * TextSimilarity(target, candidate)
+		weights += ws.Text
+	}
+
+	if weights == 0 {
+		return 0
+	}
+
+	return score / weights
+}
+
+// TextSimilarity returns the trigram similarity of the titles and the descriptions, the title
+// counts twice as much as the description.
+func TextSimilarity(a, b response.FoodResponse) float64 {
+	return (2*TitleSimilarity(a.Title, b.Title) + TitleSimilarity(a.Description, b.Description)) / 3
+}
+
+// Seed is a food the user likes, or dislikes when its weight is negative, used to recommend foods.
+type Seed struct {
+	Food   response.FoodResponse
+	Weight float64
+}
+
+// PreferenceWeight returns the weight of a food the user marked as favorite and/or rated from 1 to 5,
+// a rating of 0 means the food is not rated. A favorite adds 1 and a rating adds from -1 to 1.
+func PreferenceWeight(favorite bool, rating int) float64 {
+	var weight float64
+	if favorite {
+		weight++
+	}
+
+	if rating > 0 {
+		weight += float64(rating-3) / 2
+	}
+
+	return weight
+}
+
+// RankSimilar returns up to limit candidates with a score of at least MinScore, the most similar to
+// the target first. The target is skipped and the ties are sorted by id so the result is deterministic.
+func RankSimilar(scorer Scorer, target response.FoodResponse, candidates []response.FoodResponse, limit int) []response.ScoredFoodResponse {
+	var ranked []response.ScoredFoodResponse
+
+	for _, candidate := range candidates {
+		if candidate.ID == target.ID {
+			continue
+		}
+
+		if score := scorer.Score(target, candidate); score >= MinScore {
+			ranked = append(ranked, response.ScoredFoodResponse{FoodResponse: candidate, Score: round(score)})
+		}
+	}
+
+	return top(ranked, limit)
+}
+
+// Recommend returns up to limit candidates with a score of at least MinScore, the score of a candidate
+// is the average of its scores against the seeds weighted by the weight of each seed. The seeds and
+// the excluded ids are never recommended.
+func Recommend(scorer Scorer, seeds []Seed, candidates []response.FoodResponse, exclude map[string]bool, limit int) []response.ScoredFoodResponse {
+	var ranked []response.ScoredFoodResponse
+
+	skip := make(map[string]bool, len(seeds)+len(exclude))
+	for id := range exclude {
+		skip[id] = true
+	}
+
+	for _, seed := range seeds {
+		skip[seed.Food.ID] = true
+	}
+
+	for _, candidate := range candidates {
+		if skip[candidate.ID] {
+			continue
+		}
+
+		var score, weights float64
+		for _, seed := range seeds {
+			if seed.Weight == 0 {
+				continue
+			}
+
+			score += seed.Weight * scorer.Score(seed.Food, candidate)
+			if seed.Weight > 0 {
+				weights += seed.Weight
+			} else {
+				weights -= seed.Weight
+			}
+		}
+
+		if weights > 0 && score/weights >= MinScore {
+			ranked = append(ranked, response.ScoredFoodResponse{FoodResponse: candidate, Score: round(score / weights)})
+		}
+	}
+
+	return top(ranked, limit)
+}
+
+// top sorts the foods by score and then by id and keeps the first limit ones.
+func top(ranked []response.ScoredFoodResponse, limit int) []response.ScoredFoodResponse {
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+
+		return ranked[i].ID < ranked[j].ID
+	})
+
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	return ranked
+}
+
+// round keeps four decimals of the score so the order does not depend on floating point noise.
+func round(score float64) float64 {
+	return float64(int64(score*10000+0.5)) / 10000
+}
+
+func tagSet(tags []string) map[string]struct{} {
+	set := make(map[string]struct{})
+
+	for _, tag := range tags {
+		if normalized := strings.ToLower(strings.TrimSpace(tag)); normalized != "" {
+			set[normalized] = struct{}{}
+		}
+	}
+
+	return set
+}
+
+// jaccard returns the shared items divided by the distinct items of both sets.
+func jaccard(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for item := range a {
+		if _, ok := b[item]; ok {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
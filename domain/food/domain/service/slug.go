@@ -0,0 +1,93 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SlugMaxLength is the maximum length of the slug generated from the title, the
+// suffix added to resolve a collision can be added on top of it.
+const SlugMaxLength = 140
+
+// defaultSlug is used when the title does not contain any letter or digit.
+const defaultSlug = "food"
+
+// transliterations replaces the accented latin letters with their ASCII equivalent.
+var transliterations = map[rune]string{
+	'á': "a", 'à': "a", 'â': "a", 'ä': "a", 'ã': "a", 'å': "a",
+	'é': "e", 'è': "e", 'ê': "e", 'ë': "e",
+	'í': "i", 'ì': "i", 'î': "i", 'ï': "i",
+	'ó': "o", 'ò': "o", 'ô': "o", 'ö': "o", 'õ': "o", 'ø': "o",
+	'ú': "u", 'ù': "u", 'û': "u", 'ü': "u",
+	'ñ': "n", 'ç': "c", 'ý': "y", 'ÿ': "y",
+	'æ': "ae", 'œ': "oe", 'ß': "ss",
+}
+
+// Slugify returns the URL friendly version of the title: lowercase ASCII letters and
+// digits separated by a single dash, e.g. "Spicy Chicken Curry!" is "spicy-chicken-curry".
+func Slugify(title string) string {
+	var builder strings.Builder
+	dash := false
+
+	for _, r := range strings.ToLower(title) {
+		if replacement, ok := transliterations[r]; ok {
+			builder.WriteString(replacement)
+			dash = false
+			continue
+		}
+
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			builder.WriteRune(r)
+			dash = false
+			continue
+		}
+
+		if !dash && builder.Len() > 0 {
+			builder.WriteByte('-')
+			dash = true
+		}
+	}
+
+	slug := builder.String()
+	if len(slug) > SlugMaxLength {
+		slug = slug[:SlugMaxLength]
+	}
+
+	slug = strings.Trim(slug, "-")
+	if slug == "" {
+		return defaultSlug
+	}
+
+	return slug
+}
+
+// UniqueSlug returns the base slug when it is free, otherwise the base with the first
+// free numeric suffix: "spicy-chicken-curry-2", "spicy-chicken-curry-3" and so on.
+func UniqueSlug(base string, taken map[string]bool) string {
+	if !taken[base] {
+		return base
+	}
+
+	for n := 2; ; n++ {
+		slug := base + "-" + strconv.Itoa(n)
+		if !taken[slug] {
+			return slug
+		}
+	}
+}
+
+// SlugMatchesTitle reports whether the slug was generated from the title, with or without
+// a collision suffix. It is used to keep the slug stable when the title does not change.
+func SlugMatchesTitle(slug, title string) bool {
+	base := Slugify(title)
+	if slug == base {
+		return true
+	}
+
+	if !strings.HasPrefix(slug, base+"-") {
+		return false
+	}
+
+	_, err := strconv.Atoi(strings.TrimPrefix(slug, base+"-"))
+	return err == nil
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"food-api/domain/food/application/v1/response"
+	"food-api/domain/food/domain/model"
+	"math"
+	"time"
+)
+
+// Weights of the events that make a food trend.
+const (
+	ViewWeight     = 1.0
+	FavoriteWeight = 3.0
+	RatingWeight   = 2.0
+)
+
+// TrendingDuration returns how long ago the activity of the window starts.
+func TrendingDuration(window string) time.Duration {
+	if window == model.TrendingWindowWeek {
+		return 7 * 24 * time.Hour
+	}
+
+	return 24 * time.Hour
+}
+
+// DecayWeight returns the weight of an activity that happened age ago, it halves every halfLife.
+func DecayWeight(age, halfLife time.Duration) float64 {
+	if age <= 0 || halfLife <= 0 {
+		return 1
+	}
+
+	return math.Pow(0.5, float64(age)/float64(halfLife))
+}
+
+// RankTrending returns up to limit foods in the order of the scores, the scores of the foods that
+// are not in foods, e.g. because they are not visible to the user, are skipped.
+func RankTrending(scores []model.FoodScore, foods []response.FoodResponse, limit int) []response.ScoredFoodResponse {
+	byId := make(map[string]response.FoodResponse, len(foods))
+	for _, food := range foods {
+		byId[food.ID] = food
+	}
+
+	ranked := make([]response.ScoredFoodResponse, 0, len(scores))
+	for _, score := range scores {
+		food, ok := byId[score.FoodID]
+		if !ok || score.Score <= 0 {
+			continue
+		}
+
+		ranked = append(ranked, response.ScoredFoodResponse{FoodResponse: food, Score: round(score.Score)})
+		delete(byId, score.FoodID)
+	}
+
+	return top(ranked, limit)
+}
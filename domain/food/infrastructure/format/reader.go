@@ -0,0 +1,169 @@
+package format
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"food-api/domain/food/domain/model"
+	"io"
+	"strings"
+)
+
+// RowError is an error that only affects one row, the reader can continue with the next one.
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %s", e.Row, e.Err.Error())
+}
+
+// Reader reads foods one row at a time, it returns io.EOF when there are no more rows.
+type Reader interface {
+	Read() (int, model.Food, error)
+}
+
+// NewReader returns the reader for the given format.
+func NewReader(format string, r io.Reader) (Reader, error) {
+	switch format {
+	case model.ImportFormatCSV:
+		return newCSVReader(r)
+	case model.ImportFormatNDJSON:
+		return newNDJSONReader(r), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// FormatFromContentType returns the import format that matches the content type.
+func FormatFromContentType(contentType string) string {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+
+	switch mediaType {
+	case "text/csv", "application/csv":
+		return model.ImportFormatCSV
+	case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/x-jsonlines":
+		return model.ImportFormatNDJSON
+	}
+
+	return ""
+}
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+	row     int
+}
+
+// newCSVReader reads the header of the file, the columns title and description are required.
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, errors.New("the file is empty")
+		}
+
+		return nil, err
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, required := range []string{"title", "description"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("the column %s is required", required)
+		}
+	}
+
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+func (cr *csvReader) Read() (int, model.Food, error) {
+	record, err := cr.reader.Read()
+	if err != nil {
+		return cr.row, model.Food{}, err
+	}
+
+	cr.row++
+	food := model.Food{
+		Title:        cr.field(record, "title"),
+		Description:  cr.field(record, "description"),
+		FoodImage:    cr.field(record, "food_image"),
+		Ingredients:  splitList(cr.field(record, "ingredients")),
+		Instructions: splitList(cr.field(record, "instructions")),
+		Tags:         splitList(cr.field(record, "tags")),
+		Visibility:   cr.field(record, "visibility"),
+		Language:     cr.field(record, "language"),
+	}
+
+	return cr.row, food, nil
+}
+
+func (cr *csvReader) field(record []string, name string) string {
+	i, ok := cr.columns[name]
+	if !ok || i >= len(record) {
+		return ""
+	}
+
+	return strings.TrimSpace(record[i])
+}
+
+// ListSeparator separates the ingredients and instructions inside a CSV column.
+const ListSeparator = "|"
+
+// splitList returns the non empty items of a CSV list column.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ListSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	row     int
+}
+
+func newNDJSONReader(r io.Reader) *ndjsonReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	return &ndjsonReader{scanner: scanner}
+}
+
+func (nr *ndjsonReader) Read() (int, model.Food, error) {
+	for nr.scanner.Scan() {
+		line := bytes.TrimSpace(nr.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		nr.row++
+		var food model.Food
+		if err := json.Unmarshal(line, &food); err != nil {
+			return nr.row, model.Food{}, &RowError{Row: nr.row, Err: err}
+		}
+
+		return nr.row, food, nil
+	}
+
+	if err := nr.scanner.Err(); err != nil {
+		return nr.row, model.Food{}, err
+	}
+
+	return nr.row, model.Food{}, io.EOF
+}
@@ -0,0 +1,233 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"food-api/domain/food/domain/model"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	// jsonLDScript matches the JSON-LD blocks of an HTML document.
+	jsonLDScript = regexp.MustCompile(`(?is)<script[^>]*type\s*=\s*["']?application/ld\+json["']?[^>]*>(.*?)</script>`)
+
+	// htmlTag matches the tags that some sites leave inside the recipe texts.
+	htmlTag = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// ErrRecipeNotFound is returned when the document does not contain a schema.org Recipe.
+var ErrRecipeNotFound = errors.New("the document does not contain a schema.org Recipe")
+
+// ParseRecipe extracts the first schema.org Recipe of an HTML document or a JSON-LD payload
+// and maps it to a food. The content type decides how the body is read, when it is not
+// an HTML or JSON content type the body is sniffed.
+func ParseRecipe(contentType string, body []byte) (model.Food, error) {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	trimmed := bytes.TrimSpace(body)
+
+	isJSON := mediaType == "application/ld+json" || mediaType == "application/json"
+	if mediaType != "text/html" && !isJSON {
+		isJSON = bytes.HasPrefix(trimmed, []byte("{")) || bytes.HasPrefix(trimmed, []byte("["))
+	}
+
+	var blocks [][]byte
+	if isJSON {
+		blocks = append(blocks, trimmed)
+	} else {
+		for _, match := range jsonLDScript.FindAllSubmatch(body, -1) {
+			blocks = append(blocks, match[1])
+		}
+	}
+
+	var lastErr error
+	for _, block := range blocks {
+		var document interface{}
+		if err := json.Unmarshal(cleanJSONBlock(block), &document); err != nil {
+			lastErr = err
+			continue
+		}
+
+		if recipe := findRecipe(document); recipe != nil {
+			return recipeToFood(recipe), nil
+		}
+	}
+
+	if isJSON && lastErr != nil {
+		return model.Food{}, lastErr
+	}
+
+	return model.Food{}, ErrRecipeNotFound
+}
+
+// cleanJSONBlock removes the comment and CDATA markers that some sites wrap the JSON-LD with.
+func cleanJSONBlock(block []byte) []byte {
+	block = bytes.TrimSpace(block)
+	for _, marker := range []string{"<!--", "-->", "//<![CDATA[", "//]]>", "<![CDATA[", "]]>"} {
+		block = bytes.Replace(block, []byte(marker), nil, -1)
+	}
+
+	return block
+}
+
+// findRecipe looks for the Recipe node in the document, following the graph and the main entity.
+func findRecipe(node interface{}) map[string]interface{} {
+	switch value := node.(type) {
+	case []interface{}:
+		for _, item := range value {
+			if recipe := findRecipe(item); recipe != nil {
+				return recipe
+			}
+		}
+
+	case map[string]interface{}:
+		if isRecipeType(value["@type"]) {
+			return value
+		}
+
+		for _, key := range []string{"@graph", "mainEntity", "itemListElement", "item"} {
+			if recipe := findRecipe(value[key]); recipe != nil {
+				return recipe
+			}
+		}
+	}
+
+	return nil
+}
+
+func isRecipeType(value interface{}) bool {
+	switch types := value.(type) {
+	case string:
+		name := strings.TrimSpace(types)
+		for _, prefix := range []string{"schema:", "http://schema.org/", "https://schema.org/"} {
+			name = strings.TrimPrefix(name, prefix)
+		}
+
+		return name == "Recipe"
+
+	case []interface{}:
+		for _, item := range types {
+			if isRecipeType(item) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func recipeToFood(recipe map[string]interface{}) model.Food {
+	ingredients := recipe["recipeIngredient"]
+	if ingredients == nil {
+		ingredients = recipe["ingredients"]
+	}
+
+	return model.Food{
+		Title:        cleanText(asString(recipe["name"])),
+		Description:  cleanText(asString(recipe["description"])),
+		FoodImage:    recipeImage(recipe["image"]),
+		Ingredients:  textList(ingredients),
+		Instructions: recipeInstructions(recipe["recipeInstructions"]),
+		Tags:         recipeKeywords(recipe["keywords"]),
+	}
+}
+
+// recipeKeywords returns the keywords given as comma separated text or as a list.
+func recipeKeywords(value interface{}) []string {
+	if keywords, ok := value.(string); ok {
+		return textList(strings.Replace(keywords, ",", "\n", -1))
+	}
+
+	return textList(value)
+}
+
+// recipeImage returns the first url of an image given as text, ImageObject or a list of them.
+func recipeImage(value interface{}) string {
+	switch image := value.(type) {
+	case string:
+		return strings.TrimSpace(image)
+
+	case []interface{}:
+		for _, item := range image {
+			if url := recipeImage(item); url != "" {
+				return url
+			}
+		}
+
+	case map[string]interface{}:
+		for _, key := range []string{"url", "contentUrl", "@id"} {
+			if url := asString(image[key]); url != "" {
+				return strings.TrimSpace(url)
+			}
+		}
+	}
+
+	return ""
+}
+
+// recipeInstructions flattens the instructions given as text, HowToStep or HowToSection.
+func recipeInstructions(value interface{}) []string {
+	var steps []string
+
+	switch instructions := value.(type) {
+	case string:
+		steps = append(steps, textList(instructions)...)
+
+	case []interface{}:
+		for _, item := range instructions {
+			steps = append(steps, recipeInstructions(item)...)
+		}
+
+	case map[string]interface{}:
+		if list, ok := instructions["itemListElement"]; ok {
+			return recipeInstructions(list)
+		}
+
+		text := asString(instructions["text"])
+		if text == "" {
+			text = asString(instructions["name"])
+		}
+
+		if text = cleanText(text); text != "" {
+			steps = append(steps, text)
+		}
+	}
+
+	return steps
+}
+
+// textList returns the non empty lines of a text or a list of texts.
+func textList(value interface{}) []string {
+	var items []string
+
+	switch list := value.(type) {
+	case string:
+		for _, line := range strings.Split(list, "\n") {
+			if line = cleanText(line); line != "" {
+				items = append(items, line)
+			}
+		}
+
+	case []interface{}:
+		for _, item := range list {
+			if text := cleanText(asString(item)); text != "" {
+				items = append(items, text)
+			}
+		}
+	}
+
+	return items
+}
+
+func asString(value interface{}) string {
+	text, _ := value.(string)
+	return text
+}
+
+// cleanText removes the HTML tags and entities and collapses the spaces of the text.
+func cleanText(text string) string {
+	text = html.UnescapeString(htmlTag.ReplaceAllString(text, " "))
+	return strings.Join(strings.Fields(text), " ")
+}
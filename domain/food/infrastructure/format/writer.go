@@ -0,0 +1,163 @@
+package format
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"food-api/domain/food/application/v1/response"
+	"food-api/domain/food/domain/model"
+	"io"
+	"strings"
+)
+
+// flushEvery is the number of rows written before the buffered writers are flushed.
+const flushEvery = 100
+
+// Writer writes foods one at a time, Close must be called to complete the document.
+type Writer interface {
+	Write(food response.FoodResponse) error
+	Close() error
+}
+
+// NewWriter returns the writer for the given format.
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case model.ExportFormatCSV:
+		return &csvWriter{writer: csv.NewWriter(w)}, nil
+	case model.ExportFormatNDJSON:
+		return &ndjsonWriter{encoder: json.NewEncoder(w)}, nil
+	case model.ExportFormatJSONLD:
+		return &jsonldWriter{writer: w}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// ContentType returns the content type and the file extension of the format.
+func ContentType(format string) (string, string) {
+	switch format {
+	case model.ExportFormatCSV:
+		return "text/csv; charset=utf-8", "csv"
+	case model.ExportFormatJSONLD:
+		return "application/ld+json; charset=utf-8", "jsonld"
+	default:
+		return "application/x-ndjson; charset=utf-8", "ndjson"
+	}
+}
+
+// NewRecipe returns the schema.org Recipe of the food.
+func NewRecipe(food response.FoodResponse) model.Recipe {
+	recipe := model.Recipe{
+		Type:        "Recipe",
+		Identifier:  food.ID,
+		Name:        food.Title,
+		Description: food.Description,
+		Image:       food.FoodImage,
+		Ingredients: food.Ingredients,
+		Keywords:    strings.Join(food.Tags, ", "),
+	}
+
+	for _, instruction := range food.Instructions {
+		recipe.Instructions = append(recipe.Instructions, model.HowToStep{Type: "HowToStep", Text: instruction})
+	}
+
+	return recipe
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+	rows   int
+}
+
+var csvHeader = []string{"id", "title", "description", "food_image", "ingredients", "instructions", "tags", "visibility", "language"}
+
+func (cw *csvWriter) Write(food response.FoodResponse) error {
+	if cw.rows == 0 {
+		if err := cw.writer.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+
+	cw.rows++
+	record := []string{
+		food.ID,
+		food.Title,
+		food.Description,
+		food.FoodImage,
+		strings.Join(food.Ingredients, ListSeparator),
+		strings.Join(food.Instructions, ListSeparator),
+		strings.Join(food.Tags, ListSeparator),
+		food.Visibility,
+		food.Language,
+	}
+
+	if err := cw.writer.Write(record); err != nil {
+		return err
+	}
+
+	if cw.rows%flushEvery == 0 {
+		cw.writer.Flush()
+		return cw.writer.Error()
+	}
+
+	return nil
+}
+
+func (cw *csvWriter) Close() error {
+	if cw.rows == 0 {
+		if err := cw.writer.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (nw *ndjsonWriter) Write(food response.FoodResponse) error {
+	return nw.encoder.Encode(food)
+}
+
+func (nw *ndjsonWriter) Close() error {
+	return nil
+}
+
+// jsonldWriter writes the recipes inside the @graph of a single JSON-LD document.
+type jsonldWriter struct {
+	writer io.Writer
+	rows   int
+}
+
+func (jw *jsonldWriter) Write(food response.FoodResponse) error {
+	separator := ","
+	if jw.rows == 0 {
+		separator = fmt.Sprintf(`{"@context":%q,"@graph":[`, model.SchemaContext)
+	}
+
+	recipe, err := json.Marshal(NewRecipe(food))
+	if err != nil {
+		return err
+	}
+
+	jw.rows++
+	if _, err = io.WriteString(jw.writer, separator); err != nil {
+		return err
+	}
+
+	_, err = jw.writer.Write(recipe)
+	return err
+}
+
+func (jw *jsonldWriter) Close() error {
+	if jw.rows == 0 {
+		_, err := fmt.Fprintf(jw.writer, `{"@context":%q,"@graph":[]}`, model.SchemaContext)
+		return err
+	}
+
+	_, err := io.WriteString(jw.writer, "]}")
+	return err
+}
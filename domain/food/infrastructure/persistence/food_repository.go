@@ -2,11 +2,14 @@ package persistence
 
 import (
 	"context"
+	"database/sql"
 	"food-api/domain/food/application/v1/response"
 	"food-api/domain/food/domain/model"
 	repoDomain "food-api/domain/food/domain/repository"
+	"food-api/domain/food/domain/service"
 	"food-api/infrastructure/database"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"strings"
 )
 
@@ -20,12 +23,12 @@ func NewFoodRepository(Conn *database.Data) repoDomain.FoodRepository {
 	}
 }
 
-// GetFoodById
-func (sr *sqlFoodRepo) GetFoodById(ctx context.Context, id string) (*response.FoodResponse, error) {
-	row := sr.Conn.DB.QueryRowContext(ctx, selectFoodById, id)
+// GetFoodById returns the food when it is visible to the viewer, an empty viewer is anonymous.
+func (sr *sqlFoodRepo) GetFoodById(ctx context.Context, id, viewerId string) (*response.FoodResponse, error) {
+	row := sr.Conn.DB.QueryRowContext(ctx, selectFoodById, id, viewer(viewerId))
 
 	var foodResponse response.FoodResponse
-	err := row.Scan(&foodResponse.ID, &foodResponse.UserID, &foodResponse.Title, &foodResponse.Description, &foodResponse.FoodImage)
+	err := scanFood(row, &foodResponse)
 	if err != nil {
 		return &response.FoodResponse{}, err
 	}
@@ -33,12 +36,12 @@ func (sr *sqlFoodRepo) GetFoodById(ctx context.Context, id string) (*response.Fo
 	return &foodResponse, nil
 }
 
-// GetFoodByUserId
-func (sr *sqlFoodRepo) GetFoodByUserId(ctx context.Context, userId string) (*response.FoodResponse, error) {
-	row := sr.Conn.DB.QueryRowContext(ctx, selectFoodByUserId, userId)
+// GetFoodBySlug returns the food when it is visible to the viewer, an empty viewer is anonymous.
+func (sr *sqlFoodRepo) GetFoodBySlug(ctx context.Context, slug, viewerId string) (*response.FoodResponse, error) {
+	row := sr.Conn.DB.QueryRowContext(ctx, selectFoodBySlug, slug, viewer(viewerId))
 
 	var foodResponse response.FoodResponse
-	err := row.Scan(&foodResponse.ID, &foodResponse.UserID, &foodResponse.Title, &foodResponse.Description, &foodResponse.FoodImage)
+	err := scanFood(row, &foodResponse)
 	if err != nil {
 		return &response.FoodResponse{}, err
 	}
@@ -46,9 +49,36 @@ func (sr *sqlFoodRepo) GetFoodByUserId(ctx context.Context, userId string) (*res
 	return &foodResponse, nil
 }
 
-// GetAllFood
-func (sr *sqlFoodRepo) GetAllFood(ctx context.Context) ([]response.FoodResponse, error) {
-	rows, err := sr.Conn.DB.QueryContext(ctx, selectAllFood)
+// GetSlugByHistory returns the current slug of the food that used the given old slug when the food is
+// visible to the viewer, an empty viewer is anonymous.
+func (sr *sqlFoodRepo) GetSlugByHistory(ctx context.Context, slug, viewerId string) (string, error) {
+	row := sr.Conn.DB.QueryRowContext(ctx, selectSlugByHistory, slug, viewer(viewerId))
+
+	var currentSlug string
+	err := row.Scan(&currentSlug)
+	if err != nil {
+		return "", err
+	}
+
+	return currentSlug, nil
+}
+
+// GetFoodByUserId returns a food of the user that is visible to the viewer, an empty viewer is anonymous.
+func (sr *sqlFoodRepo) GetFoodByUserId(ctx context.Context, userId, viewerId string) (*response.FoodResponse, error) {
+	row := sr.Conn.DB.QueryRowContext(ctx, selectFoodByUserId, userId, viewer(viewerId))
+
+	var foodResponse response.FoodResponse
+	err := scanFood(row, &foodResponse)
+	if err != nil {
+		return &response.FoodResponse{}, err
+	}
+
+	return &foodResponse, nil
+}
+
+// GetAllFood returns the foods visible to the viewer, an empty viewer is anonymous.
+func (sr *sqlFoodRepo) GetAllFood(ctx context.Context, viewerId string) ([]response.FoodResponse, error) {
+	rows, err := sr.Conn.DB.QueryContext(ctx, selectAllFood, viewer(viewerId))
 	if err != nil {
 		return nil, err
 	}
@@ -58,29 +88,111 @@ func (sr *sqlFoodRepo) GetAllFood(ctx context.Context) ([]response.FoodResponse,
 	var foodResponses []response.FoodResponse
 	for rows.Next() {
 		var foodRow response.FoodResponse
-		_ = rows.Scan(&foodRow.ID, &foodRow.UserID, &foodRow.Title, &foodRow.Description, &foodRow.FoodImage)
+		_ = scanFood(rows, &foodRow)
 		foodResponses = append(foodResponses, foodRow)
 	}
 
 	return foodResponses, nil
 }
 
+// GetFoodsByIds returns the foods with the given ids that are visible to the viewer, an empty viewer is anonymous.
+func (sr *sqlFoodRepo) GetFoodsByIds(ctx context.Context, ids []string, viewerId string) ([]response.FoodResponse, error) {
+	rows, err := sr.Conn.DB.QueryContext(ctx, selectFoodsByIds, pq.Array(ids), viewer(viewerId))
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var foodResponses []response.FoodResponse
+	for rows.Next() {
+		var foodRow response.FoodResponse
+		err = scanFood(rows, &foodRow)
+		if err != nil {
+			return nil, err
+		}
+
+		foodResponses = append(foodResponses, foodRow)
+	}
+
+	return foodResponses, rows.Err()
+}
+
+// GetAllFoodByUserId
+func (sr *sqlFoodRepo) GetAllFoodByUserId(ctx context.Context, userId string) ([]response.FoodResponse, error) {
+	rows, err := sr.Conn.DB.QueryContext(ctx, selectAllFoodByUserId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var foodResponses []response.FoodResponse
+	for rows.Next() {
+		var foodRow response.FoodResponse
+		err = scanFood(rows, &foodRow)
+		if err != nil {
+			return nil, err
+		}
+
+		foodResponses = append(foodResponses, foodRow)
+	}
+
+	return foodResponses, rows.Err()
+}
+
+// StreamFoodByUserId calls fn with each food of the user as the rows are read, so the foods
+// are never loaded in memory at the same time. It stops at the first error returned by fn.
+func (sr *sqlFoodRepo) StreamFoodByUserId(ctx context.Context, userId string, fn func(food response.FoodResponse) error) error {
+	rows, err := sr.Conn.DB.QueryContext(ctx, selectAllFoodByUserId, userId)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var foodRow response.FoodResponse
+		err = scanFood(rows, &foodRow)
+		if err != nil {
+			return err
+		}
+
+		if err = fn(foodRow); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 // SaveFood
 func (sr *sqlFoodRepo) SaveFood(ctx context.Context, food *model.Food) (*response.FoodResponse, error) {
+	if strings.TrimSpace(food.ID) == "" {
+		food.ID = uuid.New().String()
+	}
+
+	if food.Visibility == "" {
+		food.Visibility = model.VisibilityPrivate
+	}
+
+	slug, err := freeSlug(ctx, sr.Conn.DB, food.Title, food.ID)
+	if err != nil {
+		return &response.FoodResponse{}, err
+	}
+
+	food.Slug = slug
 	stmt, err := sr.Conn.DB.PrepareContext(ctx, insertFood)
 	if err != nil {
 		return &response.FoodResponse{}, err
 	}
 
 	defer stmt.Close()
-	if strings.TrimSpace(food.ID) == "" {
-		food.ID = uuid.New().String()
-	}
 
-	row := stmt.QueryRowContext(ctx, &food.ID, &food.UserID, &food.Title, &food.Description, &food.FoodImage, &food.CreatedAt, &food.UpdatedAt)
+	row := stmt.QueryRowContext(ctx, &food.ID, &food.UserID, &food.Title, &food.Description, &food.FoodImage, textArray(food.Ingredients), textArray(food.Instructions), textArray(food.Tags), &food.Slug, &food.Visibility, &food.Language, &food.CreatedAt, &food.UpdatedAt)
 
 	foodResult := response.FoodResponse{}
-	err = row.Scan(&foodResult.ID, &foodResult.UserID, &foodResult.Title, &foodResult.Description, &foodResult.FoodImage)
+	err = scanFood(row, &foodResult)
 	if err != nil {
 		return &response.FoodResponse{}, err
 	}
@@ -88,21 +200,114 @@ func (sr *sqlFoodRepo) SaveFood(ctx context.Context, food *model.Food) (*respons
 	return &foodResult, nil
 }
 
-// UpdateFood
+// SaveFoodBatch inserts the foods in a single transaction and returns one error per food.
+// In partial mode a failed row is rolled back to its savepoint and the others are committed,
+// otherwise the first failure rolls back the whole transaction.
+func (sr *sqlFoodRepo) SaveFoodBatch(ctx context.Context, foods []model.Food, partial bool) ([]error, error) {
+	rowErrors := make([]error, len(foods))
+
+	tx, err := sr.Conn.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return rowErrors, err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, insertFood)
+	if err != nil {
+		_ = tx.Rollback()
+		return rowErrors, err
+	}
+
+	defer stmt.Close()
+
+	for i := range foods {
+		if strings.TrimSpace(foods[i].ID) == "" {
+			foods[i].ID = uuid.New().String()
+		}
+
+		if foods[i].Visibility == "" {
+			foods[i].Visibility = model.VisibilityPrivate
+		}
+
+		if partial {
+			if _, err = tx.ExecContext(ctx, savepointFoodImport); err != nil {
+				_ = tx.Rollback()
+				return rowErrors, err
+			}
+		}
+
+		foods[i].Slug, err = freeSlug(ctx, tx, foods[i].Title, foods[i].ID)
+		if err == nil {
+			_, err = stmt.ExecContext(ctx, foods[i].ID, foods[i].UserID, foods[i].Title, foods[i].Description, foods[i].FoodImage, textArray(foods[i].Ingredients), textArray(foods[i].Instructions), textArray(foods[i].Tags), foods[i].Slug, foods[i].Visibility, foods[i].Language, foods[i].CreatedAt, foods[i].UpdatedAt)
+		}
+
+		if err == nil {
+			continue
+		}
+
+		rowErrors[i] = err
+		if !partial {
+			_ = tx.Rollback()
+			return rowErrors, err
+		}
+
+		if _, err = tx.ExecContext(ctx, rollbackFoodImport); err != nil {
+			_ = tx.Rollback()
+			return rowErrors, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return rowErrors, err
+	}
+
+	return rowErrors, nil
+}
+
+// UpdateFood keeps the slug while the title still generates it, otherwise the food gets
+// a new slug and the old one is kept in the history so it can be redirected. The food must
+// belong to the UserID of the food, sql.ErrNoRows is returned otherwise.
 func (sr *sqlFoodRepo) UpdateFood(ctx context.Context, id string, food *model.Food) error {
-	stmt, err := sr.Conn.DB.PrepareContext(ctx, updateFood)
+	tx, err := sr.Conn.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
-	defer stmt.Close()
+	var currentSlug string
+	err = tx.QueryRowContext(ctx, selectFoodSlugForUpdate, id).Scan(&currentSlug)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	slug := currentSlug
+	if !service.SlugMatchesTitle(currentSlug, food.Title) {
+		slug, err = freeSlug(ctx, tx, food.Title, id)
+		if err == nil {
+			_, err = tx.ExecContext(ctx, insertFoodSlugHistory, currentSlug, id, food.UpdatedAt)
+		}
+
+		if err == nil {
+			_, err = tx.ExecContext(ctx, deleteFoodSlugHistory, slug)
+		}
+
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
 
-	_, err = stmt.ExecContext(ctx, food.Title, food.Description, food.FoodImage, food.UpdatedAt, id)
+	err = tx.QueryRowContext(ctx, updateFood, food.Title, food.Description, food.FoodImage, textArray(food.Ingredients), textArray(food.Instructions), textArray(food.Tags), slug, food.Visibility, food.Language, food.UpdatedAt, id, food.UserID).Scan(&food.Visibility, &food.Language)
 	if err != nil {
+		_ = tx.Rollback()
 		return err
 	}
 
-	return err
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	food.Slug = slug
+	return nil
 }
 
 // DeleteFood
@@ -121,3 +326,64 @@ func (sr *sqlFoodRepo) DeleteFood(ctx context.Context, id string) error {
 
 	return nil
 }
+
+// queryer is implemented by *sql.DB and *sql.Tx.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// freeSlug returns a slug for the title that is not used, now or in the past, by another food.
+func freeSlug(ctx context.Context, q queryer, title, foodId string) (string, error) {
+	base := service.Slugify(title)
+
+	rows, err := q.QueryContext(ctx, selectFoodSlugs, base, base+"-%", foodId)
+	if err != nil {
+		return "", err
+	}
+
+	defer rows.Close()
+
+	taken := make(map[string]bool)
+	for rows.Next() {
+		var slug string
+		if err = rows.Scan(&slug); err != nil {
+			return "", err
+		}
+
+		taken[slug] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return "", err
+	}
+
+	return service.UniqueSlug(base, taken), nil
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanFood reads the columns selected by the food queries.
+func scanFood(row rowScanner, food *response.FoodResponse) error {
+	return row.Scan(&food.ID, &food.UserID, &food.Title, &food.Description, &food.FoodImage, pq.Array(&food.Ingredients), pq.Array(&food.Instructions), pq.Array(&food.Tags), &food.Slug, &food.Visibility, &food.Language)
+}
+
+// viewer returns the value of the viewer parameter of the read queries, NULL for an anonymous viewer.
+func viewer(viewerId string) interface{} {
+	if viewerId == "" {
+		return nil
+	}
+
+	return viewerId
+}
+
+// textArray returns the value of a text[] column, an empty array is stored instead of NULL.
+func textArray(values []string) pq.StringArray {
+	if values == nil {
+		return pq.StringArray{}
+	}
+
+	return values
+}
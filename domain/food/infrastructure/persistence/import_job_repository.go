@@ -0,0 +1,71 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"food-api/domain/food/application/v1/response"
+	repoDomain "food-api/domain/food/domain/repository"
+	"sync"
+)
+
+// memoryImportJobRepo keeps the import jobs in memory, they are lost when the API restarts.
+type memoryImportJobRepo struct {
+	mutex sync.RWMutex
+	jobs  map[string]response.ImportJobResponse
+}
+
+func NewImportJobRepository() repoDomain.ImportJobRepository {
+	return &memoryImportJobRepo{
+		jobs: make(map[string]response.ImportJobResponse),
+	}
+}
+
+// SaveImportJob creates or replaces the job with the same id.
+func (mr *memoryImportJobRepo) SaveImportJob(ctx context.Context, job *response.ImportJobResponse) error {
+	if job == nil || job.ID == "" {
+		return errors.New("cannot save a job without id")
+	}
+
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	mr.jobs[job.ID] = copyImportJob(job)
+
+	return nil
+}
+
+// GetImportJobById
+func (mr *memoryImportJobRepo) GetImportJobById(ctx context.Context, id string) (*response.ImportJobResponse, error) {
+	mr.mutex.RLock()
+	defer mr.mutex.RUnlock()
+
+	job, ok := mr.jobs[id]
+	if !ok {
+		return &response.ImportJobResponse{}, errors.New("import job not found")
+	}
+
+	result := copyImportJob(&job)
+	return &result, nil
+}
+
+// DeleteImportJob removes the job, a missing job is not an error.
+func (mr *memoryImportJobRepo) DeleteImportJob(ctx context.Context, id string) error {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	delete(mr.jobs, id)
+
+	return nil
+}
+
+// copyImportJob avoids sharing the report between the goroutine running the job and the readers.
+func copyImportJob(job *response.ImportJobResponse) response.ImportJobResponse {
+	result := *job
+	if job.Report != nil {
+		report := *job.Report
+		report.Rows = append([]response.ImportRowResponse(nil), job.Report.Rows...)
+		result.Report = &report
+	}
+
+	return result
+}
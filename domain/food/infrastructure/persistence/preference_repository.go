@@ -0,0 +1,93 @@
+package persistence
+
+import (
+	"context"
+	"food-api/domain/food/domain/model"
+	repoDomain "food-api/domain/food/domain/repository"
+	"food-api/domain/food/domain/service"
+	"food-api/infrastructure/database"
+	"time"
+)
+
+type sqlPreferenceRepo struct {
+	Conn *database.Data
+}
+
+func NewPreferenceRepository(Conn *database.Data) repoDomain.PreferenceRepository {
+	return &sqlPreferenceRepo{
+		Conn: Conn,
+	}
+}
+
+// SaveFavorite marks or unmarks the food as a favorite of the user, the rating is kept.
+func (sr *sqlPreferenceRepo) SaveFavorite(ctx context.Context, preference *model.FoodPreference) error {
+	stmt, err := sr.Conn.DB.PrepareContext(ctx, insertFoodFavorite)
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, preference.UserID, preference.FoodID, preference.Favorite, preference.CreatedAt, preference.UpdatedAt)
+	return err
+}
+
+// SaveRating adds or replaces the rating of the food by the user, the favorite is kept.
+func (sr *sqlPreferenceRepo) SaveRating(ctx context.Context, preference *model.FoodPreference) error {
+	stmt, err := sr.Conn.DB.PrepareContext(ctx, insertFoodRating)
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, preference.UserID, preference.FoodID, preference.Rating, preference.CreatedAt, preference.UpdatedAt)
+	return err
+}
+
+// GetPreferencesByUserId returns the favorites and the ratings of the user.
+func (sr *sqlPreferenceRepo) GetPreferencesByUserId(ctx context.Context, userId string) ([]model.FoodPreference, error) {
+	rows, err := sr.Conn.DB.QueryContext(ctx, selectFoodPreferences, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var preferences []model.FoodPreference
+	for rows.Next() {
+		preference := model.FoodPreference{UserID: userId}
+		err = rows.Scan(&preference.FoodID, &preference.Favorite, &preference.Rating)
+		if err != nil {
+			return nil, err
+		}
+
+		preferences = append(preferences, preference)
+	}
+
+	return preferences, rows.Err()
+}
+
+// GetPopularFood returns the foods with more favorites and ratings since the given time, the favorites
+// and the ratings are weighted like in the trending foods.
+func (sr *sqlPreferenceRepo) GetPopularFood(ctx context.Context, since time.Time, limit int) ([]model.FoodScore, error) {
+	rows, err := sr.Conn.DB.QueryContext(ctx, selectPopularFood, since, service.FavoriteWeight, service.RatingWeight, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var scores []model.FoodScore
+	for rows.Next() {
+		var score model.FoodScore
+		err = rows.Scan(&score.FoodID, &score.Score)
+		if err != nil {
+			return nil, err
+		}
+
+		scores = append(scores, score)
+	}
+
+	return scores, rows.Err()
+}
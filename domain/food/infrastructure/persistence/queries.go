@@ -2,23 +2,90 @@ package persistence
 
 const(
 
-	// selectAllFood is a query that selects all rows in the food table
-	selectAllFood = "SELECT id, user_id, title, description, food_image FROM food WHERE deleted_at IS NULL ORDER BY created_at DESC;"
+	// selectAllFood is a query that selects all rows in the food table visible to the viewer given as $1.
+	// A food is visible to its owner, to everyone when it is public and to the followers of the owner
+	// when it is followers-only. An anonymous viewer is NULL and only sees the public foods.
+	selectAllFood = "SELECT id, user_id, title, description, food_image, ingredients, instructions, tags, slug, visibility, language FROM food WHERE deleted_at IS NULL AND (visibility = 'public' OR user_id = $1 OR (visibility = 'followers' AND EXISTS (SELECT 1 FROM user_follower uf WHERE uf.user_id = food.user_id AND uf.follower_id = $1))) ORDER BY created_at DESC;"
 
-	// selectFoodById is a query that selects a row from the food table based off of the given id.
-	selectFoodById = "SELECT id, user_id, title, description, food_image FROM food WHERE id = $1;"
+	// selectFoodById is a query that selects a row from the food table based off of the given id,
+	// when it is visible to the viewer given as $2 and not deleted.
+	selectFoodById = "SELECT id, user_id, title, description, food_image, ingredients, instructions, tags, slug, visibility, language FROM food WHERE id = $1 AND deleted_at IS NULL AND (visibility = 'public' OR user_id = $2 OR (visibility = 'followers' AND EXISTS (SELECT 1 FROM user_follower uf WHERE uf.user_id = food.user_id AND uf.follower_id = $2)));"
 
-	// selectFoodByUserId is a query that selects a row from the food table based off of the given user userId.
-	selectFoodByUserId = "SELECT id, user_id, title, description, food_image FROM food WHERE user_id = $1;"
+	// selectFoodByUserId is a query that selects a row from the food table based off of the given user userId,
+	// when it is visible to the viewer given as $2 and not deleted.
+	selectFoodByUserId = "SELECT id, user_id, title, description, food_image, ingredients, instructions, tags, slug, visibility, language FROM food WHERE user_id = $1 AND deleted_at IS NULL AND (visibility = 'public' OR user_id = $2 OR (visibility = 'followers' AND EXISTS (SELECT 1 FROM user_follower uf WHERE uf.user_id = food.user_id AND uf.follower_id = $2)));"
+
+	// selectFoodsByIds is a query that selects the rows from the food table with the ids given as an array,
+	// when they are visible to the viewer given as $2.
+	selectFoodsByIds = "SELECT id, user_id, title, description, food_image, ingredients, instructions, tags, slug, visibility, language FROM food WHERE id = ANY($1) AND deleted_at IS NULL AND (visibility = 'public' OR user_id = $2 OR (visibility = 'followers' AND EXISTS (SELECT 1 FROM user_follower uf WHERE uf.user_id = food.user_id AND uf.follower_id = $2)));"
+
+	// selectAllFoodByUserId is a query that selects all rows from the food table that belong to the given user userId.
+	selectAllFoodByUserId = "SELECT id, user_id, title, description, food_image, ingredients, instructions, tags, slug, visibility, language FROM food WHERE user_id = $1 AND deleted_at IS NULL ORDER BY created_at DESC;"
+
+	// selectFoodBySlug is a query that selects a row from the food table based off of the given slug,
+	// when it is visible to the viewer given as $2 and not deleted.
+	selectFoodBySlug = "SELECT id, user_id, title, description, food_image, ingredients, instructions, tags, slug, visibility, language FROM food WHERE slug = $1 AND deleted_at IS NULL AND (visibility = 'public' OR user_id = $2 OR (visibility = 'followers' AND EXISTS (SELECT 1 FROM user_follower uf WHERE uf.user_id = food.user_id AND uf.follower_id = $2)));"
+
+	// selectSlugByHistory is a query that selects the current slug of the food that used the given old slug,
+	// when it is visible to the viewer given as $2.
+	selectSlugByHistory = "SELECT f.slug FROM food_slug_history h INNER JOIN food f ON f.id = h.food_id WHERE h.slug = $1 AND f.deleted_at IS NULL AND (f.visibility = 'public' OR f.user_id = $2 OR (f.visibility = 'followers' AND EXISTS (SELECT 1 FROM user_follower uf WHERE uf.user_id = f.user_id AND uf.follower_id = $2)));"
+
+	// selectFoodSlugs is a query that selects the slugs, current or old, that start like the given base
+	// and do not belong to the given food id. It is used to find a free slug.
+	selectFoodSlugs = "SELECT slug FROM food WHERE (slug = $1 OR slug LIKE $2) AND id <> $3 UNION SELECT slug FROM food_slug_history WHERE (slug = $1 OR slug LIKE $2) AND food_id <> $3;"
+
+	// selectFoodSlugForUpdate is a query that selects and locks the slug of the food with the given id.
+	selectFoodSlugForUpdate = "SELECT slug FROM food WHERE id = $1 FOR UPDATE;"
 
 	// insertFood is a query that inserts a new row in the user table using the values
-	// given in order for id, user_id, title, description, food_image, created_at, updated_at.
-	insertFood = "INSERT INTO food (id, user_id, title, description, food_image, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, user_id, title, description, food_image;"
+	// given in order for id, user_id, title, description, food_image, ingredients, instructions, tags, slug, visibility, language, created_at, updated_at.
+	insertFood = "INSERT INTO food (id, user_id, title, description, food_image, ingredients, instructions, tags, slug, visibility, language, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id, user_id, title, description, food_image, ingredients, instructions, tags, slug, visibility, language;"
 
 	// updateFood is a query that updates a row in the user table based off of id.
 	// The values able to be updated are names, last_names, email and updated_at.
-	updateFood = "UPDATE food SET title=$1, description=$2, food_image=$3, updated_at=$4 WHERE id=$5;"
+	// The visibility and the language are kept when they are empty and the stored ones are returned.
+	// Only the food of the owner given in user_id is updated.
+	updateFood = "UPDATE food SET title=$1, description=$2, food_image=$3, ingredients=$4, instructions=$5, tags=$6, slug=$7, visibility=COALESCE(NULLIF($8, ''), visibility), language=COALESCE(NULLIF($9, ''), language), updated_at=$10 WHERE id=$11 AND user_id=$12 RETURNING visibility, language;"
 
 	// deleteFood is a query that deletes a row in the food table given a id.
 	deleteFood = "DELETE FROM food WHERE id=$1;"
+
+	// insertFoodSlugHistory is a query that keeps an old slug of a food so it can be redirected.
+	insertFoodSlugHistory = "INSERT INTO food_slug_history (slug, food_id, created_at) VALUES ($1, $2, $3) ON CONFLICT (slug) DO NOTHING;"
+
+	// deleteFoodSlugHistory is a query that removes an old slug when the food uses it again.
+	deleteFoodSlugHistory = "DELETE FROM food_slug_history WHERE slug = $1;"
+
+	// insertFoodTranslation is a query that adds the translation of a food to a language, or replaces it when
+	// the food already has one, using the values given in order for food_id, language, title, description,
+	// instructions, created_at, updated_at.
+	insertFoodTranslation = "INSERT INTO food_translation (food_id, language, title, description, instructions, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (food_id, language) DO UPDATE SET title = EXCLUDED.title, description = EXCLUDED.description, instructions = EXCLUDED.instructions, updated_at = EXCLUDED.updated_at RETURNING food_id, language, title, description, instructions;"
+
+	// selectFoodTranslations is a query that selects the translations of the foods given as an array.
+	selectFoodTranslations = "SELECT food_id, language, title, description, instructions FROM food_translation WHERE food_id = ANY($1) ORDER BY food_id, language;"
+
+	// deleteFoodTranslation is a query that deletes the translation of a food to a language.
+	deleteFoodTranslation = "DELETE FROM food_translation WHERE food_id = $1 AND language = $2;"
+
+	// insertFoodFavorite is a query that marks or unmarks a food as a favorite of the user using the values
+	// given in order for user_id, food_id, favorite, created_at, updated_at.
+	insertFoodFavorite = "INSERT INTO food_preference (user_id, food_id, favorite, created_at, updated_at) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (user_id, food_id) DO UPDATE SET favorite = EXCLUDED.favorite, updated_at = EXCLUDED.updated_at;"
+
+	// insertFoodRating is a query that adds or replaces the rating of a food by the user using the values
+	// given in order for user_id, food_id, rating, created_at, updated_at.
+	insertFoodRating = "INSERT INTO food_preference (user_id, food_id, rating, created_at, updated_at) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (user_id, food_id) DO UPDATE SET rating = EXCLUDED.rating, updated_at = EXCLUDED.updated_at;"
+
+	// selectFoodPreferences is a query that selects the favorites and the ratings of the user, a food without
+	// rating has a rating of 0.
+	selectFoodPreferences = "SELECT food_id, favorite, COALESCE(rating, 0) FROM food_preference WHERE user_id = $1 ORDER BY food_id;"
+
+	// selectPopularFood is a query that selects the foods with more favorites and ratings since the given
+	// time, a favorite counts $2 and a rating $3. It is used when the trending counters are unavailable.
+	selectPopularFood = "SELECT food_id, SUM(CASE WHEN favorite THEN $2::float8 ELSE 0 END + CASE WHEN rating IS NOT NULL THEN $3::float8 ELSE 0 END) AS score FROM food_preference WHERE updated_at >= $1 GROUP BY food_id ORDER BY score DESC, food_id LIMIT $4;"
+
+	// savepointFoodImport is a savepoint used to discard a single row of an import in partial mode.
+	savepointFoodImport = "SAVEPOINT food_import;"
+
+	// rollbackFoodImport discards the changes made after savepointFoodImport.
+	rollbackFoodImport = "ROLLBACK TO SAVEPOINT food_import;"
 )
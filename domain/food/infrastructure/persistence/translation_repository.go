@@ -0,0 +1,88 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"food-api/domain/food/application/v1/response"
+	"food-api/domain/food/domain/model"
+	repoDomain "food-api/domain/food/domain/repository"
+	"food-api/infrastructure/database"
+	"github.com/lib/pq"
+)
+
+type sqlTranslationRepo struct {
+	Conn *database.Data
+}
+
+func NewTranslationRepository(Conn *database.Data) repoDomain.TranslationRepository {
+	return &sqlTranslationRepo{
+		Conn: Conn,
+	}
+}
+
+// SaveTranslation adds the translation of the food to the language, or replaces the one it already has.
+func (sr *sqlTranslationRepo) SaveTranslation(ctx context.Context, translation *model.FoodTranslation) (*response.TranslationResponse, error) {
+	stmt, err := sr.Conn.DB.PrepareContext(ctx, insertFoodTranslation)
+	if err != nil {
+		return &response.TranslationResponse{}, err
+	}
+
+	defer stmt.Close()
+
+	row := stmt.QueryRowContext(ctx, translation.FoodID, translation.Language, translation.Title, translation.Description, textArray(translation.Instructions), translation.CreatedAt, translation.UpdatedAt)
+
+	var translationResult response.TranslationResponse
+	err = scanTranslation(row, &translationResult)
+	if err != nil {
+		return &response.TranslationResponse{}, err
+	}
+
+	return &translationResult, nil
+}
+
+// GetTranslationsByFoodIds returns the translations of all the given foods with a single query.
+func (sr *sqlTranslationRepo) GetTranslationsByFoodIds(ctx context.Context, foodIds []string) ([]response.TranslationResponse, error) {
+	rows, err := sr.Conn.DB.QueryContext(ctx, selectFoodTranslations, pq.Array(foodIds))
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var translations []response.TranslationResponse
+	for rows.Next() {
+		var translationRow response.TranslationResponse
+		err = scanTranslation(rows, &translationRow)
+		if err != nil {
+			return nil, err
+		}
+
+		translations = append(translations, translationRow)
+	}
+
+	return translations, rows.Err()
+}
+
+// DeleteTranslation returns sql.ErrNoRows when the food has no translation to the language.
+func (sr *sqlTranslationRepo) DeleteTranslation(ctx context.Context, foodId, language string) error {
+	result, err := sr.Conn.DB.ExecContext(ctx, deleteFoodTranslation, foodId, language)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// scanTranslation reads the columns selected by the translation queries.
+func scanTranslation(row rowScanner, translation *response.TranslationResponse) error {
+	return row.Scan(&translation.FoodID, &translation.Language, &translation.Title, &translation.Description, pq.Array(&translation.Instructions))
+}
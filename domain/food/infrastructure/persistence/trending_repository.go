@@ -0,0 +1,147 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"food-api/domain/food/domain/model"
+	repoDomain "food-api/domain/food/domain/repository"
+	"food-api/domain/food/domain/service"
+	"github.com/go-redis/redis/v8"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	trendingKey = "food:trending"
+	// trendingTimeout bounds every call to Redis so a slow Redis does not slow down the requests.
+	trendingTimeout = 300 * time.Millisecond
+	// trendingCacheTTL is how long the decayed ranking of a window is reused before computing it again.
+	trendingCacheTTL = time.Minute
+	// trendingRetryAfter is how long Redis is not called after a failure.
+	trendingRetryAfter = 30 * time.Second
+)
+
+// ErrTrendingUnavailable is returned while Redis is not called because of a recent failure.
+var ErrTrendingUnavailable = errors.New("trending counters are unavailable")
+
+// trendingWindow splits the window in buckets, the events of a bucket lose half of their weight every halfLife.
+type trendingWindow struct {
+	bucket   time.Duration
+	buckets  int
+	halfLife time.Duration
+}
+
+var trendingWindows = map[string]trendingWindow{
+	model.TrendingWindowDay:  {bucket: time.Hour, buckets: 24, halfLife: 6 * time.Hour},
+	model.TrendingWindowWeek: {bucket: 24 * time.Hour, buckets: 7, halfLife: 48 * time.Hour},
+}
+
+// redisTrendingRepo counts the events of the foods in a sorted set by window and time bucket, the buckets
+// expire by themselves once they leave the window.
+type redisTrendingRepo struct {
+	Client *redis.Client
+	// unavailableUntil is the unix nano time until which Redis is not called.
+	unavailableUntil int64
+}
+
+func NewTrendingRepository(client *redis.Client) repoDomain.TrendingRepository {
+	return &redisTrendingRepo{
+		Client: client,
+	}
+}
+
+// AddEvent adds the weight of an event of the food to the bucket of every window.
+func (rr *redisTrendingRepo) AddEvent(ctx context.Context, foodId string, weight float64, at time.Time) error {
+	if !rr.available() {
+		return ErrTrendingUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, trendingTimeout)
+	defer cancel()
+
+	pipe := rr.Client.TxPipeline()
+	for name, window := range trendingWindows {
+		key := bucketKey(name, at.Truncate(window.bucket))
+		pipe.ZIncrBy(ctx, key, weight, foodId)
+		pipe.Expire(ctx, key, window.bucket*time.Duration(window.buckets+1))
+	}
+
+	_, err := pipe.Exec(ctx)
+	return rr.check(err)
+}
+
+// GetTrending returns up to limit foods with the highest decayed score of the window, the ranking is
+// cached for a short time.
+func (rr *redisTrendingRepo) GetTrending(ctx context.Context, window string, now time.Time, limit int) ([]model.FoodScore, error) {
+	trending, ok := trendingWindows[window]
+	if !ok {
+		return nil, fmt.Errorf("unknown trending window %q", window)
+	}
+
+	if !rr.available() {
+		return nil, ErrTrendingUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, trendingTimeout)
+	defer cancel()
+
+	key := trendingKey + ":" + window
+	cached, err := rr.Client.Exists(ctx, key).Result()
+	if err != nil {
+		return nil, rr.check(err)
+	}
+
+	if cached == 0 {
+		store := &redis.ZStore{Aggregate: "SUM"}
+		current := now.Truncate(trending.bucket)
+		for i := 0; i < trending.buckets; i++ {
+			age := time.Duration(i) * trending.bucket
+			store.Keys = append(store.Keys, bucketKey(window, current.Add(-age)))
+			store.Weights = append(store.Weights, service.DecayWeight(age, trending.halfLife))
+		}
+
+		pipe := rr.Client.TxPipeline()
+		pipe.ZUnionStore(ctx, key, store)
+		pipe.Expire(ctx, key, trendingCacheTTL)
+		_, err = pipe.Exec(ctx)
+		if err != nil {
+			return nil, rr.check(err)
+		}
+	}
+
+	members, err := rr.Client.ZRevRangeWithScores(ctx, key, 0, int64(limit)-1).Result()
+	if err != nil {
+		return nil, rr.check(err)
+	}
+
+	scores := make([]model.FoodScore, 0, len(members))
+	for _, member := range members {
+		foodId, ok := member.Member.(string)
+		if !ok {
+			continue
+		}
+
+		scores = append(scores, model.FoodScore{FoodID: foodId, Score: member.Score})
+	}
+
+	return scores, nil
+}
+
+// available reports whether Redis can be called, it is not called for a while after a failure.
+func (rr *redisTrendingRepo) available() bool {
+	return time.Now().UnixNano() >= atomic.LoadInt64(&rr.unavailableUntil)
+}
+
+// check stops calling Redis for a while when the error is not a missing key.
+func (rr *redisTrendingRepo) check(err error) error {
+	if err != nil && err != redis.Nil {
+		atomic.StoreInt64(&rr.unavailableUntil, time.Now().Add(trendingRetryAfter).UnixNano())
+	}
+
+	return err
+}
+
+func bucketKey(window string, bucket time.Time) string {
+	return fmt.Sprintf("%s:%s:%d", trendingKey, window, bucket.Unix())
+}
@@ -0,0 +1,30 @@
+package application
+
+import (
+	"context"
+	repoDomain "food-api/domain/user/domain/repository"
+	"food-api/domain/user/domain/service"
+	"log"
+	"time"
+)
+
+// BootstrapAdmins promotes the users of the ADMIN_EMAILS env var to administrators, every user starts
+// with the user role so a new deploy needs them to manage the roles of the others. Only the verified
+// emails are promoted, an administrator that signs up later is promoted on the next start.
+func BootstrapAdmins(ctx context.Context, repo repoDomain.UserRepository) error {
+	emails := service.AdminEmails()
+	if len(emails) == 0 {
+		return nil
+	}
+
+	promoted, err := repo.PromoteAdmins(ctx, emails, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if promoted > 0 {
+		log.Printf("%d users of ADMIN_EMAILS were promoted to administrators", promoted)
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package application
+
+import (
+	"food-api/infrastructure/middleware"
+	"net/http"
+)
+
+// jwksMaxAge is how long the services can cache the public keys, shorter than the time a new key is
+// published before it signs tokens.
+const jwksMaxAge = "public, max-age=300"
+
+// swagger:route GET /.well-known/jwks.json Auth jwks
+//
+// JWKSHandler.
+// Response the public keys that verify the tokens
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerJWKS
+//
+// JWKSHandler response the public keys of the keyring so other services can verify the tokens, the kid
+// header of a token tells which key signed it. The keys scheduled by a rotation are already listed.
+func (lr *LoginRouter) JWKSHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", jwksMaxAge)
+	_ = middleware.JSON(w, r, http.StatusOK, lr.Token.JWKS())
+}
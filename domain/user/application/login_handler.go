@@ -1,32 +1,60 @@
 package application
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
+	"food-api/domain/user/application/v1/response"
 	"food-api/domain/user/domain/model"
 	repoDomain "food-api/domain/user/domain/repository"
+	"food-api/domain/user/domain/service"
+	"food-api/domain/user/infrastructure/notification"
 	"food-api/domain/user/infrastructure/persistence"
 	"food-api/infrastructure/auth"
 	authModel "food-api/infrastructure/auth/model"
 	"food-api/infrastructure/database"
+	"food-api/infrastructure/mail"
 	"food-api/infrastructure/middleware"
 	"github.com/dgrijalva/jwt-go"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 // LoginRouter
 type LoginRouter struct {
-	Repo  repoDomain.UserRepository
-	Redis *database.RedisService
-	Token auth.TokenInterface
+	Repo               repoDomain.UserRepository
+	ResetTokens        repoDomain.ResetTokenRepository
+	VerificationTokens repoDomain.VerificationTokenRepository
+	Notifier           service.Notifier
+	Redis              *database.RedisService
+	Token              auth.TokenInterface
+	Attempts           repoDomain.LoginAttemptRepository
+	TwoFactor          repoDomain.TwoFactorRepository
+	Challenges         repoDomain.TwoFactorChallengeRepository
+	OAuthClients       repoDomain.OAuthClientRepository
+	AuthorizationCodes repoDomain.AuthorizationCodeRepository
+	APIKeys            repoDomain.APIKeyRepository
 }
 
 // NewLoginHandler
 func NewLoginHandler(db *database.Data, redis *database.RedisService, token auth.TokenInterface) *LoginRouter {
 	return &LoginRouter{
-		Repo:  persistence.NewUserRepository(db),
-		Redis: redis,
-		Token: token,
+		Repo:               persistence.NewUserRepository(db),
+		ResetTokens:        persistence.NewResetTokenRepository(redis.Client),
+		VerificationTokens: persistence.NewVerificationTokenRepository(redis.Client),
+		Notifier:           notification.NewMailNotifier(mail.NewMailer()),
+		Redis:              redis,
+		Token:              token,
+		Attempts:           persistence.NewLoginAttemptRepository(redis.Client),
+		TwoFactor:          persistence.NewTwoFactorRepository(db),
+		Challenges:         persistence.NewTwoFactorChallengeRepository(redis.Client),
+		OAuthClients:       persistence.NewOAuthClientRepository(db),
+		AuthorizationCodes: persistence.NewAuthorizationCodeRepository(redis.Client),
+		APIKeys:            persistence.NewAPIKeyRepository(db),
 	}
 }
 
@@ -45,15 +73,28 @@ func NewLoginHandler(db *database.Data, redis *database.RedisService, token auth
 //
 //     responses:
 //        200: SwaggerDataLogin
+//		  403: SwaggerErrorMessage
 //		  409: SwaggerErrorMessage
 //		  422: SwaggerErrorMessage
+//		  429: SwaggerErrorMessage
 //		  500: SwaggerErrorMessage
 //
-// LoginHandler
+// LoginHandler, the users must verify their email first when REQUIRE_EMAIL_VERIFICATION is enabled.
+// The tokens can be limited to a subset of the scopes, they have all of them by default. The remember me
+// flag makes the session last REMEMBER_ME_TOKEN_TTL without idle timeout. The failed logins of an email and
+// of an IP are counted, too many of them lock the logins for a time that doubles with each new failure.
+// A user with two-factor authentication receives a challenge token to send with the code to /login/2fa.
 func (lr *LoginRouter) LoginHandler(w http.ResponseWriter, r *http.Request) {
-	var user model.User
+	var login model.Login
+
+	err := json.NewDecoder(r.Body).Decode(&login)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
 
-	err := json.NewDecoder(r.Body).Decode(&user)
+	user := login.User
+	scopes, err := authModel.ParseScopes(login.Scope)
 	if err != nil {
 		_ = middleware.HTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
 		return
@@ -72,28 +113,100 @@ func (lr *LoginRouter) LoginHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
+	account, ip := model.AccountSubject(user.Email), model.IPSubject(middleware.ClientIP(r))
+	locked, err := lr.Attempts.LockedFor(ctx, []string{account, ip})
+	if err != nil {
+		log.Printf("cannot check the lockout of the login: %s", err.Error())
+	}
+
+	if locked > 0 {
+		tooManyAttempts(w, r, locked)
+		return
+	}
+
 	result, err := lr.Repo.GetUserByEmailAndPassword(ctx, &user)
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, persistence.ErrPasswordMismatch) {
+		if lockout := lr.failLogin(ctx, account, ip); lockout > 0 {
+			tooManyAttempts(w, r, lockout)
+			return
+		}
+	}
+
 	if err != nil {
 		_ = middleware.HTTPError(w, r, http.StatusConflict, err.Error())
 		return
 	}
 
-	tokenDetails, err := lr.Token.CreateToken(result.ID)
+	if result.EmailVerifiedAt == nil && model.RequireEmailVerification() {
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, errors.New("the email is not verified").Error())
+		return
+	}
+
+	twoFactor, err := lr.TwoFactor.GetTwoFactor(ctx, result.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if twoFactor.Enabled() {
+		lr.challenge(w, r, result.ID, user.Email, login.Scope, login.RememberMe)
+		return
+	}
+
+	if err = lr.Attempts.Reset(ctx, account); err != nil {
+		log.Printf("cannot reset the failed logins of the user %s: %s", result.ID, err.Error())
+	}
+
+	lr.login(w, r, *result, scopes, login.RememberMe)
+}
+
+// challenge saves the login until the user sends the code of the two-factor authentication and returns
+// the token of the challenge.
+func (lr *LoginRouter) challenge(w http.ResponseWriter, r *http.Request, userId, email, scope string, rememberMe bool) {
+	token, err := service.NewToken()
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	challenge := &model.TwoFactorChallenge{
+		UserID:     userId,
+		Email:      email,
+		Scope:      scope,
+		RememberMe: rememberMe,
+		ExpiresAt:  time.Now().Add(service.TwoFactorChallengeTTL()),
+	}
+
+	if err = lr.Challenges.SaveChallenge(r.Context(), token, challenge); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_ = middleware.JSON(w, r, http.StatusOK, authModel.DataLogin{ID: userId, TwoFactorRequired: true, ChallengeToken: token})
+}
+
+// login creates the tokens of the user and responds with them.
+func (lr *LoginRouter) login(w http.ResponseWriter, r *http.Request, user response.UserResponse, scopes []string, rememberMe bool) {
+	tokenDetails, err := lr.Token.CreateToken(user.ID, authModel.TokenOptions{Role: user.Role, Scopes: scopes, RememberMe: rememberMe})
 	if err != nil {
 		_ = middleware.HTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
 		return
 	}
 
-	saveErr := lr.Redis.Auth.CreateAuth(ctx, result.ID, tokenDetails)
+	saveErr := lr.Redis.Auth.CreateAuth(r.Context(), user.ID, tokenDetails)
 	if saveErr != nil {
-		_ = middleware.HTTPErrors(w, r, http.StatusInternalServerError, userErrors)
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, saveErr.Error())
 		return
 	}
 
+	lr.saveSession(r, user.ID, tokenDetails)
+
 	userData := authModel.DataLogin{
-		ID:           result.ID,
-		Names:        result.Names,
-		LastNames:    result.LastNames,
+		ID:           user.ID,
+		Names:        user.Names,
+		LastNames:    user.LastNames,
+		Role:         user.Role,
+		Scope:        authModel.FormatScopes(scopes),
 		AccessToken:  tokenDetails.AccessToken,
 		RefreshToken: tokenDetails.RefreshToken,
 	}
@@ -155,7 +268,10 @@ func (lr *LoginRouter) LogoutHandler(w http.ResponseWriter, r *http.Request) {
 //		  403: SwaggerErrorMessage
 //		  422: SwaggerErrorMessage
 //
-// RefreshHandler is the function that uses the refresh_token to generate new pairs of refresh and access tokens.
+// RefreshHandler is the function that uses the refresh_token to generate new pairs of refresh and access tokens,
+// the new access token has the current role of the user. The refresh tokens of a login form a family, a refresh
+// token that was already used revokes the whole family and the user must login again. The refreshes do not
+// extend the lifetime of the login. The refresh tokens of the OAuth clients are rejected.
 func (lr *LoginRouter) RefreshHandler(w http.ResponseWriter, r *http.Request) {
 	var dataLogin authModel.DataLogin
 	ctx := r.Context()
@@ -189,16 +305,63 @@ func (lr *LoginRouter) RefreshHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		//Delete the previous RefreshHandler Token
-		delErr := lr.Redis.Auth.DeleteRefresh(ctx, refreshUuid)
+		//The refresh tokens of the OAuth clients are only refreshed by their client on /oauth/token
+		if clientId, _ := claims["client_id"].(string); clientId != "" {
+			_ = middleware.HTTPError(w, r, http.StatusUnauthorized, errors.New("the refresh token belongs to an OAuth client").Error())
+			return
+		}
+
+		//Rotate the previous RefreshHandler Token, a reused token revokes its family
+		family, _ := claims["family"].(string)
+		delErr := lr.Redis.Auth.RotateRefresh(ctx, refreshUuid, family)
+		if errors.Is(delErr, auth.ErrRefreshReused) {
+			log.Printf("the refresh token %s of the user %s was reused, the family %s is revoked", refreshUuid, userId, family)
+			_ = middleware.HTTPError(w, r, http.StatusUnauthorized, errors.New("the refresh token was reused, login again").Error())
+			return
+		}
+
 		if delErr != nil {
 			//if any goes wrong
 			_ = middleware.HTTPError(w, r, http.StatusUnauthorized, errors.New("unauthorized").Error())
 			return
 		}
 
+		//The refresh keeps the scopes of the login
+		scope, _ := claims["scope"].(string)
+		scopes, err := authModel.ParseScopes(scope)
+		if err != nil {
+			_ = middleware.HTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		//The refresh keeps the lifetime of the login, the tokens issued before it have none and start a new one
+		var authTime time.Time
+		if seconds, ok := claims["auth_time"].(float64); ok && family != "" {
+			authTime = time.Unix(int64(seconds), 0)
+		}
+
+		rememberMe, _ := claims["remember_me"].(bool)
+
+		//The role is read again so the changes apply on the next refresh, a deleted user cannot refresh
+		user, err := lr.Repo.GetById(ctx, userId)
+		if err != nil {
+			_ = middleware.HTTPError(w, r, http.StatusUnauthorized, errors.New("unauthorized").Error())
+			return
+		}
+
 		//Create new pairs of refresh and access tokens
-		tokenDetails, err := lr.Token.CreateToken(userId)
+		tokenDetails, err := lr.Token.CreateToken(userId, authModel.TokenOptions{
+			Role:       user.Role,
+			Scopes:     scopes,
+			Family:     family,
+			AuthTime:   authTime,
+			RememberMe: rememberMe,
+		})
+		if errors.Is(err, auth.ErrSessionExpired) {
+			_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+			return
+		}
+
 		if err != nil {
 			_ = middleware.HTTPError(w, r, http.StatusForbidden, err.Error())
 			return
@@ -211,11 +374,63 @@ func (lr *LoginRouter) RefreshHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		lr.saveSession(r, userId, tokenDetails)
+
 		dataLogin.RefreshToken = tokenDetails.RefreshToken
 		dataLogin.AccessToken = tokenDetails.AccessToken
+		dataLogin.Scope = authModel.FormatScopes(scopes)
 
 		_ = middleware.JSON(w, r, http.StatusOK, dataLogin)
 	} else {
 		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, errors.New("refresh token expired").Error())
 	}
 }
+
+// failLogin counts the failed login of the email and of the IP, it returns the longest of their lockouts.
+// A failure is only logged so the login does not depend on Redis.
+func (lr *LoginRouter) failLogin(ctx context.Context, account, ip string) time.Duration {
+	var lockout time.Duration
+
+	policies := map[string]model.LockoutPolicy{
+		account: service.AccountLockoutPolicy(),
+		ip:      service.IPLockoutPolicy(),
+	}
+
+	for subject, policy := range policies {
+		wait, err := lr.Attempts.Fail(ctx, subject, policy)
+		if err != nil {
+			log.Printf("cannot count the failed login: %s", err.Error())
+			continue
+		}
+
+		if wait > lockout {
+			lockout = wait
+		}
+	}
+
+	return lockout
+}
+
+// tooManyAttempts responds that the logins are locked for the wait.
+func tooManyAttempts(w http.ResponseWriter, r *http.Request, wait time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	_ = middleware.HTTPError(w, r, http.StatusTooManyRequests, errors.New("too many failed logins, try again later").Error())
+}
+
+// saveSession records the IP and the user agent of the session of the tokens, a failure is only logged
+// so the login does not depend on it.
+func (lr *LoginRouter) saveSession(r *http.Request, userId string, tokenDetails *authModel.TokenDetails) {
+	now := time.Now()
+	session := &authModel.Session{
+		ID:         tokenDetails.Family,
+		CreatedAt:  now,
+		LastUsedAt: now,
+		IP:         middleware.ClientIP(r),
+		UserAgent:  r.UserAgent(),
+	}
+
+	err := lr.Redis.Auth.SaveSession(r.Context(), userId, session, time.Unix(tokenDetails.RtExpires, 0))
+	if err != nil {
+		log.Printf("cannot save the session %s of the user %s: %s", session.ID, userId, err.Error())
+	}
+}
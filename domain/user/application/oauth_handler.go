@@ -0,0 +1,273 @@
+package application
+
+import (
+	"database/sql"
+	"errors"
+	"food-api/domain/user/application/v1/response"
+	"food-api/domain/user/domain/model"
+	"food-api/domain/user/domain/service"
+	"food-api/domain/user/infrastructure/persistence"
+	"food-api/infrastructure/auth"
+	authModel "food-api/infrastructure/auth/model"
+	"food-api/infrastructure/middleware"
+	"github.com/dgrijalva/jwt-go"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Error codes of the token endpoint, see RFC 6749.
+const (
+	oauthInvalidRequest       = "invalid_request"
+	oauthInvalidClient        = "invalid_client"
+	oauthInvalidGrant         = "invalid_grant"
+	oauthUnsupportedGrantType = "unsupported_grant_type"
+	oauthServerError          = "server_error"
+)
+
+// swagger:route POST /oauth/token OAuth oauthToken
+//
+// TokenHandler.
+// Exchange an authorization code or a refresh token of an OAuth client for tokens
+//
+//     consumes:
+//     - application/x-www-form-urlencoded
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerOAuthTokenResponse
+//		  400: SwaggerOAuthErrorResponse
+//		  401: SwaggerOAuthErrorResponse
+//		  500: SwaggerOAuthErrorResponse
+//
+// TokenHandler is the token endpoint of the authorization server. A confidential client authenticates with
+// its secret by HTTP Basic or in the form, a public client only sends its client_id. The authorization code
+// is exchanged once with the code verifier of its PKCE challenge. The tokens are the ones of a login limited
+// to the granted scopes, so the user sees the client in the sessions and can revoke it. The openid scope
+// adds an ID token.
+func (lr *LoginRouter) TokenHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+
+	if err := r.ParseForm(); err != nil {
+		oauthError(w, r, http.StatusBadRequest, oauthInvalidRequest, err.Error())
+		return
+	}
+
+	client, ok := lr.oauthClient(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.PostForm.Get("grant_type") {
+	case service.GrantTypeAuthorizationCode:
+		lr.exchangeCode(w, r, client)
+	case service.GrantTypeRefreshToken:
+		lr.refreshClient(w, r, client)
+	default:
+		oauthError(w, r, http.StatusBadRequest, oauthUnsupportedGrantType, "the grant_type should be authorization_code or refresh_token")
+	}
+}
+
+// swagger:route GET /.well-known/openid-configuration OAuth openIDConfiguration
+//
+// OpenIDConfigurationHandler.
+// Response the OpenID Connect discovery document
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerOpenIDConfiguration
+//
+// OpenIDConfigurationHandler response the endpoints and the features of the authorization server of the
+// OAUTH_ISSUER, the authorization endpoint is the consent page of OAUTH_CONSENT_URL.
+func (lr *LoginRouter) OpenIDConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+	algorithms := make([]string, 0)
+	for _, key := range lr.Token.JWKS().Keys {
+		if !authModel.HasScope(algorithms, key.Algorithm) {
+			algorithms = append(algorithms, key.Algorithm)
+		}
+	}
+
+	w.Header().Set("Cache-Control", jwksMaxAge)
+	_ = middleware.JSON(w, r, http.StatusOK, service.NewOpenIDConfiguration(service.OAuthIssuer(), algorithms))
+}
+
+// oauthClient authenticates the client of the token request.
+func (lr *LoginRouter) oauthClient(w http.ResponseWriter, r *http.Request) (*model.OAuthClient, bool) {
+	clientId, secret, basic := r.BasicAuth()
+	if basic {
+		// The credentials of HTTP Basic are form encoded, see RFC 6749 section 2.3.1
+		clientId, _ = url.QueryUnescape(clientId)
+		secret, _ = url.QueryUnescape(secret)
+	} else {
+		clientId, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+
+	client, err := lr.OAuthClients.GetClient(r.Context(), clientId)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		oauthError(w, r, http.StatusInternalServerError, oauthServerError, err.Error())
+		return nil, false
+	}
+
+	if err != nil || (client.Confidential && !service.VerifyClientSecret(client, secret)) || (!client.Confidential && secret != "") {
+		if basic {
+			w.Header().Set("WWW-Authenticate", `Basic realm="food-api"`)
+		}
+
+		oauthError(w, r, http.StatusUnauthorized, oauthInvalidClient, "the client authentication failed")
+		return nil, false
+	}
+
+	return client, true
+}
+
+// exchangeCode issues the tokens of an authorization code, the code must be issued to the client for the
+// same redirect URI and the code verifier must match its challenge.
+func (lr *LoginRouter) exchangeCode(w http.ResponseWriter, r *http.Request, client *model.OAuthClient) {
+	ctx := r.Context()
+
+	authorization, err := lr.AuthorizationCodes.ConsumeCode(ctx, r.PostForm.Get("code"))
+	if errors.Is(err, persistence.ErrAuthorizationCodeInvalid) {
+		oauthError(w, r, http.StatusBadRequest, oauthInvalidGrant, err.Error())
+		return
+	}
+
+	if err != nil {
+		oauthError(w, r, http.StatusInternalServerError, oauthServerError, err.Error())
+		return
+	}
+
+	if authorization.ClientID != client.ID || authorization.RedirectURI != r.PostForm.Get("redirect_uri") {
+		oauthError(w, r, http.StatusBadRequest, oauthInvalidGrant, "the code was not issued to the client for the redirect_uri")
+		return
+	}
+
+	if !service.VerifyCodeVerifier(r.PostForm.Get("code_verifier"), authorization.CodeChallenge) {
+		oauthError(w, r, http.StatusBadRequest, oauthInvalidGrant, "the code_verifier does not match the code_challenge")
+		return
+	}
+
+	user, err := lr.Repo.GetById(ctx, authorization.UserID)
+	if err != nil {
+		oauthError(w, r, http.StatusBadRequest, oauthInvalidGrant, "the user of the code does not exist")
+		return
+	}
+
+	lr.issueClientTokens(w, r, client, user, strings.Fields(authorization.Scope), "", authorization.AuthTime, authorization.Nonce)
+}
+
+// refreshClient rotates a refresh token of the client like RefreshHandler does for the logins, a reused
+// refresh token revokes its family.
+func (lr *LoginRouter) refreshClient(w http.ResponseWriter, r *http.Request, client *model.OAuthClient) {
+	ctx := r.Context()
+
+	refreshToken, err := lr.Token.VerifyAndValidateRefreshToken(r.PostForm.Get("refresh_token"))
+	if err != nil {
+		oauthError(w, r, http.StatusBadRequest, oauthInvalidGrant, err.Error())
+		return
+	}
+
+	claims, _ := refreshToken.Claims.(jwt.MapClaims)
+	refreshUuid, _ := claims["refresh_uuid"].(string)
+	userId, _ := claims["user_id"].(string)
+	family, _ := claims["family"].(string)
+	clientId, _ := claims["client_id"].(string)
+	if clientId != client.ID || refreshUuid == "" || userId == "" {
+		oauthError(w, r, http.StatusBadRequest, oauthInvalidGrant, "the refresh token was not issued to the client")
+		return
+	}
+
+	err = lr.Redis.Auth.RotateRefresh(ctx, refreshUuid, family)
+	if errors.Is(err, auth.ErrRefreshReused) {
+		log.Printf("the refresh token %s of the client %s was reused, the family %s is revoked", refreshUuid, client.ID, family)
+	}
+
+	if err != nil {
+		oauthError(w, r, http.StatusBadRequest, oauthInvalidGrant, "the refresh token is invalid or was revoked")
+		return
+	}
+
+	var authTime time.Time
+	if seconds, ok := claims["auth_time"].(float64); ok {
+		authTime = time.Unix(int64(seconds), 0)
+	}
+
+	//The role is read again so the changes apply on the next refresh, a deleted user cannot refresh
+	user, err := lr.Repo.GetById(ctx, userId)
+	if err != nil {
+		oauthError(w, r, http.StatusBadRequest, oauthInvalidGrant, "the user of the refresh token does not exist")
+		return
+	}
+
+	scope, _ := claims["scope"].(string)
+	lr.issueClientTokens(w, r, client, user, strings.Fields(scope), family, authTime, "")
+}
+
+// issueClientTokens creates the tokens of the user for the client and responds with them, an empty family
+// starts a new session.
+func (lr *LoginRouter) issueClientTokens(w http.ResponseWriter, r *http.Request, client *model.OAuthClient, user response.UserResponse,
+	scopes []string, family string, authTime time.Time, nonce string) {
+	tokenDetails, err := lr.Token.CreateToken(user.ID, authModel.TokenOptions{
+		Role:     user.Role,
+		Scopes:   scopes,
+		Family:   family,
+		AuthTime: authTime,
+		ClientID: client.ID,
+	})
+	if errors.Is(err, auth.ErrSessionExpired) {
+		oauthError(w, r, http.StatusBadRequest, oauthInvalidGrant, err.Error())
+		return
+	}
+
+	if err != nil {
+		oauthError(w, r, http.StatusInternalServerError, oauthServerError, err.Error())
+		return
+	}
+
+	if err = lr.Redis.Auth.CreateAuth(r.Context(), user.ID, tokenDetails); err != nil {
+		oauthError(w, r, http.StatusInternalServerError, oauthServerError, err.Error())
+		return
+	}
+
+	lr.saveSession(r, user.ID, tokenDetails)
+
+	token := model.OAuthToken{
+		AccessToken:  tokenDetails.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    tokenDetails.AtExpires - time.Now().Unix(),
+		RefreshToken: tokenDetails.RefreshToken,
+		Scope:        authModel.FormatScopes(scopes),
+	}
+
+	_, identity, _ := authModel.SplitScopes(token.Scope)
+	if authModel.HasScope(identity, authModel.ScopeOpenID) {
+		token.IDToken, err = lr.Token.CreateIDToken(user.ID, authModel.IDTokenOptions{
+			Issuer:   service.OAuthIssuer(),
+			ClientID: client.ID,
+			Nonce:    nonce,
+			AuthTime: authTime,
+			Claims:   service.UserClaims(user, identity),
+		})
+		if err != nil {
+			oauthError(w, r, http.StatusInternalServerError, oauthServerError, err.Error())
+			return
+		}
+	}
+
+	_ = middleware.JSON(w, r, http.StatusOK, token)
+}
+
+// oauthError responds an error of the token endpoint.
+func oauthError(w http.ResponseWriter, r *http.Request, status int, code, description string) {
+	_ = middleware.JSON(w, r, status, model.OAuthError{Error: code, ErrorDescription: description})
+}
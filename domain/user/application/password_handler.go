@@ -0,0 +1,162 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"food-api/domain/user/application/v1/response"
+	"food-api/domain/user/domain/model"
+	"food-api/domain/user/domain/service"
+	"food-api/domain/user/infrastructure/persistence"
+	"food-api/infrastructure/middleware"
+	"log"
+	"net/http"
+	"time"
+)
+
+// forgotPasswordMessage is the response of every forgot password request, it does not tell whether
+// the email belongs to a user.
+const forgotPasswordMessage = "If the email belongs to a user, a link to reset the password was sent"
+
+// passwordResetTimeout is how long saving and sending the token of a password reset can take.
+const passwordResetTimeout = 30 * time.Second
+
+// swagger:route POST /forgot-password Auth forgotPasswordRequest
+//
+// ForgotPasswordHandler.
+// Request a link to reset the password
+//
+//     consumes:
+//     - application/json
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerSuccessfullyMessage
+//		  422: SwaggerErrorMessage
+//
+// ForgotPasswordHandler sends a single use token to reset the password of the user with the email, the
+// token expires after PASSWORD_RESET_TTL. The response is the same whether or not the user exists, the
+// token is sent in background so the time of the response does not tell it either.
+func (lr *LoginRouter) ForgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var user model.User
+
+	err := json.NewDecoder(r.Body).Decode(&user)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+	userErrors := user.Validate("forgot_password")
+	if len(userErrors) > 0 {
+		_ = middleware.HTTPErrors(w, r, http.StatusUnprocessableEntity, userErrors)
+		return
+	}
+
+	ctx := r.Context()
+	result, err := lr.Repo.GetByEmail(ctx, user.Email)
+	if err != nil {
+		_ = middleware.JSONMessages(w, r, http.StatusOK, forgotPasswordMessage)
+		return
+	}
+
+	go lr.sendPasswordReset(result)
+
+	_ = middleware.JSONMessages(w, r, http.StatusOK, forgotPasswordMessage)
+}
+
+// sendPasswordReset saves a reset token of the user and sends it, a slow mail server is abandoned after
+// passwordResetTimeout.
+func (lr *LoginRouter) sendPasswordReset(user response.UserResponse) {
+	ctx, cancel := context.WithTimeout(context.Background(), passwordResetTimeout)
+	defer cancel()
+
+	token, err := service.NewToken()
+	if err == nil {
+		err = lr.ResetTokens.SaveResetToken(ctx, user.ID, token, service.PasswordResetTTL())
+	}
+
+	if err == nil {
+		err = lr.Notifier.PasswordReset(ctx, user, token)
+	}
+
+	if err != nil {
+		log.Printf("cannot send the password reset of the user %s: %s", user.ID, err.Error())
+	}
+}
+
+// swagger:route POST /reset-password Auth resetPasswordRequest
+//
+// ResetPasswordHandler.
+// Reset the password with the token sent to the user
+//
+//     consumes:
+//     - application/json
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerSuccessfullyMessage
+//		  400: SwaggerErrorMessage
+//		  422: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// ResetPasswordHandler replaces the password of the user of the token, the token can only be used once
+// and all the sessions of the user are closed.
+func (lr *LoginRouter) ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var reset model.PasswordReset
+
+	err := json.NewDecoder(r.Body).Decode(&reset)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+	resetErrors := reset.Validate()
+	if len(resetErrors) > 0 {
+		_ = middleware.HTTPErrors(w, r, http.StatusUnprocessableEntity, resetErrors)
+		return
+	}
+
+	ctx := r.Context()
+	userId, err := lr.ResetTokens.ConsumeResetToken(ctx, reset.Token)
+	if errors.Is(err, persistence.ErrResetTokenInvalid) {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	user := model.User{Password: reset.Password}
+	if err = user.HashPassword(); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err = lr.Repo.UpdatePassword(ctx, userId, user.PasswordHash, time.Now()); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, persistence.ErrResetTokenInvalid.Error())
+		return
+	}
+
+	if err = lr.Redis.Auth.DeleteUserTokens(ctx, userId); err != nil {
+		log.Printf("cannot revoke the tokens of the user %s: %s", userId, err.Error())
+	}
+
+	// The API keys were created by whoever knew the old password
+	if err = lr.APIKeys.DeleteUserAPIKeys(ctx, userId); err != nil {
+		log.Printf("cannot revoke the API keys of the user %s: %s", userId, err.Error())
+	}
+
+	_ = middleware.JSONMessages(w, r, http.StatusOK, "The password was reset")
+}
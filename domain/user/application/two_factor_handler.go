@@ -0,0 +1,135 @@
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"food-api/domain/user/domain/model"
+	"food-api/domain/user/domain/service"
+	"food-api/domain/user/infrastructure/persistence"
+	authModel "food-api/infrastructure/auth/model"
+	"food-api/infrastructure/middleware"
+	"log"
+	"net/http"
+	"time"
+)
+
+// swagger:route POST /login/2fa Auth twoFactorLoginRequest
+//
+// TwoFactorLoginHandler.
+// Second step of the login with the code of the two-factor authentication
+//
+//     consumes:
+//     - application/json
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerDataLogin
+//		  401: SwaggerErrorMessage
+//		  422: SwaggerErrorMessage
+//		  429: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// TwoFactorLoginHandler exchanges the challenge token of the login and a TOTP code or a recovery code for
+// the tokens. The challenge accepts a few wrong codes, they count as failed logins of the email and the IP.
+func (lr *LoginRouter) TwoFactorLoginHandler(w http.ResponseWriter, r *http.Request) {
+	var login model.TwoFactorLogin
+
+	if err := json.NewDecoder(r.Body).Decode(&login); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+	loginErrors := login.Validate()
+	if len(loginErrors) > 0 {
+		_ = middleware.HTTPErrors(w, r, http.StatusUnprocessableEntity, loginErrors)
+		return
+	}
+
+	// The lockout is checked before the challenge is consumed so a locked login keeps it
+	ctx := r.Context()
+	challenge, err := lr.Challenges.GetChallenge(ctx, login.ChallengeToken)
+	if errors.Is(err, persistence.ErrTwoFactorChallengeInvalid) {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	account, ip := model.AccountSubject(challenge.Email), model.IPSubject(middleware.ClientIP(r))
+	locked, err := lr.Attempts.LockedFor(ctx, []string{account, ip})
+	if err != nil {
+		log.Printf("cannot check the lockout of the login: %s", err.Error())
+	}
+
+	if locked > 0 {
+		tooManyAttempts(w, r, locked)
+		return
+	}
+
+	challenge, err = lr.Challenges.ConsumeChallenge(ctx, login.ChallengeToken)
+	if errors.Is(err, persistence.ErrTwoFactorChallengeInvalid) {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	twoFactor, err := lr.TwoFactor.GetTwoFactor(ctx, challenge.UserID)
+	if err != nil || !twoFactor.Enabled() {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, persistence.ErrTwoFactorChallengeInvalid.Error())
+		return
+	}
+
+	err = service.CheckTwoFactorCode(ctx, lr.TwoFactor, twoFactor, login.Code, time.Now())
+	if errors.Is(err, service.ErrTwoFactorCodeInvalid) {
+		if lockout := lr.failLogin(ctx, account, ip); lockout > 0 {
+			tooManyAttempts(w, r, lockout)
+			return
+		}
+
+		// The challenge is saved again so the user can fix a typo
+		challenge.Attempts++
+		if challenge.Attempts < service.MaxTwoFactorAttempts {
+			if saveErr := lr.Challenges.SaveChallenge(ctx, login.ChallengeToken, challenge); saveErr != nil {
+				log.Printf("cannot save the challenge of the user %s: %s", challenge.UserID, saveErr.Error())
+			}
+		}
+
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	scopes, err := authModel.ParseScopes(challenge.Scope)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	user, err := lr.Repo.GetById(ctx, challenge.UserID)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, errors.New("unauthorized").Error())
+		return
+	}
+
+	if err = lr.Attempts.Reset(ctx, account); err != nil {
+		log.Printf("cannot reset the failed logins of the user %s: %s", user.ID, err.Error())
+	}
+
+	lr.login(w, r, user, scopes, challenge.RememberMe)
+}
@@ -0,0 +1,315 @@
+package v1
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"food-api/domain/user/domain/model"
+	"food-api/domain/user/domain/service"
+	authModel "food-api/infrastructure/auth/model"
+	"food-api/infrastructure/middleware"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+// errAPIKeyManagement is returned when an API key or an OAuth client tries to create or change an API key.
+var errAPIKeyManagement = errors.New("the API keys can only be managed with a login")
+
+// swagger:route GET /api-keys APIKey getAPIKeys
+//
+// GetAPIKeysHandler.
+// Response the API keys of the user
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerAPIKeysResponse
+//		  401: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// GetAPIKeysHandler response the API keys of the user with their last use, the keys themselves are never
+// returned again.
+func (ur *UserRouter) GetAPIKeysHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	keys, err := ur.APIKeys.GetAPIKeys(r.Context(), metadata.UserId)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_ = middleware.JSON(w, r, http.StatusOK, keys)
+}
+
+// swagger:route GET /api-keys/{id} APIKey idAPIKeyPath
+//
+// GetAPIKeyHandler.
+// Response an API key of the user
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerAPIKeyResponse
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// GetAPIKeyHandler response an API key of the user
+func (ur *UserRouter) GetAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	key, err := ur.APIKeys.GetAPIKey(r.Context(), metadata.UserId, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_ = middleware.JSON(w, r, http.StatusOK, key)
+}
+
+// swagger:route POST /api-keys APIKey createAPIKeyRequest
+//
+// CreateAPIKeyHandler.
+// Create an API key for the scripts of the user
+//
+//     consumes:
+//     - application/json
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        201: SwaggerCreatedAPIKeyResponse
+//		  401: SwaggerErrorMessage
+//		  403: SwaggerErrorMessage
+//		  422: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// CreateAPIKeyHandler creates an API key, the key is only returned in this response. Its scopes must be a
+// subset of the scopes of the login, it has all of them by default. An API key or the token of an OAuth client
+// cannot create one.
+func (ur *UserRouter) CreateAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if metadata.APIKeyID != "" || metadata.ClientID != "" {
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, errAPIKeyManagement.Error())
+		return
+	}
+
+	var request model.APIKeyRequest
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+	now := time.Now()
+	requestErrors := request.Validate(now)
+	if len(requestErrors) > 0 {
+		_ = middleware.HTTPErrors(w, r, http.StatusUnprocessableEntity, requestErrors)
+		return
+	}
+
+	scopes, err := service.APIKeyScopes(request.Scope, metadata.Scopes)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	prefix, secret, err := service.NewAPIKey()
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	key := model.APIKey{
+		ID:        uuid.New().String(),
+		UserID:    metadata.UserId,
+		Name:      request.Name,
+		Prefix:    prefix,
+		Scope:     authModel.FormatScopes(scopes),
+		ExpiresAt: request.ExpiresAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+		KeyHash:   service.HashAPIKey(secret),
+	}
+
+	if err = ur.APIKeys.CreateAPIKey(r.Context(), &key); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_ = middleware.JSON(w, r, http.StatusCreated, model.CreatedAPIKey{APIKey: key, Key: secret})
+}
+
+// swagger:route PUT /api-keys/{id} APIKey updateAPIKeyRequest
+//
+// UpdateAPIKeyHandler.
+// Rename an API key or change its scopes
+//
+//     consumes:
+//     - application/json
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerAPIKeyResponse
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  403: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  422: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// UpdateAPIKeyHandler replaces the name and the scopes of the API key, the scopes follow the rules of the
+// creation. The expiration cannot be changed, a new key must be created.
+func (ur *UserRouter) UpdateAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if metadata.APIKeyID != "" || metadata.ClientID != "" {
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, errAPIKeyManagement.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	var request model.APIKeyRequest
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+	request.ExpiresAt = nil
+	requestErrors := request.Validate(time.Now())
+	if len(requestErrors) > 0 {
+		_ = middleware.HTTPErrors(w, r, http.StatusUnprocessableEntity, requestErrors)
+		return
+	}
+
+	scopes, err := service.APIKeyScopes(request.Scope, metadata.Scopes)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	key, err := ur.APIKeys.GetAPIKey(ctx, metadata.UserId, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	key.Name = request.Name
+	key.Scope = authModel.FormatScopes(scopes)
+	key.UpdatedAt = time.Now()
+
+	err = ur.APIKeys.UpdateAPIKey(ctx, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_ = middleware.JSON(w, r, http.StatusOK, key)
+}
+
+// swagger:route DELETE /api-keys/{id} APIKey deleteAPIKey
+//
+// DeleteAPIKeyHandler.
+// Revoke an API key of the user
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerSuccessfullyMessage
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// DeleteAPIKeyHandler revokes the API key, the requests with it are rejected at once. A leaked key can
+// revoke itself.
+func (ur *UserRouter) DeleteAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	err = ur.APIKeys.DeleteAPIKey(r.Context(), metadata.UserId, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_ = middleware.JSONMessages(w, r, http.StatusOK, "The API key was revoked")
+}
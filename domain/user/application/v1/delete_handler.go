@@ -0,0 +1,88 @@
+package v1
+
+import (
+	"database/sql"
+	"errors"
+	"food-api/domain/user/domain/model"
+	"food-api/infrastructure/middleware"
+	"github.com/go-chi/chi"
+	"net/http"
+	"time"
+)
+
+// swagger:route DELETE /users/{id}  User idUserDeletePath
+//
+// DeleteHandler.
+// Delete a user, the foods of the user are anonymized, transferred or deleted depending on the configuration
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        204: SwaggerSuccessfullyMessage
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  403: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// DeleteHandler soft deletes the user with the id and revokes all its tokens, only the user itself or an
+// administrator can delete it. The DELETED_USER_FOODS env var sets what happens to the foods of the user.
+func (ur *UserRouter) DeleteHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	if !selfOrAdmin(metadata, id) {
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, errors.New("cannot delete another user").Error())
+		return
+	}
+
+	deletion, err := model.NewDeletion(id, time.Now())
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	if _, err = ur.Repo.GetById(ctx, id); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if deletion.FoodPolicy == model.FoodPolicyTransfer {
+		if _, err = ur.Repo.GetById(ctx, deletion.TransferTo); err != nil {
+			_ = middleware.HTTPError(w, r, http.StatusInternalServerError, errors.New("cannot find the user that receives the foods").Error())
+			return
+		}
+	}
+
+	// The tokens are revoked first, a user logged out that could not be deleted can log in again
+	if err = ur.Auth.DeleteUserTokens(ctx, id); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	err = ur.Repo.DeleteUser(ctx, deletion)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_ = middleware.JSONMessages(w, r, http.StatusNoContent, "Successfully delete user")
+}
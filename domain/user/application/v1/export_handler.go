@@ -0,0 +1,378 @@
+package v1
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"food-api/domain/user/application/v1/response"
+	"food-api/domain/user/domain/model"
+	"food-api/domain/user/infrastructure/archive"
+	authModel "food-api/infrastructure/auth/model"
+	"food-api/infrastructure/middleware"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// defaultExportAsyncFoods is the number of foods from which an export runs as a job.
+const defaultExportAsyncFoods = 200
+
+// defaultExportTTL is how long the archive of an export job can be downloaded.
+const defaultExportTTL = 24 * time.Hour
+
+// defaultExportImageMaxSize is the largest image of a food saved in the archive, 5MB.
+const defaultExportImageMaxSize = 5 << 20
+
+// defaultExportImageTimeout is how long the download of an image of a food can take.
+const defaultExportImageTimeout = 10 * time.Second
+
+// swagger:route GET /users/{id}/export User idUserExportPath
+//
+// ExportHandler.
+// Export all the data of a user in a ZIP archive
+//
+//     produces:
+//      - application/zip
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerUserExportResponse
+//        202: SwaggerExportJobResponse
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  403: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// ExportHandler response a ZIP archive with the profile, the foods, the images, the favorites and ratings and
+// the follows of the user. Large accounts run as an asynchronous job with a download link that expires.
+// Only the user itself or an administrator can export it.
+func (ur *UserRouter) ExportHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	if !selfOrAdmin(metadata, id) {
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, errors.New("cannot export another user").Error())
+		return
+	}
+
+	ctx := r.Context()
+	if _, err = ur.Repo.GetById(ctx, id); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	foods, err := ur.Exports.CountUserFoods(ctx, id)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	async, _ := strconv.ParseBool(r.URL.Query().Get("async"))
+	if async || foods > exportAsyncFoods() {
+		token, err := exportToken()
+		if err != nil {
+			_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		job := response.ExportJobResponse{
+			ID:        uuid.New().String(),
+			UserID:    id,
+			Status:    model.ExportStatusPending,
+			CreatedAt: time.Now(),
+			Token:     token,
+		}
+
+		if err := ur.ExportJobs.SaveExportJob(ctx, &job); err != nil {
+			_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		location := fmt.Sprintf("%s/%s", r.URL.Path, job.ID)
+		go ur.runExportJob(job, fmt.Sprintf("%s/download?token=%s", location, token))
+
+		w.Header().Add("Location", location)
+		_ = middleware.JSON(w, r, http.StatusAccepted, job)
+		return
+	}
+
+	export, err := ur.Exports.GetUserExport(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	now := time.Now()
+	w.Header().Set("Content-Type", archive.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", exportFileName(id, now)))
+	w.WriteHeader(http.StatusOK)
+
+	if err = archive.Write(ctx, w, export, ur.Images, now); err != nil {
+		log.Printf("cannot complete the export of the user %s: %s", id, err.Error())
+	}
+}
+
+// swagger:route GET /users/{id}/export/{job} User idUserExportJobPath
+//
+// GetExportJobHandler.
+// Response the status of an asynchronous export
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerExportJobResponse
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  403: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//
+// GetExportJobHandler response the status of an asynchronous export, the download link is in the
+// response once the archive is ready.
+func (ur *UserRouter) GetExportJobHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	jobId := chi.URLParam(r, "job")
+	if id == "" || jobId == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	if !selfOrAdmin(metadata, id) {
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, errors.New("cannot export another user").Error())
+		return
+	}
+
+	job, err := ur.ExportJobs.GetExportJobById(r.Context(), jobId)
+	if err != nil || job.UserID != id {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, errors.New("export job not found").Error())
+		return
+	}
+
+	_ = middleware.JSON(w, r, http.StatusOK, job)
+}
+
+// swagger:route GET /users/{id}/export/{job}/download User idUserExportDownloadPath
+//
+// DownloadExportHandler.
+// Download the archive of an asynchronous export
+//
+//     produces:
+//      - application/zip
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerUserExportResponse
+//		  400: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  409: SwaggerErrorMessage
+//		  410: SwaggerErrorMessage
+//
+// DownloadExportHandler response the archive of an export job, the token of the link replaces the
+// authorization so the link can be opened in a browser. The link expires with the archive.
+func (ur *UserRouter) DownloadExportHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	jobId := chi.URLParam(r, "job")
+	if id == "" || jobId == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	ctx := r.Context()
+	job, err := ur.ExportJobs.GetExportJobById(ctx, jobId)
+	token := r.URL.Query().Get("token")
+	if err != nil || job.UserID != id || token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(job.Token)) != 1 {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, errors.New("export not found").Error())
+		return
+	}
+
+	if job.Status != model.ExportStatusCompleted {
+		_ = middleware.HTTPError(w, r, http.StatusConflict, fmt.Errorf("the export is %s", job.Status).Error())
+		return
+	}
+
+	if job.ExpiresAt != nil && time.Now().After(*job.ExpiresAt) {
+		ur.removeExportJob(ctx, job)
+		_ = middleware.HTTPError(w, r, http.StatusGone, errors.New("the export link expired").Error())
+		return
+	}
+
+	file, err := os.Open(job.Path)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusGone, errors.New("the export link expired").Error())
+		return
+	}
+
+	defer file.Close()
+
+	w.Header().Set("Content-Type", archive.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", exportFileName(id, job.CreatedAt)))
+	http.ServeContent(w, r, "", *job.FinishedAt, file)
+}
+
+// runExportJob writes the archive in a temporary file in background and stores the progress of the job,
+// the archive and the job are removed when the link expires.
+func (ur *UserRouter) runExportJob(job response.ExportJobResponse, downloadURL string) {
+	ctx := context.Background()
+
+	job.Status = model.ExportStatusRunning
+	if err := ur.ExportJobs.SaveExportJob(ctx, &job); err != nil {
+		log.Printf("cannot update export job %s: %s", job.ID, err.Error())
+	}
+
+	path, err := ur.writeExport(ctx, job.UserID)
+
+	now := time.Now()
+	job.FinishedAt = &now
+	if err != nil {
+		job.Status = model.ExportStatusFailed
+		job.Error = err.Error()
+	} else {
+		ttl := exportTTL()
+		expiresAt := now.Add(ttl)
+
+		job.Status = model.ExportStatusCompleted
+		job.Path = path
+		job.DownloadURL = downloadURL
+		job.ExpiresAt = &expiresAt
+
+		time.AfterFunc(ttl, func() {
+			ur.removeExportJob(context.Background(), &job)
+		})
+	}
+
+	if err := ur.ExportJobs.SaveExportJob(ctx, &job); err != nil {
+		log.Printf("cannot update export job %s: %s", job.ID, err.Error())
+	}
+}
+
+// writeExport writes the archive of the user in a temporary file and returns its path.
+func (ur *UserRouter) writeExport(ctx context.Context, userId string) (string, error) {
+	export, err := ur.Exports.GetUserExport(ctx, userId)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := ioutil.TempFile("", "user-export-*.zip")
+	if err != nil {
+		return "", err
+	}
+
+	err = archive.Write(ctx, file, export, ur.Images, time.Now())
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return "", err
+	}
+
+	return file.Name(), nil
+}
+
+// removeExportJob deletes the archive and the job once the link expired.
+func (ur *UserRouter) removeExportJob(ctx context.Context, job *response.ExportJobResponse) {
+	if job.Path != "" {
+		if err := os.Remove(job.Path); err != nil && !os.IsNotExist(err) {
+			log.Printf("cannot remove the export %s: %s", job.ID, err.Error())
+		}
+	}
+
+	if err := ur.ExportJobs.DeleteExportJob(ctx, job.ID); err != nil {
+		log.Printf("cannot remove the export job %s: %s", job.ID, err.Error())
+	}
+}
+
+// exportToken returns the random secret of a download link.
+func exportToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(token), nil
+}
+
+func exportFileName(userId string, at time.Time) string {
+	return fmt.Sprintf("export-%s-%s.zip", userId, at.Format("20060102"))
+}
+
+// exportAsyncFoods returns the number of foods from which an export runs as a job.
+func exportAsyncFoods() int {
+	foods, err := strconv.Atoi(os.Getenv("USER_EXPORT_ASYNC_FOODS"))
+	if err != nil || foods <= 0 {
+		return defaultExportAsyncFoods
+	}
+
+	return foods
+}
+
+// exportTTL returns how long the archive of an export job can be downloaded.
+func exportTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("USER_EXPORT_TTL"))
+	if err != nil || ttl <= 0 {
+		return defaultExportTTL
+	}
+
+	return ttl
+}
+
+// exportImageMaxSize returns the largest image of a food saved in the archive.
+func exportImageMaxSize() int64 {
+	size, err := strconv.ParseInt(os.Getenv("USER_EXPORT_IMAGE_MAX_SIZE"), 10, 64)
+	if err != nil || size <= 0 {
+		return defaultExportImageMaxSize
+	}
+
+	return size
+}
+
+// exportImageTimeout returns how long the download of an image of a food can take.
+func exportImageTimeout() time.Duration {
+	timeout, err := time.ParseDuration(os.Getenv("USER_EXPORT_IMAGE_TIMEOUT"))
+	if err != nil || timeout <= 0 {
+		return defaultExportImageTimeout
+	}
+
+	return timeout
+}
+
+// selfOrAdmin reports whether the user of the token can manage the account with the id.
+func selfOrAdmin(metadata *authModel.AccessDetails, id string) bool {
+	return metadata.UserId == id || authModel.HasRole(metadata.Role, authModel.RoleAdmin)
+}
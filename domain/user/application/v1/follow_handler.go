@@ -0,0 +1,97 @@
+package v1
+
+import (
+	"errors"
+	"food-api/infrastructure/middleware"
+	"github.com/go-chi/chi"
+	"net/http"
+)
+
+// swagger:route PUT /users/{id}/follow  User idUserFollowPath
+//
+// FollowHandler.
+// Follow a user, the followers can see the followers-only foods of the user
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerSuccessfullyMessage
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// FollowHandler makes the authenticated user a follower of the user with the id.
+func (ur *UserRouter) FollowHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	if id == metadata.UserId {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot follow yourself").Error())
+		return
+	}
+
+	ctx := r.Context()
+	if _, err = ur.Repo.GetById(ctx, id); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err = ur.Repo.FollowUser(ctx, id, metadata.UserId); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_ = middleware.JSONMessages(w, r, http.StatusOK, "Successfully follow user")
+}
+
+// swagger:route DELETE /users/{id}/follow  User idUserFollowPath
+//
+// UnfollowHandler.
+// Stop following a user
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        204: SwaggerSuccessfullyMessage
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// UnfollowHandler removes the authenticated user from the followers of the user with the id.
+func (ur *UserRouter) UnfollowHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	ctx := r.Context()
+	if err = ur.Repo.UnfollowUser(ctx, id, metadata.UserId); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_ = middleware.JSONMessages(w, r, http.StatusNoContent, "Successfully unfollow user")
+}
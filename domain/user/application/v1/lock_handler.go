@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"database/sql"
+	"errors"
+	"food-api/domain/user/domain/model"
+	authModel "food-api/infrastructure/auth/model"
+	"food-api/infrastructure/middleware"
+	"github.com/go-chi/chi"
+	"net"
+	"net/http"
+)
+
+// swagger:route DELETE /users/{id}/lock User idUserLockPath
+//
+// UnlockHandler.
+// Unlock the logins of a user
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerSuccessfullyMessage
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  403: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// UnlockHandler clears the failed logins and the lockout of the email of the user, only an administrator
+// can unlock it. The ip query parameter also unlocks the logins from that IP.
+func (ur *UserRouter) UnlockHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	if !authModel.HasRole(metadata.Role, authModel.RoleAdmin) {
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, errors.New("the admin role is required").Error())
+		return
+	}
+
+	ip := r.URL.Query().Get("ip")
+	if ip != "" && net.ParseIP(ip) == nil {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("the ip is invalid").Error())
+		return
+	}
+
+	ctx := r.Context()
+	user, err := ur.Repo.GetById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err = ur.Attempts.Reset(ctx, model.AccountSubject(user.Email)); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if ip != "" {
+		if err = ur.Attempts.Reset(ctx, model.IPSubject(ip)); err != nil {
+			_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	_ = middleware.JSONMessages(w, r, http.StatusOK, "The logins were unlocked")
+}
@@ -0,0 +1,225 @@
+package v1
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"food-api/domain/user/domain/model"
+	"food-api/domain/user/domain/service"
+	"food-api/infrastructure/middleware"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+// errOAuthClientManagement is returned when an API key or an OAuth client tries to register an OAuth client.
+var errOAuthClientManagement = errors.New("the OAuth clients can only be managed with a login")
+
+// swagger:route GET /oauth/clients OAuth getOAuthClients
+//
+// GetOAuthClientsHandler.
+// Response the OAuth clients registered by the user
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerOAuthClientsResponse
+//		  401: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// GetOAuthClientsHandler response the OAuth clients of the user, the secrets are never returned again.
+func (ur *UserRouter) GetOAuthClientsHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	clients, err := ur.OAuthClients.GetClients(r.Context(), metadata.UserId)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_ = middleware.JSON(w, r, http.StatusOK, clients)
+}
+
+// swagger:route GET /oauth/clients/{id} OAuth idOAuthClientPath
+//
+// GetOAuthClientHandler.
+// Response an OAuth client registered by the user
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerOAuthClientResponse
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// GetOAuthClientHandler response an OAuth client of the user, the clients of other users are not found.
+func (ur *UserRouter) GetOAuthClientHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	client, err := ur.OAuthClients.GetClient(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && client.UserID != metadata.UserId) {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, sql.ErrNoRows.Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_ = middleware.JSON(w, r, http.StatusOK, client)
+}
+
+// swagger:route POST /oauth/clients OAuth createOAuthClientRequest
+//
+// CreateOAuthClientHandler.
+// Register an OAuth client for a third-party app
+//
+//     consumes:
+//     - application/json
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        201: SwaggerCreatedOAuthClientResponse
+//		  401: SwaggerErrorMessage
+//		  403: SwaggerErrorMessage
+//		  422: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// CreateOAuthClientHandler registers an OAuth client, the secret of a confidential client is only returned in
+// this response. The redirect URIs are compared exactly with the ones of the authorization requests.
+func (ur *UserRouter) CreateOAuthClientHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if metadata.APIKeyID != "" || metadata.ClientID != "" {
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, errOAuthClientManagement.Error())
+		return
+	}
+
+	var request model.OAuthClientRequest
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+	requestErrors := request.Validate()
+	if len(requestErrors) > 0 {
+		_ = middleware.HTTPErrors(w, r, http.StatusUnprocessableEntity, requestErrors)
+		return
+	}
+
+	now := time.Now()
+	client := model.CreatedOAuthClient{
+		OAuthClient: model.OAuthClient{
+			ID:           uuid.New().String(),
+			UserID:       metadata.UserId,
+			Name:         request.Name,
+			RedirectURIs: request.RedirectURIs,
+			Scope:        request.Scope,
+			Confidential: request.Confidential,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		},
+	}
+
+	if client.Confidential {
+		client.Secret, err = service.NewToken()
+		if err != nil {
+			_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		client.SecretHash = service.HashClientSecret(client.Secret)
+	}
+
+	if err = ur.OAuthClients.CreateClient(r.Context(), &client.OAuthClient); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_ = middleware.JSON(w, r, http.StatusCreated, client)
+}
+
+// swagger:route DELETE /oauth/clients/{id} OAuth deleteOAuthClient
+//
+// DeleteOAuthClientHandler.
+// Remove an OAuth client registered by the user
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerSuccessfullyMessage
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  403: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// DeleteOAuthClientHandler removes the OAuth client, its codes and refresh tokens cannot be exchanged
+// anymore. The access tokens it already has are valid until they expire.
+func (ur *UserRouter) DeleteOAuthClientHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if metadata.APIKeyID != "" || metadata.ClientID != "" {
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, errOAuthClientManagement.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	err = ur.OAuthClients.DeleteClient(r.Context(), metadata.UserId, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_ = middleware.JSONMessages(w, r, http.StatusOK, "The OAuth client was removed")
+}
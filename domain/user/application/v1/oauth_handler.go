@@ -0,0 +1,219 @@
+package v1
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"food-api/domain/user/domain/model"
+	"food-api/domain/user/domain/service"
+	authModel "food-api/infrastructure/auth/model"
+	"food-api/infrastructure/middleware"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// errOAuthConsent is returned when an API key or an OAuth client tries to approve an OAuth client.
+var errOAuthConsent = errors.New("the OAuth clients can only be approved with a login")
+
+// swagger:route GET /oauth/authorize OAuth oauthAuthorize
+//
+// AuthorizeHandler.
+// Response what the consent screen shows for the authorization request of an OAuth client
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerOAuthConsentResponse
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  403: SwaggerErrorMessage
+//		  422: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// AuthorizeHandler checks the authorization request the client sent to the consent page with the query
+// parameters of the authorization code flow and response the name of the client and the requested scopes.
+// An unknown client or redirect URI responds 400, the user must not be sent to the redirect URI. Another
+// invalid request responds the redirect URI with the error, the consent page sends the browser to it.
+func (ur *UserRouter) AuthorizeHandler(w http.ResponseWriter, r *http.Request) {
+	request := model.NewAuthorizationRequest(r.URL.Query())
+	_, client, scopes, ok := ur.authorization(w, r, request)
+	if !ok {
+		return
+	}
+
+	_ = middleware.JSON(w, r, http.StatusOK, model.OAuthConsent{
+		ClientID:    client.ID,
+		ClientName:  client.Name,
+		Scopes:      scopes,
+		RedirectURI: request.RedirectURI,
+		State:       request.State,
+	})
+}
+
+// swagger:route POST /oauth/authorize OAuth oauthConsent
+//
+// ConsentHandler.
+// Approve or deny the authorization request of an OAuth client
+//
+//     consumes:
+//     - application/json
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerOAuthRedirectResponse
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  403: SwaggerErrorMessage
+//		  422: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// ConsentHandler records the decision of the user and response the redirect URI the browser is sent to, with
+// an authorization code valid for OAUTH_CODE_TTL or with the access_denied error. The code keeps the time the
+// user logged in for the auth_time of the ID token. The code is exchanged once
+// with the code verifier of PKCE on /oauth/token.
+func (ur *UserRouter) ConsentHandler(w http.ResponseWriter, r *http.Request) {
+	var decision model.AuthorizationDecision
+	if err := json.NewDecoder(r.Body).Decode(&decision); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+	request := decision.AuthorizationRequest
+	metadata, client, scopes, ok := ur.authorization(w, r, request)
+	if !ok {
+		return
+	}
+
+	params := url.Values{}
+	if request.State != "" {
+		params.Set("state", request.State)
+	}
+
+	if !decision.Approve {
+		params.Set("error", "access_denied")
+		_ = middleware.JSON(w, r, http.StatusOK, model.OAuthRedirect{RedirectTo: service.AuthorizationRedirect(request.RedirectURI, params)})
+		return
+	}
+
+	code, err := service.NewToken()
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	now := time.Now()
+	authorization := &model.AuthorizationCode{
+		ClientID:      client.ID,
+		UserID:        metadata.UserId,
+		RedirectURI:   request.RedirectURI,
+		Scope:         authModel.FormatScopes(scopes),
+		Nonce:         request.Nonce,
+		CodeChallenge: request.CodeChallenge,
+		AuthTime:      metadata.AuthTime,
+		ExpiresAt:     now.Add(service.AuthorizationCodeTTL()),
+	}
+
+	if err = ur.AuthorizationCodes.SaveCode(r.Context(), code, authorization); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	params.Set("code", code)
+	_ = middleware.JSON(w, r, http.StatusOK, model.OAuthRedirect{RedirectTo: service.AuthorizationRedirect(request.RedirectURI, params)})
+}
+
+// swagger:route GET /oauth/userinfo OAuth oauthUserInfo
+//
+// UserInfoHandler.
+// Response the claims of the user to an OAuth client
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerUserInfoResponse
+//		  401: SwaggerErrorMessage
+//		  403: SwaggerErrorMessage
+//
+// UserInfoHandler is the OpenID Connect userinfo endpoint, the access token of the client must have the
+// openid scope. The profile and the email scopes release the names and the email of the user.
+func (ur *UserRouter) UserInfoHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if metadata.ClientID == "" || !authModel.HasScope(metadata.IdentityScopes, authModel.ScopeOpenID) {
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, errors.New("the token does not have the openid scope").Error())
+		return
+	}
+
+	user, err := ur.Repo.GetById(r.Context(), metadata.UserId)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, errors.New("unauthorized").Error())
+		return
+	}
+
+	claims := service.UserClaims(user, metadata.IdentityScopes)
+	claims["sub"] = user.ID
+
+	_ = middleware.JSON(w, r, http.StatusOK, claims)
+}
+
+// authorization checks the authorization request for the login of the request, it returns the metadata of
+// the login, the client and the scopes the user grants to it.
+func (ur *UserRouter) authorization(w http.ResponseWriter, r *http.Request, request model.AuthorizationRequest) (*authModel.AccessDetails, *model.OAuthClient, []string, bool) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return nil, nil, nil, false
+	}
+
+	if metadata.APIKeyID != "" || metadata.ClientID != "" {
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, errOAuthConsent.Error())
+		return nil, nil, nil, false
+	}
+
+	client, err := ur.OAuthClients.GetClient(r.Context(), request.ClientID)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("the client_id is unknown").Error())
+		return nil, nil, nil, false
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return nil, nil, nil, false
+	}
+
+	if !client.HasRedirectURI(request.RedirectURI) {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, service.ErrRedirectURIInvalid.Error())
+		return nil, nil, nil, false
+	}
+
+	// The redirect URI is the one of the client, the errors of the request are sent to it
+	if err = service.CheckAuthorizationRequest(request); err != nil {
+		params := service.AuthorizationErrorParams(err, request.State)
+		_ = middleware.JSON(w, r, http.StatusOK, model.OAuthRedirect{RedirectTo: service.AuthorizationRedirect(request.RedirectURI, params)})
+		return nil, nil, nil, false
+	}
+
+	scopes, err := service.ClientScopes(client, request.Scope, metadata.Scopes)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		return nil, nil, nil, false
+	}
+
+	return metadata, client, scopes, true
+}
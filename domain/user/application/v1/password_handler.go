@@ -0,0 +1,139 @@
+package v1
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"food-api/domain/user/domain/model"
+	"food-api/domain/user/domain/service"
+	"food-api/infrastructure/middleware"
+	"github.com/go-chi/chi"
+	"log"
+	"net/http"
+	"time"
+)
+
+// errPasswordManagement is returned when an API key or an OAuth client tries to change the password.
+var errPasswordManagement = errors.New("the password can only be changed with a login")
+
+// swagger:route PUT /users/{id}/password User userPasswordRequest
+//
+// ChangePasswordHandler.
+// Change the password of a user
+//
+//     consumes:
+//     - application/json
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerSuccessfullyMessage
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  403: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  422: SwaggerErrorMessage
+//		  429: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// ChangePasswordHandler replaces the password of the user after checking the current one, only the user
+// itself can change it and only with a login. A wrong current password counts as a failed login of the
+// account. The other sessions of the user are closed, the current one is kept.
+func (ur *UserRouter) ChangePasswordHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	if metadata.UserId != id {
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, errors.New("cannot change the password of another user").Error())
+		return
+	}
+
+	if metadata.APIKeyID != "" || metadata.ClientID != "" {
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, errPasswordManagement.Error())
+		return
+	}
+
+	var change model.PasswordChange
+	if err = json.NewDecoder(r.Body).Decode(&change); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+	changeErrors := change.Validate()
+	if len(changeErrors) > 0 {
+		_ = middleware.HTTPErrors(w, r, http.StatusUnprocessableEntity, changeErrors)
+		return
+	}
+
+	ctx := r.Context()
+	result, err := ur.Repo.GetById(ctx, id)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	account := model.AccountSubject(result.Email)
+	locked, err := ur.Attempts.LockedFor(ctx, []string{account})
+	if err != nil {
+		log.Printf("cannot check the lockout of the user %s: %s", id, err.Error())
+	}
+
+	if locked > 0 {
+		tooManyAttempts(w, r, locked)
+		return
+	}
+
+	current := &model.User{Email: result.Email, Password: change.CurrentPassword}
+	if _, err = ur.Repo.GetUserByEmailAndPassword(ctx, current); err != nil {
+		lockout, failErr := ur.Attempts.Fail(ctx, account, service.AccountLockoutPolicy())
+		if failErr != nil {
+			log.Printf("cannot count the failed password of the user %s: %s", id, failErr.Error())
+		}
+
+		if lockout > 0 {
+			tooManyAttempts(w, r, lockout)
+			return
+		}
+
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, errors.New("the current password does not match").Error())
+		return
+	}
+
+	user := model.User{Password: change.Password}
+	if err = user.HashPassword(); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	err = ur.Repo.UpdatePassword(ctx, id, user.PasswordHash, time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	refreshUuid := fmt.Sprintf("%s++%s", metadata.TokenUuid, metadata.UserId)
+	if err = ur.Auth.DeleteUserTokens(ctx, id, metadata.TokenUuid, refreshUuid); err != nil {
+		log.Printf("cannot revoke the tokens of the user %s: %s", id, err.Error())
+	}
+
+	_ = middleware.JSONMessages(w, r, http.StatusOK, "The password was changed")
+}
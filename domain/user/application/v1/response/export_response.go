@@ -0,0 +1,93 @@
+package response
+
+import "time"
+
+// ProfileResponse is the profile of the user in the personal data export.
+type ProfileResponse struct {
+	ID        string    `json:"id"`
+	Names     string    `json:"names"`
+	LastNames string    `json:"last_names"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ExportTranslationResponse is a translation of a food of the user.
+type ExportTranslationResponse struct {
+	Language     string   `json:"language"`
+	Title        string   `json:"title,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	Instructions []string `json:"instructions,omitempty"`
+}
+
+// ExportFoodResponse is a food of the user with its translations.
+type ExportFoodResponse struct {
+	ID           string                      `json:"id"`
+	Title        string                      `json:"title"`
+	Description  string                      `json:"description"`
+	FoodImage    string                      `json:"food_image,omitempty"`
+	Ingredients  []string                    `json:"ingredients,omitempty"`
+	Instructions []string                    `json:"instructions,omitempty"`
+	Tags         []string                    `json:"tags,omitempty"`
+	Slug         string                      `json:"slug"`
+	Visibility   string                      `json:"visibility"`
+	Language     string                      `json:"language,omitempty"`
+	Translations []ExportTranslationResponse `json:"translations,omitempty"`
+	CreatedAt    time.Time                   `json:"created_at"`
+	UpdatedAt    time.Time                   `json:"updated_at"`
+}
+
+// ExportPreferenceResponse is a favorite and/or a rating of the user, a rating of 0 means the food is not rated.
+type ExportPreferenceResponse struct {
+	FoodID    string    `json:"food_id"`
+	Favorite  bool      `json:"favorite"`
+	Rating    int       `json:"rating,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ExportFollowResponse is a follower of the user or a user the user follows.
+type ExportFollowResponse struct {
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserExportResponse is all the data owned by the user.
+type UserExportResponse struct {
+	Profile     ProfileResponse            `json:"profile"`
+	Foods       []ExportFoodResponse       `json:"foods"`
+	Preferences []ExportPreferenceResponse `json:"preferences"`
+	Followers   []ExportFollowResponse     `json:"followers"`
+	Following   []ExportFollowResponse     `json:"following"`
+}
+
+// ExportJobResponse is the state of an asynchronous personal data export.
+type ExportJobResponse struct {
+	ID     string `json:"id"`
+	UserID string `json:"user_id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// DownloadURL is the link to the archive once the export is completed, it does not need the token.
+	DownloadURL string     `json:"download_url,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	// Token is the secret of the download link.
+	Token string `json:"-"`
+	// Path is the file of the archive.
+	Path string `json:"-"`
+}
+
+// ExportJobResponse It is the response of an asynchronous personal data export
+// swagger:response SwaggerExportJobResponse
+type SwaggerExportJobResponse struct {
+	// in: body
+	Body ExportJobResponse
+}
+
+// The ZIP archive with the data of the user
+// swagger:response SwaggerUserExportResponse
+type SwaggerUserExportResponse struct {
+	// in: body
+	Body []byte
+}
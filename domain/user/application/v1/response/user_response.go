@@ -1,10 +1,14 @@
 package response
 
+import "time"
+
 type UserResponse struct {
-	ID           string     `json:"id,omitempty"`
-	Names        string     `json:"names,omitempty"`
-	LastNames    string     `json:"last_names,omitempty"`
-	Email        string     `json:"email,omitempty"`
+	ID              string     `json:"id,omitempty"`
+	Names           string     `json:"names,omitempty"`
+	LastNames       string     `json:"last_names,omitempty"`
+	Email           string     `json:"email,omitempty"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	Role            string     `json:"role,omitempty"`
 }
 
 // UserResponse It is the response of the all users information
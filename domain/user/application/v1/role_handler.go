@@ -0,0 +1,96 @@
+package v1
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"food-api/domain/user/domain/model"
+	authModel "food-api/infrastructure/auth/model"
+	"food-api/infrastructure/middleware"
+	"github.com/go-chi/chi"
+	"log"
+	"net/http"
+	"time"
+)
+
+// swagger:route PUT /users/{id}/role User userRoleRequest
+//
+// RoleHandler.
+// Change the role of a user
+//
+//     consumes:
+//     - application/json
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerSuccessfullyMessage
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  403: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  409: SwaggerErrorMessage
+//		  422: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// RoleHandler sets the role of the user, only an administrator can change it. The sessions of the user
+// are closed so the new role applies on the next login, an administrator cannot remove its own role.
+func (ur *UserRouter) RoleHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	if !authModel.HasRole(metadata.Role, authModel.RoleAdmin) {
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, errors.New("the admin role is required").Error())
+		return
+	}
+
+	var change model.RoleChange
+	if err = json.NewDecoder(r.Body).Decode(&change); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+	roleErrors := change.Validate()
+	if len(roleErrors) > 0 {
+		_ = middleware.HTTPErrors(w, r, http.StatusUnprocessableEntity, roleErrors)
+		return
+	}
+
+	if id == metadata.UserId && change.Role != authModel.RoleAdmin {
+		_ = middleware.HTTPError(w, r, http.StatusConflict, errors.New("an administrator cannot remove its own role").Error())
+		return
+	}
+
+	ctx := r.Context()
+	err = ur.Repo.UpdateRole(ctx, id, change.Role, time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if id != metadata.UserId {
+		if err = ur.Auth.DeleteUserTokens(ctx, id); err != nil {
+			log.Printf("cannot revoke the tokens of the user %s: %s", id, err.Error())
+		}
+	}
+
+	_ = middleware.JSONMessages(w, r, http.StatusOK, "The role was changed")
+}
@@ -0,0 +1,122 @@
+package v1
+
+import (
+	"errors"
+	"food-api/infrastructure/auth"
+	"food-api/infrastructure/middleware"
+	"github.com/go-chi/chi"
+	"net/http"
+)
+
+// swagger:route GET /sessions Session getSessions
+//
+// GetSessionsHandler.
+// Response the active sessions of the user
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerSessionsResponse
+//		  401: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// GetSessionsHandler response the logins of the user with the date of the last use, the IP and the user
+// agent, the session of the token is marked as current.
+func (ur *UserRouter) GetSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	sessions, err := ur.Auth.ListSessions(r.Context(), metadata.UserId)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == metadata.Family
+	}
+
+	_ = middleware.JSON(w, r, http.StatusOK, sessions)
+}
+
+// swagger:route DELETE /sessions/{id} Session idSessionPath
+//
+// DeleteSessionHandler.
+// Revoke a session of the user
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerSuccessfullyMessage
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// DeleteSessionHandler revokes the access and refresh tokens of the session, the device must login again.
+func (ur *UserRouter) DeleteSessionHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return
+	}
+
+	err = ur.Auth.DeleteSession(r.Context(), metadata.UserId, id)
+	if errors.Is(err, auth.ErrSessionNotFound) {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_ = middleware.JSONMessages(w, r, http.StatusOK, "The session was revoked")
+}
+
+// swagger:route DELETE /sessions Session deleteSessions
+//
+// DeleteSessionsHandler.
+// Log out everywhere
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerSuccessfullyMessage
+//		  401: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// DeleteSessionsHandler revokes the tokens of all the sessions of the user, including the current one.
+func (ur *UserRouter) DeleteSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err = ur.Auth.DeleteUserTokens(r.Context(), metadata.UserId); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_ = middleware.JSONMessages(w, r, http.StatusOK, "Successfully logged out everywhere")
+}
@@ -0,0 +1,361 @@
+package v1
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"food-api/domain/user/application/v1/response"
+	"food-api/domain/user/domain/model"
+	"food-api/domain/user/domain/service"
+	"food-api/domain/user/infrastructure/persistence"
+	authModel "food-api/infrastructure/auth/model"
+	"food-api/infrastructure/middleware"
+	"github.com/go-chi/chi"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// errTwoFactorManagement is returned when an API key or an OAuth client tries to manage the two-factor
+// authentication.
+var errTwoFactorManagement = errors.New("the two-factor authentication can only be managed with a login")
+
+// swagger:route POST /users/{id}/2fa User idUserTwoFactorPath
+//
+// EnrollTwoFactorHandler.
+// Start the enrollment of the two-factor authentication
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerTwoFactorEnrollment
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  403: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  409: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// EnrollTwoFactorHandler generates the TOTP secret of the user, it is pending until a code is confirmed.
+// Enrolling again replaces a pending secret, an enabled one must be disabled first.
+func (ur *UserRouter) EnrollTwoFactorHandler(w http.ResponseWriter, r *http.Request) {
+	_, user, ok := ur.twoFactorUser(w, r)
+	if !ok {
+		return
+	}
+
+	secret, err := service.NewTOTPSecret()
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	err = ur.TwoFactor.SaveSecret(r.Context(), user.ID, secret, time.Now())
+	if errors.Is(err, persistence.ErrTwoFactorEnabled) {
+		_ = middleware.HTTPError(w, r, http.StatusConflict, err.Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_ = middleware.JSON(w, r, http.StatusOK, model.TwoFactorEnrollment{Secret: secret, URI: service.TOTPURI(user.Email, secret)})
+}
+
+// swagger:route POST /users/{id}/2fa/confirm User userTwoFactorConfirmRequest
+//
+// ConfirmTwoFactorHandler.
+// Enable the two-factor authentication with a code of the pending secret
+//
+//     consumes:
+//     - application/json
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerRecoveryCodes
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  403: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  409: SwaggerErrorMessage
+//		  422: SwaggerErrorMessage
+//		  429: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// ConfirmTwoFactorHandler enables the pending secret once the user proves the authenticator app has it and
+// returns the recovery codes, they are only shown once. The other sessions of the user are closed.
+func (ur *UserRouter) ConfirmTwoFactorHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, user, ok := ur.twoFactorUser(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	twoFactor, err := ur.TwoFactor.GetTwoFactor(ctx, user.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, errors.New("the two-factor authentication is not enrolled").Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if twoFactor.Enabled() {
+		_ = middleware.HTTPError(w, r, http.StatusConflict, persistence.ErrTwoFactorEnabled.Error())
+		return
+	}
+
+	if !ur.checkTwoFactorCode(w, r, user, twoFactor) {
+		return
+	}
+
+	codes, err := service.NewRecoveryCodes()
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	err = ur.TwoFactor.Enable(ctx, user.ID, normalizeRecoveryCodes(codes), time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = middleware.HTTPError(w, r, http.StatusConflict, persistence.ErrTwoFactorEnabled.Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	refreshUuid := fmt.Sprintf("%s++%s", metadata.TokenUuid, metadata.UserId)
+	if err = ur.Auth.DeleteUserTokens(ctx, user.ID, metadata.TokenUuid, refreshUuid); err != nil {
+		log.Printf("cannot revoke the tokens of the user %s: %s", user.ID, err.Error())
+	}
+
+	_ = middleware.JSON(w, r, http.StatusOK, model.RecoveryCodes{Codes: codes})
+}
+
+// swagger:route POST /users/{id}/2fa/recovery-codes User userTwoFactorRecoveryRequest
+//
+// RecoveryCodesHandler.
+// Replace the recovery codes of the two-factor authentication
+//
+//     consumes:
+//     - application/json
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerRecoveryCodes
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  403: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  422: SwaggerErrorMessage
+//		  429: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// RecoveryCodesHandler generates new recovery codes after checking a code of the user, the previous ones
+// cannot be used anymore.
+func (ur *UserRouter) RecoveryCodesHandler(w http.ResponseWriter, r *http.Request) {
+	_, user, ok := ur.twoFactorUser(w, r)
+	if !ok {
+		return
+	}
+
+	twoFactor, ok := ur.enabledTwoFactor(w, r, user)
+	if !ok || !ur.checkTwoFactorCode(w, r, user, twoFactor) {
+		return
+	}
+
+	codes, err := service.NewRecoveryCodes()
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err = ur.TwoFactor.ReplaceRecoveryCodes(r.Context(), user.ID, normalizeRecoveryCodes(codes), time.Now()); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_ = middleware.JSON(w, r, http.StatusOK, model.RecoveryCodes{Codes: codes})
+}
+
+// swagger:route DELETE /users/{id}/2fa User userTwoFactorDisableRequest
+//
+// DisableTwoFactorHandler.
+// Disable the two-factor authentication
+//
+//     consumes:
+//     - application/json
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerSuccessfullyMessage
+//		  400: SwaggerErrorMessage
+//		  401: SwaggerErrorMessage
+//		  403: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
+//		  422: SwaggerErrorMessage
+//		  429: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// DisableTwoFactorHandler removes the secret and the recovery codes after checking a code of the user,
+// a recovery code disables it when the device is lost.
+func (ur *UserRouter) DisableTwoFactorHandler(w http.ResponseWriter, r *http.Request) {
+	_, user, ok := ur.twoFactorUser(w, r)
+	if !ok {
+		return
+	}
+
+	twoFactor, ok := ur.enabledTwoFactor(w, r, user)
+	if !ok || !ur.checkTwoFactorCode(w, r, user, twoFactor) {
+		return
+	}
+
+	if err := ur.TwoFactor.Disable(r.Context(), user.ID); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_ = middleware.JSONMessages(w, r, http.StatusOK, "The two-factor authentication was disabled")
+}
+
+// twoFactorUser returns the user of the path, only the user itself can manage its two-factor authentication
+// and only with a login, an API key or an OAuth client cannot.
+func (ur *UserRouter) twoFactorUser(w http.ResponseWriter, r *http.Request) (*authModel.AccessDetails, response.UserResponse, bool) {
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return nil, response.UserResponse{}, false
+	}
+
+	if metadata.APIKeyID != "" || metadata.ClientID != "" {
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, errTwoFactorManagement.Error())
+		return nil, response.UserResponse{}, false
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("cannot get id").Error())
+		return nil, response.UserResponse{}, false
+	}
+
+	if metadata.UserId != id {
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, errors.New("cannot change the two-factor authentication of another user").Error())
+		return nil, response.UserResponse{}, false
+	}
+
+	user, err := ur.Repo.GetById(r.Context(), id)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return nil, response.UserResponse{}, false
+	}
+
+	return metadata, user, true
+}
+
+// enabledTwoFactor returns the secret of the user when the two-factor authentication is enabled.
+func (ur *UserRouter) enabledTwoFactor(w http.ResponseWriter, r *http.Request, user response.UserResponse) (*model.TwoFactor, bool) {
+	twoFactor, err := ur.TwoFactor.GetTwoFactor(r.Context(), user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	if !twoFactor.Enabled() {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, errors.New("the two-factor authentication is not enabled").Error())
+		return nil, false
+	}
+
+	return twoFactor, true
+}
+
+// checkTwoFactorCode checks the code of the body, the wrong codes count as failed logins of the email so
+// a stolen access token cannot guess them.
+func (ur *UserRouter) checkTwoFactorCode(w http.ResponseWriter, r *http.Request, user response.UserResponse, twoFactor *model.TwoFactor) bool {
+	var code model.TwoFactorCode
+	if err := json.NewDecoder(r.Body).Decode(&code); err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		return false
+	}
+
+	defer r.Body.Close()
+	codeErrors := code.Validate()
+	if len(codeErrors) > 0 {
+		_ = middleware.HTTPErrors(w, r, http.StatusUnprocessableEntity, codeErrors)
+		return false
+	}
+
+	ctx := r.Context()
+	account := model.AccountSubject(user.Email)
+	locked, err := ur.Attempts.LockedFor(ctx, []string{account})
+	if err != nil {
+		log.Printf("cannot check the lockout of the user %s: %s", user.ID, err.Error())
+	}
+
+	if locked > 0 {
+		tooManyAttempts(w, r, locked)
+		return false
+	}
+
+	err = service.CheckTwoFactorCode(ctx, ur.TwoFactor, twoFactor, code.Code, time.Now())
+	if errors.Is(err, service.ErrTwoFactorCodeInvalid) {
+		lockout, failErr := ur.Attempts.Fail(ctx, account, service.AccountLockoutPolicy())
+		if failErr != nil {
+			log.Printf("cannot count the failed code of the user %s: %s", user.ID, failErr.Error())
+		}
+
+		if lockout > 0 {
+			tooManyAttempts(w, r, lockout)
+			return false
+		}
+
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, err.Error())
+		return false
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return false
+	}
+
+	return true
+}
+
+// tooManyAttempts responds that the codes are locked for the wait.
+func tooManyAttempts(w http.ResponseWriter, r *http.Request, wait time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	_ = middleware.HTTPError(w, r, http.StatusTooManyRequests, errors.New("too many failed codes, try again later").Error())
+}
+
+// normalizeRecoveryCodes returns the codes as they are stored.
+func normalizeRecoveryCodes(codes []string) []string {
+	normalized := make([]string, len(codes))
+	for i, code := range codes {
+		normalized[i] = service.NormalizeRecoveryCode(code)
+	}
+
+	return normalized
+}
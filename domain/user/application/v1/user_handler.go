@@ -1,29 +1,64 @@
 package v1
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"food-api/domain/user/application/v1/response"
 	"food-api/domain/user/domain/model"
 	repoDomain "food-api/domain/user/domain/repository"
+	"food-api/domain/user/domain/service"
+	"food-api/domain/user/infrastructure/archive"
+	"food-api/domain/user/infrastructure/notification"
 	"food-api/domain/user/infrastructure/persistence"
+	"food-api/infrastructure/auth"
 	"food-api/infrastructure/database"
+	"food-api/infrastructure/mail"
 	"food-api/infrastructure/middleware"
 	"github.com/go-chi/chi"
+	"log"
 	"net/http"
 	"time"
 )
 
+// errEmailManagement is returned when an API key or an OAuth client tries to change the email, the email
+// receives the password reset links so a leaked key must not take over the account.
+var errEmailManagement = errors.New("the email can only be changed with a login")
+
 // UserRouter
 type UserRouter struct {
-	Repo repoDomain.UserRepository
+	Repo               repoDomain.UserRepository
+	Exports            repoDomain.ExportRepository
+	ExportJobs         repoDomain.ExportJobRepository
+	Images             archive.ImageFetcher
+	VerificationTokens repoDomain.VerificationTokenRepository
+	Notifier           service.Notifier
+	Auth               auth.InterfaceAuth
+	Token              auth.TokenInterface
+	Attempts           repoDomain.LoginAttemptRepository
+	TwoFactor          repoDomain.TwoFactorRepository
+	APIKeys            repoDomain.APIKeyRepository
+	OAuthClients       repoDomain.OAuthClientRepository
+	AuthorizationCodes repoDomain.AuthorizationCodeRepository
 }
 
 // NewUserHandler
-func NewUserHandler(db *database.Data) *UserRouter {
+func NewUserHandler(db *database.Data, redis *database.RedisService) *UserRouter {
 	return &UserRouter{
-		Repo: persistence.NewUserRepository(db),
+		Repo:               persistence.NewUserRepository(db),
+		Exports:            persistence.NewExportRepository(db),
+		ExportJobs:         persistence.NewExportJobRepository(),
+		Images:             archive.NewHTTPImageFetcher(exportImageMaxSize(), exportImageTimeout()),
+		VerificationTokens: persistence.NewVerificationTokenRepository(redis.Client),
+		Notifier:           notification.NewMailNotifier(mail.NewMailer()),
+		Auth:               redis.Auth,
+		Token:              auth.NewToken(),
+		Attempts:           persistence.NewLoginAttemptRepository(redis.Client),
+		TwoFactor:          persistence.NewTwoFactorRepository(db),
+		APIKeys:            persistence.NewAPIKeyRepository(db),
+		OAuthClients:       persistence.NewOAuthClientRepository(db),
+		AuthorizationCodes: persistence.NewAuthorizationCodeRepository(redis.Client),
 	}
 }
 
@@ -40,9 +75,10 @@ func NewUserHandler(db *database.Data) *UserRouter {
 //     responses:
 //        200: SwaggerAllUserResponse
 //		  401: SwaggerErrorMessage
+//		  403: SwaggerErrorMessage
 //		  404: SwaggerErrorMessage
 //
-// GetAllUserHandler response all the users.
+// GetAllUserHandler response all the users, only the administrators can list them.
 func (ur *UserRouter) GetAllUserHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -115,7 +151,7 @@ func (ur *UserRouter) GetOneHandler(w http.ResponseWriter, r *http.Request) {
 //		  409: SwaggerErrorMessage
 //		  422: SwaggerErrorMessage
 //
-// CreateHandler Create a new user.
+// CreateHandler Create a new user, a token to verify the email is sent to the user.
 func (ur *UserRouter) CreateHandler(w http.ResponseWriter, r *http.Request) {
 	var now time.Time
 	var user model.User
@@ -149,6 +185,11 @@ func (ur *UserRouter) CreateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The user can ask for another verification email when this one fails
+	if err = service.SendEmailVerification(ctx, ur.VerificationTokens, ur.Notifier, *result); err != nil {
+		log.Printf("cannot send the email verification of the user %s: %s", result.ID, err.Error())
+	}
+
 	w.Header().Add("Location", fmt.Sprintf("%s%s", r.URL.String(), result.ID))
 	_ = middleware.JSON(w, r, http.StatusCreated, result)
 }
@@ -170,10 +211,15 @@ func (ur *UserRouter) CreateHandler(w http.ResponseWriter, r *http.Request) {
 //        200: SwaggerUserResponse
 //		  400: SwaggerErrorMessage
 //		  401: SwaggerErrorMessage
+//		  403: SwaggerErrorMessage
+//		  404: SwaggerErrorMessage
 //		  409: SwaggerErrorMessage
 //		  422: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
 //
-// UpdateHandler update a stored user by id.
+// UpdateHandler update a stored user by id, only the user itself or an administrator can update it
+// because the email receives the password reset links. A new email is not verified and the pending
+// verification link stops working, an API key or an OAuth client cannot change it.
 func (ur *UserRouter) UpdateHandler(w http.ResponseWriter, r *http.Request) {
 	var now time.Time
 	id := chi.URLParam(r, "id")
@@ -183,8 +229,19 @@ func (ur *UserRouter) UpdateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	metadata, err := ur.Token.ExtractTokenMetadata(r)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if !selfOrAdmin(metadata, id) {
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, errors.New("cannot update another user").Error())
+		return
+	}
+
 	var userUpdate model.User
-	err := json.NewDecoder(r.Body).Decode(&userUpdate)
+	err = json.NewDecoder(r.Body).Decode(&userUpdate)
 	if err != nil {
 		_ = middleware.HTTPError(w, r, http.StatusBadRequest, err.Error())
 		return
@@ -198,6 +255,22 @@ func (ur *UserRouter) UpdateHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
+	current, err := ur.Repo.GetById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = middleware.HTTPError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if current.Email != userUpdate.Email && (metadata.APIKeyID != "" || metadata.ClientID != "") {
+		_ = middleware.HTTPError(w, r, http.StatusForbidden, errEmailManagement.Error())
+		return
+	}
+
 	userUpdate.UpdatedAt = now
 
 	err = ur.Repo.UpdateUser(ctx, id, userUpdate)
@@ -206,6 +279,13 @@ func (ur *UserRouter) UpdateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A link sent to the previous email must not verify the new one
+	if current.Email != userUpdate.Email {
+		if err = ur.VerificationTokens.DeleteVerificationToken(ctx, id); err != nil {
+			log.Printf("cannot remove the verification token of the user %s: %s", id, err.Error())
+		}
+	}
+
 	result := response.UserResponse{
 		ID:        userUpdate.ID,
 		Names:     userUpdate.Names,
@@ -0,0 +1,134 @@
+package application
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"food-api/domain/user/domain/model"
+	"food-api/domain/user/domain/service"
+	"food-api/domain/user/infrastructure/persistence"
+	"food-api/infrastructure/middleware"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// resendVerificationMessage is the response of every resend request, it does not tell whether
+// the email belongs to a user.
+const resendVerificationMessage = "If the email belongs to a user that is not verified, a verification link was sent"
+
+// swagger:route GET /verify-email Auth verifyEmailRequest
+//
+// VerifyEmailHandler.
+// Verify the email of a user with the token sent to it
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerSuccessfullyMessage
+//		  400: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// VerifyEmailHandler marks the email of the user of the token as verified, the token can only be used once and
+// only while the user still has the email it was sent to.
+func (lr *LoginRouter) VerifyEmailHandler(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, errors.New("token is required").Error())
+		return
+	}
+
+	ctx := r.Context()
+	userId, email, err := lr.VerificationTokens.ConsumeVerificationToken(ctx, token)
+	if errors.Is(err, persistence.ErrVerificationTokenInvalid) {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	err = lr.Repo.VerifyEmail(ctx, userId, email, time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = middleware.HTTPError(w, r, http.StatusBadRequest, persistence.ErrVerificationTokenInvalid.Error())
+		return
+	}
+
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	_ = middleware.JSONMessages(w, r, http.StatusOK, "The email was verified")
+}
+
+// swagger:route POST /verify-email/resend Auth resendVerificationRequest
+//
+// ResendVerificationHandler.
+// Send the verification email again
+//
+//     consumes:
+//     - application/json
+//
+//     produces:
+//      - application/json
+//
+//	   schemes: http, https
+//
+//     responses:
+//        200: SwaggerSuccessfullyMessage
+//		  422: SwaggerErrorMessage
+//		  429: SwaggerErrorMessage
+//		  500: SwaggerErrorMessage
+//
+// ResendVerificationHandler sends a new verification token to the email, the previous one stops working.
+// An email can receive one every EMAIL_VERIFICATION_RESEND_INTERVAL, the response is the same whether
+// or not the user exists.
+func (lr *LoginRouter) ResendVerificationHandler(w http.ResponseWriter, r *http.Request) {
+	var user model.User
+
+	err := json.NewDecoder(r.Body).Decode(&user)
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	defer r.Body.Close()
+	userErrors := user.Validate("forgot_password")
+	if len(userErrors) > 0 {
+		_ = middleware.HTTPErrors(w, r, http.StatusUnprocessableEntity, userErrors)
+		return
+	}
+
+	ctx := r.Context()
+	wait, err := lr.VerificationTokens.ThrottleResend(ctx, user.Email, service.EmailVerificationResendInterval())
+	if err != nil {
+		_ = middleware.HTTPError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if wait > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		_ = middleware.HTTPError(w, r, http.StatusTooManyRequests, errors.New("wait before requesting another verification email").Error())
+		return
+	}
+
+	result, err := lr.Repo.GetByEmail(ctx, user.Email)
+	if err != nil || result.EmailVerifiedAt != nil {
+		_ = middleware.JSONMessages(w, r, http.StatusOK, resendVerificationMessage)
+		return
+	}
+
+	if err = service.SendEmailVerification(ctx, lr.VerificationTokens, lr.Notifier, result); err != nil {
+		log.Printf("cannot send the email verification of the user %s: %s", result.ID, err.Error())
+	}
+
+	_ = middleware.JSONMessages(w, r, http.StatusOK, resendVerificationMessage)
+}
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// APIKeyNameMaxLength is the maximum length of the name of an API key.
+const APIKeyNameMaxLength = 100
+
+// APIKey is a long-lived key of a user for the scripts, only the hash of the key is stored. The prefix is
+// the public part of the key that identifies it.
+// swagger:model
+type APIKey struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scope      string     `json:"scope"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	KeyHash    string     `json:"-"`
+	// Role is the current role of the user of the key
+	Role string `json:"-"`
+}
+
+// Expired reports whether the key cannot be used anymore at now.
+func (ak *APIKey) Expired(now time.Time) bool {
+	return ak.ExpiresAt != nil && !now.Before(*ak.ExpiresAt)
+}
+
+// CreatedAPIKey is the key created with its secret, the secret is only shown once.
+// swagger:model
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// APIKeyRequest is the body to create or update an API key, the scope is the space separated list of
+// scopes of the key. The expiration is only set on creation, a key without one does not expire.
+type APIKeyRequest struct {
+	// Required: true
+	Name      string     `json:"name"`
+	Scope     string     `json:"scope,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Validate returns the errors of the request, the scope is checked against the scopes of the token.
+func (ar *APIKeyRequest) Validate(now time.Time) map[string]string {
+	var errorMessages = make(map[string]string)
+
+	ar.Name = strings.TrimSpace(ar.Name)
+	if ar.Name == "" {
+		errorMessages["name_required"] = "name is required"
+	}
+
+	if len(ar.Name) > APIKeyNameMaxLength {
+		errorMessages["invalid_name"] = "name should be at most 100 characters"
+	}
+
+	if ar.ExpiresAt != nil && !ar.ExpiresAt.After(now) {
+		errorMessages["invalid_expires_at"] = "expires_at should be in the future"
+	}
+
+	return errorMessages
+}
+
+// swagger:parameters getAPIKeys
+type SwaggerAPIKeys struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+}
+
+// swagger:parameters idAPIKeyPath deleteAPIKey
+type SwaggerAPIKeyPath struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	ID string
+}
+
+// Information of a new API key
+// swagger:parameters createAPIKeyRequest
+type SwaggerCreateAPIKeyRequest struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: body
+	Body APIKeyRequest
+}
+
+// Information to update an API key
+// swagger:parameters updateAPIKeyRequest
+type SwaggerUpdateAPIKeyRequest struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	ID string
+
+	// in: body
+	Body struct {
+		// Required: true
+		Name  string `json:"name"`
+		Scope string `json:"scope,omitempty"`
+	}
+}
+
+// APIKey It is the response of an API key.
+// swagger:response SwaggerAPIKeyResponse
+type SwaggerAPIKeyResponse struct {
+	// in: body
+	Body APIKey
+}
+
+// APIKeys It is the response of the API keys of a user.
+// swagger:response SwaggerAPIKeysResponse
+type SwaggerAPIKeysResponse struct {
+	// in: body
+	Body []APIKey
+}
+
+// CreatedAPIKey It is the response of a new API key.
+// swagger:response SwaggerCreatedAPIKeyResponse
+type SwaggerCreatedAPIKeyResponse struct {
+	// in: body
+	Body CreatedAPIKey
+}
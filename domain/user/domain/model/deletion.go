@@ -0,0 +1,72 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Policies for the foods of a deleted user, configured with the DELETED_USER_FOODS env var.
+const (
+	// FoodPolicyAnonymize moves the foods to the deleted user account so they are no longer linked to the user,
+	// they keep their visibility so only the public ones are still visible. It is the default.
+	FoodPolicyAnonymize = "anonymize"
+	// FoodPolicyTransfer moves the foods to the user of the DELETED_USER_FOODS_TRANSFER_TO env var.
+	FoodPolicyTransfer = "transfer"
+	// FoodPolicyCascade deletes the foods with the user.
+	FoodPolicyCascade = "cascade"
+)
+
+// DeletedUserID is the account that keeps the anonymized foods of the deleted users, it cannot log in.
+const DeletedUserID = "00000000-0000-0000-0000-000000000000"
+
+// Deletion is how a user is deleted.
+type Deletion struct {
+	UserID string
+	// FoodPolicy is one of the food policies.
+	FoodPolicy string
+	// TransferTo is the user that receives the foods with FoodPolicyTransfer.
+	TransferTo string
+	DeletedAt  time.Time
+}
+
+// NewDeletion returns the deletion of the user with the food policy configured in the env vars.
+func NewDeletion(userId string, deletedAt time.Time) (*Deletion, error) {
+	deletion := &Deletion{
+		UserID:     userId,
+		FoodPolicy: strings.ToLower(strings.TrimSpace(os.Getenv("DELETED_USER_FOODS"))),
+		TransferTo: strings.TrimSpace(os.Getenv("DELETED_USER_FOODS_TRANSFER_TO")),
+		DeletedAt:  deletedAt,
+	}
+
+	switch deletion.FoodPolicy {
+	case "":
+		deletion.FoodPolicy = FoodPolicyAnonymize
+	case FoodPolicyAnonymize, FoodPolicyCascade:
+	case FoodPolicyTransfer:
+		if deletion.TransferTo == "" {
+			return nil, fmt.Errorf("the %s policy needs DELETED_USER_FOODS_TRANSFER_TO", FoodPolicyTransfer)
+		}
+
+		if deletion.TransferTo == userId {
+			return nil, fmt.Errorf("cannot transfer the foods to the deleted user")
+		}
+	default:
+		return nil, fmt.Errorf("unknown deleted user foods policy %q", deletion.FoodPolicy)
+	}
+
+	return deletion, nil
+}
+
+// FoodOwner returns the user that keeps the foods, it is empty when the foods are deleted.
+func (d Deletion) FoodOwner() string {
+	switch d.FoodPolicy {
+	case FoodPolicyTransfer:
+		return d.TransferTo
+	case FoodPolicyCascade:
+		return ""
+	}
+
+	return DeletedUserID
+}
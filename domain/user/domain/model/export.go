@@ -0,0 +1,57 @@
+package model
+
+const (
+	ExportStatusPending   = "pending"
+	ExportStatusRunning   = "running"
+	ExportStatusCompleted = "completed"
+	ExportStatusFailed    = "failed"
+)
+
+// Information for the personal data export
+// swagger:parameters idUserExportPath
+type SwaggerUserExportRequest struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	ID string
+
+	// generate the archive as an asynchronous job
+	// in: query
+	Async bool `json:"async"`
+}
+
+// swagger:parameters idUserExportJobPath
+type SwaggerUserExportJob struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	ID string
+
+	// in: path
+	// Required: true
+	JobID string `json:"job"`
+}
+
+// swagger:parameters idUserExportDownloadPath
+type SwaggerUserExportDownload struct {
+	// in: path
+	// Required: true
+	ID string
+
+	// in: path
+	// Required: true
+	JobID string `json:"job"`
+
+	// secret of the download link
+	// in: query
+	// Required: true
+	Token string `json:"token"`
+}
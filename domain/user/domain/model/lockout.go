@@ -0,0 +1,55 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// LockoutPolicy locks the logins of a subject after MaxAttempts failures within the Window. The lockout
+// starts at Base and doubles with each new failure up to Max.
+type LockoutPolicy struct {
+	MaxAttempts int64
+	Base        time.Duration
+	Max         time.Duration
+	Window      time.Duration
+}
+
+// Lockout returns how long the logins are locked after the failures, zero when they are allowed.
+func (lp LockoutPolicy) Lockout(failures int64) time.Duration {
+	if lp.MaxAttempts <= 0 || failures < lp.MaxAttempts {
+		return 0
+	}
+
+	lockout := lp.Base
+	for i := lp.MaxAttempts; i < failures && lockout < lp.Max; i++ {
+		lockout *= 2
+	}
+
+	if lockout > lp.Max {
+		return lp.Max
+	}
+
+	return lockout
+}
+
+// AccountSubject is the subject of the failed logins of an email.
+func AccountSubject(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// IPSubject is the subject of the failed logins of an IP.
+func IPSubject(ip string) string {
+	return "ip:" + ip
+}
+
+// swagger:parameters idUserLockPath
+type SwaggerUserLock struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	ID string
+}
@@ -0,0 +1,9 @@
+package model
+
+// Login is the body of the login, the scope is the space separated list of scopes requested for the tokens
+// and the remember me flag asks for a longer session.
+type Login struct {
+	User
+	Scope      string `json:"scope,omitempty"`
+	RememberMe bool   `json:"remember_me,omitempty"`
+}
@@ -0,0 +1,373 @@
+package model
+
+import (
+	authModel "food-api/infrastructure/auth/model"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Limits of the registration of an OAuth client
+const (
+	OAuthClientNameMaxLength = 100
+	OAuthMaxRedirectURIs     = 10
+)
+
+// OAuthClient is a third-party app that logs the users in with the authorization code flow. A confidential
+// client authenticates with its secret, only the hash of the secret is stored. A public client, e.g. a
+// mobile app, cannot keep a secret and only relies on PKCE. The scope is the most a client can request.
+// swagger:model
+type OAuthClient struct {
+	ID           string    `json:"client_id"`
+	UserID       string    `json:"user_id"`
+	Name         string    `json:"name"`
+	RedirectURIs []string  `json:"redirect_uris"`
+	Scope        string    `json:"scope"`
+	Confidential bool      `json:"confidential"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	SecretHash   string    `json:"-"`
+}
+
+// HasRedirectURI reports whether the redirect URI was registered, the URIs are compared exactly.
+func (oc *OAuthClient) HasRedirectURI(redirectURI string) bool {
+	for _, uri := range oc.RedirectURIs {
+		if uri == redirectURI {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CreatedOAuthClient is the client registered with its secret, the secret is only shown once.
+// swagger:model
+type CreatedOAuthClient struct {
+	OAuthClient
+	Secret string `json:"client_secret,omitempty"`
+}
+
+// OAuthClientRequest is the body to register an OAuth client, the scope is the space separated list of
+// the scopes of the API and of the OpenID Connect scopes the client can request.
+type OAuthClientRequest struct {
+	// Required: true
+	Name string `json:"name"`
+	// Required: true
+	RedirectURIs []string `json:"redirect_uris"`
+	// Required: true
+	Scope        string `json:"scope"`
+	Confidential bool   `json:"confidential"`
+}
+
+// Validate returns the errors of the request.
+func (cr *OAuthClientRequest) Validate() map[string]string {
+	var errorMessages = make(map[string]string)
+
+	cr.Name = strings.TrimSpace(cr.Name)
+	if cr.Name == "" {
+		errorMessages["name_required"] = "name is required"
+	}
+
+	if len(cr.Name) > OAuthClientNameMaxLength {
+		errorMessages["invalid_name"] = "name should be at most 100 characters"
+	}
+
+	if len(cr.RedirectURIs) == 0 {
+		errorMessages["redirect_uris_required"] = "redirect_uris is required"
+	}
+
+	if len(cr.RedirectURIs) > OAuthMaxRedirectURIs {
+		errorMessages["invalid_redirect_uris"] = "redirect_uris should have at most 10 URIs"
+	}
+
+	for _, redirectURI := range cr.RedirectURIs {
+		if !ValidRedirectURI(redirectURI) {
+			errorMessages["invalid_redirect_uri"] = "the redirect URIs should be https, http on loopback or a private-use scheme, without fragment"
+		}
+	}
+
+	scopes, identity, err := authModel.SplitScopes(cr.Scope)
+	if err != nil {
+		errorMessages["invalid_scope"] = err.Error()
+	} else if len(scopes)+len(identity) == 0 {
+		errorMessages["scope_required"] = "scope is required"
+	} else {
+		cr.Scope = authModel.FormatScopes(append(identity, scopes...))
+	}
+
+	return errorMessages
+}
+
+// ValidRedirectURI reports whether the URI can receive the authorization codes, see RFC 8252 for the
+// loopback and the private-use schemes of the native apps.
+func ValidRedirectURI(redirectURI string) bool {
+	uri, err := url.Parse(redirectURI)
+	if err != nil || uri.Fragment != "" || strings.Contains(redirectURI, "#") {
+		return false
+	}
+
+	switch uri.Scheme {
+	case "https":
+		return uri.Host != ""
+	case "http":
+		host := uri.Hostname()
+		return host == "localhost" || host == "127.0.0.1" || host == "::1"
+	default:
+		return strings.Contains(uri.Scheme, ".")
+	}
+}
+
+// AuthorizationRequest is the request of an OAuth client to act for the user, the code challenge of PKCE
+// is required and only the S256 method is supported.
+type AuthorizationRequest struct {
+	ResponseType        string `json:"response_type"`
+	ClientID            string `json:"client_id"`
+	RedirectURI         string `json:"redirect_uri"`
+	Scope               string `json:"scope,omitempty"`
+	State               string `json:"state,omitempty"`
+	Nonce               string `json:"nonce,omitempty"`
+	CodeChallenge       string `json:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method"`
+}
+
+// NewAuthorizationRequest returns the authorization request of the query parameters.
+func NewAuthorizationRequest(query url.Values) AuthorizationRequest {
+	return AuthorizationRequest{
+		ResponseType:        query.Get("response_type"),
+		ClientID:            query.Get("client_id"),
+		RedirectURI:         query.Get("redirect_uri"),
+		Scope:               query.Get("scope"),
+		State:               query.Get("state"),
+		Nonce:               query.Get("nonce"),
+		CodeChallenge:       query.Get("code_challenge"),
+		CodeChallengeMethod: query.Get("code_challenge_method"),
+	}
+}
+
+// AuthorizationDecision is the answer of the user on the consent screen.
+type AuthorizationDecision struct {
+	AuthorizationRequest
+	Approve bool `json:"approve"`
+}
+
+// OAuthConsent is what the consent screen shows to the user before the decision.
+// swagger:model
+type OAuthConsent struct {
+	ClientID    string   `json:"client_id"`
+	ClientName  string   `json:"client_name"`
+	Scopes      []string `json:"scopes"`
+	RedirectURI string   `json:"redirect_uri"`
+	State       string   `json:"state,omitempty"`
+}
+
+// OAuthRedirect is the URI of the client the browser of the user is sent to with the code or the error.
+// swagger:model
+type OAuthRedirect struct {
+	RedirectTo string `json:"redirect_to"`
+}
+
+// AuthorizationCode is the grant of the user to a client, it is exchanged once for the tokens.
+type AuthorizationCode struct {
+	ClientID      string    `json:"client_id"`
+	UserID        string    `json:"user_id"`
+	RedirectURI   string    `json:"redirect_uri"`
+	Scope         string    `json:"scope"`
+	Nonce         string    `json:"nonce,omitempty"`
+	CodeChallenge string    `json:"code_challenge"`
+	AuthTime      time.Time `json:"auth_time"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// OAuthToken is the response of the token endpoint, see RFC 6749.
+// swagger:model
+type OAuthToken struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+	Scope        string `json:"scope"`
+}
+
+// OAuthError is an error of the token endpoint, see RFC 6749.
+// swagger:model
+type OAuthError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// OpenIDConfiguration is the OpenID Connect discovery document, see OpenID Connect Discovery 1.0.
+// swagger:model
+type OpenIDConfiguration struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+// swagger:parameters getOAuthClients oauthUserInfo
+type SwaggerOAuthClients struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+}
+
+// swagger:parameters idOAuthClientPath deleteOAuthClient
+type SwaggerOAuthClientPath struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	ID string
+}
+
+// Information of a new OAuth client
+// swagger:parameters createOAuthClientRequest
+type SwaggerCreateOAuthClientRequest struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: body
+	Body OAuthClientRequest
+}
+
+// Authorization request of an OAuth client
+// swagger:parameters oauthAuthorize
+type SwaggerAuthorizationRequest struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: query
+	// Required: true
+	ResponseType string `json:"response_type"`
+	// in: query
+	// Required: true
+	ClientID string `json:"client_id"`
+	// in: query
+	// Required: true
+	RedirectURI string `json:"redirect_uri"`
+	// in: query
+	Scope string `json:"scope"`
+	// in: query
+	State string `json:"state"`
+	// in: query
+	Nonce string `json:"nonce"`
+	// in: query
+	// Required: true
+	CodeChallenge string `json:"code_challenge"`
+	// in: query
+	// Required: true
+	CodeChallengeMethod string `json:"code_challenge_method"`
+}
+
+// Decision of the user on the consent screen
+// swagger:parameters oauthConsent
+type SwaggerAuthorizationDecision struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: body
+	Body AuthorizationDecision
+}
+
+// Token request of an OAuth client, the client authenticates with HTTP Basic or with the form
+// swagger:parameters oauthToken
+type SwaggerTokenRequest struct {
+	// in: formData
+	// Required: true
+	GrantType string `json:"grant_type"`
+	// in: formData
+	Code string `json:"code"`
+	// in: formData
+	RedirectURI string `json:"redirect_uri"`
+	// in: formData
+	CodeVerifier string `json:"code_verifier"`
+	// in: formData
+	RefreshToken string `json:"refresh_token"`
+	// in: formData
+	ClientID string `json:"client_id"`
+	// in: formData
+	ClientSecret string `json:"client_secret"`
+}
+
+// OAuthClient It is the response of an OAuth client.
+// swagger:response SwaggerOAuthClientResponse
+type SwaggerOAuthClientResponse struct {
+	// in: body
+	Body OAuthClient
+}
+
+// OAuthClients It is the response of the OAuth clients of a user.
+// swagger:response SwaggerOAuthClientsResponse
+type SwaggerOAuthClientsResponse struct {
+	// in: body
+	Body []OAuthClient
+}
+
+// CreatedOAuthClient It is the response of a new OAuth client.
+// swagger:response SwaggerCreatedOAuthClientResponse
+type SwaggerCreatedOAuthClientResponse struct {
+	// in: body
+	Body CreatedOAuthClient
+}
+
+// OAuthConsent It is the response of the consent screen.
+// swagger:response SwaggerOAuthConsentResponse
+type SwaggerOAuthConsentResponse struct {
+	// in: body
+	Body OAuthConsent
+}
+
+// OAuthRedirect It is the response of the decision of the user.
+// swagger:response SwaggerOAuthRedirectResponse
+type SwaggerOAuthRedirectResponse struct {
+	// in: body
+	Body OAuthRedirect
+}
+
+// OAuthToken It is the response of the token endpoint.
+// swagger:response SwaggerOAuthTokenResponse
+type SwaggerOAuthTokenResponse struct {
+	// in: body
+	Body OAuthToken
+}
+
+// OAuthError It is an error of the token endpoint.
+// swagger:response SwaggerOAuthErrorResponse
+type SwaggerOAuthErrorResponse struct {
+	// in: body
+	Body OAuthError
+}
+
+// UserInfo It is the response of the claims of the user.
+// swagger:response SwaggerUserInfoResponse
+type SwaggerUserInfoResponse struct {
+	// in: body
+	Body map[string]interface{}
+}
+
+// OpenIDConfiguration It is the response of the discovery document.
+// swagger:response SwaggerOpenIDConfiguration
+type SwaggerOpenIDConfiguration struct {
+	// in: body
+	Body OpenIDConfiguration
+}
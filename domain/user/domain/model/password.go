@@ -0,0 +1,87 @@
+package model
+
+// PasswordMinLength is the minimum length of a password.
+const PasswordMinLength = 6
+
+// PasswordReset is the new password of a user with the token sent to reset it.
+type PasswordReset struct {
+	// Required: true
+	Token string `json:"token"`
+	// Required: true
+	Password string `json:"password"`
+}
+
+// Validate returns the errors of the reset, the token is checked when it is used.
+func (pr PasswordReset) Validate() map[string]string {
+	errorMessages := validatePassword(pr.Password)
+	if pr.Token == "" {
+		errorMessages["token_required"] = "token is required"
+	}
+
+	return errorMessages
+}
+
+// PasswordChange is the new password of a user with the current one.
+type PasswordChange struct {
+	// Required: true
+	CurrentPassword string `json:"current_password"`
+	// Required: true
+	Password string `json:"password"`
+}
+
+// Validate returns the errors of the change, the current password is checked against the stored one.
+func (pc PasswordChange) Validate() map[string]string {
+	errorMessages := validatePassword(pc.Password)
+	if pc.CurrentPassword == "" {
+		errorMessages["current_password_required"] = "current password is required"
+	}
+
+	return errorMessages
+}
+
+func validatePassword(password string) map[string]string {
+	var errorMessages = make(map[string]string)
+
+	if password == "" {
+		errorMessages["password_required"] = "password is required"
+	}
+
+	if password != "" && len(password) < PasswordMinLength {
+		errorMessages["invalid_password"] = "password should be at least 6 characters"
+	}
+
+	return errorMessages
+}
+
+// Information to request a password reset
+// swagger:parameters forgotPasswordRequest
+type SwaggerForgotPasswordRequest struct {
+	// in: body
+	Body struct {
+		// Required: true
+		Email string `json:"email"`
+	}
+}
+
+// Information to reset a password
+// swagger:parameters resetPasswordRequest
+type SwaggerResetPasswordRequest struct {
+	// in: body
+	Body PasswordReset
+}
+
+// Information to change the password of a user
+// swagger:parameters userPasswordRequest
+type SwaggerUserPasswordRequest struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	ID string
+
+	// in: body
+	Body PasswordChange
+}
@@ -0,0 +1,41 @@
+package model
+
+import authModel "food-api/infrastructure/auth/model"
+
+// RoleChange is the new role of a user.
+type RoleChange struct {
+	// Required: true
+	// Enum: user,moderator,admin
+	Role string `json:"role"`
+}
+
+// Validate returns the errors of the change.
+func (rc RoleChange) Validate() map[string]string {
+	var errorMessages = make(map[string]string)
+
+	if rc.Role == "" {
+		errorMessages["role_required"] = "role is required"
+	}
+
+	if rc.Role != "" && !authModel.ValidRole(rc.Role) {
+		errorMessages["invalid_role"] = "role must be user, moderator or admin"
+	}
+
+	return errorMessages
+}
+
+// Information to change the role of a user
+// swagger:parameters userRoleRequest
+type SwaggerUserRoleRequest struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	ID string
+
+	// in: body
+	Body RoleChange
+}
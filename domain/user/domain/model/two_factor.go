@@ -0,0 +1,127 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// TwoFactor is the TOTP secret of a user, it is pending until the user confirms a code.
+// LastStep is the time step of the last code used so a code cannot be replayed.
+type TwoFactor struct {
+	UserID    string
+	Secret    string
+	EnabledAt *time.Time
+	LastStep  int64
+}
+
+// Enabled reports whether the logins of the user require a code.
+func (tf *TwoFactor) Enabled() bool {
+	return tf != nil && tf.EnabledAt != nil
+}
+
+// TwoFactorEnrollment is the secret of a new enrollment, the otpauth URI is shown as a QR code.
+// swagger:model
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// RecoveryCodes are the single use codes that replace the TOTP code when the device is lost.
+// swagger:model
+type RecoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+// TwoFactorCode is a TOTP code or a recovery code of the user.
+type TwoFactorCode struct {
+	// Required: true
+	Code string `json:"code"`
+}
+
+// Validate returns the errors of the code, it is checked against the secret when it is used.
+func (tc *TwoFactorCode) Validate() map[string]string {
+	var errorMessages = make(map[string]string)
+
+	tc.Code = strings.TrimSpace(tc.Code)
+	if tc.Code == "" {
+		errorMessages["code_required"] = "code is required"
+	}
+
+	return errorMessages
+}
+
+// TwoFactorLogin is the second step of the login, the challenge token returned by the login with a code.
+type TwoFactorLogin struct {
+	// Required: true
+	ChallengeToken string `json:"challenge_token"`
+	TwoFactorCode
+}
+
+// Validate returns the errors of the second step of the login.
+func (tl *TwoFactorLogin) Validate() map[string]string {
+	errorMessages := tl.TwoFactorCode.Validate()
+	if tl.ChallengeToken == "" {
+		errorMessages["challenge_token_required"] = "challenge token is required"
+	}
+
+	return errorMessages
+}
+
+// TwoFactorChallenge is the login waiting for the code of the user, it keeps the options of the login.
+type TwoFactorChallenge struct {
+	UserID     string    `json:"user_id"`
+	Email      string    `json:"email"`
+	Scope      string    `json:"scope,omitempty"`
+	RememberMe bool      `json:"remember_me,omitempty"`
+	Attempts   int       `json:"attempts"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Information to confirm a code of the user
+// swagger:parameters userTwoFactorConfirmRequest userTwoFactorRecoveryRequest userTwoFactorDisableRequest
+type SwaggerUserTwoFactorCodeRequest struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	ID string
+
+	// in: body
+	Body TwoFactorCode
+}
+
+// swagger:parameters idUserTwoFactorPath
+type SwaggerUserTwoFactor struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	ID string
+}
+
+// Information of the second step of the login
+// swagger:parameters twoFactorLoginRequest
+type SwaggerTwoFactorLoginRequest struct {
+	// in: body
+	Body TwoFactorLogin
+}
+
+// TwoFactorEnrollment It is the response of the enrollment.
+// swagger:response SwaggerTwoFactorEnrollment
+type SwaggerTwoFactorEnrollment struct {
+	// in: body
+	Body TwoFactorEnrollment
+}
+
+// RecoveryCodes It is the response of the recovery codes.
+// swagger:response SwaggerRecoveryCodes
+type SwaggerRecoveryCodes struct {
+	// in: body
+	Body RecoveryCodes
+}
@@ -10,19 +10,21 @@ import (
 // Data of User
 // swagger:model
 type User struct {
-	ID           string     `json:"id,omitempty"`
+	ID              string     `json:"id,omitempty"`
 	// Required: true
-	Names        string     `json:"names,omitempty"`
+	Names           string     `json:"names,omitempty"`
 	// Required: true
-	LastNames    string     `json:"last_names,omitempty"`
+	LastNames       string     `json:"last_names,omitempty"`
 	// Required: true
-	Email        string     `json:"email,omitempty"`
+	Email           string     `json:"email,omitempty"`
 	// Required: true
-	Password     string     `json:"password,omitempty"`
-	PasswordHash string     `json:"-"`
-	CreatedAt    time.Time  `json:"-"`
-	UpdatedAt    time.Time  `json:"-"`
-	DeletedAt    *time.Time `json:"-"`
+	Password        string     `json:"password,omitempty"`
+	PasswordHash    string     `json:"-"`
+	EmailVerifiedAt *time.Time `json:"-"`
+	Role            string     `json:"-"`
+	CreatedAt       time.Time  `json:"-"`
+	UpdatedAt       time.Time  `json:"-"`
+	DeletedAt       *time.Time `json:"-"`
 }
 
 // Information from user
@@ -76,6 +78,30 @@ type SwaggerUser struct {
 	ID string
 }
 
+// swagger:parameters idUserFollowPath
+type SwaggerUserFollow struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	ID string
+}
+
+// swagger:parameters idUserDeletePath
+type SwaggerUserDelete struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	ID string
+}
+
 // HashPassword generates a hash of the password and places the result in PasswordHash.
 func (u *User) HashPassword() error {
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"os"
+	"strconv"
+)
+
+// RequireEmailVerification reports whether the users must verify their email to log in, it is set
+// with the REQUIRE_EMAIL_VERIFICATION env var and it is disabled by default.
+func RequireEmailVerification() bool {
+	required, _ := strconv.ParseBool(os.Getenv("REQUIRE_EMAIL_VERIFICATION"))
+	return required
+}
+
+// Token to verify the email of a user
+// swagger:parameters verifyEmailRequest
+type SwaggerVerifyEmailRequest struct {
+	// in: query
+	// Required: true
+	Token string `json:"token"`
+}
+
+// Information to send the verification email again
+// swagger:parameters resendVerificationRequest
+type SwaggerResendVerificationRequest struct {
+	// in: body
+	Body struct {
+		// Required: true
+		Email string `json:"email"`
+	}
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"food-api/domain/user/domain/model"
+	"time"
+)
+
+// APIKeyRepository keeps the API keys of the users.
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *model.APIKey) error
+	GetAPIKeys(ctx context.Context, userId string) ([]model.APIKey, error)
+	GetAPIKey(ctx context.Context, userId, id string) (*model.APIKey, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (*model.APIKey, error)
+	UpdateAPIKey(ctx context.Context, key *model.APIKey) error
+	DeleteAPIKey(ctx context.Context, userId, id string) error
+	DeleteUserAPIKeys(ctx context.Context, userId string) error
+	TouchAPIKey(ctx context.Context, id string, at time.Time) error
+}
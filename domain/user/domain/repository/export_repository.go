@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"food-api/domain/user/application/v1/response"
+)
+
+type ExportRepository interface {
+	CountUserFoods(ctx context.Context, userId string) (int, error)
+	GetUserExport(ctx context.Context, userId string) (*response.UserExportResponse, error)
+}
+
+type ExportJobRepository interface {
+	SaveExportJob(ctx context.Context, job *response.ExportJobResponse) error
+	GetExportJobById(ctx context.Context, id string) (*response.ExportJobResponse, error)
+	DeleteExportJob(ctx context.Context, id string) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"food-api/domain/user/domain/model"
+	"time"
+)
+
+// LoginAttemptRepository counts the failed logins of the emails and the IPs and locks them.
+type LoginAttemptRepository interface {
+	LockedFor(ctx context.Context, subjects []string) (time.Duration, error)
+	Fail(ctx context.Context, subject string, policy model.LockoutPolicy) (time.Duration, error)
+	Reset(ctx context.Context, subject string) error
+}
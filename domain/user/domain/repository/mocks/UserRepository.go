@@ -4,11 +4,11 @@ package mocks
 
 import (
 	context "context"
+	response "food-api/domain/user/application/v1/response"
 	model "food-api/domain/user/domain/model"
+	time "time"
 
 	mock "github.com/stretchr/testify/mock"
-
-	response "food-api/domain/user/application/v1/response"
 )
 
 // UserRepository is an autogenerated mock type for the UserRepository type
@@ -39,6 +39,34 @@ func (_m *UserRepository) CreateUser(ctx context.Context, user *model.User) (*re
 	return r0, r1
 }
 
+// DeleteUser provides a mock function with given fields: ctx, deletion
+func (_m *UserRepository) DeleteUser(ctx context.Context, deletion *model.Deletion) error {
+	ret := _m.Called(ctx, deletion)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Deletion) error); ok {
+		r0 = rf(ctx, deletion)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FollowUser provides a mock function with given fields: ctx, userId, followerId
+func (_m *UserRepository) FollowUser(ctx context.Context, userId string, followerId string) error {
+	ret := _m.Called(ctx, userId, followerId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userId, followerId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetAllUser provides a mock function with given fields: ctx
 func (_m *UserRepository) GetAllUser(ctx context.Context) ([]response.UserResponse, error) {
 	ret := _m.Called(ctx)
@@ -62,6 +90,27 @@ func (_m *UserRepository) GetAllUser(ctx context.Context) ([]response.UserRespon
 	return r0, r1
 }
 
+// GetByEmail provides a mock function with given fields: ctx, email
+func (_m *UserRepository) GetByEmail(ctx context.Context, email string) (response.UserResponse, error) {
+	ret := _m.Called(ctx, email)
+
+	var r0 response.UserResponse
+	if rf, ok := ret.Get(0).(func(context.Context, string) response.UserResponse); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(response.UserResponse)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetById provides a mock function with given fields: ctx, id
 func (_m *UserRepository) GetById(ctx context.Context, id string) (response.UserResponse, error) {
 	ret := _m.Called(ctx, id)
@@ -106,6 +155,69 @@ func (_m *UserRepository) GetUserByEmailAndPassword(ctx context.Context, user *m
 	return r0, r1
 }
 
+// PromoteAdmins provides a mock function with given fields: ctx, emails, updatedAt
+func (_m *UserRepository) PromoteAdmins(ctx context.Context, emails []string, updatedAt time.Time) (int64, error) {
+	ret := _m.Called(ctx, emails, updatedAt)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, []string, time.Time) int64); ok {
+		r0 = rf(ctx, emails, updatedAt)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string, time.Time) error); ok {
+		r1 = rf(ctx, emails, updatedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnfollowUser provides a mock function with given fields: ctx, userId, followerId
+func (_m *UserRepository) UnfollowUser(ctx context.Context, userId string, followerId string) error {
+	ret := _m.Called(ctx, userId, followerId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userId, followerId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePassword provides a mock function with given fields: ctx, id, passwordHash, updatedAt
+func (_m *UserRepository) UpdatePassword(ctx context.Context, id string, passwordHash string, updatedAt time.Time) error {
+	ret := _m.Called(ctx, id, passwordHash, updatedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) error); ok {
+		r0 = rf(ctx, id, passwordHash, updatedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateRole provides a mock function with given fields: ctx, id, role, updatedAt
+func (_m *UserRepository) UpdateRole(ctx context.Context, id string, role string, updatedAt time.Time) error {
+	ret := _m.Called(ctx, id, role, updatedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) error); ok {
+		r0 = rf(ctx, id, role, updatedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateUser provides a mock function with given fields: ctx, id, user
 func (_m *UserRepository) UpdateUser(ctx context.Context, id string, user model.User) error {
 	ret := _m.Called(ctx, id, user)
@@ -119,3 +231,17 @@ func (_m *UserRepository) UpdateUser(ctx context.Context, id string, user model.
 
 	return r0
 }
+
+// VerifyEmail provides a mock function with given fields: ctx, id, email, verifiedAt
+func (_m *UserRepository) VerifyEmail(ctx context.Context, id string, email string, verifiedAt time.Time) error {
+	ret := _m.Called(ctx, id, email, verifiedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) error); ok {
+		r0 = rf(ctx, id, email, verifiedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
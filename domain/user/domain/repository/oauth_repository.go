@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"food-api/domain/user/domain/model"
+)
+
+// OAuthClientRepository keeps the OAuth clients registered by the users.
+type OAuthClientRepository interface {
+	CreateClient(ctx context.Context, client *model.OAuthClient) error
+	GetClients(ctx context.Context, userId string) ([]model.OAuthClient, error)
+	GetClient(ctx context.Context, id string) (*model.OAuthClient, error)
+	DeleteClient(ctx context.Context, userId, id string) error
+}
+
+// AuthorizationCodeRepository keeps the authorization codes until the clients exchange them.
+type AuthorizationCodeRepository interface {
+	SaveCode(ctx context.Context, code string, authorization *model.AuthorizationCode) error
+	ConsumeCode(ctx context.Context, code string) (*model.AuthorizationCode, error)
+}
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type ResetTokenRepository interface {
+	SaveResetToken(ctx context.Context, userId, token string, ttl time.Duration) error
+	ConsumeResetToken(ctx context.Context, token string) (string, error)
+}
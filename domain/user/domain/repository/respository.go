@@ -4,12 +4,21 @@ import (
 	"context"
 	"food-api/domain/user/application/v1/response"
 	"food-api/domain/user/domain/model"
+	"time"
 )
 
 type UserRepository interface {
 	GetAllUser(ctx context.Context) ([]response.UserResponse, error)
 	GetById(ctx context.Context, id string) (response.UserResponse, error)
+	GetByEmail(ctx context.Context, email string) (response.UserResponse, error)
 	CreateUser(ctx context.Context, user *model.User) (*response.UserResponse, error)
 	UpdateUser(ctx context.Context, id string, user model.User) error
 	GetUserByEmailAndPassword(ctx context.Context, user *model.User) (*response.UserResponse, error)
+	FollowUser(ctx context.Context, userId, followerId string) error
+	UnfollowUser(ctx context.Context, userId, followerId string) error
+	DeleteUser(ctx context.Context, deletion *model.Deletion) error
+	UpdatePassword(ctx context.Context, id, passwordHash string, updatedAt time.Time) error
+	VerifyEmail(ctx context.Context, id, email string, verifiedAt time.Time) error
+	UpdateRole(ctx context.Context, id, role string, updatedAt time.Time) error
+	PromoteAdmins(ctx context.Context, emails []string, updatedAt time.Time) (int64, error)
 }
\ No newline at end of file
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"food-api/domain/user/domain/model"
+	"time"
+)
+
+// TwoFactorRepository keeps the TOTP secrets and the recovery codes of the users.
+type TwoFactorRepository interface {
+	GetTwoFactor(ctx context.Context, userId string) (*model.TwoFactor, error)
+	SaveSecret(ctx context.Context, userId, secret string, at time.Time) error
+	Enable(ctx context.Context, userId string, recoveryCodes []string, at time.Time) error
+	ReplaceRecoveryCodes(ctx context.Context, userId string, recoveryCodes []string, at time.Time) error
+	Disable(ctx context.Context, userId string) error
+	UseStep(ctx context.Context, userId string, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userId, recoveryCode string, at time.Time) (bool, error)
+}
+
+// TwoFactorChallengeRepository keeps the logins waiting for the code of the user.
+type TwoFactorChallengeRepository interface {
+	SaveChallenge(ctx context.Context, token string, challenge *model.TwoFactorChallenge) error
+	GetChallenge(ctx context.Context, token string) (*model.TwoFactorChallenge, error)
+	ConsumeChallenge(ctx context.Context, token string) (*model.TwoFactorChallenge, error)
+}
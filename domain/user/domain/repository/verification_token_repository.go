@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// VerificationTokenRepository keeps the single use tokens that verify the email of the users, a token only
+// verifies the email it was sent to.
+type VerificationTokenRepository interface {
+	SaveVerificationToken(ctx context.Context, userId, email, token string, ttl time.Duration) error
+	ConsumeVerificationToken(ctx context.Context, token string) (string, string, error)
+	DeleteVerificationToken(ctx context.Context, userId string) error
+	ThrottleResend(ctx context.Context, email string, interval time.Duration) (time.Duration, error)
+}
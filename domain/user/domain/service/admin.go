@@ -0,0 +1,19 @@
+package service
+
+import (
+	"os"
+	"strings"
+)
+
+// AdminEmails returns the emails of the ADMIN_EMAILS env var separated by commas, the users of these
+// emails are promoted to administrators when the API starts.
+func AdminEmails() []string {
+	emails := make([]string, 0)
+	for _, email := range strings.Split(os.Getenv("ADMIN_EMAILS"), ",") {
+		if email = strings.TrimSpace(email); email != "" {
+			emails = append(emails, email)
+		}
+	}
+
+	return emails
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"food-api/domain/user/domain/repository"
+	"food-api/infrastructure/auth"
+	authModel "food-api/infrastructure/auth/model"
+	"log"
+	"strings"
+	"time"
+)
+
+// apiKeyPrefix starts every API key so the secret scanners and the users recognize them.
+const apiKeyPrefix = "fapi"
+
+// apiKeyPrefixSize is the random bytes of the prefix, the prefix is unique so it must not collide.
+const apiKeyPrefixSize = 8
+
+// NewAPIKey returns a random API key and its prefix, the key is "fapi_<prefix>_<secret>".
+func NewAPIKey() (string, string, error) {
+	prefix := make([]byte, apiKeyPrefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return "", "", err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+
+	id := hex.EncodeToString(prefix)
+
+	return id, apiKeyPrefix + "_" + id + "_" + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// APIKeyPrefix returns the prefix that identifies the key.
+func APIKeyPrefix(key string) (string, bool) {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyPrefix || parts[1] == "" || parts[2] == "" {
+		return "", false
+	}
+
+	return parts[1], true
+}
+
+// HashAPIKey returns the hash of the key that is stored, the keys are random so a fast hash is enough.
+func HashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// APIKeyAuthenticator verifies the API keys of the AuthMiddleware.
+type APIKeyAuthenticator struct {
+	Keys repository.APIKeyRepository
+}
+
+// NewAPIKeyAuthenticator returns the verifier of the API keys of the repository.
+func NewAPIKeyAuthenticator(keys repository.APIKeyRepository) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{Keys: keys}
+}
+
+// VerifyAPIKey returns the access details of the user of the key with the scopes of the key and records its
+// use, a failure to record it is only logged.
+func (aa *APIKeyAuthenticator) VerifyAPIKey(ctx context.Context, key string) (*authModel.AccessDetails, error) {
+	prefix, ok := APIKeyPrefix(key)
+	if !ok {
+		return nil, auth.ErrAPIKeyInvalid
+	}
+
+	apiKey, err := aa.Keys.GetAPIKeyByPrefix(ctx, prefix)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, auth.ErrAPIKeyInvalid
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if subtle.ConstantTimeCompare([]byte(HashAPIKey(key)), []byte(apiKey.KeyHash)) != 1 || apiKey.Expired(now) {
+		return nil, auth.ErrAPIKeyInvalid
+	}
+
+	scopes, err := authModel.ParseScopes(apiKey.Scope)
+	if err != nil {
+		return nil, auth.ErrAPIKeyInvalid
+	}
+
+	if err = aa.Keys.TouchAPIKey(ctx, apiKey.ID, now); err != nil {
+		log.Printf("cannot update the last use of the API key %s: %s", apiKey.ID, err.Error())
+	}
+
+	return &authModel.AccessDetails{UserId: apiKey.UserID, Role: apiKey.Role, Scopes: scopes, APIKeyID: apiKey.ID}, nil
+}
+
+// APIKeyScopes returns the scopes of a new API key, they must be a subset of the scopes of the token that
+// creates it. An empty scope takes the ones of the token.
+func APIKeyScopes(scope string, tokenScopes []string) ([]string, error) {
+	if strings.TrimSpace(scope) == "" {
+		return tokenScopes, nil
+	}
+
+	scopes, err := authModel.ParseScopes(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, requested := range scopes {
+		if !authModel.HasScope(tokenScopes, requested) {
+			return nil, errors.New("the token does not have the " + requested + " scope")
+		}
+	}
+
+	return scopes, nil
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"food-api/domain/user/domain/model"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Defaults of the lockout of the logins
+const (
+	defaultLoginMaxAttempts      = 5
+	defaultLoginMaxAttemptsPerIP = 20
+	defaultLoginLockout          = time.Minute
+	defaultLoginLockoutMax       = time.Hour
+	defaultLoginAttemptsWindow   = 15 * time.Minute
+)
+
+// AccountLockoutPolicy returns the lockout of an email, the LOGIN_MAX_ATTEMPTS env var sets the failures
+// before the lockout.
+func AccountLockoutPolicy() model.LockoutPolicy {
+	return lockoutPolicy(intEnv("LOGIN_MAX_ATTEMPTS", defaultLoginMaxAttempts))
+}
+
+// IPLockoutPolicy returns the lockout of an IP, the LOGIN_MAX_ATTEMPTS_PER_IP env var sets the failures
+// before the lockout. It is higher than the one of an email because an IP is shared by many users.
+func IPLockoutPolicy() model.LockoutPolicy {
+	return lockoutPolicy(intEnv("LOGIN_MAX_ATTEMPTS_PER_IP", defaultLoginMaxAttemptsPerIP))
+}
+
+// lockoutPolicy reads the LOGIN_LOCKOUT, LOGIN_LOCKOUT_MAX and LOGIN_ATTEMPTS_WINDOW env vars.
+func lockoutPolicy(maxAttempts int64) model.LockoutPolicy {
+	return model.LockoutPolicy{
+		MaxAttempts: maxAttempts,
+		Base:        durationEnv("LOGIN_LOCKOUT", defaultLoginLockout),
+		Max:         durationEnv("LOGIN_LOCKOUT_MAX", defaultLoginLockoutMax),
+		Window:      durationEnv("LOGIN_ATTEMPTS_WINDOW", defaultLoginAttemptsWindow),
+	}
+}
+
+func intEnv(key string, defaultValue int64) int64 {
+	value, err := strconv.ParseInt(os.Getenv(key), 10, 64)
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+
+	return value
+}
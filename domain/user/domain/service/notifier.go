@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"food-api/domain/user/application/v1/response"
+	"net/url"
+	"os"
+)
+
+// defaultPasswordResetURL is the page that resets the password when PASSWORD_RESET_URL is not set.
+const defaultPasswordResetURL = "http://localhost:8888/reset-password"
+
+// defaultEmailVerificationURL is the endpoint that verifies the email when EMAIL_VERIFICATION_URL is not set.
+const defaultEmailVerificationURL = "http://localhost:8888/api/verify-email"
+
+// Notifier sends the messages of the account to the user.
+type Notifier interface {
+	PasswordReset(ctx context.Context, user response.UserResponse, token string) error
+	VerifyEmail(ctx context.Context, user response.UserResponse, token string) error
+}
+
+// PasswordResetLink returns the link of the PASSWORD_RESET_URL page with the token.
+func PasswordResetLink(token string) string {
+	return tokenLink(os.Getenv("PASSWORD_RESET_URL"), defaultPasswordResetURL, token)
+}
+
+// EmailVerificationLink returns the link of the EMAIL_VERIFICATION_URL endpoint with the token.
+func EmailVerificationLink(token string) string {
+	return tokenLink(os.Getenv("EMAIL_VERIFICATION_URL"), defaultEmailVerificationURL, token)
+}
+
+func tokenLink(link, defaultLink, token string) string {
+	if link == "" {
+		link = defaultLink
+	}
+
+	return link + "?token=" + url.QueryEscape(token)
+}
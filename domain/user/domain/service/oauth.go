@@ -0,0 +1,232 @@
+package service
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"food-api/domain/user/application/v1/response"
+	"food-api/domain/user/domain/model"
+	authModel "food-api/infrastructure/auth/model"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Values of the OAuth requests supported by the authorization server
+const (
+	ResponseTypeCode           = "code"
+	CodeChallengeMethodS256    = "S256"
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeRefreshToken      = "refresh_token"
+)
+
+// Defaults of the authorization server
+const (
+	defaultOAuthIssuer          = "http://localhost:8888"
+	defaultAuthorizationCodeTTL = time.Minute
+)
+
+// Errors of the authorization requests, they are sent to the redirect URI of the client.
+var (
+	ErrUnsupportedResponseType = errors.New("only the code response type is supported")
+	ErrCodeChallengeRequired   = errors.New("a S256 code_challenge is required")
+)
+
+// authorizationErrorCodes are the error codes of RFC 6749 §4.1.2.1 of the errors of the authorization requests.
+var authorizationErrorCodes = map[error]string{
+	ErrUnsupportedResponseType: "unsupported_response_type",
+	ErrCodeChallengeRequired:   "invalid_request",
+}
+
+// ErrRedirectURIInvalid is returned when the redirect URI was not registered by the client, the user is not
+// sent to it.
+var ErrRedirectURIInvalid = errors.New("the redirect_uri is not registered for the client")
+
+// codeVerifierPattern is the format of a PKCE code verifier, see RFC 7636.
+var codeVerifierPattern = regexp.MustCompile(`^[A-Za-z0-9\-._~]{43,128}$`)
+
+// codeChallengePattern is the format of a S256 code challenge, a base64url SHA-256 without padding.
+var codeChallengePattern = regexp.MustCompile(`^[A-Za-z0-9\-_]{43}$`)
+
+// OAuthIssuer returns the OAUTH_ISSUER env var, the public URL of the API that identifies the authorization
+// server in the ID tokens and the discovery document.
+func OAuthIssuer() string {
+	issuer := os.Getenv("OAUTH_ISSUER")
+	if issuer == "" {
+		issuer = defaultOAuthIssuer
+	}
+
+	return strings.TrimSuffix(issuer, "/")
+}
+
+// OAuthConsentURL returns the OAUTH_CONSENT_URL env var, the page of the frontend where the users log in and
+// approve the clients. It is the authorization endpoint of the discovery document.
+func OAuthConsentURL() string {
+	consentURL := os.Getenv("OAUTH_CONSENT_URL")
+	if consentURL == "" {
+		consentURL = OAuthIssuer() + "/oauth/authorize"
+	}
+
+	return consentURL
+}
+
+// AuthorizationCodeTTL returns the OAUTH_CODE_TTL env var, how long a client has to exchange a code.
+func AuthorizationCodeTTL() time.Duration {
+	return durationEnv("OAUTH_CODE_TTL", defaultAuthorizationCodeTTL)
+}
+
+// HashClientSecret returns the hash of the secret that is stored, the secrets are random so a fast hash
+// is enough.
+func HashClientSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// VerifyClientSecret reports whether the secret is the one of the confidential client.
+func VerifyClientSecret(client *model.OAuthClient, secret string) bool {
+	if !client.Confidential || secret == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(HashClientSecret(secret)), []byte(client.SecretHash)) == 1
+}
+
+// CheckAuthorizationRequest returns the error of the parameters of the request that are not about the
+// client, the redirect URI and the scope.
+func CheckAuthorizationRequest(request model.AuthorizationRequest) error {
+	if request.ResponseType != ResponseTypeCode {
+		return ErrUnsupportedResponseType
+	}
+
+	if request.CodeChallengeMethod != CodeChallengeMethodS256 || !codeChallengePattern.MatchString(request.CodeChallenge) {
+		return ErrCodeChallengeRequired
+	}
+
+	return nil
+}
+
+// VerifyCodeVerifier reports whether the PKCE code verifier is the one of the S256 challenge.
+func VerifyCodeVerifier(verifier, challenge string) bool {
+	if !codeVerifierPattern.MatchString(verifier) {
+		return false
+	}
+
+	hash := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(hash[:])
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+// ClientScopes returns the scopes the client requests, all the scopes of the client when it requests none.
+// The scopes of the API must also be in the scopes of the login that approves them.
+func ClientScopes(client *model.OAuthClient, scope string, loginScopes []string) ([]string, error) {
+	if strings.TrimSpace(scope) == "" {
+		scope = client.Scope
+	}
+
+	scopes, identity, err := authModel.SplitScopes(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := strings.Fields(client.Scope)
+	granted := append(identity, scopes...)
+	for _, s := range granted {
+		if !authModel.HasScope(allowed, s) {
+			return nil, fmt.Errorf("the client cannot request the %s scope", s)
+		}
+	}
+
+	for _, s := range scopes {
+		if !authModel.HasScope(loginScopes, s) {
+			return nil, fmt.Errorf("the token does not have the %s scope", s)
+		}
+	}
+
+	if len(granted) == 0 {
+		return nil, errors.New("the client has no scope")
+	}
+
+	return granted, nil
+}
+
+// AuthorizationErrorParams returns the query parameters the redirect URI receives for an error of the
+// authorization request, see RFC 6749 §4.1.2.1.
+func AuthorizationErrorParams(err error, state string) url.Values {
+	code, ok := authorizationErrorCodes[err]
+	if !ok {
+		code = "invalid_request"
+	}
+
+	params := url.Values{}
+	params.Set("error", code)
+	params.Set("error_description", err.Error())
+	if state != "" {
+		params.Set("state", state)
+	}
+
+	return params
+}
+
+// AuthorizationRedirect returns the redirect URI with the parameters added to its query.
+func AuthorizationRedirect(redirectURI string, params url.Values) string {
+	uri, err := url.Parse(redirectURI)
+	if err != nil {
+		return redirectURI
+	}
+
+	query := uri.Query()
+	for name, values := range params {
+		for _, value := range values {
+			query.Add(name, value)
+		}
+	}
+
+	uri.RawQuery = query.Encode()
+
+	return uri.String()
+}
+
+// UserClaims returns the claims of the user released by the OpenID Connect scopes.
+func UserClaims(user response.UserResponse, identityScopes []string) map[string]interface{} {
+	claims := map[string]interface{}{}
+
+	if authModel.HasScope(identityScopes, authModel.ScopeProfile) {
+		claims["name"] = strings.TrimSpace(user.Names + " " + user.LastNames)
+		claims["given_name"] = user.Names
+		claims["family_name"] = user.LastNames
+	}
+
+	if authModel.HasScope(identityScopes, authModel.ScopeEmail) {
+		claims["email"] = user.Email
+		claims["email_verified"] = user.EmailVerifiedAt != nil
+	}
+
+	return claims
+}
+
+// NewOpenIDConfiguration returns the discovery document of the issuer, the algorithms are the ones of the
+// signing keys.
+func NewOpenIDConfiguration(issuer string, algorithms []string) model.OpenIDConfiguration {
+	return model.OpenIDConfiguration{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             OAuthConsentURL(),
+		TokenEndpoint:                     issuer + "/api/oauth/token",
+		UserInfoEndpoint:                  issuer + "/api/v1/oauth/userinfo",
+		JWKSURI:                           issuer + "/.well-known/jwks.json",
+		ScopesSupported:                   append(append([]string(nil), authModel.IdentityScopes...), authModel.AllScopes...),
+		ResponseTypesSupported:            []string{ResponseTypeCode},
+		GrantTypesSupported:               []string{GrantTypeAuthorizationCode, GrantTypeRefreshToken},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  algorithms,
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{CodeChallengeMethodS256},
+		ClaimsSupported: []string{"iss", "sub", "aud", "exp", "iat", "auth_time", "nonce", "name", "given_name",
+			"family_name", "email", "email_verified"},
+	}
+}
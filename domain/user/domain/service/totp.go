@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"food-api/domain/user/domain/model"
+	"food-api/domain/user/domain/repository"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// TOTP parameters of RFC 6238, the authenticator apps only support these ones.
+const (
+	totpDigits = 6
+	totpPeriod = 30 * time.Second
+	// totpSkew is how many steps before and after the current one are accepted for the clock drift
+	totpSkew = 1
+	// totpSecretSize is the size of the secret, 160 bits as recommended for HMAC-SHA1
+	totpSecretSize = 20
+)
+
+// recoveryCodeCount is how many recovery codes a user has.
+const recoveryCodeCount = 10
+
+// MaxTwoFactorAttempts is how many codes can be tried with a challenge before it is invalid.
+const MaxTwoFactorAttempts = 5
+
+// defaultTwoFactorChallengeTTL is how long the challenge of a login waits for the code.
+const defaultTwoFactorChallengeTTL = 5 * time.Minute
+
+// defaultTOTPIssuer is the issuer shown by the authenticator apps when TOTP_ISSUER is not set.
+const defaultTOTPIssuer = "food-api"
+
+// ErrTwoFactorCodeInvalid is returned for a wrong, expired or already used code.
+var ErrTwoFactorCodeInvalid = errors.New("the code is invalid")
+
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewTOTPSecret returns a random secret encoded in base32 as the authenticator apps expect it.
+func NewTOTPSecret() (string, error) {
+	secret := make([]byte, totpSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return base32NoPadding.EncodeToString(secret), nil
+}
+
+// TOTPURI returns the otpauth URI of the secret, the authenticator apps read it from a QR code.
+func TOTPURI(account, secret string) string {
+	issuer := os.Getenv("TOTP_ISSUER")
+	if issuer == "" {
+		issuer = defaultTOTPIssuer
+	}
+
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(int(totpPeriod.Seconds())))
+
+	uri := url.URL{Scheme: "otpauth", Host: "totp", Path: "/" + issuer + ":" + account, RawQuery: query.Encode()}
+
+	return uri.String()
+}
+
+// TOTPStep returns the time step of the instant.
+func TOTPStep(at time.Time) int64 {
+	return at.Unix() / int64(totpPeriod.Seconds())
+}
+
+// TOTPCode returns the code of the secret at the time step.
+func TOTPCode(secret string, step int64) (string, error) {
+	key, err := base32NoPadding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return "", err
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000), nil
+}
+
+// VerifyTOTP returns the time step of the code when it is valid around the instant.
+func VerifyTOTP(secret, code string, at time.Time) (int64, bool) {
+	if len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := TOTPStep(at)
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		expected, err := TOTPCode(secret, step)
+		if err != nil {
+			return 0, false
+		}
+
+		if hmac.Equal([]byte(expected), []byte(code)) {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// NewRecoveryCodes returns the recovery codes shown once to the user, only their hash is stored.
+func NewRecoveryCodes() ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		random := make([]byte, 7)
+		if _, err := rand.Read(random); err != nil {
+			return nil, err
+		}
+
+		code := strings.ToLower(base32NoPadding.EncodeToString(random))[:10]
+		codes[i] = code[:5] + "-" + code[5:]
+	}
+
+	return codes, nil
+}
+
+// NormalizeRecoveryCode returns the code as it is hashed, the users may type it without the dash or
+// in uppercase.
+func NormalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// CheckTwoFactorCode checks the TOTP code or the recovery code of the user and marks it as used so it
+// cannot be used again.
+func CheckTwoFactorCode(ctx context.Context, repo repository.TwoFactorRepository, twoFactor *model.TwoFactor,
+	code string, at time.Time) error {
+	if twoFactor == nil || twoFactor.Secret == "" {
+		return ErrTwoFactorCodeInvalid
+	}
+
+	if len(code) == totpDigits {
+		step, ok := VerifyTOTP(twoFactor.Secret, code, at)
+		if !ok || step <= twoFactor.LastStep {
+			return ErrTwoFactorCodeInvalid
+		}
+
+		used, err := repo.UseStep(ctx, twoFactor.UserID, step)
+		if err != nil {
+			return err
+		}
+
+		if !used {
+			return ErrTwoFactorCodeInvalid
+		}
+
+		return nil
+	}
+
+	if !twoFactor.Enabled() {
+		return ErrTwoFactorCodeInvalid
+	}
+
+	used, err := repo.UseRecoveryCode(ctx, twoFactor.UserID, NormalizeRecoveryCode(code), at)
+	if err != nil {
+		return err
+	}
+
+	if !used {
+		return ErrTwoFactorCodeInvalid
+	}
+
+	return nil
+}
+
+// TwoFactorChallengeTTL returns how long the challenge of a login waits for the code, it is set with the
+// TWO_FACTOR_CHALLENGE_TTL env var.
+func TwoFactorChallengeTTL() time.Duration {
+	return durationEnv("TWO_FACTOR_CHALLENGE_TTL", defaultTwoFactorChallengeTTL)
+}
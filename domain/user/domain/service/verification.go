@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"food-api/domain/user/application/v1/response"
+	"food-api/domain/user/domain/repository"
+	"os"
+	"time"
+)
+
+// defaultPasswordResetTTL is how long a reset token can be used.
+const defaultPasswordResetTTL = time.Hour
+
+// defaultEmailVerificationTTL is how long a verification token can be used.
+const defaultEmailVerificationTTL = 24 * time.Hour
+
+// defaultEmailVerificationResendInterval is the minimum time between two verification emails to the same email.
+const defaultEmailVerificationResendInterval = time.Minute
+
+// NewToken returns a random secret sent to the user, e.g. to reset the password or verify the email.
+func NewToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(token), nil
+}
+
+// SendEmailVerification issues a verification token for the user, the previous one is invalidated,
+// and sends it to the email of the user.
+func SendEmailVerification(ctx context.Context, tokens repository.VerificationTokenRepository, notifier Notifier,
+	user response.UserResponse) error {
+	token, err := NewToken()
+	if err != nil {
+		return err
+	}
+
+	if err = tokens.SaveVerificationToken(ctx, user.ID, user.Email, token, EmailVerificationTTL()); err != nil {
+		return err
+	}
+
+	return notifier.VerifyEmail(ctx, user, token)
+}
+
+// PasswordResetTTL returns how long a reset token can be used, it is set with the PASSWORD_RESET_TTL env var.
+func PasswordResetTTL() time.Duration {
+	return durationEnv("PASSWORD_RESET_TTL", defaultPasswordResetTTL)
+}
+
+// EmailVerificationTTL returns how long a verification token can be used, it is set with the
+// EMAIL_VERIFICATION_TTL env var.
+func EmailVerificationTTL() time.Duration {
+	return durationEnv("EMAIL_VERIFICATION_TTL", defaultEmailVerificationTTL)
+}
+
+// EmailVerificationResendInterval returns the minimum time between two verification emails to the
+// same email, it is set with the EMAIL_VERIFICATION_RESEND_INTERVAL env var.
+func EmailVerificationResendInterval() time.Duration {
+	return durationEnv("EMAIL_VERIFICATION_RESEND_INTERVAL", defaultEmailVerificationResendInterval)
+}
+
+func durationEnv(key string, defaultDuration time.Duration) time.Duration {
+	duration, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || duration <= 0 {
+		return defaultDuration
+	}
+
+	return duration
+}
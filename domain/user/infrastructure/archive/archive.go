@@ -0,0 +1,92 @@
+package archive
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"food-api/domain/user/application/v1/response"
+	"io"
+	"time"
+)
+
+// ContentType is the content type of the archive.
+const ContentType = "application/zip"
+
+// imageEntry is an image of the manifest, the file is empty when the image could not be downloaded.
+type imageEntry struct {
+	FoodID string `json:"food_id"`
+	URL    string `json:"url"`
+	File   string `json:"file,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Write writes a ZIP archive with a JSON file for the profile, the foods, the favorites and ratings, the
+// followers and the followed users. The images of the foods are downloaded with the fetcher and saved in
+// the images folder, the food points to the file. The images.json manifest keeps the URL of every image,
+// an image that cannot be downloaded is kept as a URL with the error. A nil fetcher only writes the manifest.
+func Write(ctx context.Context, w io.Writer, export *response.UserExportResponse, images ImageFetcher, now time.Time) error {
+	archive := zip.NewWriter(w)
+
+	foods := make([]response.ExportFoodResponse, len(export.Foods))
+	copy(foods, export.Foods)
+
+	manifest := make([]imageEntry, 0)
+	for i := range foods {
+		if foods[i].FoodImage == "" {
+			continue
+		}
+
+		entry := imageEntry{FoodID: foods[i].ID, URL: foods[i].FoodImage}
+		if images != nil {
+			extension, data, err := images.Fetch(ctx, entry.URL)
+			if err != nil {
+				entry.Error = err.Error()
+			} else {
+				entry.File = fmt.Sprintf("images/%s.%s", foods[i].ID, extension)
+				if err = writeFile(archive, entry.File, now, data); err != nil {
+					return err
+				}
+
+				foods[i].FoodImage = entry.File
+			}
+		}
+
+		manifest = append(manifest, entry)
+	}
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", export.Profile},
+		{"foods.json", foods},
+		{"images.json", manifest},
+		{"preferences.json", export.Preferences},
+		{"followers.json", export.Followers},
+		{"following.json", export.Following},
+	}
+
+	for _, file := range files {
+		data, err := json.MarshalIndent(file.data, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		if err = writeFile(archive, file.name, now, data); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+func writeFile(archive *zip.Writer, name string, modified time.Time, data []byte) error {
+	file, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(data)
+	return err
+}
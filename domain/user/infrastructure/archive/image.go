@@ -0,0 +1,141 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrImageURL is returned for an image that is not linked by an http or https URL.
+var ErrImageURL = errors.New("the image is not an http or https URL")
+
+// ErrImageTooLarge is returned for an image larger than the limit of the fetcher.
+var ErrImageTooLarge = errors.New("the image is too large")
+
+// ErrImageType is returned when the content is not a png, jpeg, gif or webp image.
+var ErrImageType = errors.New("the content is not a supported image")
+
+// ErrImageAddress is returned when the host of the image resolves to a private or local address.
+var ErrImageAddress = errors.New("the image is not on a public address")
+
+// imageExtensions are the extensions of the images saved in the archive.
+var imageExtensions = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpg",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// privateNetworks are the networks that are not reachable from the internet, the images are never
+// downloaded from them so an export cannot reach the internal services.
+var privateNetworks = parseNetworks("10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10", "fc00::/7")
+
+// ImageFetcher downloads the images of the foods linked by URL.
+type ImageFetcher interface {
+	Fetch(ctx context.Context, url string) (extension string, data []byte, err error)
+}
+
+// HTTPImageFetcher downloads the images with the client, the images larger than MaxSize are rejected.
+type HTTPImageFetcher struct {
+	Client  *http.Client
+	MaxSize int64
+}
+
+var _ ImageFetcher = &HTTPImageFetcher{}
+
+// NewHTTPImageFetcher returns a fetcher whose requests, redirects included, time out and only connect to
+// public addresses.
+func NewHTTPImageFetcher(maxSize int64, timeout time.Duration) *HTTPImageFetcher {
+	dialer := &net.Dialer{Timeout: timeout, Control: publicAddressOnly}
+
+	return &HTTPImageFetcher{
+		Client: &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: timeout},
+		},
+		MaxSize: maxSize,
+	}
+}
+
+// Fetch returns the extension and the content of the image of the URL.
+func (hf *HTTPImageFetcher) Fetch(ctx context.Context, rawURL string) (string, []byte, error) {
+	imageURL, err := url.Parse(rawURL)
+	if err != nil || (imageURL.Scheme != "http" && imageURL.Scheme != "https") {
+		return "", nil, ErrImageURL
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL.String(), nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	resp, err := hf.Client.Do(request)
+	if err != nil {
+		return "", nil, err
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("the image responded %d", resp.StatusCode)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, hf.MaxSize+1))
+	if err != nil {
+		return "", nil, err
+	}
+
+	if int64(len(data)) > hf.MaxSize {
+		return "", nil, ErrImageTooLarge
+	}
+
+	extension, ok := imageExtensions[http.DetectContentType(data)]
+	if !ok {
+		return "", nil, ErrImageType
+	}
+
+	return extension, data, nil
+}
+
+// publicAddressOnly rejects the connections to a loopback, private, link-local or multicast address,
+// it runs after the host is resolved so a public name of a private address is rejected too.
+func publicAddressOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return ErrImageAddress
+	}
+
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return ErrImageAddress
+		}
+	}
+
+	return nil
+}
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks
+}
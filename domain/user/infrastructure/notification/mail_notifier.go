@@ -0,0 +1,63 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"food-api/domain/user/application/v1/response"
+	"food-api/domain/user/domain/service"
+	"food-api/infrastructure/mail"
+	"time"
+)
+
+// linkData is the data of the emails with a link for the user.
+type linkData struct {
+	Names   string
+	Link    string
+	Expires string
+}
+
+// mailNotifier sends the messages of the account by email.
+type mailNotifier struct {
+	Mailer mail.Mailer
+}
+
+func NewMailNotifier(mailer mail.Mailer) service.Notifier {
+	return &mailNotifier{
+		Mailer: mailer,
+	}
+}
+
+// PasswordReset implements service.Notifier.
+func (mn *mailNotifier) PasswordReset(ctx context.Context, user response.UserResponse, token string) error {
+	data := linkData{Names: user.Names, Link: service.PasswordResetLink(token), Expires: formatDuration(service.PasswordResetTTL())}
+	return mn.send(ctx, passwordResetTemplate, data, user)
+}
+
+// VerifyEmail implements service.Notifier.
+func (mn *mailNotifier) VerifyEmail(ctx context.Context, user response.UserResponse, token string) error {
+	data := linkData{Names: user.Names, Link: service.EmailVerificationLink(token), Expires: formatDuration(service.EmailVerificationTTL())}
+	return mn.send(ctx, verifyEmailTemplate, data, user)
+}
+
+func (mn *mailNotifier) send(ctx context.Context, template *mail.Template, data interface{}, user response.UserResponse) error {
+	message, err := template.Render(data, user.Email)
+	if err != nil {
+		return err
+	}
+
+	return mn.Mailer.Send(ctx, message)
+}
+
+// formatDuration returns the duration in hours or minutes for the emails, e.g. 24 hours.
+func formatDuration(duration time.Duration) string {
+	count, unit := int64(duration/time.Minute), "minute"
+	if duration >= time.Hour && duration%time.Hour == 0 {
+		count, unit = int64(duration/time.Hour), "hour"
+	}
+
+	if count != 1 {
+		unit += "s"
+	}
+
+	return fmt.Sprintf("%d %s", count, unit)
+}
@@ -0,0 +1,37 @@
+package notification
+
+import "food-api/infrastructure/mail"
+
+// passwordResetTemplate is the email with the link to reset the password.
+var passwordResetTemplate = mail.MustTemplate("password_reset",
+	`Reset your Food API password`,
+	`Hi {{.Names}},
+
+Someone asked to reset the password of your Food API account. Open this link to choose a new one:
+
+{{.Link}}
+
+The link can be used once and expires in {{.Expires}}. If you did not ask for it, ignore this email and your password stays the same.
+`,
+	`<p>Hi {{.Names}},</p>
+<p>Someone asked to reset the password of your Food API account. Open this link to choose a new one:</p>
+<p><a href="{{.Link}}">Reset the password</a></p>
+<p>The link can be used once and expires in {{.Expires}}. If you did not ask for it, ignore this email and your password stays the same.</p>
+`)
+
+// verifyEmailTemplate is the email with the link to verify the email of a new user.
+var verifyEmailTemplate = mail.MustTemplate("verify_email",
+	`Verify your Food API email`,
+	`Hi {{.Names}},
+
+Welcome to Food API. Open this link to verify your email:
+
+{{.Link}}
+
+The link expires in {{.Expires}}, you can ask for another one from the login page.
+`,
+	`<p>Hi {{.Names}},</p>
+<p>Welcome to Food API. Open this link to verify your email:</p>
+<p><a href="{{.Link}}">Verify the email</a></p>
+<p>The link expires in {{.Expires}}, you can ask for another one from the login page.</p>
+`)
@@ -0,0 +1,124 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"food-api/domain/user/domain/model"
+	repoDomain "food-api/domain/user/domain/repository"
+	"food-api/infrastructure/database"
+	"time"
+)
+
+type sqlAPIKeyRepo struct {
+	Conn *database.Data
+}
+
+func NewAPIKeyRepository(Conn *database.Data) repoDomain.APIKeyRepository {
+	return &sqlAPIKeyRepo{
+		Conn: Conn,
+	}
+}
+
+// CreateAPIKey inserts the API key, the key itself is never stored, only its hash.
+func (sr *sqlAPIKeyRepo) CreateAPIKey(ctx context.Context, key *model.APIKey) error {
+	_, err := sr.Conn.DB.ExecContext(ctx, insertAPIKey, key.ID, key.UserID, key.Name, key.Prefix, key.KeyHash, key.Scope,
+		key.ExpiresAt, key.CreatedAt)
+
+	return err
+}
+
+// GetAPIKeys returns the API keys of the user, the newest first.
+func (sr *sqlAPIKeyRepo) GetAPIKeys(ctx context.Context, userId string) ([]model.APIKey, error) {
+	rows, err := sr.Conn.DB.QueryContext(ctx, selectAPIKeys, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	keys := make([]model.APIKey, 0)
+	for rows.Next() {
+		var key model.APIKey
+		err = rows.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.Scope, &key.ExpiresAt, &key.LastUsedAt,
+			&key.CreatedAt, &key.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// GetAPIKey returns the API key of the user, a key of another user returns sql.ErrNoRows.
+func (sr *sqlAPIKeyRepo) GetAPIKey(ctx context.Context, userId, id string) (*model.APIKey, error) {
+	key := &model.APIKey{}
+	err := sr.Conn.DB.QueryRowContext(ctx, selectAPIKey, userId, id).Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix,
+		&key.Scope, &key.ExpiresAt, &key.LastUsedAt, &key.CreatedAt, &key.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// GetAPIKeyByPrefix returns the API key of the prefix with its hash and the role of its user.
+func (sr *sqlAPIKeyRepo) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*model.APIKey, error) {
+	key := &model.APIKey{}
+	err := sr.Conn.DB.QueryRowContext(ctx, selectAPIKeyByPrefix, prefix).Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix,
+		&key.KeyHash, &key.Scope, &key.ExpiresAt, &key.LastUsedAt, &key.CreatedAt, &key.UpdatedAt, &key.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// UpdateAPIKey replaces the name and the scope of the API key, a key of another user returns sql.ErrNoRows.
+func (sr *sqlAPIKeyRepo) UpdateAPIKey(ctx context.Context, key *model.APIKey) error {
+	result, err := sr.Conn.DB.ExecContext(ctx, updateAPIKey, key.Name, key.Scope, key.UpdatedAt, key.UserID, key.ID)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result)
+}
+
+// DeleteAPIKey revokes the API key, a key of another user returns sql.ErrNoRows.
+func (sr *sqlAPIKeyRepo) DeleteAPIKey(ctx context.Context, userId, id string) error {
+	result, err := sr.Conn.DB.ExecContext(ctx, deleteAPIKey, userId, id)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result)
+}
+
+// DeleteUserAPIKeys revokes all the API keys of the user, e.g. when its password is reset.
+func (sr *sqlAPIKeyRepo) DeleteUserAPIKeys(ctx context.Context, userId string) error {
+	_, err := sr.Conn.DB.ExecContext(ctx, deleteUserAPIKeys, userId)
+
+	return err
+}
+
+// TouchAPIKey records the last use of the API key.
+func (sr *sqlAPIKeyRepo) TouchAPIKey(ctx context.Context, id string, at time.Time) error {
+	_, err := sr.Conn.DB.ExecContext(ctx, touchAPIKey, at, id)
+
+	return err
+}
+
+// expectAffected returns sql.ErrNoRows when the statement did not change any row.
+func expectAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
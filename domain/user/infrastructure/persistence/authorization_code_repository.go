@@ -0,0 +1,70 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"food-api/domain/user/domain/model"
+	repoDomain "food-api/domain/user/domain/repository"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+const authorizationCodeKey = "oauth:code"
+
+// ErrAuthorizationCodeInvalid is returned for a code that does not exist, expired or was already exchanged.
+var ErrAuthorizationCodeInvalid = errors.New("the authorization code is invalid or expired")
+
+// redisAuthorizationCodeRepo keeps the authorization codes under the hash of the code.
+type redisAuthorizationCodeRepo struct {
+	Client *redis.Client
+}
+
+func NewAuthorizationCodeRepository(client *redis.Client) repoDomain.AuthorizationCodeRepository {
+	return &redisAuthorizationCodeRepo{
+		Client: client,
+	}
+}
+
+// SaveCode saves the authorization until it expires.
+func (cr *redisAuthorizationCodeRepo) SaveCode(ctx context.Context, code string, authorization *model.AuthorizationCode) error {
+	ttl := time.Until(authorization.ExpiresAt)
+	if ttl <= 0 {
+		return ErrAuthorizationCodeInvalid
+	}
+
+	value, err := json.Marshal(authorization)
+	if err != nil {
+		return err
+	}
+
+	return cr.Client.Set(ctx, codeKey(code), value, ttl).Err()
+}
+
+// ConsumeCode returns the authorization of the code and deletes it, so a code is only exchanged once.
+func (cr *redisAuthorizationCodeRepo) ConsumeCode(ctx context.Context, code string) (*model.AuthorizationCode, error) {
+	key := codeKey(code)
+
+	pipe := cr.Client.TxPipeline()
+	get := pipe.Get(ctx, key)
+	pipe.Del(ctx, key)
+	_, err := pipe.Exec(ctx)
+	if err == redis.Nil {
+		return nil, ErrAuthorizationCodeInvalid
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	authorization := &model.AuthorizationCode{}
+	if err = json.Unmarshal([]byte(get.Val()), authorization); err != nil {
+		return nil, err
+	}
+
+	return authorization, nil
+}
+
+func codeKey(code string) string {
+	return authorizationCodeKey + ":" + hashToken(code)
+}
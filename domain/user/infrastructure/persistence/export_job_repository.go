@@ -0,0 +1,58 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"food-api/domain/user/application/v1/response"
+	repoDomain "food-api/domain/user/domain/repository"
+	"sync"
+)
+
+// memoryExportJobRepo keeps the export jobs in memory, they are lost when the API restarts.
+type memoryExportJobRepo struct {
+	mutex sync.RWMutex
+	jobs  map[string]response.ExportJobResponse
+}
+
+func NewExportJobRepository() repoDomain.ExportJobRepository {
+	return &memoryExportJobRepo{
+		jobs: make(map[string]response.ExportJobResponse),
+	}
+}
+
+// SaveExportJob creates or replaces the job with the same id.
+func (mr *memoryExportJobRepo) SaveExportJob(ctx context.Context, job *response.ExportJobResponse) error {
+	if job == nil || job.ID == "" {
+		return errors.New("cannot save a job without id")
+	}
+
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	mr.jobs[job.ID] = *job
+
+	return nil
+}
+
+// GetExportJobById
+func (mr *memoryExportJobRepo) GetExportJobById(ctx context.Context, id string) (*response.ExportJobResponse, error) {
+	mr.mutex.RLock()
+	defer mr.mutex.RUnlock()
+
+	job, ok := mr.jobs[id]
+	if !ok {
+		return &response.ExportJobResponse{}, errors.New("export job not found")
+	}
+
+	return &job, nil
+}
+
+// DeleteExportJob removes the job, a missing job is not an error.
+func (mr *memoryExportJobRepo) DeleteExportJob(ctx context.Context, id string) error {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	delete(mr.jobs, id)
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"food-api/domain/user/application/v1/response"
+	repoDomain "food-api/domain/user/domain/repository"
+	"food-api/infrastructure/database"
+	"github.com/lib/pq"
+)
+
+type sqlExportRepo struct {
+	Conn *database.Data
+}
+
+func NewExportRepository(Conn *database.Data) repoDomain.ExportRepository {
+	return &sqlExportRepo{
+		Conn: Conn,
+	}
+}
+
+// CountUserFoods returns the number of foods of the user, it decides whether the export runs as a job.
+func (sr *sqlExportRepo) CountUserFoods(ctx context.Context, userId string) (int, error) {
+	var count int
+	err := sr.Conn.DB.QueryRowContext(ctx, countUserFoods, userId).Scan(&count)
+
+	return count, err
+}
+
+// GetUserExport returns the profile, the foods with their translations, the favorites and ratings and the
+// follows of the user. The queries run in a read only transaction so the export is consistent.
+func (sr *sqlExportRepo) GetUserExport(ctx context.Context, userId string) (*response.UserExportResponse, error) {
+	tx, err := sr.Conn.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	export := &response.UserExportResponse{
+		Foods:       []response.ExportFoodResponse{},
+		Preferences: []response.ExportPreferenceResponse{},
+		Followers:   []response.ExportFollowResponse{},
+		Following:   []response.ExportFollowResponse{},
+	}
+
+	profile := &export.Profile
+	err = tx.QueryRowContext(ctx, selectUserProfile, userId).
+		Scan(&profile.ID, &profile.Names, &profile.LastNames, &profile.Email, &profile.CreatedAt, &profile.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if export.Foods, err = exportFoods(ctx, tx, userId); err != nil {
+		return nil, err
+	}
+
+	if export.Preferences, err = exportPreferences(ctx, tx, userId); err != nil {
+		return nil, err
+	}
+
+	if export.Followers, err = exportFollows(ctx, tx, selectUserFollowers, userId); err != nil {
+		return nil, err
+	}
+
+	if export.Following, err = exportFollows(ctx, tx, selectUserFollowing, userId); err != nil {
+		return nil, err
+	}
+
+	return export, tx.Commit()
+}
+
+func exportFoods(ctx context.Context, tx *sql.Tx, userId string) ([]response.ExportFoodResponse, error) {
+	rows, err := tx.QueryContext(ctx, selectUserFoods, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	foods := []response.ExportFoodResponse{}
+	byId := make(map[string]int)
+	for rows.Next() {
+		var food response.ExportFoodResponse
+		err = rows.Scan(&food.ID, &food.Title, &food.Description, &food.FoodImage, pq.Array(&food.Ingredients), pq.Array(&food.Instructions),
+			pq.Array(&food.Tags), &food.Slug, &food.Visibility, &food.Language, &food.CreatedAt, &food.UpdatedAt)
+		if err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+
+		byId[food.ID] = len(foods)
+		foods = append(foods, food)
+	}
+
+	_ = rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = tx.QueryContext(ctx, selectUserFoodTranslations, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var foodId string
+		var translation response.ExportTranslationResponse
+		err = rows.Scan(&foodId, &translation.Language, &translation.Title, &translation.Description, pq.Array(&translation.Instructions))
+		if err != nil {
+			return nil, err
+		}
+
+		if i, ok := byId[foodId]; ok {
+			foods[i].Translations = append(foods[i].Translations, translation)
+		}
+	}
+
+	return foods, rows.Err()
+}
+
+func exportPreferences(ctx context.Context, tx *sql.Tx, userId string) ([]response.ExportPreferenceResponse, error) {
+	rows, err := tx.QueryContext(ctx, selectUserPreferences, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	preferences := []response.ExportPreferenceResponse{}
+	for rows.Next() {
+		var preference response.ExportPreferenceResponse
+		err = rows.Scan(&preference.FoodID, &preference.Favorite, &preference.Rating, &preference.CreatedAt, &preference.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		preferences = append(preferences, preference)
+	}
+
+	return preferences, rows.Err()
+}
+
+// exportFollows returns the followers or the followed users depending on the query.
+func exportFollows(ctx context.Context, tx *sql.Tx, query, userId string) ([]response.ExportFollowResponse, error) {
+	rows, err := tx.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	follows := []response.ExportFollowResponse{}
+	for rows.Next() {
+		var follow response.ExportFollowResponse
+		if err = rows.Scan(&follow.UserID, &follow.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		follows = append(follows, follow)
+	}
+
+	return follows, rows.Err()
+}
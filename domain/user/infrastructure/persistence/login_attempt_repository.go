@@ -0,0 +1,78 @@
+package persistence
+
+import (
+	"context"
+	"food-api/domain/user/domain/model"
+	repoDomain "food-api/domain/user/domain/repository"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+const loginAttemptKey = "login"
+
+// redisLoginAttemptRepo keeps a counter of failed logins and a lock per subject, the subjects are
+// hashed to keep the emails and the IPs out of Redis.
+type redisLoginAttemptRepo struct {
+	Client *redis.Client
+}
+
+func NewLoginAttemptRepository(client *redis.Client) repoDomain.LoginAttemptRepository {
+	return &redisLoginAttemptRepo{
+		Client: client,
+	}
+}
+
+// LockedFor returns how long the logins of the subjects are locked, the longest lock wins.
+func (lr *redisLoginAttemptRepo) LockedFor(ctx context.Context, subjects []string) (time.Duration, error) {
+	var locked time.Duration
+	for _, subject := range subjects {
+		wait, err := lr.Client.PTTL(ctx, lockKey(subject)).Result()
+		if err != nil {
+			return 0, err
+		}
+
+		if wait > locked {
+			locked = wait
+		}
+	}
+
+	return locked, nil
+}
+
+// Fail counts a failed login of the subject and returns how long it is locked, zero when it is not.
+// The counter lives for the window after the lock so the next failures lock for longer.
+func (lr *redisLoginAttemptRepo) Fail(ctx context.Context, subject string, policy model.LockoutPolicy) (time.Duration, error) {
+	key := attemptsKey(subject)
+
+	failures, err := lr.Client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	lockout := policy.Lockout(failures)
+
+	pipe := lr.Client.TxPipeline()
+	pipe.PExpire(ctx, key, policy.Window+lockout)
+	if lockout > 0 {
+		pipe.Set(ctx, lockKey(subject), failures, lockout)
+	}
+
+	if _, err = pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	return lockout, nil
+}
+
+// Reset clears the failures and the lock of the subject, e.g. after a valid login or by an admin.
+func (lr *redisLoginAttemptRepo) Reset(ctx context.Context, subject string) error {
+	return lr.Client.Del(ctx, attemptsKey(subject), lockKey(subject)).Err()
+}
+
+func attemptsKey(subject string) string {
+	return loginAttemptKey + ":attempts:" + hashToken(subject)
+}
+
+func lockKey(subject string) string {
+	return loginAttemptKey + ":lock:" + hashToken(subject)
+}
@@ -0,0 +1,94 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"food-api/domain/user/domain/model"
+	repoDomain "food-api/domain/user/domain/repository"
+	"food-api/infrastructure/database"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type sqlOAuthClientRepo struct {
+	Conn *database.Data
+}
+
+func NewOAuthClientRepository(Conn *database.Data) repoDomain.OAuthClientRepository {
+	return &sqlOAuthClientRepo{
+		Conn: Conn,
+	}
+}
+
+// CreateClient inserts the OAuth client, a public client has no secret.
+func (sr *sqlOAuthClientRepo) CreateClient(ctx context.Context, client *model.OAuthClient) error {
+	secretHash := sql.NullString{String: client.SecretHash, Valid: client.SecretHash != ""}
+
+	_, err := sr.Conn.DB.ExecContext(ctx, insertOAuthClient, client.ID, client.UserID, client.Name, secretHash,
+		pq.Array(client.RedirectURIs), client.Scope, client.CreatedAt)
+
+	return err
+}
+
+// GetClients returns the OAuth clients of the user, the newest first.
+func (sr *sqlOAuthClientRepo) GetClients(ctx context.Context, userId string) ([]model.OAuthClient, error) {
+	rows, err := sr.Conn.DB.QueryContext(ctx, selectOAuthClients, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	clients := make([]model.OAuthClient, 0)
+	for rows.Next() {
+		client, err := scanOAuthClient(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		clients = append(clients, *client)
+	}
+
+	return clients, rows.Err()
+}
+
+// GetClient returns the OAuth client of the id, an id that is not a client returns sql.ErrNoRows.
+func (sr *sqlOAuthClientRepo) GetClient(ctx context.Context, id string) (*model.OAuthClient, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, sql.ErrNoRows
+	}
+
+	return scanOAuthClient(sr.Conn.DB.QueryRowContext(ctx, selectOAuthClient, id))
+}
+
+// DeleteClient removes the OAuth client, a client of another user returns sql.ErrNoRows. The tokens it
+// already has are valid until they expire or the user revokes their sessions.
+func (sr *sqlOAuthClientRepo) DeleteClient(ctx context.Context, userId, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return sql.ErrNoRows
+	}
+
+	result, err := sr.Conn.DB.ExecContext(ctx, deleteOAuthClient, userId, id)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result)
+}
+
+// scanOAuthClient reads the row of a client, the clients with a secret are confidential.
+func scanOAuthClient(row interface{ Scan(...interface{}) error }) (*model.OAuthClient, error) {
+	client := &model.OAuthClient{}
+	var secretHash sql.NullString
+
+	err := row.Scan(&client.ID, &client.UserID, &client.Name, &secretHash, pq.Array(&client.RedirectURIs),
+		&client.Scope, &client.CreatedAt, &client.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	client.SecretHash = secretHash.String
+	client.Confidential = secretHash.Valid
+
+	return client, nil
+}
@@ -3,22 +3,150 @@ package persistence
 const(
 
 	// selectAllUser is a query that selects all rows in the user table
-	selectAllUser = "SELECT id, names, last_names, email FROM \"user\" WHERE deleted_at IS NULL ORDER BY created_at DESC;"
+	selectAllUser = "SELECT id, names, last_names, email, role FROM \"user\" WHERE deleted_at IS NULL ORDER BY created_at DESC;"
 
-	// selectUserById is a query that selects a row from the user table based off of the given id.
-	selectUserById = "SELECT id, names, last_names, email FROM \"user\" WHERE id = $1;"
+	// selectUserById is a query that selects a row from the user table based off of the given id,
+	// the deleted users are not found.
+	selectUserById = "SELECT id, names, last_names, email, email_verified_at, role FROM \"user\" WHERE id = $1 AND deleted_at IS NULL;"
 
-	// selectUserByEmail is a query that selects a row from the user table based off of the given email.
-	selectUserByEmail = "SELECT id, names, last_names, email, \"password\", created_at, updated_at FROM \"user\" WHERE email = $1;"
+	// selectUserByEmail is a query that selects a row from the user table based off of the given email,
+	// the deleted users cannot log in.
+	selectUserByEmail = "SELECT id, names, last_names, email, \"password\", email_verified_at, role, created_at, updated_at FROM \"user\" WHERE email = $1 AND deleted_at IS NULL;"
+
+	// selectUserInfoByEmail is a query that selects a row from the user table based off of the given email,
+	// without the password.
+	selectUserInfoByEmail = "SELECT id, names, last_names, email, email_verified_at FROM \"user\" WHERE email = $1 AND deleted_at IS NULL;"
 
 	// insertUser is a query that inserts a new row in the user table using the values
 	// given in order for id, names, last_names, username, email, password, created_at and updated_at.
 	insertUser = "INSERT INTO \"user\" (id, names, last_names, email, \"password\", created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, names, last_names, email;"
 
 	// updateUser is a query that updates a row in the user table based off of id.
-	// The values able to be updated are names, last_names, email and updated_at.
-	updateUser = "UPDATE \"user\" SET names=$1, last_names=$2, email=$3, updated_at=$4 WHERE id=$5;"
+	// The values able to be updated are names, last_names, email and updated_at, a new email is not verified.
+	updateUser = "UPDATE \"user\" SET names=$1, last_names=$2, email=$3, updated_at=$4, email_verified_at=CASE WHEN email=$3 THEN email_verified_at END WHERE id=$5;"
+
+	// verifyUserEmail is a query that marks the email of the user $2 as verified at $1 while it is still $3,
+	// verifying it again keeps the first date.
+	verifyUserEmail = "UPDATE \"user\" SET email_verified_at=COALESCE(email_verified_at, $1), updated_at=$1 WHERE id=$2 AND email=$3 AND deleted_at IS NULL;"
+
+	// updateUserPassword is a query that replaces the password hash of the user given as $3.
+	updateUserPassword = "UPDATE \"user\" SET \"password\"=$1, updated_at=$2 WHERE id=$3 AND deleted_at IS NULL;"
+
+	// updateUserRole is a query that sets the role $1 to the user $3.
+	updateUserRole = "UPDATE \"user\" SET role=$1, updated_at=$2 WHERE id=$3 AND deleted_at IS NULL;"
+
+	// promoteAdmins is a query that sets the admin role to the users with the emails $1 that verified them,
+	// updated_at is $2.
+	promoteAdmins = "UPDATE \"user\" SET role='admin', updated_at=$2 WHERE email = ANY($1) AND email_verified_at IS NOT NULL AND deleted_at IS NULL AND role <> 'admin';"
+
+	// deleteUser is a query that soft deletes a row in the user table given a id, the deleted_at and
+	// updated_at are $1. The personal data is scrubbed and the email is replaced by a tombstone so the
+	// address can sign up again.
+	deleteUser = "UPDATE \"user\" SET names='Deleted', last_names='User', email='deleted+' || id || '@food-api.invalid', \"password\"='', email_verified_at=NULL, deleted_at=$1, updated_at=$1 WHERE id=$2 AND deleted_at IS NULL;"
+
+	// deleteUserFollowers is a query that removes the followers of the user and the users the user follows.
+	deleteUserFollowers = "DELETE FROM user_follower WHERE user_id=$1 OR follower_id=$1;"
+
+	// transferUserFoods is a query that moves the foods of the user $3 to the user $1.
+	transferUserFoods = "UPDATE food SET user_id=$1, updated_at=$2 WHERE user_id=$3;"
+
+	// deleteUserFoods is a query that deletes the foods of the user, their translations, preferences and
+	// slug history are deleted in cascade.
+	deleteUserFoods = "DELETE FROM food WHERE user_id=$1;"
+
+	// insertFollower is a query that makes follower_id a follower of user_id, following twice is ignored.
+	insertFollower = "INSERT INTO user_follower (user_id, follower_id, created_at) VALUES ($1, $2, $3) ON CONFLICT (user_id, follower_id) DO NOTHING;"
+
+	// deleteFollower is a query that removes follower_id from the followers of user_id.
+	deleteFollower = "DELETE FROM user_follower WHERE user_id=$1 AND follower_id=$2;"
+
+	// selectUserProfile is a query that selects the profile of the user for the personal data export.
+	selectUserProfile = "SELECT id, names, last_names, email, created_at, updated_at FROM \"user\" WHERE id = $1 AND deleted_at IS NULL;"
+
+	// countUserFoods is a query that counts the foods of the user.
+	countUserFoods = "SELECT COUNT(*) FROM food WHERE user_id = $1;"
+
+	// selectUserFoods is a query that selects every food of the user, whatever its visibility.
+	selectUserFoods = "SELECT id, title, description, COALESCE(food_image, ''), ingredients, instructions, tags, slug, visibility, language, created_at, updated_at FROM food WHERE user_id = $1 ORDER BY created_at, id;"
+
+	// selectUserFoodTranslations is a query that selects the translations of the foods of the user.
+	selectUserFoodTranslations = "SELECT ft.food_id, ft.language, ft.title, ft.description, ft.instructions FROM food_translation ft JOIN food f ON f.id = ft.food_id WHERE f.user_id = $1 ORDER BY ft.food_id, ft.language;"
+
+	// selectUserPreferences is a query that selects the favorites and the ratings of the user, a food without
+	// rating has a rating of 0.
+	selectUserPreferences = "SELECT food_id, favorite, COALESCE(rating, 0), created_at, updated_at FROM food_preference WHERE user_id = $1 ORDER BY food_id;"
+
+	// selectUserFollowers is a query that selects the followers of the user.
+	selectUserFollowers = "SELECT follower_id, created_at FROM user_follower WHERE user_id = $1 ORDER BY created_at, follower_id;"
+
+	// selectUserFollowing is a query that selects the users the user follows.
+	selectUserFollowing = "SELECT user_id, created_at FROM user_follower WHERE follower_id = $1 ORDER BY created_at, user_id;"
+
+	// selectTwoFactor is a query that selects the TOTP secret of the user.
+	selectTwoFactor = "SELECT user_id, secret, enabled_at, last_step FROM user_two_factor WHERE user_id = $1;"
+
+	// upsertTwoFactorSecret is a query that saves the pending secret $2 of the user $1, the secret of an
+	// enabled two-factor authentication is not replaced.
+	upsertTwoFactorSecret = "INSERT INTO user_two_factor (user_id, secret, created_at, updated_at) VALUES ($1, $2, $3, $3) ON CONFLICT (user_id) DO UPDATE SET secret=$2, last_step=0, updated_at=$3 WHERE user_two_factor.enabled_at IS NULL;"
+
+	// enableTwoFactor is a query that enables the pending secret of the user $2 at $1.
+	enableTwoFactor = "UPDATE user_two_factor SET enabled_at=$1, updated_at=$1 WHERE user_id=$2 AND enabled_at IS NULL;"
+
+	// deleteTwoFactor is a query that removes the secret of the user.
+	deleteTwoFactor = "DELETE FROM user_two_factor WHERE user_id=$1;"
+
+	// updateTwoFactorStep is a query that records the time step $1 of the code used by the user $2, an older
+	// or the same step is not updated so a code cannot be replayed.
+	updateTwoFactorStep = "UPDATE user_two_factor SET last_step=$1 WHERE user_id=$2 AND last_step < $1;"
+
+	// insertRecoveryCode is a query that inserts the hash $2 of a recovery code of the user $1.
+	insertRecoveryCode = "INSERT INTO user_recovery_code (user_id, code_hash, created_at) VALUES ($1, $2, $3);"
+
+	// deleteRecoveryCodes is a query that removes the recovery codes of the user.
+	deleteRecoveryCodes = "DELETE FROM user_recovery_code WHERE user_id=$1;"
+
+	// useRecoveryCode is a query that marks the recovery code $3 of the user $2 as used at $1, a code can
+	// only be used once.
+	useRecoveryCode = "UPDATE user_recovery_code SET used_at=$1 WHERE user_id=$2 AND code_hash=$3 AND used_at IS NULL;"
+
+	// insertAPIKey is a query that inserts a new row in the api_key table using the values given in order for
+	// id, user_id, name, prefix, key_hash, scope, expires_at, created_at and updated_at.
+	insertAPIKey = "INSERT INTO api_key (id, user_id, name, prefix, key_hash, scope, expires_at, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8);"
+
+	// selectAPIKeys is a query that selects the API keys of the user, the newest first.
+	selectAPIKeys = "SELECT id, user_id, name, prefix, scope, expires_at, last_used_at, created_at, updated_at FROM api_key WHERE user_id = $1 ORDER BY created_at DESC, id;"
+
+	// selectAPIKey is a query that selects the API key $2 of the user $1.
+	selectAPIKey = "SELECT id, user_id, name, prefix, scope, expires_at, last_used_at, created_at, updated_at FROM api_key WHERE user_id = $1 AND id = $2;"
+
+	// selectAPIKeyByPrefix is a query that selects the API key of the prefix with its hash and the role of
+	// its user, the keys of the deleted users are not found.
+	selectAPIKeyByPrefix = "SELECT k.id, k.user_id, k.name, k.prefix, k.key_hash, k.scope, k.expires_at, k.last_used_at, k.created_at, k.updated_at, u.role FROM api_key k JOIN \"user\" u ON u.id = k.user_id WHERE k.prefix = $1 AND u.deleted_at IS NULL;"
+
+	// updateAPIKey is a query that sets the name $1 and the scope $2 of the API key $5 of the user $4.
+	updateAPIKey = "UPDATE api_key SET name=$1, scope=$2, updated_at=$3 WHERE user_id=$4 AND id=$5;"
+
+	// deleteAPIKey is a query that removes the API key $2 of the user $1.
+	deleteAPIKey = "DELETE FROM api_key WHERE user_id=$1 AND id=$2;"
+
+	// deleteUserAPIKeys is a query that removes all the API keys of the user $1.
+	deleteUserAPIKeys = "DELETE FROM api_key WHERE user_id=$1;"
+
+	// touchAPIKey is a query that records the last use $1 of the API key $2, it is only written once per
+	// minute so the requests of a script do not update it each time.
+	touchAPIKey = "UPDATE api_key SET last_used_at=$1 WHERE id=$2 AND (last_used_at IS NULL OR last_used_at < $1 - interval '1 minute');"
+
+	// insertOAuthClient is a query that inserts a new row in the oauth_client table using the values given in
+	// order for id, user_id, name, secret_hash, redirect_uris, scope, created_at and updated_at.
+	insertOAuthClient = "INSERT INTO oauth_client (id, user_id, name, secret_hash, redirect_uris, scope, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $7);"
+
+	// selectOAuthClients is a query that selects the OAuth clients of the user, the newest first.
+	selectOAuthClients = "SELECT id, user_id, name, secret_hash, redirect_uris, scope, created_at, updated_at FROM oauth_client WHERE user_id = $1 ORDER BY created_at DESC, id;"
+
+	// selectOAuthClient is a query that selects the OAuth client by id, the clients of the deleted users are
+	// not found.
+	selectOAuthClient = "SELECT c.id, c.user_id, c.name, c.secret_hash, c.redirect_uris, c.scope, c.created_at, c.updated_at FROM oauth_client c JOIN \"user\" u ON u.id = c.user_id WHERE c.id = $1 AND u.deleted_at IS NULL;"
 
-	// deleteUser is a query that deletes a row in the user table given a id.
-	deleteUser = "DELETE FROM \"user\" WHERE id=$1;"
+	// deleteOAuthClient is a query that removes the OAuth client $2 of the user $1.
+	deleteOAuthClient = "DELETE FROM oauth_client WHERE user_id=$1 AND id=$2;"
 )
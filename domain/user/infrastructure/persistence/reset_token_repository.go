@@ -0,0 +1,36 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	repoDomain "food-api/domain/user/domain/repository"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+const resetTokenKey = "password:reset"
+
+// ErrResetTokenInvalid is returned for a reset token that does not exist, expired or was already used.
+var ErrResetTokenInvalid = errors.New("the reset token is invalid or expired")
+
+// redisResetTokenRepo keeps the reset tokens of the passwords, a user only has the last token requested.
+type redisResetTokenRepo struct {
+	tokens tokenStore
+}
+
+func NewResetTokenRepository(client *redis.Client) repoDomain.ResetTokenRepository {
+	return &redisResetTokenRepo{
+		tokens: tokenStore{Client: client, prefix: resetTokenKey, invalid: ErrResetTokenInvalid},
+	}
+}
+
+// SaveResetToken saves the token of the user for ttl and invalidates the previous one.
+func (rr *redisResetTokenRepo) SaveResetToken(ctx context.Context, userId, token string, ttl time.Duration) error {
+	return rr.tokens.save(ctx, userId, token, "", ttl)
+}
+
+// ConsumeResetToken returns the user of the token and deletes it, a token can only be used once.
+func (rr *redisResetTokenRepo) ConsumeResetToken(ctx context.Context, token string) (string, error) {
+	userId, _, err := rr.tokens.consume(ctx, token)
+	return userId, err
+}
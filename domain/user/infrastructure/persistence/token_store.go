@@ -0,0 +1,98 @@
+package persistence
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/go-redis/redis/v8"
+	"strings"
+	"time"
+)
+
+// tokenDataSeparator separates the user of a token from its data, the ids of the users never contain it.
+const tokenDataSeparator = "\n"
+
+// tokenStore keeps the hash of single use tokens under a prefix so a copy of Redis cannot use them,
+// a user only has the last token issued. A token can carry data that is returned when it is consumed.
+type tokenStore struct {
+	Client *redis.Client
+	prefix string
+	// invalid is returned for a token that does not exist, expired or was already used
+	invalid error
+}
+
+// save saves the token of the user and its data for ttl and invalidates the previous one.
+func (ts tokenStore) save(ctx context.Context, userId, token, data string, ttl time.Duration) error {
+	userKey := ts.prefix + ":user:" + userId
+
+	previous, err := ts.Client.Get(ctx, userKey).Result()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+
+	pipe := ts.Client.TxPipeline()
+	if previous != "" {
+		pipe.Del(ctx, previous)
+	}
+
+	key := ts.key(token)
+	pipe.Set(ctx, key, userId+tokenDataSeparator+data, ttl)
+	pipe.Set(ctx, userKey, key, ttl)
+	_, err = pipe.Exec(ctx)
+
+	return err
+}
+
+// consume returns the user of the token and its data and deletes it, a token can only be used once.
+func (ts tokenStore) consume(ctx context.Context, token string) (string, string, error) {
+	key := ts.key(token)
+
+	pipe := ts.Client.TxPipeline()
+	get := pipe.Get(ctx, key)
+	pipe.Del(ctx, key)
+	_, err := pipe.Exec(ctx)
+	if err == redis.Nil {
+		return "", "", ts.invalid
+	}
+
+	if err != nil {
+		return "", "", err
+	}
+
+	value := strings.SplitN(get.Val(), tokenDataSeparator, 2)
+	userId, data := value[0], ""
+	if len(value) == 2 {
+		data = value[1]
+	}
+
+	if err = ts.Client.Del(ctx, ts.prefix+":user:"+userId).Err(); err != nil {
+		return "", "", err
+	}
+
+	return userId, data, nil
+}
+
+// revoke deletes the token the user has, if any.
+func (ts tokenStore) revoke(ctx context.Context, userId string) error {
+	userKey := ts.prefix + ":user:" + userId
+
+	key, err := ts.Client.Get(ctx, userKey).Result()
+	if err == redis.Nil {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return ts.Client.Del(ctx, key, userKey).Err()
+}
+
+func (ts tokenStore) key(token string) string {
+	return ts.prefix + ":" + hashToken(token)
+}
+
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
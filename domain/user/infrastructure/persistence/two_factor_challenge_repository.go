@@ -0,0 +1,90 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"food-api/domain/user/domain/model"
+	repoDomain "food-api/domain/user/domain/repository"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+const twoFactorChallengeKey = "login:2fa"
+
+// ErrTwoFactorChallengeInvalid is returned for a challenge that does not exist, expired or was already used.
+var ErrTwoFactorChallengeInvalid = errors.New("the challenge token is invalid or expired")
+
+// redisTwoFactorChallengeRepo keeps the challenges of the logins under the hash of their token.
+type redisTwoFactorChallengeRepo struct {
+	Client *redis.Client
+}
+
+func NewTwoFactorChallengeRepository(client *redis.Client) repoDomain.TwoFactorChallengeRepository {
+	return &redisTwoFactorChallengeRepo{
+		Client: client,
+	}
+}
+
+// SaveChallenge saves the challenge until it expires, saving it again keeps its expiration.
+func (cr *redisTwoFactorChallengeRepo) SaveChallenge(ctx context.Context, token string, challenge *model.TwoFactorChallenge) error {
+	ttl := time.Until(challenge.ExpiresAt)
+	if ttl <= 0 {
+		return ErrTwoFactorChallengeInvalid
+	}
+
+	value, err := json.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+
+	return cr.Client.Set(ctx, challengeKey(token), value, ttl).Err()
+}
+
+// GetChallenge returns the challenge of the token without using it, e.g. to check the lockout of the
+// login before the challenge is consumed.
+func (cr *redisTwoFactorChallengeRepo) GetChallenge(ctx context.Context, token string) (*model.TwoFactorChallenge, error) {
+	value, err := cr.Client.Get(ctx, challengeKey(token)).Result()
+	if err == redis.Nil {
+		return nil, ErrTwoFactorChallengeInvalid
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeChallenge(value)
+}
+
+// ConsumeChallenge returns the challenge of the token and deletes it, so two requests cannot use it at
+// the same time. A wrong code saves it again with one attempt less.
+func (cr *redisTwoFactorChallengeRepo) ConsumeChallenge(ctx context.Context, token string) (*model.TwoFactorChallenge, error) {
+	key := challengeKey(token)
+
+	pipe := cr.Client.TxPipeline()
+	get := pipe.Get(ctx, key)
+	pipe.Del(ctx, key)
+	_, err := pipe.Exec(ctx)
+	if err == redis.Nil {
+		return nil, ErrTwoFactorChallengeInvalid
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeChallenge(get.Val())
+}
+
+func decodeChallenge(value string) (*model.TwoFactorChallenge, error) {
+	challenge := &model.TwoFactorChallenge{}
+	if err := json.Unmarshal([]byte(value), challenge); err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+func challengeKey(token string) string {
+	return twoFactorChallengeKey + ":" + hashToken(token)
+}
@@ -0,0 +1,164 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"food-api/domain/user/domain/model"
+	repoDomain "food-api/domain/user/domain/repository"
+	"food-api/infrastructure/database"
+	"time"
+)
+
+// ErrTwoFactorEnabled is returned when a user enrolls again without disabling the two-factor authentication.
+var ErrTwoFactorEnabled = errors.New("the two-factor authentication is already enabled")
+
+// sqlTwoFactorRepo keeps the TOTP secrets and the hash of the recovery codes.
+type sqlTwoFactorRepo struct {
+	Conn *database.Data
+}
+
+func NewTwoFactorRepository(Conn *database.Data) repoDomain.TwoFactorRepository {
+	return &sqlTwoFactorRepo{
+		Conn: Conn,
+	}
+}
+
+// GetTwoFactor returns the secret of the user, a user that never enrolled returns sql.ErrNoRows.
+func (sr *sqlTwoFactorRepo) GetTwoFactor(ctx context.Context, userId string) (*model.TwoFactor, error) {
+	twoFactor := &model.TwoFactor{}
+	err := sr.Conn.DB.QueryRowContext(ctx, selectTwoFactor, userId).
+		Scan(&twoFactor.UserID, &twoFactor.Secret, &twoFactor.EnabledAt, &twoFactor.LastStep)
+	if err != nil {
+		return nil, err
+	}
+
+	return twoFactor, nil
+}
+
+// SaveSecret saves the pending secret of the user, it replaces a previous pending one.
+func (sr *sqlTwoFactorRepo) SaveSecret(ctx context.Context, userId, secret string, at time.Time) error {
+	result, err := sr.Conn.DB.ExecContext(ctx, upsertTwoFactorSecret, userId, secret, at)
+	if err != nil {
+		return err
+	}
+
+	saved, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if saved == 0 {
+		return ErrTwoFactorEnabled
+	}
+
+	return nil
+}
+
+// Enable enables the pending secret of the user with new recovery codes, a user without a pending secret
+// returns sql.ErrNoRows.
+func (sr *sqlTwoFactorRepo) Enable(ctx context.Context, userId string, recoveryCodes []string, at time.Time) error {
+	tx, err := sr.Conn.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, enableTwoFactor, at, userId)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	enabled, err := result.RowsAffected()
+	if err != nil || enabled == 0 {
+		_ = tx.Rollback()
+		if err == nil {
+			err = sql.ErrNoRows
+		}
+
+		return err
+	}
+
+	if err = replaceRecoveryCodes(ctx, tx, userId, recoveryCodes, at); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReplaceRecoveryCodes replaces the recovery codes of the user, the previous ones cannot be used anymore.
+func (sr *sqlTwoFactorRepo) ReplaceRecoveryCodes(ctx context.Context, userId string, recoveryCodes []string, at time.Time) error {
+	tx, err := sr.Conn.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err = replaceRecoveryCodes(ctx, tx, userId, recoveryCodes, at); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Disable removes the secret and the recovery codes of the user.
+func (sr *sqlTwoFactorRepo) Disable(ctx context.Context, userId string) error {
+	tx, err := sr.Conn.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, deleteRecoveryCodes, userId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, deleteTwoFactor, userId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UseStep records the time step of a code of the user, it returns false when a code of the same or a
+// later step was already used.
+func (sr *sqlTwoFactorRepo) UseStep(ctx context.Context, userId string, step int64) (bool, error) {
+	result, err := sr.Conn.DB.ExecContext(ctx, updateTwoFactorStep, step, userId)
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := result.RowsAffected()
+
+	return updated > 0, err
+}
+
+// UseRecoveryCode marks the recovery code of the user as used, it returns false when the code does not
+// exist or was already used.
+func (sr *sqlTwoFactorRepo) UseRecoveryCode(ctx context.Context, userId, recoveryCode string, at time.Time) (bool, error) {
+	result, err := sr.Conn.DB.ExecContext(ctx, useRecoveryCode, at, userId, hashToken(recoveryCode))
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := result.RowsAffected()
+
+	return updated > 0, err
+}
+
+// replaceRecoveryCodes stores the hash of the recovery codes in place of the previous ones.
+func replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userId string, recoveryCodes []string, at time.Time) error {
+	if _, err := tx.ExecContext(ctx, deleteRecoveryCodes, userId); err != nil {
+		return err
+	}
+
+	for _, code := range recoveryCodes {
+		if _, err := tx.ExecContext(ctx, insertRecoveryCode, userId, hashToken(code), at); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
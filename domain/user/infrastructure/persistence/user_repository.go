@@ -2,15 +2,21 @@ package persistence
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"food-api/domain/user/application/v1/response"
 	"food-api/domain/user/domain/model"
 	repoDomain "food-api/domain/user/domain/repository"
 	"food-api/infrastructure/database"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"strings"
+	"time"
 )
 
+// ErrPasswordMismatch is returned when the password of the login is not the one of the user.
+var ErrPasswordMismatch = errors.New("password does not match")
+
 type sqlUserRepo struct {
 	Conn *database.Data
 }
@@ -32,7 +38,7 @@ func (sr *sqlUserRepo) GetAllUser(ctx context.Context) ([]response.UserResponse,
 	var users []response.UserResponse
 	for rows.Next() {
 		var userRow response.UserResponse
-		_ = rows.Scan(&userRow.ID, &userRow.Names, &userRow.LastNames, &userRow.Email)
+		_ = rows.Scan(&userRow.ID, &userRow.Names, &userRow.LastNames, &userRow.Email, &userRow.Role)
 		users = append(users, userRow)
 	}
 
@@ -43,7 +49,20 @@ func (sr *sqlUserRepo) GetById(ctx context.Context, id string) (response.UserRes
 	row := sr.Conn.DB.QueryRowContext(ctx, selectUserById, id)
 
 	var userScan response.UserResponse
-	err := row.Scan(&userScan.ID, &userScan.Names, &userScan.LastNames, &userScan.Email)
+	err := row.Scan(&userScan.ID, &userScan.Names, &userScan.LastNames, &userScan.Email, &userScan.EmailVerifiedAt, &userScan.Role)
+	if err != nil {
+		return response.UserResponse{}, err
+	}
+
+	return userScan, nil
+}
+
+// GetByEmail returns the user of the email, the password is not returned.
+func (sr *sqlUserRepo) GetByEmail(ctx context.Context, email string) (response.UserResponse, error) {
+	row := sr.Conn.DB.QueryRowContext(ctx, selectUserInfoByEmail, email)
+
+	var userScan response.UserResponse
+	err := row.Scan(&userScan.ID, &userScan.Names, &userScan.LastNames, &userScan.Email, &userScan.EmailVerifiedAt)
 	if err != nil {
 		return response.UserResponse{}, err
 	}
@@ -93,22 +112,183 @@ func (sr *sqlUserRepo) GetUserByEmailAndPassword(ctx context.Context, user *mode
 	row := sr.Conn.DB.QueryRowContext(ctx, selectUserByEmail, user.Email)
 
 	userScan := model.User{}
-	err := row.Scan(&userScan.ID, &userScan.Names, &userScan.LastNames, &userScan.Email, &userScan.PasswordHash, &userScan.CreatedAt, &userScan.UpdatedAt)
+	err := row.Scan(&userScan.ID, &userScan.Names, &userScan.LastNames, &userScan.Email, &userScan.PasswordHash, &userScan.EmailVerifiedAt, &userScan.Role, &userScan.CreatedAt, &userScan.UpdatedAt)
 	if err != nil {
 		return &response.UserResponse{}, err
 	}
 
 	validate := userScan.PasswordMatch(user.Password)
 	if !validate {
-		return &response.UserResponse{}, errors.New("password does not match")
+		return &response.UserResponse{}, ErrPasswordMismatch
 	}
 
 	userResponse := response.UserResponse{
-		ID:        userScan.ID,
-		Names:     userScan.Names,
-		LastNames: userScan.LastNames,
-		Email:     userScan.Email,
+		ID:              userScan.ID,
+		Names:           userScan.Names,
+		LastNames:       userScan.LastNames,
+		Email:           userScan.Email,
+		EmailVerifiedAt: userScan.EmailVerifiedAt,
+		Role:            userScan.Role,
 	}
 
 	return &userResponse, nil
 }
+
+// FollowUser makes followerId a follower of userId, the followers can see the followers-only foods.
+func (sr *sqlUserRepo) FollowUser(ctx context.Context, userId, followerId string) error {
+	stmt, err := sr.Conn.DB.PrepareContext(ctx, insertFollower)
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, userId, followerId, time.Now())
+	return err
+}
+
+// UnfollowUser removes followerId from the followers of userId.
+func (sr *sqlUserRepo) UnfollowUser(ctx context.Context, userId, followerId string) error {
+	stmt, err := sr.Conn.DB.PrepareContext(ctx, deleteFollower)
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, userId, followerId)
+	return err
+}
+
+// DeleteUser soft deletes the user, removes its followers and keeps, moves or deletes its foods
+// depending on the food policy of the deletion. A user already deleted returns sql.ErrNoRows.
+func (sr *sqlUserRepo) DeleteUser(ctx context.Context, deletion *model.Deletion) error {
+	tx, err := sr.Conn.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, deleteUser, deletion.DeletedAt, deletion.UserID)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil || deleted == 0 {
+		_ = tx.Rollback()
+		if err == nil {
+			err = sql.ErrNoRows
+		}
+
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, deleteUserFollowers, deletion.UserID); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if owner := deletion.FoodOwner(); owner != "" {
+		_, err = tx.ExecContext(ctx, transferUserFoods, owner, deletion.DeletedAt, deletion.UserID)
+	} else {
+		_, err = tx.ExecContext(ctx, deleteUserFoods, deletion.UserID)
+	}
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdatePassword replaces the password hash of the user, a deleted or missing user returns sql.ErrNoRows.
+func (sr *sqlUserRepo) UpdatePassword(ctx context.Context, id, passwordHash string, updatedAt time.Time) error {
+	stmt, err := sr.Conn.DB.PrepareContext(ctx, updateUserPassword)
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, passwordHash, updatedAt, id)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// VerifyEmail marks the email of the user as verified, a deleted or missing user or a user whose email is
+// not the one given returns sql.ErrNoRows.
+func (sr *sqlUserRepo) VerifyEmail(ctx context.Context, id, email string, verifiedAt time.Time) error {
+	stmt, err := sr.Conn.DB.PrepareContext(ctx, verifyUserEmail)
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, verifiedAt, id, email)
+	if err != nil {
+		return err
+	}
+
+	verified, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if verified == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// UpdateRole sets the role of the user, a deleted or missing user returns sql.ErrNoRows.
+func (sr *sqlUserRepo) UpdateRole(ctx context.Context, id, role string, updatedAt time.Time) error {
+	stmt, err := sr.Conn.DB.PrepareContext(ctx, updateUserRole)
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, role, updatedAt, id)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// PromoteAdmins sets the admin role to the users of the emails and returns how many were promoted, only
+// the verified emails are promoted so nobody gets the role by signing up first with the email.
+func (sr *sqlUserRepo) PromoteAdmins(ctx context.Context, emails []string, updatedAt time.Time) (int64, error) {
+	result, err := sr.Conn.DB.ExecContext(ctx, promoteAdmins, pq.Array(emails), updatedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
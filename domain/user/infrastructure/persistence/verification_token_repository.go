@@ -0,0 +1,65 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	repoDomain "food-api/domain/user/domain/repository"
+	"github.com/go-redis/redis/v8"
+	"strings"
+	"time"
+)
+
+const verificationTokenKey = "email:verify"
+
+// ErrVerificationTokenInvalid is returned for a verification token that does not exist, expired or was already used.
+var ErrVerificationTokenInvalid = errors.New("the verification token is invalid or expired")
+
+// redisVerificationTokenRepo keeps the tokens that verify the email of the users and throttles
+// the verification emails sent again.
+type redisVerificationTokenRepo struct {
+	tokens tokenStore
+}
+
+func NewVerificationTokenRepository(client *redis.Client) repoDomain.VerificationTokenRepository {
+	return &redisVerificationTokenRepo{
+		tokens: tokenStore{Client: client, prefix: verificationTokenKey, invalid: ErrVerificationTokenInvalid},
+	}
+}
+
+// SaveVerificationToken saves the token sent to the email of the user for ttl and invalidates the previous one.
+func (vr *redisVerificationTokenRepo) SaveVerificationToken(ctx context.Context, userId, email, token string, ttl time.Duration) error {
+	return vr.tokens.save(ctx, userId, token, email, ttl)
+}
+
+// ConsumeVerificationToken returns the user of the token and the email it was sent to and deletes it, a token
+// can only be used once.
+func (vr *redisVerificationTokenRepo) ConsumeVerificationToken(ctx context.Context, token string) (string, string, error) {
+	return vr.tokens.consume(ctx, token)
+}
+
+// DeleteVerificationToken invalidates the token the user has, e.g. when the email changes.
+func (vr *redisVerificationTokenRepo) DeleteVerificationToken(ctx context.Context, userId string) error {
+	return vr.tokens.revoke(ctx, userId)
+}
+
+// ThrottleResend allows a verification email to the email once per interval, it returns how long
+// to wait before the next one or zero when it is allowed. The email is hashed to keep it out of Redis.
+func (vr *redisVerificationTokenRepo) ThrottleResend(ctx context.Context, email string, interval time.Duration) (time.Duration, error) {
+	key := verificationTokenKey + ":resend:" + hashToken(strings.ToLower(email))
+
+	allowed, err := vr.tokens.Client.SetNX(ctx, key, 1, interval).Result()
+	if err != nil || allowed {
+		return 0, err
+	}
+
+	wait, err := vr.tokens.Client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	if wait <= 0 {
+		wait = interval
+	}
+
+	return wait, nil
+}
@@ -1,6 +1,9 @@
 package infrastructure
 
 import (
+	"context"
+	userApp "food-api/domain/user/application"
+	"food-api/domain/user/infrastructure/persistence"
 	"food-api/infrastructure/database"
 	"log"
 )
@@ -17,6 +20,11 @@ func Start(port string) {
 		log.Fatal(err)
 	}
 
+	// The administrators are promoted once the role column exists
+	if err = userApp.BootstrapAdmins(context.Background(), persistence.NewUserRepository(db)); err != nil {
+		log.Fatal(err)
+	}
+
 	//redis details
 	redis := database.NewRedisDB()
 
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"food-api/infrastructure/auth/model"
+	"net/http"
+	"strings"
+)
+
+// APIKeyHeader is the header of the API keys, the Authorization header with the ApiKey scheme also works.
+const APIKeyHeader = "X-API-Key"
+
+// apiKeyScheme is the scheme of the API keys in the Authorization header.
+const apiKeyScheme = "ApiKey"
+
+// ErrAPIKeyInvalid is returned for an API key that does not exist, expired or was revoked.
+var ErrAPIKeyInvalid = errors.New("the API key is invalid or expired")
+
+// APIKeyVerifier returns the access details of the user of an API key.
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key string) (*model.AccessDetails, error)
+}
+
+// ExtractAPIKey returns the API key of the request, it is empty when the request uses a token.
+func ExtractAPIKey(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return strings.TrimSpace(key)
+	}
+
+	parts := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
+	if len(parts) == 2 && strings.EqualFold(parts[0], apiKeyScheme) {
+		return strings.TrimSpace(parts[1])
+	}
+
+	return ""
+}
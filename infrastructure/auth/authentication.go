@@ -9,6 +9,23 @@ import (
 	"time"
 )
 
+// ErrRefreshReused is returned when a refresh token that was already rotated out is presented again,
+// the token was probably stolen so its family is revoked.
+var ErrRefreshReused = errors.New("the refresh token was already used")
+
+// ErrRefreshNotFound is returned when the refresh token expired or was revoked.
+var ErrRefreshNotFound = errors.New("refresh token not found")
+
+// extendExpireScript sets the expiration of the key in milliseconds only when it is later than the
+// current one, a key without expiration gets it.
+const extendExpireScript = `
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl == -2 or (ttl >= 0 and ttl >= tonumber(ARGV[1])) then
+	return 0
+end
+return redis.call('PEXPIRE', KEYS[1], ARGV[1])
+`
+
 type ClientData struct {
 	client *redis.Client
 }
@@ -16,8 +33,14 @@ type ClientData struct {
 type InterfaceAuth interface {
 	CreateAuth(ctx context.Context, userId string, details *model.TokenDetails) error
 	FetchAuth(ctx context.Context, tokenUuid string) (string, error)
-	DeleteRefresh(ctx context.Context, refreshUuid string) error
+	RotateRefresh(ctx context.Context, refreshUuid, family string) error
+	DeleteFamily(ctx context.Context, family string) error
 	DeleteTokens(ctx context.Context, details *model.AccessDetails) error
+	DeleteUserTokens(ctx context.Context, userId string, except ...string) error
+	SaveSession(ctx context.Context, userId string, session *model.Session, expiresAt time.Time) error
+	TouchSession(ctx context.Context, sessionId string, at time.Time) error
+	ListSessions(ctx context.Context, userId string) ([]model.Session, error)
+	DeleteSession(ctx context.Context, userId, sessionId string) error
 }
 
 func NewAuth(client *redis.Client) *ClientData {
@@ -46,7 +69,27 @@ func (cl *ClientData) CreateAuth(ctx context.Context, userId string, details *mo
 		return errors.New("no record inserted")
 	}
 
-	return nil
+	// Keep the tokens of the user to revoke all of them, e.g. when the user is deleted
+	key := userTokensKey(userId)
+	pipe := cl.client.TxPipeline()
+	pipe.SAdd(ctx, key, details.TokenUuid, details.RefreshUuid)
+	extendExpireAt(ctx, pipe, key, rtExpires)
+
+	// Keep the tokens of the family to revoke all of them when a refresh token is reused
+	if details.Family != "" {
+		pipe.SAdd(ctx, familyKey(details.Family), details.TokenUuid, details.RefreshUuid)
+		pipe.ExpireAt(ctx, familyKey(details.Family), rtExpires)
+	}
+
+	_, err = pipe.Exec(ctx)
+
+	return err
+}
+
+// extendExpireAt queues the expiration of the key at the date unless it already expires later, so a
+// short login does not shorten the key of the tokens of a longer one.
+func extendExpireAt(ctx context.Context, pipe redis.Pipeliner, key string, at time.Time) {
+	pipe.Eval(ctx, extendExpireScript, []string{key}, time.Until(at).Milliseconds())
 }
 
 // FetchAuth Get authentication
@@ -59,14 +102,74 @@ func (cl *ClientData) FetchAuth(ctx context.Context, tokenUuid string) (string,
 	return userId, nil
 }
 
-// DeleteRefresh delete refresh token
-func (cl *ClientData) DeleteRefresh(ctx context.Context, refreshUuid string) error {
-	deleted, err := cl.client.Del(ctx, refreshUuid).Result()
-	if err != nil || deleted == 0 {
+// RotateRefresh deletes the refresh token of the family and marks it as used. A used refresh token
+// presented again revokes all the tokens of the family and returns ErrRefreshReused, the tokens issued
+// before the families have none and are only deleted.
+func (cl *ClientData) RotateRefresh(ctx context.Context, refreshUuid, family string) error {
+	if family == "" {
+		deleted, err := cl.client.Del(ctx, refreshUuid).Result()
+		if err != nil {
+			return err
+		}
+
+		// The refresh token was already used or revoked
+		if deleted == 0 {
+			return ErrRefreshNotFound
+		}
+
+		return nil
+	}
+
+	// The delete and the mark run in a transaction so two requests with the same token cannot both rotate it
+	pipe := cl.client.TxPipeline()
+	deleted := pipe.Del(ctx, refreshUuid)
+	marked := pipe.SAdd(ctx, familyUsedKey(family), refreshUuid)
+	// A used token cannot be replayed once its JWT expired, so the set lives as long as the longest refresh token
+	extendExpireAt(ctx, pipe, familyUsedKey(family), time.Now().Add(maxRefreshLifetime()))
+	pipe.SRem(ctx, familyKey(family), refreshUuid)
+	if _, err := pipe.Exec(ctx); err != nil {
 		return err
 	}
 
-	return nil
+	if deleted.Val() == 1 {
+		return nil
+	}
+
+	if marked.Val() == 0 {
+		if err := cl.DeleteFamily(ctx, family); err != nil {
+			return err
+		}
+
+		return ErrRefreshReused
+	}
+
+	// The token expired or was revoked, e.g. by a logout, it was never used
+	if err := cl.client.SRem(ctx, familyUsedKey(family), refreshUuid).Err(); err != nil {
+		return err
+	}
+
+	return ErrRefreshNotFound
+}
+
+// DeleteFamily revokes the access and refresh tokens of the family, the user must login again
+func (cl *ClientData) DeleteFamily(ctx context.Context, family string) error {
+	key := familyKey(family)
+
+	tokens, err := cl.client.SMembers(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+
+	pipe := cl.client.TxPipeline()
+	if len(tokens) > 0 {
+		pipe.Del(ctx, tokens...)
+	}
+
+	// The used tokens are kept so a replay is still detected until they expire
+	pipe.Del(ctx, key)
+	_, err = pipe.Exec(ctx)
+
+	return err
 }
 
 // DeleteTokens Once a user row in the token table
@@ -92,4 +195,52 @@ func (cl *ClientData) DeleteTokens(ctx context.Context, details *model.AccessDet
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+// DeleteUserTokens revokes all the access and refresh tokens of the user but the except ones,
+// e.g. the tokens of the current session
+func (cl *ClientData) DeleteUserTokens(ctx context.Context, userId string, except ...string) error {
+	key := userTokensKey(userId)
+
+	tokens, err := cl.client.SMembers(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+
+	keep := make(map[string]bool, len(except))
+	for _, token := range except {
+		keep[token] = true
+	}
+
+	var revoked []string
+	for _, token := range tokens {
+		if !keep[token] {
+			revoked = append(revoked, token)
+		}
+	}
+
+	if len(revoked) == 0 {
+		return nil
+	}
+
+	pipe := cl.client.TxPipeline()
+	pipe.Del(ctx, revoked...)
+	pipe.SRem(ctx, key, revoked)
+	_, err = pipe.Exec(ctx)
+
+	return err
+}
+
+// familyKey is the set with the current token uuids of the family
+func familyKey(family string) string {
+	return fmt.Sprintf("refresh:family:%s", family)
+}
+
+// familyUsedKey is the set with the refresh uuids rotated out of the family
+func familyUsedKey(family string) string {
+	return fmt.Sprintf("refresh:family:%s:used", family)
+}
+
+// userTokensKey is the set with the token uuids of the user
+func userTokensKey(userId string) string {
+	return fmt.Sprintf("user:tokens:%s", userId)
+}
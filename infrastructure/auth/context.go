@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"context"
+	"food-api/infrastructure/auth/model"
+)
+
+type contextKey struct{}
+
+// accessDetailsKey is the key of the access details in the context of the request
+var accessDetailsKey = contextKey{}
+
+// NewContext returns a copy of the context with the access details of the token
+func NewContext(ctx context.Context, details *model.AccessDetails) context.Context {
+	return context.WithValue(ctx, accessDetailsKey, details)
+}
+
+// FromContext returns the access details saved by the AuthMiddleware
+func FromContext(ctx context.Context) (*model.AccessDetails, bool) {
+	details, ok := ctx.Value(accessDetailsKey).(*model.AccessDetails)
+	return details, ok && details != nil
+}
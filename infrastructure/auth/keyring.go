@@ -0,0 +1,433 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"food-api/infrastructure/auth/model"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Algorithms of the JWT_ALGORITHM env var.
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// defaultKeyRotation is how often a new signing key is generated when JWT_KEY_ROTATION is not set.
+const defaultKeyRotation = 30 * 24 * time.Hour
+
+// keyPublishLead is how long a new key is published in the JWKS before it signs tokens, so the services
+// that cache the JWKS know it before they receive its tokens.
+const keyPublishLead = 10 * time.Minute
+
+// rsaKeyBits is the size of the generated RSA keys.
+const rsaKeyBits = 2048
+
+var (
+	keyring     *Keyring
+	onceKeyring sync.Once
+)
+
+// ErrUnknownKey is returned when a token is signed by a key that is not in the keyring.
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// SigningKey is a private key of the keyring, it signs the tokens from NotBefore.
+type SigningKey struct {
+	ID        string
+	Algorithm string
+	Private   crypto.Signer
+	NotBefore time.Time
+}
+
+// Method returns the jwt-go method of the algorithm of the key.
+func (k *SigningKey) Method() jwt.SigningMethod {
+	if k.Algorithm == AlgorithmEdDSA {
+		return SigningMethodEdDSA
+	}
+
+	return jwt.SigningMethodRS256
+}
+
+// JWK returns the public key in the JWKS format.
+func (k *SigningKey) JWK() model.JWK {
+	jwk := model.JWK{KeyID: k.ID, Use: "sig", Algorithm: k.Algorithm}
+
+	switch public := k.Private.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+
+	return jwk
+}
+
+// GenerateKey returns a new key of the algorithm identified by a random kid.
+func GenerateKey(algorithm string, notBefore time.Time) (*SigningKey, error) {
+	var private crypto.Signer
+	var err error
+
+	switch algorithm {
+	case AlgorithmRS256:
+		private, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case AlgorithmEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %s", algorithm)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &SigningKey{ID: uuid.New().String(), Algorithm: algorithm, Private: private, NotBefore: notBefore}, nil
+}
+
+// Keyring keeps the keys that sign and verify the tokens. The newest active key signs, the previous ones
+// still verify the tokens they signed until the retention passed. When the keyring has a path the keys
+// are PEM files of the folder so every instance of the API shares them.
+type Keyring struct {
+	mu        sync.RWMutex
+	keys      []*SigningKey
+	algorithm string
+	path      string
+	rotation  time.Duration
+	retention time.Duration
+}
+
+// NewKeyring returns a keyring with the keys of the path, a key is generated when it has none.
+// The rotation generates a new key once the signing key is older, zero disables it. The retention is
+// how long a replaced key verifies tokens, it must be the lifetime of the longest token.
+func NewKeyring(algorithm, path string, rotation, retention time.Duration) (*Keyring, error) {
+	kr := &Keyring{algorithm: algorithm, path: path, rotation: rotation, retention: retention}
+
+	if err := kr.load(); err != nil {
+		return nil, err
+	}
+
+	if len(kr.keys) == 0 {
+		key, err := GenerateKey(algorithm, time.Now())
+		if err != nil {
+			return nil, err
+		}
+
+		if err = kr.Add(key); err != nil {
+			return nil, err
+		}
+	}
+
+	return kr, nil
+}
+
+// DefaultKeyring returns the keyring of the JWT_ALGORITHM, JWT_KEYS_PATH and JWT_KEY_ROTATION env vars
+// and starts the rotation. Without JWT_KEYS_PATH the keys only live in memory, the tokens are invalid
+// after a restart.
+func DefaultKeyring() *Keyring {
+	onceKeyring.Do(initKeyring)
+	return keyring
+}
+
+func initKeyring() {
+	algorithm := os.Getenv("JWT_ALGORITHM")
+	if algorithm == "" {
+		algorithm = AlgorithmRS256
+	}
+
+	rotation := defaultKeyRotation
+	if value := os.Getenv("JWT_KEY_ROTATION"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			log.Fatalf("invalid JWT_KEY_ROTATION %s: %s", value, err.Error())
+		}
+
+		rotation = parsed
+	}
+
+	var err error
+	keyring, err = NewKeyring(algorithm, os.Getenv("JWT_KEYS_PATH"), rotation, maxRefreshLifetime())
+	if err != nil {
+		log.Fatalf("cannot load the signing keys: %s", err.Error())
+	}
+
+	keyring.StartRotation()
+}
+
+// Add adds the key to the keyring and saves it in the path.
+func (kr *Keyring) Add(key *SigningKey) error {
+	if kr.path != "" {
+		if err := saveKey(kr.path, key); err != nil {
+			return err
+		}
+	}
+
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	kr.keys = append(kr.keys, key)
+	sortKeys(kr.keys)
+
+	return nil
+}
+
+// SigningKey returns the newest key active at now.
+func (kr *Keyring) SigningKey(now time.Time) *SigningKey {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	for i := len(kr.keys) - 1; i >= 0; i-- {
+		if !kr.keys[i].NotBefore.After(now) {
+			return kr.keys[i]
+		}
+	}
+
+	// Only scheduled keys, the oldest one is the closest to be active
+	if len(kr.keys) > 0 {
+		return kr.keys[0]
+	}
+
+	return nil
+}
+
+// Key returns the key with the kid.
+func (kr *Keyring) Key(kid string) (*SigningKey, bool) {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	for _, key := range kr.keys {
+		if key.ID == kid {
+			return key, true
+		}
+	}
+
+	return nil, false
+}
+
+// JWKS returns the public keys of the keyring, including the scheduled ones.
+func (kr *Keyring) JWKS() model.JWKS {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	jwks := model.JWKS{Keys: make([]model.JWK, 0, len(kr.keys))}
+	for _, key := range kr.keys {
+		jwks.Keys = append(jwks.Keys, key.JWK())
+	}
+
+	return jwks
+}
+
+// Rotate reloads the keys of the path, schedules a new key when the newest one is older than the
+// rotation and removes the keys replaced for longer than the retention. The reload keeps the instances
+// from generating a key each.
+func (kr *Keyring) Rotate(now time.Time) error {
+	if err := kr.load(); err != nil {
+		return err
+	}
+
+	kr.mu.RLock()
+	newest := kr.keys[len(kr.keys)-1]
+	kr.mu.RUnlock()
+
+	if kr.rotation > 0 && !now.Before(newest.NotBefore.Add(kr.rotation-keyPublishLead)) {
+		key, err := GenerateKey(kr.algorithm, now.Add(keyPublishLead))
+		if err != nil {
+			return err
+		}
+
+		if err = kr.Add(key); err != nil {
+			return err
+		}
+	}
+
+	return kr.prune(now)
+}
+
+// StartRotation rotates the keys in background, a failure is only logged so the current keys keep working.
+func (kr *Keyring) StartRotation() {
+	if kr.rotation <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(keyPublishLead)
+		defer ticker.Stop()
+
+		for now := range ticker.C {
+			if err := kr.Rotate(now); err != nil {
+				log.Printf("cannot rotate the signing keys: %s", err.Error())
+			}
+		}
+	}()
+}
+
+// prune removes the keys whose successor signs for longer than the retention.
+func (kr *Keyring) prune(now time.Time) error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	keys := kr.keys[:0]
+	var removed []*SigningKey
+	for i, key := range kr.keys {
+		if i+1 < len(kr.keys) && now.After(kr.keys[i+1].NotBefore.Add(kr.retention)) {
+			removed = append(removed, key)
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	kr.keys = keys
+
+	if kr.path == "" {
+		return nil
+	}
+
+	for _, key := range removed {
+		if err := os.Remove(keyFile(kr.path, key.ID)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// load adds the keys of the path that are not in the keyring yet.
+func (kr *Keyring) load() error {
+	if kr.path == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(kr.path, 0700); err != nil {
+		return err
+	}
+
+	files, err := filepath.Glob(filepath.Join(kr.path, "*.pem"))
+	if err != nil {
+		return err
+	}
+
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	known := make(map[string]bool, len(kr.keys))
+	for _, key := range kr.keys {
+		known[key.ID] = true
+	}
+
+	for _, file := range files {
+		kid := strings.TrimSuffix(filepath.Base(file), ".pem")
+		if known[kid] {
+			continue
+		}
+
+		key, err := readKey(file, kid)
+		if err != nil {
+			return err
+		}
+
+		kr.keys = append(kr.keys, key)
+	}
+
+	sortKeys(kr.keys)
+
+	return nil
+}
+
+// saveKey writes the key in a PKCS #8 PEM file named by the kid, the headers keep the algorithm and
+// the activation date.
+func saveKey(path string, key *SigningKey) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key.Private)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(path, 0700); err != nil {
+		return err
+	}
+
+	block := &pem.Block{
+		Type: "PRIVATE KEY",
+		Headers: map[string]string{
+			"Algorithm":  key.Algorithm,
+			"Not-Before": key.NotBefore.UTC().Format(time.RFC3339),
+		},
+		Bytes: der,
+	}
+
+	return ioutil.WriteFile(keyFile(path, key.ID), pem.EncodeToMemory(block), 0600)
+}
+
+func readKey(file, kid string) (*SigningKey, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("%s is not a PEM private key", file)
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &SigningKey{ID: kid}
+	switch private := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Algorithm, key.Private = AlgorithmRS256, private
+	case ed25519.PrivateKey:
+		key.Algorithm, key.Private = AlgorithmEdDSA, private
+	default:
+		return nil, fmt.Errorf("%s has an unsupported key type", file)
+	}
+
+	// A key added by hand has no header, it is active since the file was written
+	if notBefore, ok := block.Headers["Not-Before"]; ok {
+		key.NotBefore, err = time.Parse(time.RFC3339, notBefore)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+
+		key.NotBefore = info.ModTime()
+	}
+
+	return key, nil
+}
+
+func keyFile(path, kid string) string {
+	return filepath.Join(path, kid+".pem")
+}
+
+// sortKeys orders the keys from the oldest to the newest.
+func sortKeys(keys []*SigningKey) {
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].NotBefore.Before(keys[j].NotBefore)
+	})
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"os"
+	"time"
+)
+
+// Default lifetimes of the tokens
+const (
+	defaultAccessTokenLifetime     = 15 * time.Minute
+	defaultRefreshTokenLifetime    = 7 * 24 * time.Hour
+	defaultRememberMeTokenLifetime = 30 * 24 * time.Hour
+)
+
+// AccessTokenLifetime returns the ACCESS_TOKEN_TTL env var, how long an access token is valid.
+func AccessTokenLifetime() time.Duration {
+	return durationEnv("ACCESS_TOKEN_TTL", defaultAccessTokenLifetime)
+}
+
+// RefreshTokenLifetime returns how long a session lasts since the login, the REFRESH_TOKEN_TTL env var or
+// the REMEMBER_ME_TOKEN_TTL one when the user asked to be remembered. The refreshes do not extend it.
+func RefreshTokenLifetime(rememberMe bool) time.Duration {
+	if rememberMe {
+		return durationEnv("REMEMBER_ME_TOKEN_TTL", defaultRememberMeTokenLifetime)
+	}
+
+	return durationEnv("REFRESH_TOKEN_TTL", defaultRefreshTokenLifetime)
+}
+
+// RefreshIdleTimeout returns the REFRESH_IDLE_TIMEOUT env var, a refresh token not used within it expires
+// and each refresh slides the window. Zero disables it, the remembered sessions do not have it.
+func RefreshIdleTimeout() time.Duration {
+	return durationEnv("REFRESH_IDLE_TIMEOUT", 0)
+}
+
+// maxRefreshLifetime returns the lifetime of the longest refresh token, the keys must verify it.
+func maxRefreshLifetime() time.Duration {
+	lifetime := RefreshTokenLifetime(false)
+	if remember := RefreshTokenLifetime(true); remember > lifetime {
+		return remember
+	}
+
+	return lifetime
+}
+
+func durationEnv(name string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(name))
+	if err != nil || value < 0 || (value == 0 && defaultValue != 0) {
+		return defaultValue
+	}
+
+	return value
+}
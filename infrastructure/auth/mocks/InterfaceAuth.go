@@ -5,6 +5,7 @@ package mocks
 import (
 	context "context"
 	model "food-api/infrastructure/auth/model"
+	time "time"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -28,13 +29,27 @@ func (_m *InterfaceAuth) CreateAuth(ctx context.Context, userId string, details
 	return r0
 }
 
-// DeleteRefresh provides a mock function with given fields: ctx, refreshUuid
-func (_m *InterfaceAuth) DeleteRefresh(ctx context.Context, refreshUuid string) error {
-	ret := _m.Called(ctx, refreshUuid)
+// DeleteFamily provides a mock function with given fields: ctx, family
+func (_m *InterfaceAuth) DeleteFamily(ctx context.Context, family string) error {
+	ret := _m.Called(ctx, family)
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, refreshUuid)
+		r0 = rf(ctx, family)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteSession provides a mock function with given fields: ctx, userId, sessionId
+func (_m *InterfaceAuth) DeleteSession(ctx context.Context, userId string, sessionId string) error {
+	ret := _m.Called(ctx, userId, sessionId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userId, sessionId)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -56,6 +71,27 @@ func (_m *InterfaceAuth) DeleteTokens(ctx context.Context, details *model.Access
 	return r0
 }
 
+// DeleteUserTokens provides a mock function with given fields: ctx, userId, except
+func (_m *InterfaceAuth) DeleteUserTokens(ctx context.Context, userId string, except ...string) error {
+	_va := make([]interface{}, len(except))
+	for _i := range except {
+		_va[_i] = except[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, userId)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...string) error); ok {
+		r0 = rf(ctx, userId, except...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FetchAuth provides a mock function with given fields: ctx, tokenUuid
 func (_m *InterfaceAuth) FetchAuth(ctx context.Context, tokenUuid string) (string, error) {
 	ret := _m.Called(ctx, tokenUuid)
@@ -76,3 +112,68 @@ func (_m *InterfaceAuth) FetchAuth(ctx context.Context, tokenUuid string) (strin
 
 	return r0, r1
 }
+
+// ListSessions provides a mock function with given fields: ctx, userId
+func (_m *InterfaceAuth) ListSessions(ctx context.Context, userId string) ([]model.Session, error) {
+	ret := _m.Called(ctx, userId)
+
+	var r0 []model.Session
+	if rf, ok := ret.Get(0).(func(context.Context, string) []model.Session); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Session)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RotateRefresh provides a mock function with given fields: ctx, refreshUuid, family
+func (_m *InterfaceAuth) RotateRefresh(ctx context.Context, refreshUuid string, family string) error {
+	ret := _m.Called(ctx, refreshUuid, family)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, refreshUuid, family)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveSession provides a mock function with given fields: ctx, userId, session, expiresAt
+func (_m *InterfaceAuth) SaveSession(ctx context.Context, userId string, session *model.Session, expiresAt time.Time) error {
+	ret := _m.Called(ctx, userId, session, expiresAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *model.Session, time.Time) error); ok {
+		r0 = rf(ctx, userId, session, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TouchSession provides a mock function with given fields: ctx, sessionId, at
+func (_m *InterfaceAuth) TouchSession(ctx context.Context, sessionId string, at time.Time) error {
+	ret := _m.Called(ctx, sessionId, at)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, sessionId, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
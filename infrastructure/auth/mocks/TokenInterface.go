@@ -3,12 +3,11 @@
 package mocks
 
 import (
+	model "food-api/infrastructure/auth/model"
+	jwt "github.com/dgrijalva/jwt-go"
 	http "net/http"
 
-	jwt "github.com/dgrijalva/jwt-go"
 	mock "github.com/stretchr/testify/mock"
-
-	model "food-api/infrastructure/auth/model"
 )
 
 // TokenInterface is an autogenerated mock type for the TokenInterface type
@@ -16,13 +15,34 @@ type TokenInterface struct {
 	mock.Mock
 }
 
-// CreateToken provides a mock function with given fields: userid
-func (_m *TokenInterface) CreateToken(userid string) (*model.TokenDetails, error) {
-	ret := _m.Called(userid)
+// CreateIDToken provides a mock function with given fields: userid, options
+func (_m *TokenInterface) CreateIDToken(userid string, options model.IDTokenOptions) (string, error) {
+	ret := _m.Called(userid, options)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, model.IDTokenOptions) string); ok {
+		r0 = rf(userid, options)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, model.IDTokenOptions) error); ok {
+		r1 = rf(userid, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateToken provides a mock function with given fields: userid, options
+func (_m *TokenInterface) CreateToken(userid string, options model.TokenOptions) (*model.TokenDetails, error) {
+	ret := _m.Called(userid, options)
 
 	var r0 *model.TokenDetails
-	if rf, ok := ret.Get(0).(func(string) *model.TokenDetails); ok {
-		r0 = rf(userid)
+	if rf, ok := ret.Get(0).(func(string, model.TokenOptions) *model.TokenDetails); ok {
+		r0 = rf(userid, options)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.TokenDetails)
@@ -30,8 +50,8 @@ func (_m *TokenInterface) CreateToken(userid string) (*model.TokenDetails, error
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(userid)
+	if rf, ok := ret.Get(1).(func(string, model.TokenOptions) error); ok {
+		r1 = rf(userid, options)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -62,6 +82,20 @@ func (_m *TokenInterface) ExtractTokenMetadata(_a0 *http.Request) (*model.Access
 	return r0, r1
 }
 
+// JWKS provides a mock function with given fields:
+func (_m *TokenInterface) JWKS() model.JWKS {
+	ret := _m.Called()
+
+	var r0 model.JWKS
+	if rf, ok := ret.Get(0).(func() model.JWKS); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(model.JWKS)
+	}
+
+	return r0
+}
+
 // VerifyAndValidateRefreshToken provides a mock function with given fields: refreshToken
 func (_m *TokenInterface) VerifyAndValidateRefreshToken(refreshToken string) (*jwt.Token, error) {
 	ret := _m.Called(refreshToken)
@@ -1,6 +1,19 @@
 package model
 
+import "time"
+
 type AccessDetails struct {
 	TokenUuid string
 	UserId    string
+	Role      string
+	Scopes    []string
+	Family    string
+	// APIKeyID is the API key of the request, it is empty for the tokens
+	APIKeyID string
+	// ClientID is the OAuth client of the token with the OpenID Connect scopes the user granted to it, it
+	// is empty for the logins
+	ClientID       string
+	IdentityScopes []string
+	// AuthTime is when the user logged in, it is zero for the API keys and the tokens issued before it
+	AuthTime time.Time
 }
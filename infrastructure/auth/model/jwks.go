@@ -0,0 +1,29 @@
+package model
+
+// JWK is the public key of a signing key, see RFC 7517.
+// swagger:model
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	// RSA modulus and exponent
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519 curve and public key
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// JWKS is the set of public keys that verify the tokens.
+// swagger:model
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS It is the response of the public keys.
+// swagger:response SwaggerJWKS
+type SwaggerJWKS struct {
+	//in: body
+	Body JWKS
+}
@@ -6,8 +6,13 @@ type DataLogin struct {
 	ID           string `json:"id,omitempty"`
 	Names        string `json:"names,omitempty"`
 	LastNames    string `json:"last_names,omitempty"`
+	Role         string `json:"role,omitempty"`
+	Scope        string `json:"scope,omitempty"`
 	AccessToken  string `json:"access_token,omitempty"`
 	RefreshToken string `json:"refresh_token,omitempty"`
+	// The login waits for the code of the two-factor authentication, the challenge token is sent with it
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
 }
 
 // Information from user
@@ -19,6 +24,10 @@ type SwaggerUserLoginRequest struct {
 		Email    string `json:"email,omitempty"`
 		// Required: true
 		Password string `json:"password,omitempty"`
+		// Space separated scopes of the tokens, all of them when it is empty
+		Scope    string `json:"scope,omitempty"`
+		// Keep the session for longer on this device
+		RememberMe bool `json:"remember_me,omitempty"`
 	}
 }
 
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Scopes of the tokens, a token only reaches the routes of its scopes.
+const (
+	ScopeFoodsRead  = "foods:read"
+	ScopeFoodsWrite = "foods:write"
+	ScopeUsersRead  = "users:read"
+	ScopeUsersWrite = "users:write"
+)
+
+// AllScopes are the scopes of a token when no subset is requested.
+var AllScopes = []string{ScopeFoodsRead, ScopeFoodsWrite, ScopeUsersRead, ScopeUsersWrite}
+
+// OpenID Connect scopes of the tokens of the OAuth clients, they release the claims of the user instead of
+// giving access to routes.
+const (
+	ScopeOpenID  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+)
+
+// IdentityScopes are the OpenID Connect scopes an OAuth client can request.
+var IdentityScopes = []string{ScopeOpenID, ScopeProfile, ScopeEmail}
+
+// ValidScope reports whether the scope exists.
+func ValidScope(scope string) bool {
+	for _, s := range AllScopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseScopes returns the scopes of a space separated list, an empty list means all the scopes.
+func ParseScopes(scope string) ([]string, error) {
+	fields := strings.Fields(scope)
+	if len(fields) == 0 {
+		return append([]string(nil), AllScopes...), nil
+	}
+
+	scopes := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if !ValidScope(field) {
+			return nil, fmt.Errorf("invalid scope %s", field)
+		}
+
+		if !HasScope(scopes, field) {
+			scopes = append(scopes, field)
+		}
+	}
+
+	return scopes, nil
+}
+
+// FormatScopes returns the scopes as a space separated list, the format of the scope claim.
+func FormatScopes(scopes []string) string {
+	return strings.Join(scopes, " ")
+}
+
+// HasScope reports whether the scopes contain the required one.
+func HasScope(scopes []string, required string) bool {
+	for _, scope := range scopes {
+		if scope == required {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SplitScopes returns the scopes of the API and the OpenID Connect scopes of a space separated list, the
+// format of the scope claim of the tokens of the OAuth clients. Unlike ParseScopes, an empty list means
+// no scope.
+func SplitScopes(scope string) ([]string, []string, error) {
+	scopes := make([]string, 0)
+	identity := make([]string, 0)
+
+	for _, field := range strings.Fields(scope) {
+		switch {
+		case HasScope(IdentityScopes, field):
+			if !HasScope(identity, field) {
+				identity = append(identity, field)
+			}
+		case ValidScope(field):
+			if !HasScope(scopes, field) {
+				scopes = append(scopes, field)
+			}
+		default:
+			return nil, nil, fmt.Errorf("invalid scope %s", field)
+		}
+	}
+
+	return scopes, identity, nil
+}
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// Session is a login of a user, it lives while the tokens of its refresh family are valid.
+// swagger:model
+type Session struct {
+	ID         string    `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	IP         string    `json:"ip,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	Current    bool      `json:"current"`
+}
+
+// Session It is the response of the sessions of the user.
+// swagger:response SwaggerSessionsResponse
+type SwaggerSessionsResponse struct {
+	// in: body
+	Body []Session
+}
+
+// swagger:parameters idSessionPath
+type SwaggerSession struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+
+	// in: path
+	// Required: true
+	ID string
+}
+
+// swagger:parameters getSessions deleteSessions
+type SwaggerSessions struct {
+	// type: apiKey
+	// in: header
+	// Required: true
+	Authorization string
+}
@@ -5,6 +5,7 @@ type TokenDetails struct {
 	RefreshToken string
 	TokenUuid    string
 	RefreshUuid  string
+	Family       string
 	AtExpires    int64
 	RtExpires    int64
 }
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// TokenOptions are the claims of the tokens of a user. The family, the authentication time and the
+// remember me flag of a login are kept by its refreshes, an empty family and a zero time start a new login.
+// The tokens of an OAuth client carry its id, their scopes include the OpenID Connect ones.
+type TokenOptions struct {
+	Role       string
+	Scopes     []string
+	Family     string
+	AuthTime   time.Time
+	RememberMe bool
+	ClientID   string
+}
+
+// IDTokenOptions are the claims of an OpenID Connect ID token of a user for an OAuth client, the claims
+// are the ones of the user released by the scopes.
+type IDTokenOptions struct {
+	Issuer   string
+	ClientID string
+	Nonce    string
+	AuthTime time.Time
+	Claims   map[string]interface{}
+}
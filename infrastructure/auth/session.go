@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"food-api/infrastructure/auth/model"
+	"sort"
+	"time"
+)
+
+// ErrSessionNotFound is returned when the session does not exist or belongs to another user.
+var ErrSessionNotFound = errors.New("session not found")
+
+// SaveSession saves the metadata of the session of the refresh family, the creation date is only set by
+// the login and the refresh updates the rest. The session expires with the refresh token.
+func (cl *ClientData) SaveSession(ctx context.Context, userId string, session *model.Session, expiresAt time.Time) error {
+	key := sessionKey(session.ID)
+
+	pipe := cl.client.TxPipeline()
+	pipe.HSetNX(ctx, key, "created_at", session.CreatedAt.UTC().Format(time.RFC3339Nano))
+	pipe.HMSet(ctx, key, map[string]interface{}{
+		"user_id":      userId,
+		"last_used_at": session.LastUsedAt.UTC().Format(time.RFC3339Nano),
+		"ip":           session.IP,
+		"user_agent":   session.UserAgent,
+	})
+	pipe.ExpireAt(ctx, key, expiresAt)
+	pipe.SAdd(ctx, userSessionsKey(userId), session.ID)
+	extendExpireAt(ctx, pipe, userSessionsKey(userId), expiresAt)
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
+// TouchSession updates the last use of the session, a session that expired is not created again.
+func (cl *ClientData) TouchSession(ctx context.Context, sessionId string, at time.Time) error {
+	key := sessionKey(sessionId)
+
+	exists, err := cl.client.Exists(ctx, key).Result()
+	if err != nil || exists == 0 {
+		return err
+	}
+
+	return cl.client.HSet(ctx, key, "last_used_at", at.UTC().Format(time.RFC3339Nano)).Err()
+}
+
+// ListSessions returns the sessions of the user from the most recently used. The sessions whose tokens
+// were all revoked, e.g. by a password change, are removed.
+func (cl *ClientData) ListSessions(ctx context.Context, userId string) ([]model.Session, error) {
+	ids, err := cl.client.SMembers(ctx, userSessionsKey(userId)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]model.Session, 0, len(ids))
+	for _, id := range ids {
+		session, err := cl.session(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if session == nil {
+			if err = cl.removeSession(ctx, userId, id); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		sessions = append(sessions, *session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+	})
+
+	return sessions, nil
+}
+
+// DeleteSession revokes the tokens of the session of the user.
+func (cl *ClientData) DeleteSession(ctx context.Context, userId, sessionId string) error {
+	member, err := cl.client.SIsMember(ctx, userSessionsKey(userId), sessionId).Result()
+	if err != nil {
+		return err
+	}
+
+	if !member {
+		return ErrSessionNotFound
+	}
+
+	if err = cl.DeleteFamily(ctx, sessionId); err != nil {
+		return err
+	}
+
+	return cl.removeSession(ctx, userId, sessionId)
+}
+
+// session returns the session while a token of its family is valid, nil otherwise.
+func (cl *ClientData) session(ctx context.Context, id string) (*model.Session, error) {
+	tokens, err := cl.client.SMembers(ctx, familyKey(id)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	valid, err := cl.client.Exists(ctx, tokens...).Result()
+	if err != nil || valid == 0 {
+		return nil, err
+	}
+
+	values, err := cl.client.HGetAll(ctx, sessionKey(id)).Result()
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+
+	session := &model.Session{ID: id, IP: values["ip"], UserAgent: values["user_agent"]}
+	session.CreatedAt, _ = time.Parse(time.RFC3339Nano, values["created_at"])
+	session.LastUsedAt, _ = time.Parse(time.RFC3339Nano, values["last_used_at"])
+
+	return session, nil
+}
+
+func (cl *ClientData) removeSession(ctx context.Context, userId, sessionId string) error {
+	pipe := cl.client.TxPipeline()
+	pipe.Del(ctx, sessionKey(sessionId))
+	pipe.SRem(ctx, userSessionsKey(userId), sessionId)
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
+// sessionKey is the hash with the metadata of the session
+func sessionKey(sessionId string) string {
+	return fmt.Sprintf("session:%s", sessionId)
+}
+
+// userSessionsKey is the set with the session ids of the user
+func userSessionsKey(userId string) string {
+	return fmt.Sprintf("user:sessions:%s", userId)
+}
@@ -1,65 +1,115 @@
 package auth
 
 import (
+	"errors"
 	"fmt"
 	"food-api/infrastructure/auth/model"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/google/uuid"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 )
 
-type Token struct{}
+// Values of the token_type claim, an access token cannot be used as a refresh token and the other way around
+const (
+	tokenTypeAccess  = "access"
+	tokenTypeRefresh = "refresh"
+	tokenTypeID      = "id"
+)
+
+// ErrSessionExpired is returned when the lifetime of the login is over, the user must login again
+var ErrSessionExpired = errors.New("the session expired")
+
+// Token signs the tokens with the keys of the keyring
+type Token struct {
+	Keys *Keyring
+}
 
 func NewToken() *Token {
-	return &Token{}
+	return &Token{Keys: DefaultKeyring()}
 }
 
 type TokenInterface interface {
-	CreateToken(userid string) (*model.TokenDetails, error)
+	CreateToken(userid string, options model.TokenOptions) (*model.TokenDetails, error)
+	CreateIDToken(userid string, options model.IDTokenOptions) (string, error)
 	ExtractTokenMetadata(*http.Request) (*model.AccessDetails, error)
 	VerifyAndValidateRefreshToken(refreshToken string) (*jwt.Token, error)
+	JWKS() model.JWKS
 }
 
 //Token implements the TokenInterface
 var _ TokenInterface = &Token{}
 
-// CreateToken generates a valid token for the system
-func (t *Token) CreateToken(userid string) (*model.TokenDetails, error) {
+// CreateToken generates a valid token for the system, the access token carries the role of the user and
+// both tokens carry the scopes so a refresh keeps them. The refresh tokens of a login belong to a family, an
+// empty family starts a new one. The refresh token expires at the end of the lifetime of the login or when
+// it is not used within the idle timeout, whichever comes first. The tokens of an OAuth client carry its id
+func (t *Token) CreateToken(userid string, options model.TokenOptions) (*model.TokenDetails, error) {
 
 	tokenDetails := &model.TokenDetails{}
 
-	tokenDetails.AtExpires = time.Now().Add(time.Minute * 15).Unix()
+	now := time.Now()
+	authTime := options.AuthTime
+	if authTime.IsZero() {
+		authTime = now
+	}
+
+	rtExpires := authTime.Add(RefreshTokenLifetime(options.RememberMe))
+	if idle := RefreshIdleTimeout(); idle > 0 && !options.RememberMe && now.Add(idle).Before(rtExpires) {
+		rtExpires = now.Add(idle)
+	}
+
+	if !rtExpires.After(now) {
+		return nil, ErrSessionExpired
+	}
+
+	tokenDetails.AtExpires = now.Add(AccessTokenLifetime()).Unix()
 	tokenDetails.TokenUuid = uuid.New().String()
-	tokenDetails.RtExpires = time.Now().Add(time.Hour * 24 * 7).Unix()
+	tokenDetails.RtExpires = rtExpires.Unix()
 	tokenDetails.RefreshUuid = tokenDetails.TokenUuid + "++" + userid
+	tokenDetails.Family = options.Family
+	if tokenDetails.Family == "" {
+		tokenDetails.Family = uuid.New().String()
+	}
 
 	var err error
 
 	//Creating Access Token
 	atClaims := jwt.MapClaims{}
 	atClaims["authorized"] = true
+	atClaims["token_type"] = tokenTypeAccess
 	atClaims["access_uuid"] = tokenDetails.TokenUuid
 	atClaims["user_id"] = userid
+	atClaims["role"] = options.Role
+	atClaims["scope"] = model.FormatScopes(options.Scopes)
+	atClaims["family"] = tokenDetails.Family
+	atClaims["auth_time"] = authTime.Unix()
 	atClaims["exp"] = tokenDetails.AtExpires
-	at := jwt.NewWithClaims(jwt.SigningMethodHS256, atClaims)
+	if options.ClientID != "" {
+		atClaims["client_id"] = options.ClientID
+	}
 
-	tokenDetails.AccessToken, err = at.SignedString([]byte(os.Getenv("ACCESS_SECRET")))
+	tokenDetails.AccessToken, err = t.sign(atClaims, now)
 	if err != nil {
 		return nil, err
 	}
 
 	//Creating RefreshHandler Token
 	rtClaims := jwt.MapClaims{}
+	rtClaims["token_type"] = tokenTypeRefresh
 	rtClaims["refresh_uuid"] = tokenDetails.RefreshUuid
 	rtClaims["user_id"] = userid
+	rtClaims["family"] = tokenDetails.Family
+	rtClaims["scope"] = model.FormatScopes(options.Scopes)
+	rtClaims["auth_time"] = authTime.Unix()
+	rtClaims["remember_me"] = options.RememberMe
 	rtClaims["exp"] = tokenDetails.RtExpires
+	if options.ClientID != "" {
+		rtClaims["client_id"] = options.ClientID
+	}
 
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, rtClaims)
-	tokenDetails.RefreshToken, err = refreshToken.SignedString([]byte(os.Getenv("REFRESH_SECRET")))
-
+	tokenDetails.RefreshToken, err = t.sign(rtClaims, now)
 	if err != nil {
 		return nil, err
 	}
@@ -67,10 +117,41 @@ func (t *Token) CreateToken(userid string) (*model.TokenDetails, error) {
 	return tokenDetails, nil
 }
 
-// ExtractTokenMetadata extract metadata from token
+// CreateIDToken generates the OpenID Connect ID token of the user for the OAuth client, it expires with the
+// access token. Its token_type keeps it from being used as an access token
+func (t *Token) CreateIDToken(userid string, options model.IDTokenOptions) (string, error) {
+	now := time.Now()
+
+	claims := jwt.MapClaims{}
+	for name, value := range options.Claims {
+		claims[name] = value
+	}
+
+	claims["token_type"] = tokenTypeID
+	claims["iss"] = options.Issuer
+	claims["sub"] = userid
+	claims["aud"] = options.ClientID
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(AccessTokenLifetime()).Unix()
+	if !options.AuthTime.IsZero() {
+		claims["auth_time"] = options.AuthTime.Unix()
+	}
+
+	if options.Nonce != "" {
+		claims["nonce"] = options.Nonce
+	}
+
+	return t.sign(claims, now)
+}
+
+// ExtractTokenMetadata extract metadata from token, the AuthMiddleware already saves them in the context
 func (t *Token) ExtractTokenMetadata(r *http.Request) (*model.AccessDetails, error) {
+	if details, ok := FromContext(r.Context()); ok {
+		return details, nil
+	}
+
 	fmt.Println("We Entered Metadata")
-	token, err := VerifyToken(r)
+	token, err := t.verify(extractToken(r), tokenTypeAccess)
 
 	if err != nil {
 		return nil, err
@@ -88,9 +169,45 @@ func (t *Token) ExtractTokenMetadata(r *http.Request) (*model.AccessDetails, err
 			return nil, err
 		}
 
+		// The tokens issued before the roles have none, they belong to users
+		role, ok := claims["role"].(string)
+		if !ok {
+			role = model.RoleUser
+		}
+
+		// The tokens issued before the scopes have all of them, the ones of an OAuth client only have
+		// the scopes the user granted to it
+		scope, _ := claims["scope"].(string)
+		clientId, _ := claims["client_id"].(string)
+		var scopes, identityScopes []string
+		if clientId != "" {
+			scopes, identityScopes, err = model.SplitScopes(scope)
+		} else {
+			scopes, err = model.ParseScopes(scope)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		// The family is the session of the token
+		family, _ := claims["family"].(string)
+
+		// The tokens issued before the authentication time have none
+		var authTime time.Time
+		if seconds, ok := claims["auth_time"].(float64); ok {
+			authTime = time.Unix(int64(seconds), 0)
+		}
+
 		accessDetail := &model.AccessDetails{
-			TokenUuid: accessUuid,
-			UserId:    userId,
+			TokenUuid:      accessUuid,
+			UserId:         userId,
+			Role:           role,
+			Scopes:         scopes,
+			Family:         family,
+			ClientID:       clientId,
+			IdentityScopes: identityScopes,
+			AuthTime:       authTime,
 		}
 
 		return accessDetail, nil
@@ -98,60 +215,54 @@ func (t *Token) ExtractTokenMetadata(r *http.Request) (*model.AccessDetails, err
 	return nil, err
 }
 
-// TokenValid validate that it is a valid system token
-func TokenValid(r *http.Request) error {
-	token, err := VerifyToken(r)
+// VerifyAndValidateRefreshToken verify refresh token and its signature and
+// validate that it is a valid system refresh token
+func (t *Token) VerifyAndValidateRefreshToken(refreshToken string) (*jwt.Token, error) {
+	return t.verify(refreshToken, tokenTypeRefresh)
+}
 
-	if err != nil {
-		return err
-	}
+// JWKS returns the public keys that verify the tokens
+func (t *Token) JWKS() model.JWKS {
+	return t.Keys.JWKS()
+}
 
-	if _, ok := token.Claims.(jwt.Claims); !ok && !token.Valid {
-		return err
+// sign signs the claims with the signing key, the kid header tells which key verifies the token
+func (t *Token) sign(claims jwt.MapClaims, now time.Time) (string, error) {
+	key := t.Keys.SigningKey(now)
+	if key == nil {
+		return "", ErrUnknownKey
 	}
 
-	return nil
+	token := jwt.NewWithClaims(key.Method(), claims)
+	token.Header["kid"] = key.ID
+
+	return token.SignedString(key.Private)
 }
 
-// VerifyToken verify token and its signature
-func VerifyToken(r *http.Request) (*jwt.Token, error) {
-	tokenString := extractToken(r)
+// verify verifies the signature of the token with the key of its kid and checks the type of the token
+func (t *Token) verify(tokenString, tokenType string) (*jwt.Token, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-
-		//Make sure that the token method conform to "SigningMethodHMAC"
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		kid, _ := token.Header["kid"].(string)
+		key, ok := t.Keys.Key(kid)
+		if !ok {
+			return nil, ErrUnknownKey
 		}
 
-		return []byte(os.Getenv("ACCESS_SECRET")), nil
-	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	return token, nil
-}
-
-// VerifyAndValidateRefreshToken verify refresh token and its signature and
-// validate that it is a valid system refresh token
-func (t *Token)  VerifyAndValidateRefreshToken(refreshToken string) (*jwt.Token, error) {
-	token, err := jwt.Parse(refreshToken, func(token *jwt.Token) (interface{}, error) {
-
-		//Make sure that the token method conform to "SigningMethodHMAC"
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		//Make sure that the token method is the one of the key
+		if token.Method.Alg() != key.Method().Alg() {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 
-		return []byte(os.Getenv("REFRESH_SECRET")), nil
+		return key.Private.Public(), nil
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	if _, ok := token.Claims.(jwt.Claims); !ok && !token.Valid {
-		return nil, err
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid || claims["token_type"] != tokenType {
+		return nil, fmt.Errorf("invalid %s token", tokenType)
 	}
 
 	return token, nil
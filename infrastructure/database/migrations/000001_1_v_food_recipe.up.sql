@@ -0,0 +1,2 @@
+ALTER TABLE "food" ADD COLUMN IF NOT EXISTS ingredients text[] NOT NULL DEFAULT '{}';
+ALTER TABLE "food" ADD COLUMN IF NOT EXISTS instructions text[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "food_slug_history";
+
+DROP INDEX IF EXISTS food_slug_key;
+
+ALTER TABLE "food" DROP COLUMN IF EXISTS slug;
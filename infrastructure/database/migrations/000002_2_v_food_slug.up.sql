@@ -0,0 +1,22 @@
+ALTER TABLE "food" ADD COLUMN IF NOT EXISTS slug character varying(160);
+
+UPDATE "food"
+SET slug = COALESCE(NULLIF(trim(both '-' from regexp_replace(lower(title), '[^a-z0-9]+', '-', 'g')), ''), 'food') || '-' || substr(id::text, 1, 8)
+WHERE slug IS NULL;
+
+ALTER TABLE "food" ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS food_slug_key ON "food" (slug);
+
+CREATE TABLE IF NOT EXISTS "food_slug_history" (
+    slug character varying(160) NOT NULL,
+    food_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (slug),
+    CONSTRAINT fk_food FOREIGN KEY (food_id)
+        REFERENCES "food" (id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
+
+ALTER TABLE "food_slug_history" OWNER to postgres;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS "user_follower";
+
+DROP INDEX IF EXISTS food_visibility_idx;
+
+ALTER TABLE "food" DROP CONSTRAINT IF EXISTS food_visibility_check;
+
+ALTER TABLE "food" DROP COLUMN IF EXISTS visibility;
@@ -0,0 +1,25 @@
+-- The foods created before the visibility existed stay public, only the new ones are private by default
+ALTER TABLE "food" ADD COLUMN IF NOT EXISTS visibility character varying(20) NOT NULL DEFAULT 'public';
+
+ALTER TABLE "food" ALTER COLUMN visibility SET DEFAULT 'private';
+
+ALTER TABLE "food" ADD CONSTRAINT food_visibility_check CHECK (visibility IN ('private', 'followers', 'public'));
+
+CREATE INDEX IF NOT EXISTS food_visibility_idx ON "food" (visibility);
+
+CREATE TABLE IF NOT EXISTS "user_follower" (
+    user_id uuid NOT NULL,
+    follower_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (user_id, follower_id),
+    CONSTRAINT fk_user FOREIGN KEY (user_id)
+        REFERENCES "user" (id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE,
+    CONSTRAINT fk_follower FOREIGN KEY (follower_id)
+        REFERENCES "user" (id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
+
+ALTER TABLE "user_follower" OWNER to postgres;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "food_translation";
+
+ALTER TABLE "food" DROP COLUMN IF EXISTS language;
@@ -0,0 +1,18 @@
+ALTER TABLE "food" ADD COLUMN IF NOT EXISTS language character varying(35) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS "food_translation" (
+    food_id uuid NOT NULL,
+    language character varying(35) NOT NULL,
+    title character varying(150) NOT NULL,
+    description text NOT NULL,
+    instructions text[] NOT NULL DEFAULT '{}',
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (food_id, language),
+    CONSTRAINT fk_food FOREIGN KEY (food_id)
+        REFERENCES "food" (id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
+
+ALTER TABLE "food_translation" OWNER to postgres;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "food_preference";
+
+DROP INDEX IF EXISTS food_tags_idx;
+
+ALTER TABLE "food" DROP COLUMN IF EXISTS tags;
@@ -0,0 +1,24 @@
+ALTER TABLE "food" ADD COLUMN IF NOT EXISTS tags text[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS food_tags_idx ON "food" USING GIN (tags);
+
+CREATE TABLE IF NOT EXISTS "food_preference" (
+    user_id uuid NOT NULL,
+    food_id uuid NOT NULL,
+    favorite boolean NOT NULL DEFAULT false,
+    rating smallint,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (user_id, food_id),
+    CONSTRAINT food_preference_rating_check CHECK (rating BETWEEN 1 AND 5),
+    CONSTRAINT fk_user FOREIGN KEY (user_id)
+        REFERENCES "user" (id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE,
+    CONSTRAINT fk_food FOREIGN KEY (food_id)
+        REFERENCES "food" (id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
+
+ALTER TABLE "food_preference" OWNER to postgres;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS food_user_id_idx;
+
+DELETE FROM "user" WHERE id = '00000000-0000-0000-0000-000000000000' AND NOT EXISTS (SELECT 1 FROM "food" WHERE user_id = '00000000-0000-0000-0000-000000000000');
@@ -0,0 +1,5 @@
+INSERT INTO "user" (id, names, last_names, email, password, created_at, updated_at, deleted_at)
+VALUES ('00000000-0000-0000-0000-000000000000', 'Deleted', 'User', 'deleted-user@food-api.invalid', '', now(), now(), now())
+ON CONFLICT (id) DO NOTHING;
+
+CREATE INDEX IF NOT EXISTS food_user_id_idx ON "food" (user_id);
@@ -0,0 +1,3 @@
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS role character varying(20) NOT NULL DEFAULT 'user';
+
+ALTER TABLE "user" ADD CONSTRAINT user_role_check CHECK (role IN ('user', 'moderator', 'admin'));
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS "user_two_factor" (
+    user_id uuid NOT NULL,
+    secret character varying(64) NOT NULL,
+    enabled_at timestamp with time zone,
+    last_step bigint NOT NULL DEFAULT 0,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (user_id),
+    CONSTRAINT fk_user FOREIGN KEY (user_id)
+        REFERENCES "user" (id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
+
+ALTER TABLE "user_two_factor" OWNER to postgres;
+
+CREATE TABLE IF NOT EXISTS "user_recovery_code" (
+    user_id uuid NOT NULL,
+    code_hash character varying(64) NOT NULL,
+    used_at timestamp with time zone,
+    created_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (user_id, code_hash),
+    CONSTRAINT fk_user FOREIGN KEY (user_id)
+        REFERENCES "user" (id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
+
+ALTER TABLE "user_recovery_code" OWNER to postgres;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS "api_key" (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    name character varying(100) NOT NULL,
+    prefix character varying(16) NOT NULL,
+    key_hash character varying(64) NOT NULL,
+    scope character varying(255) NOT NULL,
+    expires_at timestamp with time zone,
+    last_used_at timestamp with time zone,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT api_key_prefix_key UNIQUE (prefix),
+    CONSTRAINT fk_user FOREIGN KEY (user_id)
+        REFERENCES "user" (id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
+
+ALTER TABLE "api_key" OWNER to postgres;
+
+CREATE INDEX IF NOT EXISTS api_key_user_id_idx ON "api_key" (user_id);
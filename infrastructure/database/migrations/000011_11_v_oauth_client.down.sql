@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS oauth_client_user_id_idx;
+
+DROP TABLE IF EXISTS "oauth_client";
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS "oauth_client" (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    name character varying(100) NOT NULL,
+    secret_hash character varying(64),
+    redirect_uris text[] NOT NULL,
+    scope character varying(255) NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_user FOREIGN KEY (user_id)
+        REFERENCES "user" (id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
+
+ALTER TABLE "oauth_client" OWNER to postgres;
+
+CREATE INDEX IF NOT EXISTS oauth_client_user_id_idx ON "oauth_client" (user_id);
@@ -0,0 +1,2 @@
+-- The scrubbed personal data cannot be restored
+SELECT 1;
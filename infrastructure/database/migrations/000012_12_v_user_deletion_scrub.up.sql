@@ -0,0 +1,3 @@
+-- The users deleted before the scrub keep their email, the address could not sign up again
+UPDATE "user" SET names = 'Deleted', last_names = 'User', email = 'deleted+' || id || '@food-api.invalid', "password" = '', email_verified_at = NULL
+WHERE deleted_at IS NOT NULL AND id <> '00000000-0000-0000-0000-000000000000' AND email NOT LIKE 'deleted+%@food-api.invalid';
@@ -0,0 +1,83 @@
+package mail
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+)
+
+// Drivers of the MAIL_DRIVER env var.
+const (
+	DriverSMTP   = "smtp"
+	DriverFile   = "file"
+	DriverMemory = "memory"
+)
+
+// defaultFrom is the sender of the emails when MAIL_FROM is not set.
+const defaultFrom = "Food API <no-reply@food-api.local>"
+
+// developmentEnv is the APP_ENV value of the development environment, the only one where an unset
+// MAIL_DRIVER silently falls back to the file driver.
+const developmentEnv = "development"
+
+// defaultFilePath is the folder of the file driver when MAIL_FILE_PATH is not set.
+const defaultFilePath = "mail"
+
+var (
+	mailer   Mailer
+	onceMail sync.Once
+)
+
+// Message is an email with a plain text body and an optional HTML body.
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer sends the emails.
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// NewMailer returns the mailer of the MAIL_DRIVER env var. The file driver is the default so the
+// development environment does not need a mail server; outside of it an unset driver is logged as a
+// warning because no email would reach the users, and an unknown driver stops the startup.
+func NewMailer() Mailer {
+	onceMail.Do(initMailer)
+	return mailer
+}
+
+func initMailer() {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = defaultFrom
+	}
+
+	driver := os.Getenv("MAIL_DRIVER")
+	if driver == "" && os.Getenv("APP_ENV") != developmentEnv {
+		log.Printf("WARNING: MAIL_DRIVER is not set, emails are written to files and never delivered; " +
+			"set MAIL_DRIVER=smtp or APP_ENV=development")
+	}
+
+	switch driver {
+	case DriverSMTP:
+		mailer = NewSMTPMailer(os.Getenv("SMTP_HOST"), os.Getenv("SMTP_PORT"), os.Getenv("SMTP_USERNAME"),
+			os.Getenv("SMTP_PASSWORD"), from)
+	case DriverMemory:
+		mailer = NewMemoryMailer(from)
+	case DriverFile, "":
+		path := os.Getenv("MAIL_FILE_PATH")
+		if path == "" {
+			path = defaultFilePath
+		}
+
+		mailer = NewFileMailer(path, from)
+	default:
+		// A typo must not fall back to the file driver, the emails would never leave the server
+		log.Fatalf("unknown MAIL_DRIVER %q, it should be %s, %s or %s", driver, DriverSMTP, DriverFile, DriverMemory)
+	}
+}
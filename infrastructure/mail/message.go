@@ -0,0 +1,143 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// ErrInvalidHeader is returned for a header with a line break, it would let the value add headers.
+var ErrInvalidHeader = errors.New("the email headers cannot have line breaks")
+
+// ErrNoRecipients is returned for a message without recipients.
+var ErrNoRecipients = errors.New("the email has no recipients")
+
+// withFrom returns the message with the sender when it has none.
+func (m Message) withFrom(from string) Message {
+	if m.From == "" {
+		m.From = from
+	}
+
+	return m
+}
+
+// Recipients returns the addresses of the recipients without their names.
+func (m Message) Recipients() ([]string, error) {
+	if len(m.To) == 0 {
+		return nil, ErrNoRecipients
+	}
+
+	recipients := make([]string, 0, len(m.To))
+	for _, to := range m.To {
+		address, err := mail.ParseAddress(to)
+		if err != nil {
+			return nil, err
+		}
+
+		recipients = append(recipients, address.Address)
+	}
+
+	return recipients, nil
+}
+
+// sender returns the address of the sender without its name.
+func (m Message) sender() (string, error) {
+	address, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return "", err
+	}
+
+	return address.Address, nil
+}
+
+// Bytes returns the message in the RFC 5322 format, the text and the HTML are the alternatives of a
+// multipart body.
+func (m Message) Bytes(now time.Time) ([]byte, error) {
+	for _, value := range append([]string{m.From, m.Subject}, m.To...) {
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, ErrInvalidHeader
+		}
+	}
+
+	if _, err := m.Recipients(); err != nil {
+		return nil, err
+	}
+
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	body := multipart.NewWriter(&buffer)
+
+	headers := []string{
+		"From: " + from.String(),
+		"To: " + strings.Join(m.To, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", m.Subject),
+		"Date: " + now.Format(time.RFC1123Z),
+		"Message-ID: " + messageId(from.Address, now),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + body.Boundary(),
+	}
+
+	var message bytes.Buffer
+	message.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+
+	if err = writePart(body, "text/plain", m.Text); err != nil {
+		return nil, err
+	}
+
+	if m.HTML != "" {
+		if err = writePart(body, "text/html", m.HTML); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = body.Close(); err != nil {
+		return nil, err
+	}
+
+	message.Write(buffer.Bytes())
+
+	return message.Bytes(), nil
+}
+
+func writePart(body *multipart.Writer, contentType, content string) error {
+	part, err := body.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType + "; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+
+	writer := quotedprintable.NewWriter(part)
+	if _, err = writer.Write([]byte(content)); err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+// messageId returns a unique Message-ID in the domain of the sender.
+func messageId(from string, now time.Time) string {
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		domain = from[at+1:]
+	}
+
+	random := make([]byte, 8)
+	_, _ = rand.Read(random)
+
+	return fmt.Sprintf("<%d.%s@%s>", now.UnixNano(), hex.EncodeToString(random), domain)
+}
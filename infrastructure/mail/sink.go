@@ -0,0 +1,93 @@
+package mail
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// fileMailer writes every email in a .eml file of a folder instead of sending it, the files can be
+// opened with any email client.
+type fileMailer struct {
+	Path string
+	From string
+}
+
+func NewFileMailer(path, from string) Mailer {
+	return &fileMailer{
+		Path: path,
+		From: from,
+	}
+}
+
+var _ Mailer = &fileMailer{}
+
+// Send implements Mailer.
+func (fm *fileMailer) Send(ctx context.Context, message Message) error {
+	now := time.Now()
+	data, err := message.withFrom(fm.From).Bytes(now)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(fm.Path, 0755); err != nil {
+		return err
+	}
+
+	random := make([]byte, 4)
+	if _, err = rand.Read(random); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405.000000000"), hex.EncodeToString(random))
+
+	return ioutil.WriteFile(filepath.Join(fm.Path, name), data, 0600)
+}
+
+// MemoryMailer keeps the emails in memory instead of sending them, the tests read them with Messages.
+type MemoryMailer struct {
+	From string
+
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemoryMailer(from string) *MemoryMailer {
+	return &MemoryMailer{
+		From: from,
+	}
+}
+
+var _ Mailer = &MemoryMailer{}
+
+// Send implements Mailer, the message is checked as it would be sent.
+func (mm *MemoryMailer) Send(ctx context.Context, message Message) error {
+	message = message.withFrom(mm.From)
+	if _, err := message.Bytes(time.Now()); err != nil {
+		return err
+	}
+
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+
+	mm.messages = append(mm.messages, message)
+
+	return nil
+}
+
+// Messages returns the emails sent in order.
+func (mm *MemoryMailer) Messages() []Message {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+
+	messages := make([]Message, len(mm.messages))
+	copy(messages, mm.messages)
+
+	return messages
+}
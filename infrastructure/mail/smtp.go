@@ -0,0 +1,112 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+// smtpMailer sends the emails through an SMTP server, the connection uses STARTTLS when the server
+// supports it.
+type smtpMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func NewSMTPMailer(host, port, username, password, from string) Mailer {
+	if port == "" {
+		port = "587"
+	}
+
+	return &smtpMailer{
+		Host:     host,
+		Port:     port,
+		Username: username,
+		Password: password,
+		From:     from,
+	}
+}
+
+var _ Mailer = &smtpMailer{}
+
+// Send implements Mailer.
+func (sm *smtpMailer) Send(ctx context.Context, message Message) error {
+	message = message.withFrom(sm.From)
+
+	now := time.Now()
+	data, err := message.Bytes(now)
+	if err != nil {
+		return err
+	}
+
+	recipients, err := message.Recipients()
+	if err != nil {
+		return err
+	}
+
+	sender, err := message.sender()
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(sm.Host, sm.Port))
+	if err != nil {
+		return err
+	}
+
+	// The deadline of the context covers the whole conversation with the server
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, sm.Host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: sm.Host}); err != nil {
+			return err
+		}
+	}
+
+	if sm.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", sm.Username, sm.Password, sm.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err = client.Mail(sender); err != nil {
+		return err
+	}
+
+	for _, recipient := range recipients {
+		if err = client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err = writer.Write(data); err != nil {
+		return err
+	}
+
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
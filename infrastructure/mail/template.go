@@ -0,0 +1,79 @@
+package mail
+
+import (
+	"bytes"
+	htmlTemplate "html/template"
+	"strings"
+	textTemplate "text/template"
+)
+
+// Template renders the subject, the plain text and the HTML of an email, the HTML values are escaped.
+type Template struct {
+	subject *textTemplate.Template
+	text    *textTemplate.Template
+	html    *htmlTemplate.Template
+}
+
+// NewTemplate parses the templates of an email, the html is optional.
+func NewTemplate(name, subject, text, html string) (*Template, error) {
+	subjectTemplate, err := textTemplate.New(name + ".subject").Parse(subject)
+	if err != nil {
+		return nil, err
+	}
+
+	textBody, err := textTemplate.New(name + ".txt").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &Template{subject: subjectTemplate, text: textBody}
+	if html != "" {
+		if template.html, err = htmlTemplate.New(name + ".html").Parse(html); err != nil {
+			return nil, err
+		}
+	}
+
+	return template, nil
+}
+
+// MustTemplate is like NewTemplate but panics when a template cannot be parsed, it is used for
+// the templates declared in the code.
+func MustTemplate(name, subject, text, html string) *Template {
+	template, err := NewTemplate(name, subject, text, html)
+	if err != nil {
+		panic(err)
+	}
+
+	return template
+}
+
+// Render returns the message to the recipients with the templates executed with data.
+func (t *Template) Render(data interface{}, to ...string) (Message, error) {
+	message := Message{To: to}
+
+	var buffer bytes.Buffer
+	if err := t.subject.Execute(&buffer, data); err != nil {
+		return Message{}, err
+	}
+
+	// The subject is a header, it is a single line
+	message.Subject = strings.Join(strings.Fields(buffer.String()), " ")
+
+	buffer.Reset()
+	if err := t.text.Execute(&buffer, data); err != nil {
+		return Message{}, err
+	}
+
+	message.Text = buffer.String()
+
+	if t.html != nil {
+		buffer.Reset()
+		if err := t.html.Execute(&buffer, data); err != nil {
+			return Message{}, err
+		}
+
+		message.HTML = buffer.String()
+	}
+
+	return message, nil
+}
@@ -2,27 +2,120 @@ package middleware
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"food-api/infrastructure/auth"
+	"food-api/infrastructure/auth/model"
 	"github.com/go-chi/cors"
 	"io/ioutil"
+	"log"
+	"net"
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 )
 
-func AuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
-		err := auth.TokenValid(r)
+// AuthMiddleware verifies the token and checks in Redis that it was not revoked, e.g. by a logout. The access
+// details of the token are saved in the context of the request for the handlers and the last use of the
+// session is updated. The requests with an API key are verified by the apiKeys instead.
+func AuthMiddleware(token auth.TokenInterface, store auth.InterfaceAuth, apiKeys auth.APIKeyVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			if key := auth.ExtractAPIKey(r); key != "" {
+				if apiKeys == nil {
+					_ = HTTPError(w, r, http.StatusUnauthorized, "unauthorized")
+					return
+				}
 
-		if err != nil {
-			_ = HTTPError(w, r, http.StatusUnauthorized, err.Error())
-			return
-		}
+				metadata, err := apiKeys.VerifyAPIKey(ctx, key)
+				if errors.Is(err, auth.ErrAPIKeyInvalid) {
+					_ = HTTPError(w, r, http.StatusUnauthorized, err.Error())
+					return
+				}
 
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+				if err != nil {
+					_ = HTTPError(w, r, http.StatusInternalServerError, err.Error())
+					return
+				}
+
+				next.ServeHTTP(w, r.WithContext(auth.NewContext(ctx, metadata)))
+				return
+			}
+
+			metadata, err := token.ExtractTokenMetadata(r)
+			if err != nil || metadata == nil {
+				_ = HTTPError(w, r, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			userId, err := store.FetchAuth(ctx, metadata.TokenUuid)
+			if err != nil || userId != metadata.UserId {
+				_ = HTTPError(w, r, http.StatusUnauthorized, "the token was revoked")
+				return
+			}
+
+			if metadata.Family != "" {
+				if err = store.TouchSession(ctx, metadata.Family, time.Now()); err != nil {
+					log.Printf("cannot update the session %s: %s", metadata.Family, err.Error())
+				}
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.NewContext(ctx, metadata)))
+		})
+	}
+}
+
+// RequireRole only lets through the users with the role or a more powerful one, it goes after AuthMiddleware.
+func RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			metadata, ok := auth.FromContext(r.Context())
+			if !ok {
+				_ = HTTPError(w, r, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			if !model.HasRole(metadata.Role, role) {
+				_ = HTTPError(w, r, http.StatusForbidden, fmt.Sprintf("the %s role is required", role))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireScope only lets through the tokens with the scope, it goes after AuthMiddleware.
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			metadata, ok := auth.FromContext(r.Context())
+			if !ok {
+				_ = HTTPError(w, r, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			if !model.HasScope(metadata.Scopes, scope) {
+				_ = HTTPError(w, r, http.StatusForbidden, fmt.Sprintf("the %s scope is required", scope))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientIP returns the IP of the client without the port, chi RealIP already replaced it by the one of
+// the proxy headers.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
 }
 
 func CORSMiddleware(next http.Handler) http.Handler {
@@ -3,6 +3,8 @@ package infrastructure
 import (
 	v1Food "food-api/domain/food/application/v1"
 	v1User "food-api/domain/user/application/v1"
+	userService "food-api/domain/user/domain/service"
+	authModel "food-api/infrastructure/auth/model"
 	"food-api/infrastructure/database"
 	"food-api/infrastructure/middleware"
 	"github.com/go-chi/chi"
@@ -10,40 +12,138 @@ import (
 )
 
 // Routes returns the API V1 Handler with configuration.
-func Routes(conn *database.Data) http.Handler {
+func Routes(conn *database.Data, redis *database.RedisService) http.Handler {
 	router := chi.NewRouter()
 
-	ur := v1User.NewUserHandler(conn)
-	router.Mount("/users", routesUser(ur))
+	ur := v1User.NewUserHandler(conn, redis)
+	fr := v1Food.NewFoodHandler(conn, redis)
+	authenticate := middleware.AuthMiddleware(ur.Token, redis.Auth, userService.NewAPIKeyAuthenticator(ur.APIKeys))
+	router.Mount("/users", routesUser(ur, fr, authenticate))
 
-	fr := v1Food.NewFoodHandler(conn)
-	router.With(middleware.AuthMiddleware).Mount("/foods", routesFood(fr))
+	router.With(authenticate).Mount("/foods", routesFood(fr))
+	router.With(authenticate).Mount("/sessions", routesSession(ur))
+	router.With(authenticate).Mount("/api-keys", routesAPIKey(ur))
+	router.With(authenticate).Mount("/oauth", routesOAuth(ur))
+	router.Mount("/public/foods", routesPublicFood(fr))
 
 	return router
 }
 
-// routesUser returns user router with each endpoint.
-func routesUser(handler *v1User.UserRouter) http.Handler {
+// routesUser returns user router with each endpoint, each route requires a scope of the token.
+func routesUser(handler *v1User.UserRouter, foodHandler *v1Food.FoodRouter, authenticate func(http.Handler) http.Handler) http.Handler {
 	router := chi.NewRouter()
 
-	router.With(middleware.AuthMiddleware).Get("/", handler.GetAllUserHandler)
-	router.With(middleware.AuthMiddleware).Get("/{id}", handler.GetOneHandler)
+	read := middleware.RequireScope(authModel.ScopeUsersRead)
+	write := middleware.RequireScope(authModel.ScopeUsersWrite)
+
+	router.With(authenticate, read, middleware.RequireRole(authModel.RoleAdmin)).Get("/", handler.GetAllUserHandler)
+	router.With(authenticate, read).Get("/{id}", handler.GetOneHandler)
 	router.Post("/", handler.CreateHandler)
-	router.With(middleware.AuthMiddleware).Put("/{id}", handler.UpdateHandler)
+	router.With(authenticate, write).Put("/{id}", handler.UpdateHandler)
+	router.With(authenticate, write).Delete("/{id}", handler.DeleteHandler)
+	router.With(authenticate, write, middleware.MaxSizeAllowed).Put("/{id}/password", handler.ChangePasswordHandler)
+	router.With(authenticate, write, middleware.RequireRole(authModel.RoleAdmin), middleware.MaxSizeAllowed).
+		Put("/{id}/role", handler.RoleHandler)
+	router.With(authenticate, write, middleware.RequireRole(authModel.RoleAdmin)).Delete("/{id}/lock", handler.UnlockHandler)
+	router.With(authenticate, write).Post("/{id}/2fa", handler.EnrollTwoFactorHandler)
+	router.With(authenticate, write, middleware.MaxSizeAllowed).Post("/{id}/2fa/confirm", handler.ConfirmTwoFactorHandler)
+	router.With(authenticate, write, middleware.MaxSizeAllowed).Post("/{id}/2fa/recovery-codes", handler.RecoveryCodesHandler)
+	router.With(authenticate, write, middleware.MaxSizeAllowed).Delete("/{id}/2fa", handler.DisableTwoFactorHandler)
+	router.With(authenticate, read).Get("/{id}/export", handler.ExportHandler)
+	router.With(authenticate, read).Get("/{id}/export/{job}", handler.GetExportJobHandler)
+	router.Get("/{id}/export/{job}/download", handler.DownloadExportHandler)
+	router.With(authenticate, write).Put("/{id}/follow", handler.FollowHandler)
+	router.With(authenticate, write).Delete("/{id}/follow", handler.UnfollowHandler)
+	router.With(authenticate, middleware.RequireScope(authModel.ScopeFoodsRead)).
+		Get("/{id}/recommendations", foodHandler.RecommendationsHandler)
+
+	return router
+}
+
+// routesSession returns session router with each endpoint.
+func routesSession(handler *v1User.UserRouter) http.Handler {
+	router := chi.NewRouter()
+
+	router.With(middleware.RequireScope(authModel.ScopeUsersRead)).Get("/", handler.GetSessionsHandler)
+	router.With(middleware.RequireScope(authModel.ScopeUsersWrite)).Delete("/", handler.DeleteSessionsHandler)
+	router.With(middleware.RequireScope(authModel.ScopeUsersWrite)).Delete("/{id}", handler.DeleteSessionHandler)
+
+	return router
+}
+
+// routesAPIKey returns API key router with each endpoint.
+func routesAPIKey(handler *v1User.UserRouter) http.Handler {
+	router := chi.NewRouter()
+
+	read := middleware.RequireScope(authModel.ScopeUsersRead)
+	write := middleware.RequireScope(authModel.ScopeUsersWrite)
+
+	router.With(read).Get("/", handler.GetAPIKeysHandler)
+	router.With(write, middleware.MaxSizeAllowed).Post("/", handler.CreateAPIKeyHandler)
+	router.With(read).Get("/{id}", handler.GetAPIKeyHandler)
+	router.With(write, middleware.MaxSizeAllowed).Put("/{id}", handler.UpdateAPIKeyHandler)
+	router.With(write).Delete("/{id}", handler.DeleteAPIKeyHandler)
 
 	return router
 }
 
-// routesFood returns food router with each endpoint.
+// routesOAuth returns the router of the OAuth clients, of the consent screen and of the userinfo endpoint.
+// The consent does not require a scope, the granted scopes are checked against the ones of the login.
+func routesOAuth(handler *v1User.UserRouter) http.Handler {
+	router := chi.NewRouter()
+
+	read := middleware.RequireScope(authModel.ScopeUsersRead)
+	write := middleware.RequireScope(authModel.ScopeUsersWrite)
+
+	router.Get("/authorize", handler.AuthorizeHandler)
+	router.With(middleware.MaxSizeAllowed).Post("/authorize", handler.ConsentHandler)
+	router.Get("/userinfo", handler.UserInfoHandler)
+	router.With(read).Get("/clients", handler.GetOAuthClientsHandler)
+	router.With(write, middleware.MaxSizeAllowed).Post("/clients", handler.CreateOAuthClientHandler)
+	router.With(read).Get("/clients/{id}", handler.GetOAuthClientHandler)
+	router.With(write).Delete("/clients/{id}", handler.DeleteOAuthClientHandler)
+
+	return router
+}
+
+// routesFood returns food router with each endpoint, each route requires a scope of the token.
 func routesFood(handler *v1Food.FoodRouter) http.Handler {
 	router := chi.NewRouter()
 
-	router.Get("/", handler.GetAllFoodHandler)
-	router.Get("/{id}", handler.GetOneHandler)
-	router.Get("/user/{id}", handler.GetOneByUserHandler)
-	router.With(middleware.MaxSizeAllowed).Post("/", handler.CreateHandler)
-	router.With(middleware.MaxSizeAllowed).Put("/{id}", handler.UpdateHandler)
-	router.Delete("/{id}", handler.DeleteHandler)
+	read := middleware.RequireScope(authModel.ScopeFoodsRead)
+	write := middleware.RequireScope(authModel.ScopeFoodsWrite)
+
+	router.With(read).Get("/", handler.GetAllFoodHandler)
+	router.With(read).Get("/export", handler.ExportHandler)
+	router.With(read).Get("/trending", handler.TrendingHandler)
+	router.With(write).Post("/import", handler.ImportHandler)
+	router.With(write).Get("/import/{id}", handler.GetImportJobHandler)
+	router.With(write, middleware.MaxSizeAllowed).Post("/import/recipe", handler.ImportRecipeHandler)
+	router.With(read).Get("/by-slug/{slug}", handler.GetBySlugHandler)
+	router.With(read).Get("/{id}", handler.GetOneHandler)
+	router.With(read).Get("/{id}/duplicates", handler.DuplicatesHandler)
+	router.With(read).Get("/{id}/similar", handler.SimilarHandler)
+	router.With(write).Put("/{id}/favorite", handler.FavoriteHandler)
+	router.With(write).Delete("/{id}/favorite", handler.UnfavoriteHandler)
+	router.With(write, middleware.MaxSizeAllowed).Put("/{id}/rating", handler.RatingHandler)
+	router.With(read).Get("/{id}/translations", handler.TranslationsHandler)
+	router.With(write, middleware.MaxSizeAllowed).Put("/{id}/translations/{lang}", handler.SaveTranslationHandler)
+	router.With(write).Delete("/{id}/translations/{lang}", handler.DeleteTranslationHandler)
+	router.With(read).Get("/user/{id}", handler.GetOneByUserHandler)
+	router.With(write, middleware.MaxSizeAllowed).Post("/", handler.CreateHandler)
+	router.With(write, middleware.MaxSizeAllowed).Put("/{id}", handler.UpdateHandler)
+	router.With(write).Delete("/{id}", handler.DeleteHandler)
+
+	return router
+}
+
+// routesPublicFood returns the anonymous router of the public foods.
+func routesPublicFood(handler *v1Food.FoodRouter) http.Handler {
+	router := chi.NewRouter()
+
+	router.Get("/", handler.GetAllPublicFoodHandler)
+	router.Get("/by-slug/{slug}", handler.GetPublicBySlugHandler)
+	router.Get("/{id}", handler.GetOnePublicHandler)
 
 	return router
 }
@@ -19,13 +19,30 @@ func RoutesLogin(conn *database.Data, redis *database.RedisService) http.Handler
 	return router
 }
 
+// RoutesWellKnown returns the Handler of the well-known documents.
+func RoutesWellKnown(conn *database.Data, redis *database.RedisService) http.Handler {
+	router := chi.NewRouter()
+
+	lr := userApp.NewLoginHandler(conn, redis, auth.NewToken())
+	router.Get("/jwks.json", lr.JWKSHandler)
+	router.Get("/openid-configuration", lr.OpenIDConfigurationHandler)
+
+	return router
+}
+
 // routesFood returns login router with each endpoint.
 func routesLogin(handler *userApp.LoginRouter) http.Handler {
 	router := chi.NewRouter()
 
 	router.Post("/login", handler.LoginHandler)
+	router.Post("/login/2fa", handler.TwoFactorLoginHandler)
 	router.Post("/logout", handler.LogoutHandler)
 	router.Post("/refresh", handler.RefreshHandler)
+	router.Post("/forgot-password", handler.ForgotPasswordHandler)
+	router.Post("/reset-password", handler.ResetPasswordHandler)
+	router.Get("/verify-email", handler.VerifyEmailHandler)
+	router.Post("/verify-email/resend", handler.ResendVerificationHandler)
+	router.Post("/oauth/token", handler.TokenHandler)
 
 	return router
 }
\ No newline at end of file
@@ -50,8 +50,9 @@ func newServer(port string, conn *database.Data, redis *database.RedisService) *
 	}))
 
 	router.Mount("/health", healChecker(conn, redis))
+	router.Mount("/.well-known", RoutesWellKnown(conn, redis))
 	router.Mount("/api", RoutesLogin(conn, redis))
-	router.Mount("/api/v1", Routes(conn))
+	router.Mount("/api/v1", Routes(conn, redis))
 
 	s := &http.Server{
 		Addr:         ":" + port,
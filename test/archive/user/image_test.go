@@ -0,0 +1,71 @@
+package user
+
+import (
+	"context"
+	"food-api/domain/user/infrastructure/archive"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// pngImage is the signature of a PNG image for test
+const pngImage = "\x89PNG\r\n\x1a\nimage"
+
+// newImageServer returns a server with an image, a large image and a text file
+func newImageServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pancakes.png", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(pngImage))
+	})
+	mux.HandleFunc("/large.png", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(pngImage + "0123456789"))
+	})
+	mux.HandleFunc("/notes.txt", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("not an image"))
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestHTTPImageFetcher_Fetch(t *testing.T) {
+	ctx := context.Background()
+	server := newImageServer()
+	defer server.Close()
+
+	fetcher := &archive.HTTPImageFetcher{Client: server.Client(), MaxSize: int64(len(pngImage))}
+
+	t.Run("Error URL", func(tt *testing.T) {
+		_, _, err := fetcher.Fetch(ctx, "file:///etc/passwd")
+		assert.Equal(tt, archive.ErrImageURL, err)
+	})
+
+	t.Run("Error Not Found", func(tt *testing.T) {
+		_, _, err := fetcher.Fetch(ctx, server.URL+"/unknown.png")
+		assert.Error(tt, err)
+	})
+
+	t.Run("Error Too Large", func(tt *testing.T) {
+		_, _, err := fetcher.Fetch(ctx, server.URL+"/large.png")
+		assert.Equal(tt, archive.ErrImageTooLarge, err)
+	})
+
+	t.Run("Error Type", func(tt *testing.T) {
+		_, _, err := fetcher.Fetch(ctx, server.URL+"/notes.txt")
+		assert.Equal(tt, archive.ErrImageType, err)
+	})
+
+	t.Run("Error Private Address", func(tt *testing.T) {
+		_, _, err := archive.NewHTTPImageFetcher(1<<20, time.Second).Fetch(ctx, server.URL+"/pancakes.png")
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), archive.ErrImageAddress.Error())
+	})
+
+	t.Run("Fetch Successfully", func(tt *testing.T) {
+		extension, data, err := fetcher.Fetch(ctx, server.URL+"/pancakes.png")
+		assert.NoError(tt, err)
+		assert.Equal(tt, "png", extension)
+		assert.Equal(tt, pngImage, string(data))
+	})
+}
@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"context"
+	"food-api/infrastructure/auth"
+	"food-api/infrastructure/auth/model"
+	"github.com/alicebob/miniredis"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// NewMockAuth initialize a redis server in memory for the tokens
+func NewMockAuth() (*miniredis.Miniredis, auth.InterfaceAuth) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr: mr.Addr(),
+	})
+
+	return mr, auth.NewAuth(client)
+}
+
+// dataTokenDetails returns the uuids of tokens of the family, the signed tokens are not needed
+func dataTokenDetails(userId, tokenUuid, family string) *model.TokenDetails {
+	now := time.Now()
+
+	return &model.TokenDetails{
+		TokenUuid:   tokenUuid,
+		RefreshUuid: tokenUuid + "++" + userId,
+		Family:      family,
+		AtExpires:   now.Add(15 * time.Minute).Unix(),
+		RtExpires:   now.Add(7 * 24 * time.Hour).Unix(),
+	}
+}
+
+func TestClientData_RotateRefresh(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Rotate Refresh", func(tt *testing.T) {
+		mr, store := NewMockAuth()
+		defer mr.Close()
+
+		first := dataTokenDetails("user-1", "token-1", "family-1")
+		assert.NoError(tt, store.CreateAuth(ctx, "user-1", first))
+		assert.NoError(tt, store.RotateRefresh(ctx, first.RefreshUuid, "family-1"))
+		assert.False(tt, mr.Exists(first.RefreshUuid))
+
+		// The used tokens expire with the longest refresh token
+		assert.True(tt, mr.TTL("refresh:family:family-1:used") > 0)
+
+		second := dataTokenDetails("user-1", "token-2", "family-1")
+		assert.NoError(tt, store.CreateAuth(ctx, "user-1", second))
+		assert.NoError(tt, store.RotateRefresh(ctx, second.RefreshUuid, "family-1"))
+	})
+
+	t.Run("Error Reused Refresh", func(tt *testing.T) {
+		mr, store := NewMockAuth()
+		defer mr.Close()
+
+		first := dataTokenDetails("user-1", "token-1", "family-1")
+		assert.NoError(tt, store.CreateAuth(ctx, "user-1", first))
+		assert.NoError(tt, store.RotateRefresh(ctx, first.RefreshUuid, "family-1"))
+
+		second := dataTokenDetails("user-1", "token-2", "family-1")
+		assert.NoError(tt, store.CreateAuth(ctx, "user-1", second))
+
+		other := dataTokenDetails("user-1", "token-3", "family-2")
+		assert.NoError(tt, store.CreateAuth(ctx, "user-1", other))
+
+		// The stolen token is replayed, the whole family is revoked
+		assert.Equal(tt, auth.ErrRefreshReused, store.RotateRefresh(ctx, first.RefreshUuid, "family-1"))
+
+		_, err := store.FetchAuth(ctx, second.TokenUuid)
+		assert.Error(tt, err)
+		assert.Equal(tt, auth.ErrRefreshNotFound, store.RotateRefresh(ctx, second.RefreshUuid, "family-1"))
+
+		// The other logins of the user keep working
+		userId, err := store.FetchAuth(ctx, other.TokenUuid)
+		assert.NoError(tt, err)
+		assert.Equal(tt, "user-1", userId)
+		assert.NoError(tt, store.RotateRefresh(ctx, other.RefreshUuid, "family-2"))
+
+		// A new replay is still detected
+		assert.Equal(tt, auth.ErrRefreshReused, store.RotateRefresh(ctx, first.RefreshUuid, "family-1"))
+	})
+
+	t.Run("Error Revoked Refresh", func(tt *testing.T) {
+		mr, store := NewMockAuth()
+		defer mr.Close()
+
+		details := dataTokenDetails("user-1", "token-1", "family-1")
+		assert.NoError(tt, store.CreateAuth(ctx, "user-1", details))
+		assert.NoError(tt, store.DeleteTokens(ctx, &model.AccessDetails{TokenUuid: details.TokenUuid, UserId: "user-1"}))
+
+		assert.Equal(tt, auth.ErrRefreshNotFound, store.RotateRefresh(ctx, details.RefreshUuid, "family-1"))
+		assert.Equal(tt, auth.ErrRefreshNotFound, store.RotateRefresh(ctx, details.RefreshUuid, "family-1"))
+	})
+
+	t.Run("Rotate Refresh Without Family", func(tt *testing.T) {
+		mr, store := NewMockAuth()
+		defer mr.Close()
+
+		details := dataTokenDetails("user-1", "token-1", "")
+		assert.NoError(tt, store.CreateAuth(ctx, "user-1", details))
+
+		assert.NoError(tt, store.RotateRefresh(ctx, details.RefreshUuid, ""))
+		assert.Equal(tt, auth.ErrRefreshNotFound, store.RotateRefresh(ctx, details.RefreshUuid, ""))
+	})
+}
+
+func TestClientData_CreateAuth(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Keep Longer Expiration", func(tt *testing.T) {
+		mr, store := NewMockAuth()
+		defer mr.Close()
+
+		remember := dataTokenDetails("user-1", "token-1", "family-1")
+		remember.RtExpires = time.Now().Add(30 * 24 * time.Hour).Unix()
+		assert.NoError(tt, store.CreateAuth(ctx, "user-1", remember))
+
+		// A short login after the remember me login does not expire the tokens of the user earlier
+		short := dataTokenDetails("user-1", "token-2", "family-2")
+		short.RtExpires = time.Now().Add(24 * time.Hour).Unix()
+		assert.NoError(tt, store.CreateAuth(ctx, "user-1", short))
+		assert.True(tt, mr.TTL("user:tokens:user-1") > 29*24*time.Hour)
+
+		// A longer login extends it
+		longer := dataTokenDetails("user-1", "token-3", "family-3")
+		longer.RtExpires = time.Now().Add(60 * 24 * time.Hour).Unix()
+		assert.NoError(tt, store.CreateAuth(ctx, "user-1", longer))
+		assert.True(tt, mr.TTL("user:tokens:user-1") > 59*24*time.Hour)
+	})
+}
@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"food-api/infrastructure/auth"
+	"food-api/infrastructure/auth/model"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+const retention = 7 * 24 * time.Hour
+
+func TestToken_Keyring(t *testing.T) {
+	for _, algorithm := range []string{auth.AlgorithmRS256, auth.AlgorithmEdDSA} {
+		t.Run("Sign And Verify "+algorithm, func(tt *testing.T) {
+			keys, err := auth.NewKeyring(algorithm, "", 0, retention)
+			assert.NoError(tt, err)
+
+			token := &auth.Token{Keys: keys}
+			details, err := token.CreateToken("user-1", model.TokenOptions{Role: model.RoleUser, Scopes: model.AllScopes})
+			assert.NoError(tt, err)
+
+			request := httptest.NewRequest("GET", "/api/v1/foods", nil)
+			request.Header.Set("Authorization", "Bearer "+details.AccessToken)
+
+			metadata, err := token.ExtractTokenMetadata(request)
+			assert.NoError(tt, err)
+			assert.Equal(tt, "user-1", metadata.UserId)
+
+			parsed, err := token.VerifyAndValidateRefreshToken(details.RefreshToken)
+			assert.NoError(tt, err)
+			assert.Equal(tt, algorithm, parsed.Method.Alg())
+			assert.Equal(tt, keys.SigningKey(time.Now()).ID, parsed.Header["kid"])
+
+			// An access token is not a refresh token and the other way around
+			_, err = token.VerifyAndValidateRefreshToken(details.AccessToken)
+			assert.Error(tt, err)
+
+			request.Header.Set("Authorization", "Bearer "+details.RefreshToken)
+			_, err = token.ExtractTokenMetadata(request)
+			assert.Error(tt, err)
+		})
+	}
+
+	t.Run("Error Unknown Key", func(tt *testing.T) {
+		keys, err := auth.NewKeyring(auth.AlgorithmEdDSA, "", 0, retention)
+		assert.NoError(tt, err)
+
+		other, err := auth.NewKeyring(auth.AlgorithmEdDSA, "", 0, retention)
+		assert.NoError(tt, err)
+
+		details, err := (&auth.Token{Keys: other}).CreateToken("user-1", model.TokenOptions{Role: model.RoleUser, Scopes: model.AllScopes})
+		assert.NoError(tt, err)
+
+		_, err = (&auth.Token{Keys: keys}).VerifyAndValidateRefreshToken(details.RefreshToken)
+		assert.Error(tt, err)
+	})
+
+	t.Run("Error HMAC Token", func(tt *testing.T) {
+		keys, err := auth.NewKeyring(auth.AlgorithmEdDSA, "", 0, retention)
+		assert.NoError(tt, err)
+
+		// A token signed with the public key as an HMAC secret must not be accepted
+		key := keys.SigningKey(time.Now())
+		hmac := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"token_type": "refresh"})
+		hmac.Header["kid"] = key.ID
+		signed, err := hmac.SignedString([]byte(key.Private.Public().(ed25519.PublicKey)))
+		assert.NoError(tt, err)
+
+		_, err = (&auth.Token{Keys: keys}).VerifyAndValidateRefreshToken(signed)
+		assert.Error(tt, err)
+	})
+}
+
+func TestKeyring_Rotate(t *testing.T) {
+	path, err := ioutil.TempDir("", "keys")
+	assert.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	now := time.Now()
+	rotation := 24 * time.Hour
+
+	keys, err := auth.NewKeyring(auth.AlgorithmEdDSA, path, rotation, retention)
+	assert.NoError(t, err)
+
+	first := keys.SigningKey(now)
+	firstToken, err := (&auth.Token{Keys: keys}).CreateToken("user-1", model.TokenOptions{Role: model.RoleUser, Scopes: model.AllScopes})
+	assert.NoError(t, err)
+
+	// The key is recent, nothing to rotate
+	assert.NoError(t, keys.Rotate(now))
+	assert.Len(t, keys.JWKS().Keys, 1)
+
+	// The new key is published before it signs
+	rotatedAt := now.Add(rotation)
+	assert.NoError(t, keys.Rotate(rotatedAt))
+	assert.Len(t, keys.JWKS().Keys, 2)
+	assert.Equal(t, first.ID, keys.SigningKey(rotatedAt).ID)
+
+	second := keys.SigningKey(rotatedAt.Add(time.Hour))
+	assert.NotEqual(t, first.ID, second.ID)
+
+	// Another instance loads the same keys from the folder
+	shared, err := auth.NewKeyring(auth.AlgorithmEdDSA, path, rotation, retention)
+	assert.NoError(t, err)
+	assert.Equal(t, keys.JWKS(), shared.JWKS())
+	_, err = (&auth.Token{Keys: shared}).VerifyAndValidateRefreshToken(firstToken.RefreshToken)
+	assert.NoError(t, err)
+
+	// The replaced key is removed after the retention
+	prunedAt := rotatedAt.Add(retention + time.Hour)
+	assert.NoError(t, shared.Rotate(prunedAt))
+
+	_, ok := shared.Key(first.ID)
+	assert.False(t, ok)
+	_, ok = shared.Key(second.ID)
+	assert.True(t, ok)
+
+	_, err = os.Stat(path + "/" + first.ID + ".pem")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestKeyring_JWKS(t *testing.T) {
+	for _, algorithm := range []string{auth.AlgorithmRS256, auth.AlgorithmEdDSA} {
+		keys, err := auth.NewKeyring(algorithm, "", 0, retention)
+		assert.NoError(t, err)
+
+		jwks := keys.JWKS()
+		assert.Len(t, jwks.Keys, 1)
+
+		jwk := jwks.Keys[0]
+		assert.Equal(t, keys.SigningKey(time.Now()).ID, jwk.KeyID)
+		assert.Equal(t, algorithm, jwk.Algorithm)
+		assert.Equal(t, "sig", jwk.Use)
+
+		if algorithm == auth.AlgorithmRS256 {
+			assert.Equal(t, "RSA", jwk.KeyType)
+			assert.Equal(t, "AQAB", jwk.E)
+			assert.NotEmpty(t, jwk.N)
+		} else {
+			assert.Equal(t, "OKP", jwk.KeyType)
+			assert.Equal(t, "Ed25519", jwk.Curve)
+			assert.NotEmpty(t, jwk.X)
+		}
+	}
+}
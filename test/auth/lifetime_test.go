@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"food-api/infrastructure/auth"
+	"food-api/infrastructure/auth/model"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestToken_CreateTokenLifetimes(t *testing.T) {
+	env := map[string]string{
+		"ACCESS_TOKEN_TTL":      "5m",
+		"REFRESH_TOKEN_TTL":     "24h",
+		"REMEMBER_ME_TOKEN_TTL": "720h",
+		"REFRESH_IDLE_TIMEOUT":  "2h",
+	}
+
+	for name, value := range env {
+		_ = os.Setenv(name, value)
+	}
+
+	defer func() {
+		for name := range env {
+			_ = os.Unsetenv(name)
+		}
+	}()
+
+	keys, err := auth.NewKeyring(auth.AlgorithmEdDSA, "", 0, retention)
+	assert.NoError(t, err)
+
+	token := &auth.Token{Keys: keys}
+
+	// assertExpires checks the expiration of a token with a margin for the time of the test
+	assertExpires := func(tt *testing.T, expected time.Time, actual int64) {
+		assert.WithinDuration(tt, expected, time.Unix(actual, 0), 2*time.Second)
+	}
+
+	t.Run("Idle Timeout", func(tt *testing.T) {
+		now := time.Now()
+		details, err := token.CreateToken("user-1", model.TokenOptions{Role: model.RoleUser})
+		assert.NoError(tt, err)
+
+		assertExpires(tt, now.Add(5*time.Minute), details.AtExpires)
+		assertExpires(tt, now.Add(2*time.Hour), details.RtExpires)
+
+		parsed, err := token.VerifyAndValidateRefreshToken(details.RefreshToken)
+		assert.NoError(tt, err)
+		assert.Equal(tt, false, parsed.Claims.(jwt.MapClaims)["remember_me"])
+	})
+
+	t.Run("End Of The Login", func(tt *testing.T) {
+		now := time.Now()
+		details, err := token.CreateToken("user-1", model.TokenOptions{Family: "family-1", AuthTime: now.Add(-23 * time.Hour)})
+		assert.NoError(tt, err)
+
+		assert.Equal(tt, "family-1", details.Family)
+		assertExpires(tt, now.Add(time.Hour), details.RtExpires)
+	})
+
+	t.Run("Error Expired Login", func(tt *testing.T) {
+		details, err := token.CreateToken("user-1", model.TokenOptions{Family: "family-1", AuthTime: time.Now().Add(-25 * time.Hour)})
+		assert.Equal(tt, auth.ErrSessionExpired, err)
+		assert.Nil(tt, details)
+	})
+
+	t.Run("Remember Me", func(tt *testing.T) {
+		now := time.Now()
+		details, err := token.CreateToken("user-1", model.TokenOptions{RememberMe: true})
+		assert.NoError(tt, err)
+
+		assertExpires(tt, now.Add(720*time.Hour), details.RtExpires)
+	})
+
+	t.Run("Default Lifetimes", func(tt *testing.T) {
+		for name := range env {
+			_ = os.Unsetenv(name)
+		}
+
+		assert.Equal(tt, 15*time.Minute, auth.AccessTokenLifetime())
+		assert.Equal(tt, 7*24*time.Hour, auth.RefreshTokenLifetime(false))
+		assert.Equal(tt, 30*24*time.Hour, auth.RefreshTokenLifetime(true))
+		assert.Equal(tt, time.Duration(0), auth.RefreshIdleTimeout())
+	})
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"food-api/infrastructure/auth"
+	"food-api/infrastructure/auth/model"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSplitScopes(t *testing.T) {
+	t.Run("Split Scopes", func(tt *testing.T) {
+		scopes, identity, err := model.SplitScopes("openid foods:read email openid foods:read")
+		assert.NoError(tt, err)
+		assert.Equal(tt, []string{model.ScopeFoodsRead}, scopes)
+		assert.Equal(tt, []string{model.ScopeOpenID, model.ScopeEmail}, identity)
+	})
+
+	t.Run("Empty Scope Has None", func(tt *testing.T) {
+		scopes, identity, err := model.SplitScopes("")
+		assert.NoError(tt, err)
+		assert.Empty(tt, scopes)
+		assert.Empty(tt, identity)
+	})
+
+	t.Run("Error Invalid Scope", func(tt *testing.T) {
+		_, _, err := model.SplitScopes("openid foods:delete")
+		assert.Error(tt, err)
+	})
+}
+
+func TestToken_CreateClientToken(t *testing.T) {
+	keys, err := auth.NewKeyring(auth.AlgorithmEdDSA, "", 0, retention)
+	assert.NoError(t, err)
+
+	token := &auth.Token{Keys: keys}
+
+	t.Run("Scopes Of The Client", func(tt *testing.T) {
+		details, err := token.CreateToken("user-1", model.TokenOptions{Role: model.RoleUser, ClientID: "client-1",
+			Scopes: []string{model.ScopeOpenID, model.ScopeEmail, model.ScopeFoodsRead}})
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest("GET", "/api/v1/oauth/userinfo", nil)
+		request.Header.Set("Authorization", "Bearer "+details.AccessToken)
+
+		metadata, err := token.ExtractTokenMetadata(request)
+		assert.NoError(tt, err)
+		assert.Equal(tt, "client-1", metadata.ClientID)
+		assert.Equal(tt, []string{model.ScopeFoodsRead}, metadata.Scopes)
+		assert.Equal(tt, []string{model.ScopeOpenID, model.ScopeEmail}, metadata.IdentityScopes)
+		assert.False(tt, metadata.AuthTime.IsZero())
+
+		refresh, err := token.VerifyAndValidateRefreshToken(details.RefreshToken)
+		assert.NoError(tt, err)
+		assert.Equal(tt, "client-1", refresh.Claims.(jwt.MapClaims)["client_id"])
+	})
+
+	t.Run("Auth Time Of The Login", func(tt *testing.T) {
+		authTime := time.Now().Add(-time.Hour)
+		details, err := token.CreateToken("user-1", model.TokenOptions{Role: model.RoleUser, AuthTime: authTime})
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest("GET", "/api/v1/foods", nil)
+		request.Header.Set("Authorization", "Bearer "+details.AccessToken)
+
+		metadata, err := token.ExtractTokenMetadata(request)
+		assert.NoError(tt, err)
+		assert.Equal(tt, authTime.Unix(), metadata.AuthTime.Unix())
+	})
+
+	t.Run("Only OpenID Scope Has No Route", func(tt *testing.T) {
+		details, err := token.CreateToken("user-1", model.TokenOptions{Role: model.RoleUser, ClientID: "client-1",
+			Scopes: []string{model.ScopeOpenID}})
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest("GET", "/api/v1/foods", nil)
+		request.Header.Set("Authorization", "Bearer "+details.AccessToken)
+
+		metadata, err := token.ExtractTokenMetadata(request)
+		assert.NoError(tt, err)
+		assert.Empty(tt, metadata.Scopes)
+	})
+
+	t.Run("Login Without Client", func(tt *testing.T) {
+		details, err := token.CreateToken("user-1", model.TokenOptions{Role: model.RoleUser})
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest("GET", "/api/v1/foods", nil)
+		request.Header.Set("Authorization", "Bearer "+details.AccessToken)
+
+		metadata, err := token.ExtractTokenMetadata(request)
+		assert.NoError(tt, err)
+		assert.Empty(tt, metadata.ClientID)
+		assert.Equal(tt, model.AllScopes, metadata.Scopes)
+	})
+}
+
+func TestToken_CreateIDToken(t *testing.T) {
+	keys, err := auth.NewKeyring(auth.AlgorithmRS256, "", 0, retention)
+	assert.NoError(t, err)
+
+	token := &auth.Token{Keys: keys}
+	authTime := time.Now().Add(-time.Minute)
+
+	idToken, err := token.CreateIDToken("user-1", model.IDTokenOptions{Issuer: "http://localhost:8888", ClientID: "client-1",
+		Nonce: "nonce-1", AuthTime: authTime, Claims: map[string]interface{}{"email": "daniel.delapava@jikkosoft.com"}})
+	assert.NoError(t, err)
+
+	parsed, err := jwt.Parse(idToken, func(parsed *jwt.Token) (interface{}, error) {
+		key, _ := keys.Key(parsed.Header["kid"].(string))
+		return key.Private.Public(), nil
+	})
+	assert.NoError(t, err)
+
+	claims := parsed.Claims.(jwt.MapClaims)
+	assert.Equal(t, "http://localhost:8888", claims["iss"])
+	assert.Equal(t, "user-1", claims["sub"])
+	assert.Equal(t, "client-1", claims["aud"])
+	assert.Equal(t, "nonce-1", claims["nonce"])
+	assert.Equal(t, float64(authTime.Unix()), claims["auth_time"])
+	assert.Equal(t, "daniel.delapava@jikkosoft.com", claims["email"])
+
+	// An ID token is not an access token
+	request := httptest.NewRequest("GET", "/api/v1/foods", nil)
+	request.Header.Set("Authorization", "Bearer "+idToken)
+	_, err = token.ExtractTokenMetadata(request)
+	assert.Error(t, err)
+}
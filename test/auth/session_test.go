@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"food-api/infrastructure/auth"
+	"food-api/infrastructure/auth/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestClientData_Sessions(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	expiresAt := now.Add(7 * 24 * time.Hour)
+
+	t.Run("List Sessions", func(tt *testing.T) {
+		mr, store := NewMockAuth()
+		defer mr.Close()
+
+		phone := dataTokenDetails("user-1", "token-1", "family-1")
+		laptop := dataTokenDetails("user-1", "token-2", "family-2")
+		assert.NoError(tt, store.CreateAuth(ctx, "user-1", phone))
+		assert.NoError(tt, store.CreateAuth(ctx, "user-1", laptop))
+
+		assert.NoError(tt, store.SaveSession(ctx, "user-1", &model.Session{ID: "family-1", CreatedAt: now, LastUsedAt: now,
+			IP: "10.0.0.1", UserAgent: "phone"}, expiresAt))
+		assert.NoError(tt, store.SaveSession(ctx, "user-1", &model.Session{ID: "family-2", CreatedAt: now, LastUsedAt: now,
+			IP: "10.0.0.2", UserAgent: "laptop"}, expiresAt))
+
+		// The refresh keeps the creation date
+		refreshedAt := now.Add(time.Hour)
+		assert.NoError(tt, store.SaveSession(ctx, "user-1", &model.Session{ID: "family-1", CreatedAt: refreshedAt,
+			LastUsedAt: refreshedAt, IP: "10.0.0.3", UserAgent: "phone"}, expiresAt))
+
+		usedAt := now.Add(2 * time.Hour)
+		assert.NoError(tt, store.TouchSession(ctx, "family-2", usedAt))
+		assert.NoError(tt, store.TouchSession(ctx, "unknown", usedAt))
+		assert.False(tt, mr.Exists("session:unknown"))
+
+		sessions, err := store.ListSessions(ctx, "user-1")
+		assert.NoError(tt, err)
+		assert.Equal(tt, []model.Session{
+			{ID: "family-2", CreatedAt: now, LastUsedAt: usedAt, IP: "10.0.0.2", UserAgent: "laptop"},
+			{ID: "family-1", CreatedAt: now, LastUsedAt: refreshedAt, IP: "10.0.0.3", UserAgent: "phone"},
+		}, sessions)
+
+		sessions, err = store.ListSessions(ctx, "user-2")
+		assert.NoError(tt, err)
+		assert.Empty(tt, sessions)
+	})
+
+	t.Run("Delete Session", func(tt *testing.T) {
+		mr, store := NewMockAuth()
+		defer mr.Close()
+
+		details := dataTokenDetails("user-1", "token-1", "family-1")
+		assert.NoError(tt, store.CreateAuth(ctx, "user-1", details))
+		assert.NoError(tt, store.SaveSession(ctx, "user-1", &model.Session{ID: "family-1", CreatedAt: now, LastUsedAt: now}, expiresAt))
+
+		assert.Equal(tt, auth.ErrSessionNotFound, store.DeleteSession(ctx, "user-2", "family-1"))
+		assert.NoError(tt, store.DeleteSession(ctx, "user-1", "family-1"))
+		assert.Equal(tt, auth.ErrSessionNotFound, store.DeleteSession(ctx, "user-1", "family-1"))
+
+		_, err := store.FetchAuth(ctx, details.TokenUuid)
+		assert.Error(tt, err)
+
+		sessions, err := store.ListSessions(ctx, "user-1")
+		assert.NoError(tt, err)
+		assert.Empty(tt, sessions)
+	})
+
+	t.Run("Log Out Everywhere", func(tt *testing.T) {
+		mr, store := NewMockAuth()
+		defer mr.Close()
+
+		for _, family := range []string{"family-1", "family-2"} {
+			assert.NoError(tt, store.CreateAuth(ctx, "user-1", dataTokenDetails("user-1", "token-"+family, family)))
+			assert.NoError(tt, store.SaveSession(ctx, "user-1", &model.Session{ID: family, CreatedAt: now, LastUsedAt: now}, expiresAt))
+		}
+
+		assert.NoError(tt, store.DeleteUserTokens(ctx, "user-1"))
+
+		sessions, err := store.ListSessions(ctx, "user-1")
+		assert.NoError(tt, err)
+		assert.Empty(tt, sessions)
+		assert.False(tt, mr.Exists("session:family-1"))
+	})
+	t.Run("Keep Longer Expiration", func(tt *testing.T) {
+		mr, store := NewMockAuth()
+		defer mr.Close()
+
+		remember := now.Add(30 * 24 * time.Hour)
+		assert.NoError(tt, store.SaveSession(ctx, "user-1", &model.Session{ID: "family-1", CreatedAt: now, LastUsedAt: now}, remember))
+		short := now.Add(24 * time.Hour)
+		assert.NoError(tt, store.SaveSession(ctx, "user-1", &model.Session{ID: "family-2", CreatedAt: now, LastUsedAt: now}, short))
+
+		assert.True(tt, mr.TTL("user:sessions:user-1") > 29*24*time.Hour)
+		assert.True(tt, mr.TTL("session:family-2") <= 24*time.Hour)
+	})
+}
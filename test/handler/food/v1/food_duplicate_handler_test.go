@@ -0,0 +1,140 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	v1 "food-api/domain/food/application/v1"
+	responseFood "food-api/domain/food/application/v1/response"
+	repoMock "food-api/domain/food/domain/repository/mocks"
+	authMock "food-api/infrastructure/auth/mocks"
+	modelAuth "food-api/infrastructure/auth/model"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFoodRouter_DuplicatesHandler(t *testing.T) {
+
+	t.Run("Error Param Duplicates Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/{id}/duplicates", nil)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+
+		testFoodHandler.DuplicatesHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Error Not Found Duplicates Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/{id}/duplicates", nil)
+		response := httptest.NewRecorder()
+
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("id", "1")
+
+		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetFoodById", mock.Anything, "1", mock.Anything).Return(&responseFood.FoodResponse{}, errors.New("not found"))
+
+		testFoodHandler.DuplicatesHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Error Not Owner Duplicates Handler", func(tt *testing.T) {
+		foodTest := dataFoodResponse()[0]
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/{id}/duplicates", nil)
+		response := httptest.NewRecorder()
+
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("id", foodTest.ID)
+
+		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetFoodById", mock.Anything, foodTest.ID, mock.Anything).Return(&foodTest, nil)
+
+		testFoodHandler.DuplicatesHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		mockRepository.AssertNotCalled(tt, "GetAllFoodByUserId", mock.Anything, mock.Anything)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Error SQL Duplicates Handler", func(tt *testing.T) {
+		foodTest := dataFoodResponse()[0]
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/{id}/duplicates", nil)
+		response := httptest.NewRecorder()
+
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("id", foodTest.ID)
+
+		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: foodTest.UserID}, nil)
+		mockRepository.On("GetFoodById", mock.Anything, foodTest.ID, mock.Anything).Return(&foodTest, nil)
+		mockRepository.On("GetAllFoodByUserId", mock.Anything, foodTest.UserID).Return(nil, errors.New("error sql"))
+
+		testFoodHandler.DuplicatesHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+	})
+
+	t.Run("Duplicates Handler", func(tt *testing.T) {
+		foods := dataFoodResponse()
+		foods[0].Ingredients = []string{"Rice", "Beans"}
+		foods[1].Title = "Burrito"
+		foods[1].Ingredients = []string{"beans ", "rice"}
+
+		unrelated := foods[1]
+		unrelated.ID = "unrelated"
+		unrelated.Title = "Pancakes"
+		unrelated.Ingredients = []string{"Milk", "Eggs"}
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/{id}/duplicates", nil)
+		response := httptest.NewRecorder()
+
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("id", foods[0].ID)
+
+		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: foods[0].UserID}, nil)
+		mockRepository.On("GetFoodById", mock.Anything, foods[0].ID, foods[0].UserID).Return(&foods[0], nil)
+		mockRepository.On("GetAllFoodByUserId", mock.Anything, foods[0].UserID).Return([]responseFood.FoodResponse{foods[0], foods[1], unrelated}, nil)
+
+		testFoodHandler.DuplicatesHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var candidates []responseFood.DuplicateCandidateResponse
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&candidates))
+		assert.Len(tt, candidates, 1)
+		assert.Equal(tt, foods[1].ID, candidates[0].ID)
+		assert.True(tt, candidates[0].SameIngredients)
+	})
+}
@@ -0,0 +1,160 @@
+package v1
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	v1 "food-api/domain/food/application/v1"
+	responseFood "food-api/domain/food/application/v1/response"
+	repoMock "food-api/domain/food/domain/repository/mocks"
+	authMock "food-api/infrastructure/auth/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// streamFoods returns a mock implementation of StreamFoodByUserId that emits the given foods
+func streamFoods(foods []responseFood.FoodResponse) func(mock.Arguments) {
+	return func(args mock.Arguments) {
+		fn := args.Get(2).(func(food responseFood.FoodResponse) error)
+		for _, food := range foods {
+			_ = fn(food)
+		}
+	}
+}
+
+func TestFoodRouter_ExportHandler(t *testing.T) {
+
+	t.Run("Error Token Export Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/export", nil)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(nil, errors.New("error token"))
+
+		testFoodHandler.ExportHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
+	t.Run("Error Format Export Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/export?format=xml", nil)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+
+		testFoodHandler.ExportHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Error SQL Export Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/export", nil)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("StreamFoodByUserId", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error sql")).Once()
+
+		testFoodHandler.ExportHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+	})
+
+	t.Run("CSV Export Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/export?format=csv", nil)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+		foods := dataFoodResponse()
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("StreamFoodByUserId", mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(streamFoods(foods)).Once()
+
+		testFoodHandler.ExportHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		assert.True(tt, strings.HasPrefix(response.Header().Get("Content-Type"), "text/csv"))
+
+		records, err := csv.NewReader(response.Body).ReadAll()
+		assert.NoError(tt, err)
+		assert.Len(tt, records, 3)
+		assert.Equal(tt, foods[1].ID, records[2][0])
+	})
+
+	t.Run("NDJSON Export Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/export?format=ndjson", nil)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("StreamFoodByUserId", mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(streamFoods(dataFoodResponse())).Once()
+
+		testFoodHandler.ExportHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		assert.Len(tt, strings.Split(strings.TrimSpace(response.Body.String()), "\n"), 2)
+	})
+
+	t.Run("JSON-LD Export Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/export?format=jsonld", nil)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+		foods := dataFoodResponse()
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("StreamFoodByUserId", mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(streamFoods(foods)).Once()
+
+		testFoodHandler.ExportHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var document struct {
+			Context string                   `json:"@context"`
+			Graph   []map[string]interface{} `json:"@graph"`
+		}
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&document))
+		assert.Equal(tt, "https://schema.org", document.Context)
+		assert.Len(tt, document.Graph, 2)
+		assert.Equal(tt, "Recipe", document.Graph[0]["@type"])
+		assert.Equal(tt, foods[0].Title, document.Graph[0]["name"])
+	})
+
+	t.Run("Empty JSON-LD Export Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/export?format=jsonld", nil)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("StreamFoodByUserId", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+
+		testFoodHandler.ExportHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		assert.JSONEq(tt, `{"@context":"https://schema.org","@graph":[]}`, response.Body.String())
+	})
+}
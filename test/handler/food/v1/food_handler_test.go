@@ -3,18 +3,22 @@ package v1
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	v1 "food-api/domain/food/application/v1"
 	responseFood "food-api/domain/food/application/v1/response"
 	"food-api/domain/food/domain/model"
 	repoMock "food-api/domain/food/domain/repository/mocks"
+	authMock "food-api/infrastructure/auth/mocks"
+	modelAuth "food-api/infrastructure/auth/model"
 	"github.com/go-chi/chi"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -70,13 +74,30 @@ func dataFoodResponse() []responseFood.FoodResponse {
 
 func TestFoodRouter_GetAllFood(t *testing.T) {
 
+	t.Run("Error Token Get All Food Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/", nil)
+
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(nil, errors.New("error token"))
+
+		testFoodHandler.GetAllFoodHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
 	t.Run("Error Get All Food Handler", func(tt *testing.T) {
 
 		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/", nil)
 		response := httptest.NewRecorder()
 		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
 
-		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
 		mockRepository.On("GetAllFood", mock.Anything, mock.Anything).Return(nil, errors.New("error trace test"))
 
 		testFoodHandler.GetAllFoodHandler(response, request)
@@ -88,9 +109,11 @@ func TestFoodRouter_GetAllFood(t *testing.T) {
 		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/", nil)
 		response := httptest.NewRecorder()
 		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
 
-		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
-		mockRepository.On("GetAllFood", mock.Anything).Return(dataFoodResponse(), nil)
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetAllFood", mock.Anything, mock.Anything).Return(dataFoodResponse(), nil)
 
 		testFoodHandler.GetAllFoodHandler(response, request)
 		mockRepository.AssertExpectations(tt)
@@ -100,13 +123,30 @@ func TestFoodRouter_GetAllFood(t *testing.T) {
 
 func TestFoodRouter_GetOneByUserHandler(t *testing.T) {
 
+	t.Run("Error Token Get One By User Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/", nil)
+
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(nil, errors.New("error token"))
+
+		testFoodHandler.GetOneByUserHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
 	t.Run("Error Param Get One By User Handler", func(tt *testing.T) {
 
 		response := httptest.NewRecorder()
 		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/user/{id}", nil)
 
 		mockRepository := &repoMock.FoodRepository{}
-		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
+		mockToken := &authMock.TokenInterface{}
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
 
 		testFoodHandler.GetOneByUserHandler(response, request)
 		mockRepository.AssertExpectations(tt)
@@ -122,9 +162,11 @@ func TestFoodRouter_GetOneByUserHandler(t *testing.T) {
 
 		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
 		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
 
-		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
-		mockRepository.On("GetFoodByUserId", mock.Anything, mock.Anything).Return(&responseFood.FoodResponse{}, errors.New("error sql")).Once()
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetFoodByUserId", mock.Anything, mock.Anything, mock.Anything).Return(&responseFood.FoodResponse{}, errors.New("error sql")).Once()
 
 		testFoodHandler.GetOneByUserHandler(response, request)
 		mockRepository.AssertExpectations(tt)
@@ -140,9 +182,11 @@ func TestFoodRouter_GetOneByUserHandler(t *testing.T) {
 
 		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
 		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
 
-		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
-		mockRepository.On("GetFoodByUserId", mock.Anything, mock.Anything).Return(&dataFoodResponse()[0], nil).Once()
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetFoodByUserId", mock.Anything, mock.Anything, mock.Anything).Return(&dataFoodResponse()[0], nil).Once()
 
 		testFoodHandler.GetOneByUserHandler(response, request)
 		mockRepository.AssertExpectations(tt)
@@ -151,13 +195,30 @@ func TestFoodRouter_GetOneByUserHandler(t *testing.T) {
 
 func TestFoodRouter_GetOneHandler(t *testing.T) {
 
+	t.Run("Error Token Get One Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/", nil)
+
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(nil, errors.New("error token"))
+
+		testFoodHandler.GetOneHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
 	t.Run("Error Param Get One Handler", func(tt *testing.T) {
 
 		response := httptest.NewRecorder()
 		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/{id}", nil)
 
 		mockRepository := &repoMock.FoodRepository{}
-		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
+		mockToken := &authMock.TokenInterface{}
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
 
 		testFoodHandler.GetOneHandler(response, request)
 		mockRepository.AssertExpectations(tt)
@@ -173,9 +234,11 @@ func TestFoodRouter_GetOneHandler(t *testing.T) {
 
 		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
 		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
 
-		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
-		mockRepository.On("GetFoodById", mock.Anything, mock.Anything).Return(&responseFood.FoodResponse{}, errors.New("error sql")).Once()
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetFoodById", mock.Anything, mock.Anything, mock.Anything).Return(&responseFood.FoodResponse{}, errors.New("error sql")).Once()
 
 		testFoodHandler.GetOneHandler(response, request)
 		mockRepository.AssertExpectations(tt)
@@ -191,15 +254,138 @@ func TestFoodRouter_GetOneHandler(t *testing.T) {
 
 		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
 		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
 
-		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
-		mockRepository.On("GetFoodById", mock.Anything, mock.Anything).Return(&dataFoodResponse()[0], nil).Once()
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetFoodById", mock.Anything, mock.Anything, mock.Anything).Return(&dataFoodResponse()[0], nil).Once()
 
 		testFoodHandler.GetOneHandler(response, request)
 		mockRepository.AssertExpectations(tt)
 	})
 }
 
+func TestFoodRouter_GetBySlugHandler(t *testing.T) {
+
+	t.Run("Error Token Get By Slug Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/", nil)
+
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(nil, errors.New("error token"))
+
+		testFoodHandler.GetBySlugHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
+	t.Run("Error Param Get By Slug Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/by-slug/{slug}", nil)
+
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+
+		testFoodHandler.GetBySlugHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Error Not Found Get By Slug Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/by-slug/{slug}", nil)
+
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("slug", "unknown")
+
+		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetFoodBySlug", mock.Anything, "unknown", mock.Anything).Return(&responseFood.FoodResponse{}, sql.ErrNoRows).Once()
+		mockRepository.On("GetSlugByHistory", mock.Anything, "unknown", mock.Anything).Return("", sql.ErrNoRows).Once()
+
+		testFoodHandler.GetBySlugHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Error SQL Get By Slug Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/by-slug/{slug}", nil)
+
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("slug", "chicken-curry")
+
+		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetFoodBySlug", mock.Anything, "chicken-curry", mock.Anything).Return(&responseFood.FoodResponse{}, errors.New("error sql")).Once()
+
+		testFoodHandler.GetBySlugHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		mockRepository.AssertNotCalled(tt, "GetSlugByHistory", mock.Anything, mock.Anything, mock.Anything)
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+	})
+
+	t.Run("Redirect Old Slug Get By Slug Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/by-slug/{slug}", nil)
+
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("slug", "chicken-curry")
+
+		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetFoodBySlug", mock.Anything, "chicken-curry", mock.Anything).Return(&responseFood.FoodResponse{}, sql.ErrNoRows).Once()
+		mockRepository.On("GetSlugByHistory", mock.Anything, "chicken-curry", mock.Anything).Return("spicy-chicken-curry", nil).Once()
+
+		testFoodHandler.GetBySlugHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusMovedPermanently, response.Code)
+		assert.Equal(tt, "/api/v1/foods/by-slug/spicy-chicken-curry", response.Header().Get("Location"))
+	})
+
+	t.Run("Get By Slug Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/by-slug/{slug}", nil)
+
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("slug", "title")
+
+		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetFoodBySlug", mock.Anything, "title", mock.Anything).Return(&dataFoodResponse()[0], nil).Once()
+
+		testFoodHandler.GetBySlugHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+}
+
 func TestFoodRouter_CreateHandler(t *testing.T) {
 
 	t.Run("Error Body Create Handler", func(tt *testing.T) {
@@ -207,8 +393,10 @@ func TestFoodRouter_CreateHandler(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/v1/foods/", bytes.NewReader(nil))
 		response := httptest.NewRecorder()
 		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
 
-		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
 
 		testFoodHandler.CreateHandler(response, request)
 		mockRepository.AssertExpectations(tt)
@@ -223,8 +411,11 @@ func TestFoodRouter_CreateHandler(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/v1/foods/", bytes.NewReader(marshal))
 		response := httptest.NewRecorder()
 		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
 
-		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetAllFoodByUserId", mock.Anything, mock.Anything).Return(nil, nil)
 		mockRepository.On("SaveFood", mock.Anything, mock.Anything).Return(&responseFood.FoodResponse{}, errors.New("error sql"))
 
 		testFoodHandler.CreateHandler(response, request)
@@ -242,14 +433,58 @@ func TestFoodRouter_CreateHandler(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/v1/foods/", bytes.NewReader(marshal))
 		response := httptest.NewRecorder()
 		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
 
-		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
 
 		testFoodHandler.CreateHandler(response, request)
 		mockRepository.AssertExpectations(tt)
 	})
 
-	t.Run("Create Handler", func(tt *testing.T) {
+	t.Run("Validate Tags Create Handler", func(tt *testing.T) {
+
+		var foodTest = dataFood()[0]
+		foodTest.Tags = []string{strings.Repeat("a", 51)}
+
+		marshal, err := json.Marshal(foodTest)
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/foods/", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+
+		testFoodHandler.CreateHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Validate Visibility Create Handler", func(tt *testing.T) {
+
+		var foodTest = dataFood()[0]
+		foodTest.Visibility = "friends"
+
+		marshal, err := json.Marshal(foodTest)
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/foods/", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+
+		testFoodHandler.CreateHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Error Token Create Handler", func(tt *testing.T) {
 
 		marshal, err := json.Marshal(dataFood()[0])
 		assert.NoError(tt, err)
@@ -257,39 +492,125 @@ func TestFoodRouter_CreateHandler(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/api/v1/foods/", bytes.NewReader(marshal))
 		response := httptest.NewRecorder()
 		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
 
-		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
-		mockRepository.On("SaveFood", mock.Anything, mock.Anything).Return(&dataFoodResponse()[0], nil)
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(nil, errors.New("error token"))
 
 		testFoodHandler.CreateHandler(response, request)
 		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
 	})
 
-}
+	t.Run("Create Handler", func(tt *testing.T) {
 
-func TestFoodRouter_UpdateHandler(t *testing.T) {
+		marshal, err := json.Marshal(dataFood()[0])
+		assert.NoError(tt, err)
 
-	t.Run("Error Param Update Handler", func(tt *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/foods/", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+		owner := dataAccessDetails()
 
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetAllFoodByUserId", mock.Anything, owner.UserId).Return(nil, nil)
+		mockRepository.On("SaveFood", mock.Anything, mock.MatchedBy(func(food *model.Food) bool {
+			return food.UserID == owner.UserId
+		})).Return(&dataFoodResponse()[0], nil)
+
+		testFoodHandler.CreateHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Error SQL Duplicates Create Handler", func(tt *testing.T) {
+
+		marshal, err := json.Marshal(dataFood()[0])
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/foods/", bytes.NewReader(marshal))
 		response := httptest.NewRecorder()
-		request := httptest.NewRequest(http.MethodPut, "/api/v1/foods/{id}", nil)
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetAllFoodByUserId", mock.Anything, mock.Anything).Return(nil, errors.New("error sql"))
 
+		testFoodHandler.CreateHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+	})
+
+	t.Run("Duplicate Create Handler", func(tt *testing.T) {
+		owner := dataAccessDetails()
+		var foodTest = dataFood()[0]
+		foodTest.Title = "Spaghetti Carbonara"
+
+		marshal, err := json.Marshal(foodTest)
+		assert.NoError(tt, err)
+
+		candidates := dataFoodResponse()
+		candidates[0].Title = "spaghetti carbonara!"
+
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/foods/", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
 		mockRepository := &repoMock.FoodRepository{}
-		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
+		mockToken := &authMock.TokenInterface{}
 
-		testFoodHandler.UpdateHandler(response, request)
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetAllFoodByUserId", mock.Anything, owner.UserId).Return(candidates, nil)
+
+		testFoodHandler.CreateHandler(response, request)
 		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusConflict, response.Code)
+
+		var conflict responseFood.DuplicateConflictResponse
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&conflict))
+		assert.Equal(tt, []string{candidates[0].ID}, conflict.CandidateIDs)
 	})
 
-	t.Run("Error Body Update Handler", func(tt *testing.T) {
+	t.Run("Force Create Handler", func(tt *testing.T) {
 
-		request := httptest.NewRequest(http.MethodPut, "/api/v1/foods/{id}", bytes.NewReader(nil))
+		marshal, err := json.Marshal(dataFood()[0])
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/foods/?force=true", bytes.NewReader(marshal))
 		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("SaveFood", mock.Anything, mock.Anything).Return(&dataFoodResponse()[0], nil)
+
+		testFoodHandler.CreateHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		mockRepository.AssertNotCalled(tt, "GetAllFoodByUserId", mock.Anything, mock.Anything)
+		assert.Equal(tt, http.StatusCreated, response.Code)
+	})
+
+}
+
+func TestFoodRouter_UpdateHandler(t *testing.T) {
+	foodTest := dataFoodResponse()[0]
+
+	// newUpdateRequest returns an update request of the food with the body
+	newUpdateRequest := func(body []byte) *http.Request {
+		request := httptest.NewRequest(http.MethodPut, "/api/v1/foods/{id}", bytes.NewReader(body))
 
 		requestCtx := chi.NewRouteContext()
-		requestCtx.URLParams.Add("id", "1")
+		requestCtx.URLParams.Add("id", foodTest.ID)
 
-		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		return request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+	}
+
+	t.Run("Error Param Update Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPut, "/api/v1/foods/{id}", nil)
 
 		mockRepository := &repoMock.FoodRepository{}
 		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
@@ -298,25 +619,33 @@ func TestFoodRouter_UpdateHandler(t *testing.T) {
 		mockRepository.AssertExpectations(tt)
 	})
 
-	t.Run("Error SQL Update Handler", func(tt *testing.T) {
-
+	t.Run("Error Token Update Handler", func(tt *testing.T) {
 		marshal, err := json.Marshal(dataFood()[0])
 		assert.NoError(tt, err)
 
-		request := httptest.NewRequest(http.MethodPut, "/api/v1/foods/{id}", bytes.NewReader(marshal))
 		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
 
-		requestCtx := chi.NewRouteContext()
-		requestCtx.URLParams.Add("id", "1")
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(nil, errors.New("error token"))
 
-		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		testFoodHandler.UpdateHandler(response, newUpdateRequest(marshal))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
+	t.Run("Error Body Update Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
 		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
 
-		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
-		mockRepository.On("UpdateFood", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error sql")).Once()
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: foodTest.UserID}, nil)
 
-		testFoodHandler.UpdateHandler(response, request)
+		testFoodHandler.UpdateHandler(response, newUpdateRequest(nil))
 		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
 	})
 
 	t.Run("Validate Update Handler", func(tt *testing.T) {
@@ -327,45 +656,128 @@ func TestFoodRouter_UpdateHandler(t *testing.T) {
 		marshal, err := json.Marshal(userTest)
 		assert.NoError(tt, err)
 
-		request := httptest.NewRequest(http.MethodPut, "/api/v1/foods/{id}", bytes.NewReader(marshal))
 		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
 
-		requestCtx := chi.NewRouteContext()
-		requestCtx.URLParams.Add("id", "1")
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: foodTest.UserID}, nil)
 
-		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		testFoodHandler.UpdateHandler(response, newUpdateRequest(marshal))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Error Not Found Update Handler", func(tt *testing.T) {
+		marshal, err := json.Marshal(dataFood()[0])
+		assert.NoError(tt, err)
+
+		response := httptest.NewRecorder()
 		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+		viewer := &modelAuth.AccessDetails{UserId: uuid.New().String()}
 
-		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(viewer, nil)
+		mockRepository.On("GetFoodById", mock.Anything, foodTest.ID, viewer.UserId).Return(nil, errors.New("food not found"))
 
-		testFoodHandler.UpdateHandler(response, request)
+		testFoodHandler.UpdateHandler(response, newUpdateRequest(marshal))
 		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
 	})
 
-	t.Run("Update Handler", func(tt *testing.T) {
+	t.Run("Error Another User Update Handler", func(tt *testing.T) {
+		marshal, err := json.Marshal(dataFood()[0])
+		assert.NoError(tt, err)
 
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+		viewer := &modelAuth.AccessDetails{UserId: uuid.New().String(), Role: modelAuth.RoleUser}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(viewer, nil)
+		mockRepository.On("GetFoodById", mock.Anything, foodTest.ID, viewer.UserId).Return(&foodTest, nil)
+
+		testFoodHandler.UpdateHandler(response, newUpdateRequest(marshal))
+		mockRepository.AssertExpectations(tt)
+		mockRepository.AssertNotCalled(tt, "UpdateFood", mock.Anything, mock.Anything, mock.Anything)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("Error SQL Update Handler", func(tt *testing.T) {
 		marshal, err := json.Marshal(dataFood()[0])
 		assert.NoError(tt, err)
 
-		request := httptest.NewRequest(http.MethodPut, "/api/v1/foods/{id}", bytes.NewReader(marshal))
 		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
 
-		requestCtx := chi.NewRouteContext()
-		requestCtx.URLParams.Add("id", "1")
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: foodTest.UserID}, nil)
+		mockRepository.On("GetFoodById", mock.Anything, foodTest.ID, foodTest.UserID).Return(&foodTest, nil)
+		mockRepository.On("UpdateFood", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error sql")).Once()
 
-		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		testFoodHandler.UpdateHandler(response, newUpdateRequest(marshal))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusConflict, response.Code)
+	})
+
+	t.Run("Update Handler", func(tt *testing.T) {
+		foodBody := dataFood()[0]
+		marshal, err := json.Marshal(foodBody)
+		assert.NoError(tt, err)
+
+		response := httptest.NewRecorder()
 		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
 
-		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
-		mockRepository.On("UpdateFood", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: foodTest.UserID}, nil)
+		mockRepository.On("GetFoodById", mock.Anything, foodTest.ID, foodTest.UserID).Return(&foodTest, nil)
+		mockRepository.On("UpdateFood", mock.Anything, foodTest.ID, mock.MatchedBy(func(food *model.Food) bool {
+			return food.UserID == foodTest.UserID && food.ID == foodTest.ID
+		})).Return(nil).Once()
 
-		testFoodHandler.UpdateHandler(response, request)
+		testFoodHandler.UpdateHandler(response, newUpdateRequest(marshal))
 		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
 	})
 
+	t.Run("Moderator Update Handler", func(tt *testing.T) {
+		marshal, err := json.Marshal(dataFood()[0])
+		assert.NoError(tt, err)
+
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+		moderator := &modelAuth.AccessDetails{UserId: uuid.New().String(), Role: modelAuth.RoleModerator}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(moderator, nil)
+		mockRepository.On("GetFoodById", mock.Anything, foodTest.ID, moderator.UserId).Return(&foodTest, nil)
+		mockRepository.On("UpdateFood", mock.Anything, foodTest.ID, mock.MatchedBy(func(food *model.Food) bool {
+			return food.UserID == foodTest.UserID
+		})).Return(nil).Once()
+
+		testFoodHandler.UpdateHandler(response, newUpdateRequest(marshal))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
 }
 
 func TestFoodRouter_DeleteHandler(t *testing.T) {
+	foodTest := dataFoodResponse()[0]
+
+	// newDeleteRequest returns a delete request for the food
+	newDeleteRequest := func() *http.Request {
+		request := httptest.NewRequest(http.MethodDelete, "/api/v1/foods/{id}", nil)
+
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("id", foodTest.ID)
+
+		return request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+	}
 
 	t.Run("Error Param Delete Handler", func(tt *testing.T) {
 
@@ -379,40 +791,64 @@ func TestFoodRouter_DeleteHandler(t *testing.T) {
 		mockRepository.AssertExpectations(tt)
 	})
 
-	t.Run("Error SQL Delete Handler", func(tt *testing.T) {
-
-		request := httptest.NewRequest(http.MethodDelete, "/api/v1/foods/{id}", nil)
+	t.Run("Error Another User Delete Handler", func(tt *testing.T) {
 		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+		viewer := &modelAuth.AccessDetails{UserId: uuid.New().String(), Role: modelAuth.RoleUser}
 
-		requestCtx := chi.NewRouteContext()
-		requestCtx.URLParams.Add("id", "1")
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(viewer, nil)
+		mockRepository.On("GetFoodById", mock.Anything, foodTest.ID, viewer.UserId).Return(&foodTest, nil)
 
-		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		testFoodHandler.DeleteHandler(response, newDeleteRequest())
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("Error SQL Delete Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
 		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
 
-		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
-		mockRepository.On("DeleteFood", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error sql")).Once()
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: foodTest.UserID}, nil)
+		mockRepository.On("GetFoodById", mock.Anything, foodTest.ID, foodTest.UserID).Return(&foodTest, nil)
+		mockRepository.On("DeleteFood", mock.Anything, foodTest.ID).Return(errors.New("error sql")).Once()
 
-		testFoodHandler.DeleteHandler(response, request)
+		testFoodHandler.DeleteHandler(response, newDeleteRequest())
 		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
 	})
 
 	t.Run("Delete Handler", func(tt *testing.T) {
-
-		request := httptest.NewRequest(http.MethodDelete, "/api/v1/foods/{id}", nil)
 		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
 
-		requestCtx := chi.NewRouteContext()
-		requestCtx.URLParams.Add("id", "1")
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: foodTest.UserID}, nil)
+		mockRepository.On("GetFoodById", mock.Anything, foodTest.ID, foodTest.UserID).Return(&foodTest, nil)
+		mockRepository.On("DeleteFood", mock.Anything, foodTest.ID).Return(nil).Once()
 
-		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		testFoodHandler.DeleteHandler(response, newDeleteRequest())
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNoContent, response.Code)
+	})
+
+	t.Run("Moderator Delete Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
 		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+		moderator := &modelAuth.AccessDetails{UserId: uuid.New().String(), Role: modelAuth.RoleModerator}
 
-		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
-		mockRepository.On("DeleteFood", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(moderator, nil)
+		mockRepository.On("GetFoodById", mock.Anything, foodTest.ID, moderator.UserId).Return(&foodTest, nil)
+		mockRepository.On("DeleteFood", mock.Anything, foodTest.ID).Return(nil).Once()
 
-		testFoodHandler.DeleteHandler(response, request)
+		testFoodHandler.DeleteHandler(response, newDeleteRequest())
 		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNoContent, response.Code)
 	})
-
 }
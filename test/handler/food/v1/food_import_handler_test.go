@@ -0,0 +1,369 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	v1 "food-api/domain/food/application/v1"
+	responseFood "food-api/domain/food/application/v1/response"
+	"food-api/domain/food/domain/model"
+	repoMock "food-api/domain/food/domain/repository/mocks"
+	authMock "food-api/infrastructure/auth/mocks"
+	modelAuth "food-api/infrastructure/auth/model"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+const importCSV = "title,description,food_image\nPizza,Italian food,\nTaco,Mexican food,\n"
+
+// dataAccessDetails is data for test
+func dataAccessDetails() *modelAuth.AccessDetails {
+	return &modelAuth.AccessDetails{
+		UserId:    uuid.New().String(),
+		TokenUuid: uuid.New().String(),
+	}
+}
+
+// newImportRequest returns an import request for the given body and query
+func newImportRequest(body, contentType, query string) *http.Request {
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/foods/import"+query, strings.NewReader(body))
+	request.Header.Set("Content-Type", contentType)
+
+	return request
+}
+
+// failingReader is a body that cannot be read
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestFoodRouter_ImportHandler(t *testing.T) {
+
+	t.Run("Error Token Import Handler", func(tt *testing.T) {
+
+		request := newImportRequest(importCSV, "text/csv", "")
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(nil, errors.New("error token"))
+
+		testFoodHandler.ImportHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		mockToken.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
+	t.Run("Error Format Import Handler", func(tt *testing.T) {
+
+		request := newImportRequest(importCSV, "text/plain", "")
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+
+		testFoodHandler.ImportHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Error Header CSV Import Handler", func(tt *testing.T) {
+
+		request := newImportRequest("name,food_image\nPizza,\n", "text/csv", "")
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+
+		testFoodHandler.ImportHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Error Size Import Handler", func(tt *testing.T) {
+		_ = os.Setenv("FOOD_IMPORT_MAX_SIZE", "10")
+		defer os.Unsetenv("FOOD_IMPORT_MAX_SIZE")
+
+		request := newImportRequest(importCSV, "text/csv", "")
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+
+		testFoodHandler.ImportHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusRequestEntityTooLarge, response.Code)
+	})
+
+	t.Run("Error Read Import Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/foods/import", failingReader{})
+		request.Header.Set("Content-Type", "text/csv")
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+
+		testFoodHandler.ImportHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+	})
+
+	t.Run("Validate Atomic Import Handler", func(tt *testing.T) {
+
+		request := newImportRequest(importCSV+",Without title,\n", "text/csv", "")
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+
+		testFoodHandler.ImportHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+
+		var report responseFood.ImportReportResponse
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&report))
+		assert.Equal(tt, 3, report.Total)
+		assert.Equal(tt, 0, report.Succeeded)
+		assert.Equal(tt, 1, report.Failed)
+		assert.NotEmpty(tt, report.Rows[2].Errors)
+	})
+
+	t.Run("Error SQL Atomic Import Handler", func(tt *testing.T) {
+
+		request := newImportRequest(importCSV, "text/csv", "?mode=atomic")
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("SaveFoodBatch", mock.Anything, mock.Anything, false).
+			Return([]error{nil, errors.New("error sql")}, errors.New("error sql")).Once()
+
+		testFoodHandler.ImportHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+
+		var report responseFood.ImportReportResponse
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&report))
+		assert.Equal(tt, 0, report.Succeeded)
+		assert.Equal(tt, 1, report.Failed)
+		assert.Empty(tt, report.Rows[0].ID)
+	})
+
+	t.Run("Atomic Import Handler", func(tt *testing.T) {
+
+		request := newImportRequest(importCSV, "text/csv; charset=utf-8", "")
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+		accessDetails := dataAccessDetails()
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(accessDetails, nil)
+		mockRepository.On("SaveFoodBatch", mock.Anything, mock.MatchedBy(func(foods []model.Food) bool {
+			return len(foods) == 2 && foods[0].UserID == accessDetails.UserId && foods[1].Title == "Taco"
+		}), false).Return(make([]error, 2), nil).Once()
+
+		testFoodHandler.ImportHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var report responseFood.ImportReportResponse
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&report))
+		assert.Equal(tt, 2, report.Succeeded)
+		assert.Equal(tt, 0, report.Failed)
+	})
+
+	t.Run("Partial Import Handler", func(tt *testing.T) {
+
+		body := "{\"title\":\"Pizza\",\"description\":\"Italian food\"}\n" +
+			"{\"title\":\"Taco\"}\n" +
+			"not json\n" +
+			"\n" +
+			"{\"title\":\"Soup\",\"description\":\"Hot\"}\n"
+
+		request := newImportRequest(body, "application/x-ndjson", "?mode=partial")
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("SaveFoodBatch", mock.Anything, mock.Anything, true).
+			Return([]error{nil, errors.New("error sql")}, nil).Once()
+
+		testFoodHandler.ImportHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var report responseFood.ImportReportResponse
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&report))
+		assert.Equal(tt, 4, report.Total)
+		assert.Equal(tt, 1, report.Succeeded)
+		assert.Equal(tt, 3, report.Failed)
+		assert.Equal(tt, 3, report.Rows[2].Row)
+		assert.NotEmpty(tt, report.Rows[0].ID)
+		assert.NotEmpty(tt, report.Rows[3].Errors)
+	})
+
+	t.Run("Async Import Handler", func(tt *testing.T) {
+		_ = os.Setenv("FOOD_IMPORT_JOB_TTL", "10ms")
+		defer os.Unsetenv("FOOD_IMPORT_JOB_TTL")
+
+		request := newImportRequest(importCSV, "text/csv", "?async=true")
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockJobs := &repoMock.ImportJobRepository{}
+		mockToken := &authMock.TokenInterface{}
+		finished := make(chan responseFood.ImportJobResponse, 1)
+		removed := make(chan string, 1)
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, ImportJobs: mockJobs, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("SaveFoodBatch", mock.Anything, mock.Anything, false).Return(make([]error, 2), nil).Once()
+		mockJobs.On("SaveImportJob", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			job := args.Get(1).(*responseFood.ImportJobResponse)
+			if job.Status == model.ImportStatusCompleted {
+				finished <- *job
+			}
+		})
+		mockJobs.On("DeleteImportJob", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			removed <- args.String(1)
+		})
+
+		testFoodHandler.ImportHandler(response, request)
+		assert.Equal(tt, http.StatusAccepted, response.Code)
+		assert.NotEmpty(tt, response.Header().Get("Location"))
+
+		select {
+		case job := <-finished:
+			assert.Equal(tt, 2, job.Report.Succeeded)
+
+			select {
+			case id := <-removed:
+				assert.Equal(tt, job.ID, id)
+			case <-time.After(5 * time.Second):
+				tt.Fatal("the import job was not removed")
+			}
+		case <-time.After(5 * time.Second):
+			tt.Fatal("the import job did not finish")
+		}
+
+		mockRepository.AssertExpectations(tt)
+	})
+
+	t.Run("Async Rows Import Handler", func(tt *testing.T) {
+		_ = os.Setenv("FOOD_IMPORT_ASYNC_ROWS", "1")
+		defer os.Unsetenv("FOOD_IMPORT_ASYNC_ROWS")
+
+		request := newImportRequest(importCSV, "text/csv", "")
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockJobs := &repoMock.ImportJobRepository{}
+		mockToken := &authMock.TokenInterface{}
+		finished := make(chan responseFood.ImportJobResponse, 1)
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, ImportJobs: mockJobs, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("SaveFoodBatch", mock.Anything, mock.Anything, false).Return(make([]error, 2), nil).Once()
+		mockJobs.On("SaveImportJob", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			job := args.Get(1).(*responseFood.ImportJobResponse)
+			if job.Status == model.ImportStatusCompleted {
+				finished <- *job
+			}
+		})
+		mockJobs.On("DeleteImportJob", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+		testFoodHandler.ImportHandler(response, request)
+		assert.Equal(tt, http.StatusAccepted, response.Code)
+
+		select {
+		case job := <-finished:
+			assert.Equal(tt, 2, job.Report.Total)
+		case <-time.After(5 * time.Second):
+			tt.Fatal("the import job did not finish")
+		}
+	})
+}
+
+func TestFoodRouter_GetImportJobHandler(t *testing.T) {
+
+	t.Run("Error Param Get Import Job Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/import/{id}", nil)
+
+		mockJobs := &repoMock.ImportJobRepository{}
+		testFoodHandler := &v1.FoodRouter{ImportJobs: mockJobs}
+
+		testFoodHandler.GetImportJobHandler(response, request)
+		mockJobs.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Error Owner Get Import Job Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/import/{id}", nil)
+
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("id", "1")
+
+		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		mockJobs := &repoMock.ImportJobRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{ImportJobs: mockJobs, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockJobs.On("GetImportJobById", mock.Anything, "1").Return(&responseFood.ImportJobResponse{ID: "1", UserID: uuid.New().String()}, nil).Once()
+
+		testFoodHandler.GetImportJobHandler(response, request)
+		mockJobs.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Get Import Job Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/import/{id}", nil)
+
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("id", "1")
+
+		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		mockJobs := &repoMock.ImportJobRepository{}
+		mockToken := &authMock.TokenInterface{}
+		accessDetails := dataAccessDetails()
+
+		testFoodHandler := &v1.FoodRouter{ImportJobs: mockJobs, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(accessDetails, nil)
+		mockJobs.On("GetImportJobById", mock.Anything, "1").Return(&responseFood.ImportJobResponse{ID: "1", UserID: accessDetails.UserId}, nil).Once()
+
+		testFoodHandler.GetImportJobHandler(response, request)
+		mockJobs.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+}
@@ -0,0 +1,160 @@
+package v1
+
+import (
+	"errors"
+	v1 "food-api/domain/food/application/v1"
+	responseFood "food-api/domain/food/application/v1/response"
+	"food-api/domain/food/domain/model"
+	repoMock "food-api/domain/food/domain/repository/mocks"
+	"food-api/domain/food/domain/service"
+	authMock "food-api/infrastructure/auth/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFoodRouter_FavoriteHandler(t *testing.T) {
+
+	t.Run("Error Not Found Favorite Handler", func(tt *testing.T) {
+
+		request := newIdRequest(http.MethodPut, "/api/v1/foods/{id}/favorite", "1")
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockPreferences := &repoMock.PreferenceRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Preferences: mockPreferences, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetFoodById", mock.Anything, "1", mock.Anything).Return(&responseFood.FoodResponse{}, errors.New("not found"))
+
+		testFoodHandler.FavoriteHandler(response, request)
+		mockPreferences.AssertNotCalled(tt, "SaveFavorite", mock.Anything, mock.Anything)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Favorite Handler", func(tt *testing.T) {
+		foodTest := dataFoodResponse()[0]
+		accessDetails := dataAccessDetails()
+
+		request := newIdRequest(http.MethodPut, "/api/v1/foods/{id}/favorite", foodTest.ID)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockPreferences := &repoMock.PreferenceRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Preferences: mockPreferences, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(accessDetails, nil)
+		mockRepository.On("GetFoodById", mock.Anything, foodTest.ID, accessDetails.UserId).Return(&foodTest, nil)
+		mockPreferences.On("SaveFavorite", mock.Anything, mock.MatchedBy(func(preference *model.FoodPreference) bool {
+			return preference.Favorite && preference.FoodID == foodTest.ID && preference.UserID == accessDetails.UserId
+		})).Return(nil)
+
+		testFoodHandler.FavoriteHandler(response, request)
+		mockPreferences.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+
+	t.Run("Favorite Handler Without Trending Counters", func(tt *testing.T) {
+		foodTest := dataFoodResponse()[0]
+
+		request := newIdRequest(http.MethodPut, "/api/v1/foods/{id}/favorite", foodTest.ID)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockPreferences := &repoMock.PreferenceRepository{}
+		mockTrending := &repoMock.TrendingRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Preferences: mockPreferences, Trending: mockTrending, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetFoodById", mock.Anything, foodTest.ID, mock.Anything).Return(&foodTest, nil)
+		mockPreferences.On("SaveFavorite", mock.Anything, mock.Anything).Return(nil)
+		mockTrending.On("AddEvent", mock.Anything, foodTest.ID, service.FavoriteWeight, mock.Anything).Return(errors.New("connection refused"))
+
+		testFoodHandler.FavoriteHandler(response, request)
+		mockTrending.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+
+	t.Run("Unfavorite Handler", func(tt *testing.T) {
+		foodTest := dataFoodResponse()[0]
+
+		request := newIdRequest(http.MethodDelete, "/api/v1/foods/{id}/favorite", foodTest.ID)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockPreferences := &repoMock.PreferenceRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Preferences: mockPreferences, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetFoodById", mock.Anything, foodTest.ID, mock.Anything).Return(&foodTest, nil)
+		mockPreferences.On("SaveFavorite", mock.Anything, mock.MatchedBy(func(preference *model.FoodPreference) bool {
+			return !preference.Favorite
+		})).Return(nil)
+
+		testFoodHandler.UnfavoriteHandler(response, request)
+		mockPreferences.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNoContent, response.Code)
+	})
+}
+
+func TestFoodRouter_RatingHandler(t *testing.T) {
+
+	t.Run("Validate Rating Handler", func(tt *testing.T) {
+
+		request := newIdRequest(http.MethodPut, "/api/v1/foods/{id}/rating", "1")
+		request.Body = httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"rating": 6}`)).Body
+		response := httptest.NewRecorder()
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+
+		testFoodHandler.RatingHandler(response, request)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Error SQL Rating Handler", func(tt *testing.T) {
+		foodTest := dataFoodResponse()[0]
+
+		request := newIdRequest(http.MethodPut, "/api/v1/foods/{id}/rating", foodTest.ID)
+		request.Body = httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"rating": 4}`)).Body
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockPreferences := &repoMock.PreferenceRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Preferences: mockPreferences, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetFoodById", mock.Anything, foodTest.ID, mock.Anything).Return(&foodTest, nil)
+		mockPreferences.On("SaveRating", mock.Anything, mock.Anything).Return(errors.New("error sql"))
+
+		testFoodHandler.RatingHandler(response, request)
+		mockPreferences.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+	})
+
+	t.Run("Rating Handler", func(tt *testing.T) {
+		foodTest := dataFoodResponse()[0]
+
+		request := newIdRequest(http.MethodPut, "/api/v1/foods/{id}/rating", foodTest.ID)
+		request.Body = httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"rating": 4}`)).Body
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockPreferences := &repoMock.PreferenceRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Preferences: mockPreferences, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetFoodById", mock.Anything, foodTest.ID, mock.Anything).Return(&foodTest, nil)
+		mockPreferences.On("SaveRating", mock.Anything, mock.MatchedBy(func(preference *model.FoodPreference) bool {
+			return preference.Rating == 4 && preference.FoodID == foodTest.ID
+		})).Return(nil)
+
+		testFoodHandler.RatingHandler(response, request)
+		mockPreferences.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+}
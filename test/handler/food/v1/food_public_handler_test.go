@@ -0,0 +1,152 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	v1 "food-api/domain/food/application/v1"
+	responseFood "food-api/domain/food/application/v1/response"
+	repoMock "food-api/domain/food/domain/repository/mocks"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFoodRouter_GetAllPublicFoodHandler(t *testing.T) {
+
+	t.Run("Error Get All Public Food Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/public/foods/", nil)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
+		mockRepository.On("GetAllFood", mock.Anything, "").Return(nil, errors.New("error sql"))
+
+		testFoodHandler.GetAllPublicFoodHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Get All Public Food Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/public/foods/", nil)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
+		mockRepository.On("GetAllFood", mock.Anything, "").Return(dataFoodResponse(), nil)
+
+		testFoodHandler.GetAllPublicFoodHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+}
+
+func TestFoodRouter_GetOnePublicHandler(t *testing.T) {
+
+	t.Run("Error Not Found Get One Public Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/public/foods/{id}", nil)
+		response := httptest.NewRecorder()
+
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("id", "1")
+
+		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		mockRepository := &repoMock.FoodRepository{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
+		mockRepository.On("GetFoodById", mock.Anything, "1", "").Return(&responseFood.FoodResponse{}, errors.New("not found"))
+
+		testFoodHandler.GetOnePublicHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Get One Public Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/public/foods/{id}", nil)
+		response := httptest.NewRecorder()
+
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("id", "1")
+
+		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		mockRepository := &repoMock.FoodRepository{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
+		mockRepository.On("GetFoodById", mock.Anything, "1", "").Return(&dataFoodResponse()[0], nil)
+
+		testFoodHandler.GetOnePublicHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+}
+
+func TestFoodRouter_GetPublicBySlugHandler(t *testing.T) {
+
+	t.Run("Redirect Old Slug Get Public By Slug Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/public/foods/by-slug/{slug}", nil)
+		response := httptest.NewRecorder()
+
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("slug", "chicken-curry")
+
+		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		mockRepository := &repoMock.FoodRepository{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
+		mockRepository.On("GetFoodBySlug", mock.Anything, "chicken-curry", "").Return(&responseFood.FoodResponse{}, sql.ErrNoRows)
+		mockRepository.On("GetSlugByHistory", mock.Anything, "chicken-curry", "").Return("spicy-chicken-curry", nil)
+
+		testFoodHandler.GetPublicBySlugHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusMovedPermanently, response.Code)
+		assert.Equal(tt, "/api/v1/public/foods/by-slug/spicy-chicken-curry", response.Header().Get("Location"))
+	})
+
+	t.Run("Error Private Old Slug Get Public By Slug Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/public/foods/by-slug/{slug}", nil)
+		response := httptest.NewRecorder()
+
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("slug", "chicken-curry")
+
+		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		mockRepository := &repoMock.FoodRepository{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
+		mockRepository.On("GetFoodBySlug", mock.Anything, "chicken-curry", "").Return(&responseFood.FoodResponse{}, sql.ErrNoRows)
+		mockRepository.On("GetSlugByHistory", mock.Anything, "chicken-curry", "").Return("", sql.ErrNoRows)
+
+		testFoodHandler.GetPublicBySlugHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+		assert.Empty(tt, response.Header().Get("Location"))
+	})
+
+	t.Run("Get Public By Slug Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/public/foods/by-slug/{slug}", nil)
+		response := httptest.NewRecorder()
+
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("slug", "title")
+
+		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		mockRepository := &repoMock.FoodRepository{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository}
+		mockRepository.On("GetFoodBySlug", mock.Anything, "title", "").Return(&dataFoodResponse()[0], nil)
+
+		testFoodHandler.GetPublicBySlugHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+}
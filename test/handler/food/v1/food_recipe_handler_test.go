@@ -0,0 +1,172 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	v1 "food-api/domain/food/application/v1"
+	responseFood "food-api/domain/food/application/v1/response"
+	"food-api/domain/food/domain/model"
+	repoMock "food-api/domain/food/domain/repository/mocks"
+	authMock "food-api/infrastructure/auth/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const recipeHTML = `<!DOCTYPE html>
+<html>
+<head>
+	<title>Spicy chicken curry</title>
+	<script type="application/ld+json">{"@context":"https://schema.org","@type":"WebSite","name":"Recipes"}</script>
+	<script type="application/ld+json">
+	{
+		"@context": "https://schema.org",
+		"@graph": [
+			{"@type": "WebPage", "name": "Spicy chicken curry"},
+			{
+				"@type": ["Recipe", "NewsArticle"],
+				"name": "Spicy chicken curry",
+				"description": "A <b>quick</b> curry &amp; rice",
+				"image": [{"@type": "ImageObject", "url": "https://example.com/curry.jpg"}],
+				"recipeIngredient": ["500g chicken", "2 tbsp curry paste"],
+				"keywords": "Curry, Spicy,  curry",
+				"recipeInstructions": [
+					{"@type": "HowToSection", "name": "Prepare", "itemListElement": [
+						{"@type": "HowToStep", "text": "Cut the chicken"}
+					]},
+					{"@type": "HowToStep", "text": "Cook with the paste"}
+				]
+			}
+		]
+	}
+	</script>
+</head>
+<body></body>
+</html>`
+
+const recipeJSONLD = `{
+	"@context": "https://schema.org",
+	"@type": "Recipe",
+	"name": "Tomato soup",
+	"description": "Hot soup",
+	"image": "https://example.com/soup.jpg",
+	"recipeIngredient": ["Tomatoes", "Salt"],
+	"recipeInstructions": "Boil the tomatoes.\nAdd salt."
+}`
+
+func TestFoodRouter_ImportRecipeHandler(t *testing.T) {
+
+	t.Run("Error Token Import Recipe Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/foods/import/recipe", strings.NewReader(recipeJSONLD))
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(nil, errors.New("error token"))
+
+		testFoodHandler.ImportRecipeHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
+	t.Run("Error Recipe Not Found Import Recipe Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/foods/import/recipe", strings.NewReader("<html><body>No recipe</body></html>"))
+		request.Header.Set("Content-Type", "text/html")
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+
+		testFoodHandler.ImportRecipeHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Validate Import Recipe Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/foods/import/recipe", strings.NewReader(`{"@type":"Recipe","name":"Only name"}`))
+		request.Header.Set("Content-Type", "application/ld+json")
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+
+		testFoodHandler.ImportRecipeHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Preview HTML Import Recipe Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/foods/import/recipe", strings.NewReader(recipeHTML))
+		request.Header.Set("Content-Type", "text/html; charset=utf-8")
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+
+		testFoodHandler.ImportRecipeHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var food responseFood.FoodResponse
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&food))
+		assert.Equal(tt, "Spicy chicken curry", food.Title)
+		assert.Equal(tt, "A quick curry & rice", food.Description)
+		assert.Equal(tt, "https://example.com/curry.jpg", food.FoodImage)
+		assert.Equal(tt, []string{"500g chicken", "2 tbsp curry paste"}, food.Ingredients)
+		assert.Equal(tt, []string{"Cut the chicken", "Cook with the paste"}, food.Instructions)
+		assert.Equal(tt, []string{"curry", "spicy"}, food.Tags)
+	})
+
+	t.Run("Error SQL Save Import Recipe Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/foods/import/recipe?save=true", strings.NewReader(recipeJSONLD))
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("SaveFood", mock.Anything, mock.Anything).Return(&responseFood.FoodResponse{}, errors.New("error sql")).Once()
+
+		testFoodHandler.ImportRecipeHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusConflict, response.Code)
+	})
+
+	t.Run("Save Import Recipe Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/foods/import/recipe?save=true", strings.NewReader(recipeJSONLD))
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+		accessDetails := dataAccessDetails()
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(accessDetails, nil)
+		mockRepository.On("SaveFood", mock.Anything, mock.MatchedBy(func(food *model.Food) bool {
+			return food.UserID == accessDetails.UserId &&
+				food.Title == "Tomato soup" &&
+				len(food.Instructions) == 2 &&
+				food.FoodImage == "https://example.com/soup.jpg"
+		})).Return(&dataFoodResponse()[0], nil).Once()
+
+		testFoodHandler.ImportRecipeHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusCreated, response.Code)
+		assert.NotEmpty(tt, response.Header().Get("Location"))
+	})
+}
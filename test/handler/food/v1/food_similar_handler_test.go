@@ -0,0 +1,167 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	v1 "food-api/domain/food/application/v1"
+	responseFood "food-api/domain/food/application/v1/response"
+	"food-api/domain/food/domain/model"
+	repoMock "food-api/domain/food/domain/repository/mocks"
+	"food-api/domain/food/domain/service"
+	authMock "food-api/infrastructure/auth/mocks"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newIdRequest returns a request with the id param
+func newIdRequest(method, target, id string) *http.Request {
+	request := httptest.NewRequest(method, target, nil)
+
+	requestCtx := chi.NewRouteContext()
+	requestCtx.URLParams.Add("id", id)
+
+	return request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+}
+
+// dataSimilarFood is data for test, the first food is the target
+func dataSimilarFood() []responseFood.FoodResponse {
+	foods := dataFoodResponse()
+	foods[0].Title = "Spicy chicken curry"
+	foods[0].Ingredients = []string{"Chicken", "Rice"}
+	foods[0].Tags = []string{"spicy"}
+	foods[1].Title = "Chicken curry"
+	foods[1].Ingredients = []string{"Chicken", "Rice", "Onion"}
+	foods[1].Tags = []string{"spicy"}
+
+	return append(foods, responseFood.FoodResponse{ID: "pancakes", UserID: foods[0].UserID, Title: "Pancakes", Description: "Sweet"})
+}
+
+func TestFoodRouter_SimilarHandler(t *testing.T) {
+
+	t.Run("Error Not Found Similar Handler", func(tt *testing.T) {
+
+		request := newIdRequest(http.MethodGet, "/api/v1/foods/{id}/similar", "1")
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Scorer: service.NewScorer(), Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetFoodById", mock.Anything, "1", mock.Anything).Return(&responseFood.FoodResponse{}, errors.New("not found"))
+
+		testFoodHandler.SimilarHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Error SQL Similar Handler", func(tt *testing.T) {
+		foods := dataSimilarFood()
+
+		request := newIdRequest(http.MethodGet, "/api/v1/foods/{id}/similar", foods[0].ID)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Scorer: service.NewScorer(), Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetFoodById", mock.Anything, foods[0].ID, mock.Anything).Return(&foods[0], nil)
+		mockRepository.On("GetAllFood", mock.Anything, mock.Anything).Return(nil, errors.New("error sql"))
+
+		testFoodHandler.SimilarHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+	})
+
+	t.Run("Similar Handler", func(tt *testing.T) {
+		foods := dataSimilarFood()
+
+		request := newIdRequest(http.MethodGet, "/api/v1/foods/{id}/similar?limit=5", foods[0].ID)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Scorer: service.NewScorer(), Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetFoodById", mock.Anything, foods[0].ID, mock.Anything).Return(&foods[0], nil)
+		mockRepository.On("GetAllFood", mock.Anything, mock.Anything).Return(foods, nil)
+
+		testFoodHandler.SimilarHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var result []responseFood.ScoredFoodResponse
+		assert.NoError(tt, json.Unmarshal(response.Body.Bytes(), &result))
+		assert.Len(tt, result, 1)
+		assert.Equal(tt, foods[1].ID, result[0].ID)
+		assert.True(tt, result[0].Score > 0)
+	})
+}
+
+func TestFoodRouter_RecommendationsHandler(t *testing.T) {
+
+	t.Run("Error Another User Recommendations Handler", func(tt *testing.T) {
+
+		request := newIdRequest(http.MethodGet, "/api/v1/users/{id}/recommendations", "1")
+		response := httptest.NewRecorder()
+		mockPreferences := &repoMock.PreferenceRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Preferences: mockPreferences, Scorer: service.NewScorer(), Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+
+		testFoodHandler.RecommendationsHandler(response, request)
+		mockPreferences.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("Error SQL Recommendations Handler", func(tt *testing.T) {
+		accessDetails := dataAccessDetails()
+
+		request := newIdRequest(http.MethodGet, "/api/v1/users/{id}/recommendations", accessDetails.UserId)
+		response := httptest.NewRecorder()
+		mockPreferences := &repoMock.PreferenceRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Preferences: mockPreferences, Scorer: service.NewScorer(), Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(accessDetails, nil)
+		mockPreferences.On("GetPreferencesByUserId", mock.Anything, accessDetails.UserId).Return(nil, errors.New("error sql"))
+
+		testFoodHandler.RecommendationsHandler(response, request)
+		mockPreferences.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+	})
+
+	t.Run("Recommendations Handler", func(tt *testing.T) {
+		accessDetails := dataAccessDetails()
+		foods := dataSimilarFood()
+		own := responseFood.FoodResponse{ID: "own", UserID: accessDetails.UserId, Title: "Chicken curry", Ingredients: []string{"Chicken", "Rice"}}
+
+		request := newIdRequest(http.MethodGet, "/api/v1/users/{id}/recommendations", accessDetails.UserId)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockPreferences := &repoMock.PreferenceRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Preferences: mockPreferences, Scorer: service.NewScorer(), Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(accessDetails, nil)
+		mockPreferences.On("GetPreferencesByUserId", mock.Anything, accessDetails.UserId).Return([]model.FoodPreference{
+			{UserID: accessDetails.UserId, FoodID: foods[0].ID, Favorite: true, Rating: 5},
+		}, nil)
+		mockRepository.On("GetAllFood", mock.Anything, accessDetails.UserId).Return(append(foods, own), nil)
+
+		testFoodHandler.RecommendationsHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		mockPreferences.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var result []responseFood.ScoredFoodResponse
+		assert.NoError(tt, json.Unmarshal(response.Body.Bytes(), &result))
+		assert.Len(tt, result, 1)
+		assert.Equal(tt, foods[1].ID, result[0].ID)
+	})
+}
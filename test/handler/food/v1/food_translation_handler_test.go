@@ -0,0 +1,281 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	v1 "food-api/domain/food/application/v1"
+	responseFood "food-api/domain/food/application/v1/response"
+	"food-api/domain/food/domain/model"
+	repoMock "food-api/domain/food/domain/repository/mocks"
+	authMock "food-api/infrastructure/auth/mocks"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTranslationRequest returns a request with the id and lang params of the translation routes
+func newTranslationRequest(method, id, lang string, body []byte) *http.Request {
+	request := httptest.NewRequest(method, "/api/v1/foods/{id}/translations/{lang}", bytes.NewReader(body))
+
+	requestCtx := chi.NewRouteContext()
+	requestCtx.URLParams.Add("id", id)
+	requestCtx.URLParams.Add("lang", lang)
+
+	return request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+}
+
+// dataTranslation is data for test
+func dataTranslation() model.FoodTranslation {
+	return model.FoodTranslation{
+		Title:        "Título",
+		Description:  "Descripción",
+		Instructions: []string{"Mezclar", "Hornear"},
+	}
+}
+
+func TestFoodRouter_SaveTranslationHandler(t *testing.T) {
+
+	t.Run("Error Validate Save Translation Handler", func(tt *testing.T) {
+		marshal, err := json.Marshal(model.FoodTranslation{Title: "Título"})
+		assert.NoError(tt, err)
+
+		request := newTranslationRequest(http.MethodPut, "1", "es", marshal)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockTranslations := &repoMock.TranslationRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Translations: mockTranslations, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+
+		testFoodHandler.SaveTranslationHandler(response, request)
+		mockTranslations.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Error Language Save Translation Handler", func(tt *testing.T) {
+		marshal, err := json.Marshal(dataTranslation())
+		assert.NoError(tt, err)
+
+		request := newTranslationRequest(http.MethodPut, "1", "spanish!", marshal)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockTranslations := &repoMock.TranslationRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Translations: mockTranslations, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+
+		testFoodHandler.SaveTranslationHandler(response, request)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Error Not Owner Save Translation Handler", func(tt *testing.T) {
+		foodTest := dataFoodResponse()[0]
+		marshal, err := json.Marshal(dataTranslation())
+		assert.NoError(tt, err)
+
+		request := newTranslationRequest(http.MethodPut, foodTest.ID, "es", marshal)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockTranslations := &repoMock.TranslationRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Translations: mockTranslations, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetFoodById", mock.Anything, foodTest.ID, mock.Anything).Return(&foodTest, nil)
+
+		testFoodHandler.SaveTranslationHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		mockTranslations.AssertNotCalled(tt, "SaveTranslation", mock.Anything, mock.Anything)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Save Translation Handler", func(tt *testing.T) {
+		foodTest := dataFoodResponse()[0]
+		accessDetails := dataAccessDetails()
+		accessDetails.UserId = foodTest.UserID
+		marshal, err := json.Marshal(dataTranslation())
+		assert.NoError(tt, err)
+
+		request := newTranslationRequest(http.MethodPut, foodTest.ID, "es-MX", marshal)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockTranslations := &repoMock.TranslationRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Translations: mockTranslations, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(accessDetails, nil)
+		mockRepository.On("GetFoodById", mock.Anything, foodTest.ID, foodTest.UserID).Return(&foodTest, nil)
+		mockTranslations.On("SaveTranslation", mock.Anything, mock.MatchedBy(func(translation *model.FoodTranslation) bool {
+			return translation.FoodID == foodTest.ID && translation.Language == "es-mx"
+		})).Return(&responseFood.TranslationResponse{FoodID: foodTest.ID, Language: "es-mx", Title: "Título"}, nil)
+
+		testFoodHandler.SaveTranslationHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		mockTranslations.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+}
+
+func TestFoodRouter_DeleteTranslationHandler(t *testing.T) {
+
+	t.Run("Error Language Delete Translation Handler", func(tt *testing.T) {
+
+		request := newTranslationRequest(http.MethodDelete, "1", "*", nil)
+		response := httptest.NewRecorder()
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+
+		testFoodHandler.DeleteTranslationHandler(response, request)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Error Not Found Delete Translation Handler", func(tt *testing.T) {
+		foodTest := dataFoodResponse()[0]
+		accessDetails := dataAccessDetails()
+		accessDetails.UserId = foodTest.UserID
+
+		request := newTranslationRequest(http.MethodDelete, foodTest.ID, "en", nil)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockTranslations := &repoMock.TranslationRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Translations: mockTranslations, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(accessDetails, nil)
+		mockRepository.On("GetFoodById", mock.Anything, foodTest.ID, foodTest.UserID).Return(&foodTest, nil)
+		mockTranslations.On("DeleteTranslation", mock.Anything, foodTest.ID, "en").Return(errors.New("sql: no rows in result set"))
+
+		testFoodHandler.DeleteTranslationHandler(response, request)
+		mockTranslations.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Delete Translation Handler", func(tt *testing.T) {
+		foodTest := dataFoodResponse()[0]
+		accessDetails := dataAccessDetails()
+		accessDetails.UserId = foodTest.UserID
+
+		request := newTranslationRequest(http.MethodDelete, foodTest.ID, "EN", nil)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockTranslations := &repoMock.TranslationRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Translations: mockTranslations, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(accessDetails, nil)
+		mockRepository.On("GetFoodById", mock.Anything, foodTest.ID, foodTest.UserID).Return(&foodTest, nil)
+		mockTranslations.On("DeleteTranslation", mock.Anything, foodTest.ID, "en").Return(nil)
+
+		testFoodHandler.DeleteTranslationHandler(response, request)
+		mockTranslations.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNoContent, response.Code)
+	})
+}
+
+func TestFoodRouter_TranslationsHandler(t *testing.T) {
+
+	t.Run("Translations Handler", func(tt *testing.T) {
+		foodTest := dataFoodResponse()[0]
+
+		request := newTranslationRequest(http.MethodGet, foodTest.ID, "", nil)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockTranslations := &repoMock.TranslationRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Translations: mockTranslations, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockRepository.On("GetFoodById", mock.Anything, foodTest.ID, mock.Anything).Return(&foodTest, nil)
+		mockTranslations.On("GetTranslationsByFoodIds", mock.Anything, []string{foodTest.ID}).Return(nil, nil)
+
+		testFoodHandler.TranslationsHandler(response, request)
+		mockTranslations.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		assert.JSONEq(tt, "[]", response.Body.String())
+	})
+}
+
+func TestFoodRouter_LocalizedFood(t *testing.T) {
+
+	t.Run("Translated Get One Public Handler", func(tt *testing.T) {
+		foodTest := dataFoodResponse()[0]
+		foodTest.Language = "en"
+		foodTest.Instructions = []string{"Mix", "Bake"}
+
+		request := newTranslationRequest(http.MethodGet, foodTest.ID, "", nil)
+		request.Header.Set("Accept-Language", "es-AR,es;q=0.9,en;q=0.8")
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockTranslations := &repoMock.TranslationRepository{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Translations: mockTranslations}
+		mockRepository.On("GetFoodById", mock.Anything, foodTest.ID, "").Return(&foodTest, nil)
+		mockTranslations.On("GetTranslationsByFoodIds", mock.Anything, []string{foodTest.ID}).Return([]responseFood.TranslationResponse{
+			{FoodID: foodTest.ID, Language: "es", Title: "Título", Description: "Descripción"},
+		}, nil)
+
+		testFoodHandler.GetOnePublicHandler(response, request)
+		mockTranslations.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		assert.Equal(tt, "es", response.Header().Get("Content-Language"))
+		assert.Equal(tt, "Accept-Language", response.Header().Get("Vary"))
+
+		var result responseFood.FoodResponse
+		assert.NoError(tt, json.Unmarshal(response.Body.Bytes(), &result))
+		assert.Equal(tt, "Título", result.Title)
+		assert.Equal(tt, "es", result.Language)
+		assert.Equal(tt, []string{"Mix", "Bake"}, result.Instructions)
+	})
+
+	t.Run("Original Language Get All Public Food Handler", func(tt *testing.T) {
+		foods := dataFoodResponse()
+		foods[0].Language = "en"
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/public/foods/", nil)
+		request.Header.Set("Accept-Language", "en-US, es;q=0.5")
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockTranslations := &repoMock.TranslationRepository{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Translations: mockTranslations}
+		mockRepository.On("GetAllFood", mock.Anything, "").Return(foods, nil)
+		mockTranslations.On("GetTranslationsByFoodIds", mock.Anything, []string{foods[0].ID, foods[1].ID}).Return([]responseFood.TranslationResponse{
+			{FoodID: foods[0].ID, Language: "es", Title: "Título", Description: "Descripción"},
+			{FoodID: foods[1].ID, Language: "es", Title: "Título", Description: "Descripción"},
+		}, nil)
+
+		testFoodHandler.GetAllPublicFoodHandler(response, request)
+		mockTranslations.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var result []responseFood.FoodResponse
+		assert.NoError(tt, json.Unmarshal(response.Body.Bytes(), &result))
+		assert.Equal(tt, "Title", result[0].Title)
+		assert.Equal(tt, "Título", result[1].Title)
+	})
+
+	t.Run("Error Translations Get All Public Food Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/public/foods/", nil)
+		request.Header.Set("Accept-Language", "es")
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockTranslations := &repoMock.TranslationRepository{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Translations: mockTranslations}
+		mockRepository.On("GetAllFood", mock.Anything, "").Return(dataFoodResponse(), nil)
+		mockTranslations.On("GetTranslationsByFoodIds", mock.Anything, mock.Anything).Return(nil, errors.New("error sql"))
+
+		testFoodHandler.GetAllPublicFoodHandler(response, request)
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+	})
+}
@@ -0,0 +1,112 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	v1 "food-api/domain/food/application/v1"
+	responseFood "food-api/domain/food/application/v1/response"
+	"food-api/domain/food/domain/model"
+	repoMock "food-api/domain/food/domain/repository/mocks"
+	"food-api/domain/food/domain/service"
+	authMock "food-api/infrastructure/auth/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFoodRouter_TrendingHandler(t *testing.T) {
+
+	t.Run("Error Window Trending Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/trending?window=1y", nil)
+		response := httptest.NewRecorder()
+		mockTrending := &repoMock.TrendingRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Trending: mockTrending, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+
+		testFoodHandler.TrendingHandler(response, request)
+		mockTrending.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Trending Handler", func(tt *testing.T) {
+		foods := dataFoodResponse()
+		accessDetails := dataAccessDetails()
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/trending?window=7d", nil)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockTrending := &repoMock.TrendingRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Trending: mockTrending, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(accessDetails, nil)
+		mockTrending.On("GetTrending", mock.Anything, model.TrendingWindowWeek, mock.Anything, mock.Anything).Return([]model.FoodScore{
+			{FoodID: "hidden", Score: 9},
+			{FoodID: foods[1].ID, Score: 4},
+			{FoodID: foods[0].ID, Score: 2},
+		}, nil)
+		mockRepository.On("GetFoodsByIds", mock.Anything, []string{"hidden", foods[1].ID, foods[0].ID}, accessDetails.UserId).Return(foods[:2], nil)
+
+		testFoodHandler.TrendingHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		mockTrending.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		assert.Equal(tt, "redis", response.Header().Get("X-Trending-Source"))
+
+		var result []responseFood.ScoredFoodResponse
+		assert.NoError(tt, json.Unmarshal(response.Body.Bytes(), &result))
+		assert.Len(tt, result, 2)
+		assert.Equal(tt, foods[1].ID, result[0].ID)
+		assert.Equal(tt, 4.0, result[0].Score)
+	})
+
+	t.Run("Fallback Database Trending Handler", func(tt *testing.T) {
+		foods := dataFoodResponse()
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/trending", nil)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.FoodRepository{}
+		mockPreferences := &repoMock.PreferenceRepository{}
+		mockTrending := &repoMock.TrendingRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Repo: mockRepository, Preferences: mockPreferences, Trending: mockTrending, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockTrending.On("GetTrending", mock.Anything, model.TrendingWindowDay, mock.Anything, mock.Anything).Return(nil, errors.New("connection refused"))
+		mockPreferences.On("GetPopularFood", mock.Anything, mock.Anything, mock.Anything).Return([]model.FoodScore{{FoodID: foods[0].ID, Score: service.FavoriteWeight}}, nil)
+		mockRepository.On("GetFoodsByIds", mock.Anything, []string{foods[0].ID}, mock.Anything).Return(foods[:1], nil)
+
+		testFoodHandler.TrendingHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		mockPreferences.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		assert.Equal(tt, "database", response.Header().Get("X-Trending-Source"))
+
+		var result []responseFood.ScoredFoodResponse
+		assert.NoError(tt, json.Unmarshal(response.Body.Bytes(), &result))
+		assert.Len(tt, result, 1)
+	})
+
+	t.Run("Error SQL Fallback Trending Handler", func(tt *testing.T) {
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods/trending", nil)
+		response := httptest.NewRecorder()
+		mockPreferences := &repoMock.PreferenceRepository{}
+		mockTrending := &repoMock.TrendingRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testFoodHandler := &v1.FoodRouter{Preferences: mockPreferences, Trending: mockTrending, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(dataAccessDetails(), nil)
+		mockTrending.On("GetTrending", mock.Anything, model.TrendingWindowDay, mock.Anything, mock.Anything).Return(nil, errors.New("connection refused"))
+		mockPreferences.On("GetPopularFood", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("error sql"))
+
+		testFoodHandler.TrendingHandler(response, request)
+		mockPreferences.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+	})
+}
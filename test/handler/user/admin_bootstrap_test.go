@@ -0,0 +1,43 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"food-api/domain/user/application"
+	repoMock "food-api/domain/user/domain/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"os"
+	"testing"
+)
+
+func TestBootstrapAdmins(t *testing.T) {
+
+	t.Run("Without Admin Emails", func(tt *testing.T) {
+		mockRepository := &repoMock.UserRepository{}
+
+		assert.NoError(tt, application.BootstrapAdmins(context.Background(), mockRepository))
+		mockRepository.AssertNotCalled(tt, "PromoteAdmins", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error Promote Admins", func(tt *testing.T) {
+		_ = os.Setenv("ADMIN_EMAILS", "admin@example.com")
+		defer os.Unsetenv("ADMIN_EMAILS")
+
+		mockRepository := &repoMock.UserRepository{}
+		mockRepository.On("PromoteAdmins", mock.Anything, []string{"admin@example.com"}, mock.Anything).Return(int64(0), errors.New("error sql"))
+
+		assert.Error(tt, application.BootstrapAdmins(context.Background(), mockRepository))
+	})
+
+	t.Run("Promote Admins", func(tt *testing.T) {
+		_ = os.Setenv("ADMIN_EMAILS", " admin@example.com, ,ops@example.com ")
+		defer os.Unsetenv("ADMIN_EMAILS")
+
+		mockRepository := &repoMock.UserRepository{}
+		mockRepository.On("PromoteAdmins", mock.Anything, []string{"admin@example.com", "ops@example.com"}, mock.Anything).Return(int64(2), nil)
+
+		assert.NoError(tt, application.BootstrapAdmins(context.Background(), mockRepository))
+		mockRepository.AssertExpectations(tt)
+	})
+}
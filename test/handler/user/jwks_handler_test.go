@@ -0,0 +1,34 @@
+package user
+
+import (
+	"encoding/json"
+	"food-api/domain/user/application"
+	authMock "food-api/infrastructure/auth/mocks"
+	modelAuth "food-api/infrastructure/auth/model"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLoginRouter_JWKSHandler(t *testing.T) {
+	t.Run("JWKS Handler", func(tt *testing.T) {
+		jwks := modelAuth.JWKS{Keys: []modelAuth.JWK{{KeyType: "OKP", KeyID: "1", Use: "sig", Algorithm: "EdDSA", Curve: "Ed25519", X: "key"}}}
+
+		request := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+		response := httptest.NewRecorder()
+		mockToken := &authMock.TokenInterface{}
+
+		testLoginHandler := &application.LoginRouter{Token: mockToken}
+		mockToken.On("JWKS").Return(jwks)
+
+		testLoginHandler.JWKSHandler(response, request)
+		mockToken.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		assert.Contains(tt, response.Header().Get("Cache-Control"), "max-age")
+
+		var body modelAuth.JWKS
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&body))
+		assert.Equal(tt, jwks, body)
+	})
+}
@@ -2,6 +2,7 @@ package user
 
 import (
 	"bytes"
+	"database/sql"
 	"crypto/md5"
 	"encoding/hex"
 	"encoding/json"
@@ -10,6 +11,8 @@ import (
 	responseUser "food-api/domain/user/application/v1/response"
 	"food-api/domain/user/domain/model"
 	repoMock "food-api/domain/user/domain/repository/mocks"
+	"food-api/domain/user/infrastructure/persistence"
+	"food-api/infrastructure/auth"
 	authMock "food-api/infrastructure/auth/mocks"
 	modelAuth "food-api/infrastructure/auth/model"
 	"food-api/infrastructure/database"
@@ -23,6 +26,7 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 	"time"
 )
@@ -93,10 +97,16 @@ func dataTokenDetails() *modelAuth.TokenDetails {
 	}
 }
 
+// authTime is the login time of the refresh tokens for test
+var authTime = time.Unix(1600000000, 0)
+
 func dataJwt() *jwt.Token {
 	claimsData := jwt.MapClaims{}
 	claimsData["user_id"] = dataLogin().ID
 	claimsData["refresh_uuid"] = dataTokenDetails().RefreshUuid
+	claimsData["family"] = "family-1"
+	claimsData["auth_time"] = float64(authTime.Unix())
+	claimsData["remember_me"] = true
 
 	return &jwt.Token{
 		Raw:       "",
@@ -147,6 +157,23 @@ func dataUserResponse() *responseUser.UserResponse {
 	}
 }
 
+// unlockedAttempts returns the failed logins of a login that is not locked.
+func unlockedAttempts() *repoMock.LoginAttemptRepository {
+	mockAttempts := &repoMock.LoginAttemptRepository{}
+	mockAttempts.On("LockedFor", mock.Anything, mock.Anything).Return(time.Duration(0), nil)
+	mockAttempts.On("Reset", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	return mockAttempts
+}
+
+// noTwoFactor returns the two-factor authentication of a user that never enrolled.
+func noTwoFactor() *repoMock.TwoFactorRepository {
+	mockTwoFactor := &repoMock.TwoFactorRepository{}
+	mockTwoFactor.On("GetTwoFactor", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows).Maybe()
+
+	return mockTwoFactor
+}
+
 func TestLoginRouter_LoginHandler(t *testing.T) {
 
 	t.Run("Error Body Login Handler", func(tt *testing.T) {
@@ -190,13 +217,76 @@ func TestLoginRouter_LoginHandler(t *testing.T) {
 		response := httptest.NewRecorder()
 		mockRepository := &repoMock.UserRepository{}
 
-		testLoginHandler := &application.LoginRouter{Repo: mockRepository}
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Attempts: unlockedAttempts(), TwoFactor: noTwoFactor()}
 		mockRepository.On("GetUserByEmailAndPassword", mock.Anything, mock.Anything).Return(nil, errors.New("error sql"))
 
 		testLoginHandler.LoginHandler(response, request)
 		mockRepository.AssertExpectations(tt)
 	})
 
+	t.Run("Error Login Locked", func(tt *testing.T) {
+		marshal, err := json.Marshal(dataUser())
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/login", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockAttempts := &repoMock.LoginAttemptRepository{}
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Attempts: mockAttempts}
+		mockAttempts.On("LockedFor", mock.Anything, []string{"email:daniel.delapava@jikkosoft.com", "ip:192.0.2.1"}).
+			Return(90*time.Second+time.Millisecond, nil)
+
+		testLoginHandler.LoginHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		mockAttempts.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusTooManyRequests, response.Code)
+		assert.Equal(tt, "91", response.Header().Get("Retry-After"))
+	})
+
+	t.Run("Error Password Does Not Match", func(tt *testing.T) {
+		marshal, err := json.Marshal(dataUser())
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/login", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockAttempts := &repoMock.LoginAttemptRepository{}
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Attempts: mockAttempts}
+		mockAttempts.On("LockedFor", mock.Anything, mock.Anything).Return(time.Duration(0), nil)
+		mockRepository.On("GetUserByEmailAndPassword", mock.Anything, mock.Anything).Return(nil, persistence.ErrPasswordMismatch)
+		mockAttempts.On("Fail", mock.Anything, "email:daniel.delapava@jikkosoft.com", mock.Anything).Return(time.Duration(0), nil)
+		mockAttempts.On("Fail", mock.Anything, "ip:192.0.2.1", mock.Anything).Return(time.Duration(0), nil)
+
+		testLoginHandler.LoginHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		mockAttempts.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusConflict, response.Code)
+	})
+
+	t.Run("Error Unknown Email Locks The Login", func(tt *testing.T) {
+		marshal, err := json.Marshal(dataUser())
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/login", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockAttempts := &repoMock.LoginAttemptRepository{}
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Attempts: mockAttempts}
+		mockAttempts.On("LockedFor", mock.Anything, mock.Anything).Return(time.Duration(0), nil)
+		mockRepository.On("GetUserByEmailAndPassword", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+		mockAttempts.On("Fail", mock.Anything, "email:daniel.delapava@jikkosoft.com", mock.Anything).Return(time.Minute, nil)
+		mockAttempts.On("Fail", mock.Anything, "ip:192.0.2.1", mock.Anything).Return(time.Duration(0), nil)
+
+		testLoginHandler.LoginHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		mockAttempts.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusTooManyRequests, response.Code)
+		assert.Equal(tt, "60", response.Header().Get("Retry-After"))
+	})
+
 	t.Run("Error Create Token", func(tt *testing.T) {
 		marshal, err := json.Marshal(dataUser())
 		assert.NoError(tt, err)
@@ -212,9 +302,9 @@ func TestLoginRouter_LoginHandler(t *testing.T) {
 			Auth:   mockAuth,
 		}
 
-		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Redis: mockRedis, Token: mockToken}
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Attempts: unlockedAttempts(), TwoFactor: noTwoFactor(), Redis: mockRedis, Token: mockToken}
 		mockRepository.On("GetUserByEmailAndPassword", mock.Anything, mock.Anything).Return(dataUserResponse(), nil)
-		mockToken.On("CreateToken", mock.Anything).Return(nil, errors.New("error create token"))
+		mockToken.On("CreateToken", mock.Anything, mock.Anything).Return(nil, errors.New("error create token"))
 
 		testLoginHandler.LoginHandler(response, request)
 		mockRepository.AssertExpectations(tt)
@@ -235,15 +325,87 @@ func TestLoginRouter_LoginHandler(t *testing.T) {
 			Auth:   mockAuth,
 		}
 
-		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Redis: mockRedis, Token: mockToken}
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Attempts: unlockedAttempts(), TwoFactor: noTwoFactor(), Redis: mockRedis, Token: mockToken}
 		mockRepository.On("GetUserByEmailAndPassword", mock.Anything, mock.Anything).Return(dataUserResponse(), nil)
-		mockToken.On("CreateToken", mock.Anything).Return(dataTokenDetails(), nil)
+		mockToken.On("CreateToken", mock.Anything, mock.Anything).Return(dataTokenDetails(), nil)
 		mockAuth.On("CreateAuth", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error create auth"))
 
 		testLoginHandler.LoginHandler(response, request)
 		mockRepository.AssertExpectations(tt)
 	})
 
+	t.Run("Error Email Not Verified", func(tt *testing.T) {
+		_ = os.Setenv("REQUIRE_EMAIL_VERIFICATION", "true")
+		defer func() {
+			_ = os.Unsetenv("REQUIRE_EMAIL_VERIFICATION")
+		}()
+
+		marshal, err := json.Marshal(dataUser())
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/login", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Attempts: unlockedAttempts(), TwoFactor: noTwoFactor(), Token: mockToken}
+		mockRepository.On("GetUserByEmailAndPassword", mock.Anything, mock.Anything).Return(dataUserResponse(), nil)
+
+		testLoginHandler.LoginHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		mockToken.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("Error Invalid Scope", func(tt *testing.T) {
+		marshal, err := json.Marshal(model.Login{User: dataUser(), Scope: "foods:read foods:delete"})
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/login", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Token: mockToken}
+
+		testLoginHandler.LoginHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		mockToken.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Login Scoped Remember Me Successfully", func(tt *testing.T) {
+		marshal, err := json.Marshal(model.Login{User: dataUser(), Scope: "foods:read foods:read users:read", RememberMe: true})
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/login", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockAuth := &authMock.InterfaceAuth{}
+		mockToken := &authMock.TokenInterface{}
+
+		mockRedis := &database.RedisService{
+			Client: newTestRedis(),
+			Auth:   mockAuth,
+		}
+
+		scopes := []string{modelAuth.ScopeFoodsRead, modelAuth.ScopeUsersRead}
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Attempts: unlockedAttempts(), TwoFactor: noTwoFactor(), Redis: mockRedis, Token: mockToken}
+		mockRepository.On("GetUserByEmailAndPassword", mock.Anything, mock.Anything).Return(dataUserResponse(), nil)
+		mockToken.On("CreateToken", mock.Anything, modelAuth.TokenOptions{Scopes: scopes, RememberMe: true}).Return(dataTokenDetails(), nil)
+		mockAuth.On("CreateAuth", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockAuth.On("SaveSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		testLoginHandler.LoginHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		mockToken.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var dataLogin modelAuth.DataLogin
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&dataLogin))
+		assert.Equal(tt, "foods:read users:read", dataLogin.Scope)
+	})
+
 	t.Run("Login Successfully", func(tt *testing.T) {
 		marshal, err := json.Marshal(dataUser())
 		assert.NoError(tt, err)
@@ -259,13 +421,54 @@ func TestLoginRouter_LoginHandler(t *testing.T) {
 			Auth:   mockAuth,
 		}
 
-		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Redis: mockRedis, Token: mockToken}
+		mockAttempts := &repoMock.LoginAttemptRepository{}
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Attempts: mockAttempts, TwoFactor: noTwoFactor(), Redis: mockRedis, Token: mockToken}
+		mockAttempts.On("LockedFor", mock.Anything, mock.Anything).Return(time.Duration(0), nil)
+		mockAttempts.On("Reset", mock.Anything, "email:daniel.delapava@jikkosoft.com").Return(nil)
 		mockRepository.On("GetUserByEmailAndPassword", mock.Anything, mock.Anything).Return(dataUserResponse(), nil)
-		mockToken.On("CreateToken", mock.Anything).Return(dataTokenDetails(), nil)
+		mockToken.On("CreateToken", mock.Anything, mock.Anything).Return(dataTokenDetails(), nil)
 		mockAuth.On("CreateAuth", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockAuth.On("SaveSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		testLoginHandler.LoginHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		mockAttempts.AssertExpectations(tt)
+	})
+
+	t.Run("Login Two Factor Required", func(tt *testing.T) {
+		marshal, err := json.Marshal(model.Login{User: dataUser(), Scope: "foods:read", RememberMe: true})
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/login", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockTwoFactor := &repoMock.TwoFactorRepository{}
+		mockChallenges := &repoMock.TwoFactorChallengeRepository{}
+		mockToken := &authMock.TokenInterface{}
+		user := dataUserResponse()
+		enabledAt := time.Now()
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Attempts: unlockedAttempts(), TwoFactor: mockTwoFactor,
+			Challenges: mockChallenges, Token: mockToken}
+		mockRepository.On("GetUserByEmailAndPassword", mock.Anything, mock.Anything).Return(user, nil)
+		mockTwoFactor.On("GetTwoFactor", mock.Anything, user.ID).Return(&model.TwoFactor{UserID: user.ID, EnabledAt: &enabledAt}, nil)
+		mockChallenges.On("SaveChallenge", mock.Anything, mock.Anything, mock.MatchedBy(func(challenge *model.TwoFactorChallenge) bool {
+			return challenge.UserID == user.ID && challenge.Email == "daniel.delapava@jikkosoft.com" &&
+				challenge.Scope == "foods:read" && challenge.RememberMe && challenge.ExpiresAt.After(time.Now())
+		})).Return(nil)
 
 		testLoginHandler.LoginHandler(response, request)
 		mockRepository.AssertExpectations(tt)
+		mockChallenges.AssertExpectations(tt)
+		mockToken.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var dataLogin modelAuth.DataLogin
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&dataLogin))
+		assert.True(tt, dataLogin.TwoFactorRequired)
+		assert.NotEmpty(tt, dataLogin.ChallengeToken)
+		assert.Empty(tt, dataLogin.AccessToken)
 	})
 }
 
@@ -431,6 +634,31 @@ func TestLoginRouter_RefreshHandler(t *testing.T) {
 		mockRepository.AssertExpectations(tt)
 	})
 
+	t.Run("Error Refresh Token Of An OAuth Client", func(tt *testing.T) {
+		marshal, err := json.Marshal(dataLogin())
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/refresh", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+		mockAuth := &authMock.InterfaceAuth{}
+
+		mockRedis := &database.RedisService{
+			Client: newTestRedis(),
+			Auth:   mockAuth,
+		}
+
+		refreshToken := dataJwt()
+		refreshToken.Claims.(jwt.MapClaims)["client_id"] = uuid.New().String()
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Redis: mockRedis, Token: mockToken}
+		mockToken.On("VerifyAndValidateRefreshToken", mock.Anything).Return(refreshToken, nil)
+
+		testLoginHandler.RefreshHandler(response, request)
+		mockAuth.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
 	t.Run("Error Delete Refresh", func(tt *testing.T) {
 		marshal, err := json.Marshal(dataLogin())
 		assert.NoError(tt, err)
@@ -448,12 +676,38 @@ func TestLoginRouter_RefreshHandler(t *testing.T) {
 
 		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Redis: mockRedis, Token: mockToken}
 		mockToken.On("VerifyAndValidateRefreshToken", mock.Anything).Return(dataJwt(), nil)
-		mockAuth.On("DeleteRefresh", mock.Anything, mock.Anything).Return(errors.New("error delete refresh token"))
+		mockAuth.On("RotateRefresh", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error delete refresh token"))
 
 		testLoginHandler.RefreshHandler(response, request)
 		mockRepository.AssertExpectations(tt)
 	})
 
+	t.Run("Error Reused Refresh", func(tt *testing.T) {
+		marshal, err := json.Marshal(dataLogin())
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/refresh", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+		mockAuth := &authMock.InterfaceAuth{}
+
+		mockRedis := &database.RedisService{
+			Client: newTestRedis(),
+			Auth:   mockAuth,
+		}
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Redis: mockRedis, Token: mockToken}
+		mockToken.On("VerifyAndValidateRefreshToken", mock.Anything).Return(dataJwt(), nil)
+		mockAuth.On("RotateRefresh", mock.Anything, mock.Anything, "family-1").Return(auth.ErrRefreshReused)
+
+		testLoginHandler.RefreshHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		mockToken.AssertExpectations(tt)
+		mockAuth.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
 	t.Run("Error Create Token", func(tt *testing.T) {
 		marshal, err := json.Marshal(dataLogin())
 		assert.NoError(tt, err)
@@ -471,8 +725,9 @@ func TestLoginRouter_RefreshHandler(t *testing.T) {
 
 		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Redis: mockRedis, Token: mockToken}
 		mockToken.On("VerifyAndValidateRefreshToken", mock.Anything).Return(dataJwt(), nil)
-		mockAuth.On("DeleteRefresh", mock.Anything, mock.Anything).Return(nil)
-		mockToken.On("CreateToken", mock.Anything).Return(nil, errors.New("error create token"))
+		mockAuth.On("RotateRefresh", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockRepository.On("GetById", mock.Anything, mock.Anything).Return(*dataUserResponse(), nil)
+		mockToken.On("CreateToken", mock.Anything, mock.Anything).Return(nil, errors.New("error create token"))
 
 		testLoginHandler.RefreshHandler(response, request)
 		mockRepository.AssertExpectations(tt)
@@ -495,8 +750,9 @@ func TestLoginRouter_RefreshHandler(t *testing.T) {
 
 		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Redis: mockRedis, Token: mockToken}
 		mockToken.On("VerifyAndValidateRefreshToken", mock.Anything).Return(dataJwt(), nil)
-		mockAuth.On("DeleteRefresh", mock.Anything, mock.Anything).Return(nil)
-		mockToken.On("CreateToken", mock.Anything).Return(dataTokenDetails(), nil)
+		mockAuth.On("RotateRefresh", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockRepository.On("GetById", mock.Anything, mock.Anything).Return(*dataUserResponse(), nil)
+		mockToken.On("CreateToken", mock.Anything, mock.Anything).Return(dataTokenDetails(), nil)
 		mockAuth.On("CreateAuth", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error create auth"))
 
 		testLoginHandler.RefreshHandler(response, request)
@@ -520,11 +776,43 @@ func TestLoginRouter_RefreshHandler(t *testing.T) {
 
 		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Redis: mockRedis, Token: mockToken}
 		mockToken.On("VerifyAndValidateRefreshToken", mock.Anything).Return(dataJwt(), nil)
-		mockAuth.On("DeleteRefresh", mock.Anything, mock.Anything).Return(nil)
-		mockToken.On("CreateToken", mock.Anything).Return(dataTokenDetails(), nil)
+		mockAuth.On("RotateRefresh", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockRepository.On("GetById", mock.Anything, mock.Anything).Return(responseUser.UserResponse{Role: modelAuth.RoleModerator}, nil)
+		mockToken.On("CreateToken", mock.Anything, mock.MatchedBy(func(options modelAuth.TokenOptions) bool {
+			return options.Role == modelAuth.RoleModerator && options.Family == "family-1" && options.AuthTime.Equal(authTime) && options.RememberMe
+		})).Return(dataTokenDetails(), nil)
 		mockAuth.On("CreateAuth", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockAuth.On("SaveSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		testLoginHandler.RefreshHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		mockToken.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+
+	t.Run("Error Deleted User Refresh", func(tt *testing.T) {
+		marshal, err := json.Marshal(dataLogin())
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/api/refresh", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+		mockAuth := &authMock.InterfaceAuth{}
+
+		mockRedis := &database.RedisService{
+			Client: newTestRedis(),
+			Auth:   mockAuth,
+		}
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Redis: mockRedis, Token: mockToken}
+		mockToken.On("VerifyAndValidateRefreshToken", mock.Anything).Return(dataJwt(), nil)
+		mockAuth.On("RotateRefresh", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockRepository.On("GetById", mock.Anything, mock.Anything).Return(responseUser.UserResponse{}, sql.ErrNoRows)
 
 		testLoginHandler.RefreshHandler(response, request)
 		mockRepository.AssertExpectations(tt)
+		mockToken.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
 	})
 }
@@ -0,0 +1,285 @@
+package user
+
+import (
+	"database/sql"
+	"encoding/json"
+	"food-api/domain/user/application"
+	"food-api/domain/user/domain/model"
+	repoMock "food-api/domain/user/domain/repository/mocks"
+	"food-api/domain/user/domain/service"
+	"food-api/domain/user/infrastructure/persistence"
+	authMock "food-api/infrastructure/auth/mocks"
+	modelAuth "food-api/infrastructure/auth/model"
+	"food-api/infrastructure/database"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Code verifier and S256 code challenge of RFC 7636 appendix B
+const (
+	oauthCodeVerifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	oauthCodeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+)
+
+// dataConfidentialClient is data for test
+func dataConfidentialClient() *model.OAuthClient {
+	return &model.OAuthClient{ID: uuid.New().String(), UserID: uuid.New().String(), Name: "Meal Planner",
+		RedirectURIs: []string{"https://planner.example.com/callback"}, Scope: "openid profile foods:read",
+		Confidential: true, SecretHash: service.HashClientSecret("secret-1")}
+}
+
+// dataAuthorizationCode is data for test
+func dataAuthorizationCode(client *model.OAuthClient) *model.AuthorizationCode {
+	return &model.AuthorizationCode{ClientID: client.ID, UserID: uuid.New().String(), RedirectURI: client.RedirectURIs[0],
+		Scope: "openid profile foods:read", Nonce: "nonce-1", CodeChallenge: oauthCodeChallenge, AuthTime: authTime,
+		ExpiresAt: time.Now().Add(time.Minute)}
+}
+
+// newTokenRequest returns a token request authenticated with HTTP Basic
+func newTokenRequest(client *model.OAuthClient, secret string, form url.Values) *http.Request {
+	request := httptest.NewRequest(http.MethodPost, "/api/oauth/token", strings.NewReader(form.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.SetBasicAuth(url.QueryEscape(client.ID), url.QueryEscape(secret))
+
+	return request
+}
+
+// decodeOAuthError returns the error code of the response of the token endpoint
+func decodeOAuthError(tt *testing.T, response *httptest.ResponseRecorder) string {
+	var body model.OAuthError
+	assert.NoError(tt, json.NewDecoder(response.Body).Decode(&body))
+
+	return body.Error
+}
+
+func TestLoginRouter_TokenHandler(t *testing.T) {
+	client := dataConfidentialClient()
+	codeForm := url.Values{"grant_type": {"authorization_code"}, "code": {"code-1"},
+		"redirect_uri": {client.RedirectURIs[0]}, "code_verifier": {oauthCodeVerifier}}
+
+	t.Run("Error Invalid Client Secret", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+
+		testLoginHandler := &application.LoginRouter{OAuthClients: mockClients}
+		mockClients.On("GetClient", mock.Anything, client.ID).Return(client, nil)
+
+		testLoginHandler.TokenHandler(response, newTokenRequest(client, "secret-2", codeForm))
+		mockClients.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+		assert.NotEmpty(tt, response.Header().Get("WWW-Authenticate"))
+		assert.Equal(tt, "invalid_client", decodeOAuthError(tt, response))
+	})
+
+	t.Run("Error Unknown Client", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+
+		testLoginHandler := &application.LoginRouter{OAuthClients: mockClients}
+		mockClients.On("GetClient", mock.Anything, client.ID).Return(nil, sql.ErrNoRows)
+
+		testLoginHandler.TokenHandler(response, newTokenRequest(client, "secret-1", codeForm))
+		mockClients.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
+	t.Run("Error Unsupported Grant Type", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+
+		testLoginHandler := &application.LoginRouter{OAuthClients: mockClients}
+		mockClients.On("GetClient", mock.Anything, client.ID).Return(client, nil)
+
+		testLoginHandler.TokenHandler(response, newTokenRequest(client, "secret-1", url.Values{"grant_type": {"password"}}))
+		mockClients.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+		assert.Equal(tt, "unsupported_grant_type", decodeOAuthError(tt, response))
+	})
+
+	t.Run("Error Code Already Used", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+		mockCodes := &repoMock.AuthorizationCodeRepository{}
+
+		testLoginHandler := &application.LoginRouter{OAuthClients: mockClients, AuthorizationCodes: mockCodes}
+		mockClients.On("GetClient", mock.Anything, client.ID).Return(client, nil)
+		mockCodes.On("ConsumeCode", mock.Anything, "code-1").Return(nil, persistence.ErrAuthorizationCodeInvalid)
+
+		testLoginHandler.TokenHandler(response, newTokenRequest(client, "secret-1", codeForm))
+		mockCodes.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+		assert.Equal(tt, "invalid_grant", decodeOAuthError(tt, response))
+	})
+
+	t.Run("Error Wrong Code Verifier", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+		mockCodes := &repoMock.AuthorizationCodeRepository{}
+		mockRepository := &repoMock.UserRepository{}
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, OAuthClients: mockClients, AuthorizationCodes: mockCodes}
+		mockClients.On("GetClient", mock.Anything, client.ID).Return(client, nil)
+		mockCodes.On("ConsumeCode", mock.Anything, "code-1").Return(dataAuthorizationCode(client), nil)
+
+		form := url.Values{"grant_type": {"authorization_code"}, "code": {"code-1"},
+			"redirect_uri": {client.RedirectURIs[0]}, "code_verifier": {strings.Repeat("a", 43)}}
+		testLoginHandler.TokenHandler(response, newTokenRequest(client, "secret-1", form))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+		assert.Equal(tt, "invalid_grant", decodeOAuthError(tt, response))
+	})
+
+	t.Run("Error Code Of Another Client", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+		mockCodes := &repoMock.AuthorizationCodeRepository{}
+		mockRepository := &repoMock.UserRepository{}
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, OAuthClients: mockClients, AuthorizationCodes: mockCodes}
+		mockClients.On("GetClient", mock.Anything, client.ID).Return(client, nil)
+		mockCodes.On("ConsumeCode", mock.Anything, "code-1").Return(dataAuthorizationCode(dataConfidentialClient()), nil)
+
+		testLoginHandler.TokenHandler(response, newTokenRequest(client, "secret-1", codeForm))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+		assert.Equal(tt, "invalid_grant", decodeOAuthError(tt, response))
+	})
+
+	t.Run("Exchange Code Successfully", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+		mockCodes := &repoMock.AuthorizationCodeRepository{}
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+		mockAuth := &authMock.InterfaceAuth{}
+
+		mockRedis := &database.RedisService{
+			Client: newTestRedis(),
+			Auth:   mockAuth,
+		}
+
+		authorization := dataAuthorizationCode(client)
+		user := dataUserResponse()
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Redis: mockRedis, Token: mockToken,
+			OAuthClients: mockClients, AuthorizationCodes: mockCodes}
+		mockClients.On("GetClient", mock.Anything, client.ID).Return(client, nil)
+		mockCodes.On("ConsumeCode", mock.Anything, "code-1").Return(authorization, nil)
+		mockRepository.On("GetById", mock.Anything, authorization.UserID).Return(*user, nil)
+		mockToken.On("CreateToken", user.ID, mock.MatchedBy(func(options modelAuth.TokenOptions) bool {
+			return options.ClientID == client.ID && options.Family == "" && options.AuthTime.Equal(authTime) &&
+				modelAuth.FormatScopes(options.Scopes) == "openid profile foods:read"
+		})).Return(dataTokenDetails(), nil)
+		mockToken.On("CreateIDToken", user.ID, mock.MatchedBy(func(options modelAuth.IDTokenOptions) bool {
+			return options.ClientID == client.ID && options.Nonce == "nonce-1" && options.Claims["given_name"] == "Daniel" &&
+				options.Claims["email"] == nil
+		})).Return("id-token", nil)
+		mockAuth.On("CreateAuth", mock.Anything, user.ID, mock.Anything).Return(nil)
+		mockAuth.On("SaveSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		testLoginHandler.TokenHandler(response, newTokenRequest(client, "secret-1", codeForm))
+		mockToken.AssertExpectations(tt)
+		mockAuth.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		assert.Equal(tt, "no-store", response.Header().Get("Cache-Control"))
+
+		var token model.OAuthToken
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&token))
+		assert.Equal(tt, "Bearer", token.TokenType)
+		assert.Equal(tt, "id-token", token.IDToken)
+		assert.NotEmpty(tt, token.RefreshToken)
+		assert.Equal(tt, "openid profile foods:read", token.Scope)
+	})
+
+	t.Run("Error Refresh Token Of Another Client", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+		mockToken := &authMock.TokenInterface{}
+		mockAuth := &authMock.InterfaceAuth{}
+
+		mockRedis := &database.RedisService{
+			Client: newTestRedis(),
+			Auth:   mockAuth,
+		}
+
+		refreshToken := dataJwt()
+		refreshToken.Claims.(jwt.MapClaims)["client_id"] = uuid.New().String()
+		testLoginHandler := &application.LoginRouter{Redis: mockRedis, Token: mockToken, OAuthClients: mockClients}
+		mockClients.On("GetClient", mock.Anything, client.ID).Return(client, nil)
+		mockToken.On("VerifyAndValidateRefreshToken", "refresh-1").Return(refreshToken, nil)
+
+		form := url.Values{"grant_type": {"refresh_token"}, "refresh_token": {"refresh-1"}}
+		testLoginHandler.TokenHandler(response, newTokenRequest(client, "secret-1", form))
+		mockAuth.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+		assert.Equal(tt, "invalid_grant", decodeOAuthError(tt, response))
+	})
+
+	t.Run("Refresh Client Successfully", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+		mockAuth := &authMock.InterfaceAuth{}
+
+		mockRedis := &database.RedisService{
+			Client: newTestRedis(),
+			Auth:   mockAuth,
+		}
+
+		refreshToken := dataJwt()
+		refreshToken.Claims.(jwt.MapClaims)["client_id"] = client.ID
+		refreshToken.Claims.(jwt.MapClaims)["user_id"] = uuid.New().String()
+		refreshToken.Claims.(jwt.MapClaims)["scope"] = "foods:read"
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Redis: mockRedis, Token: mockToken, OAuthClients: mockClients}
+		mockClients.On("GetClient", mock.Anything, client.ID).Return(client, nil)
+		mockToken.On("VerifyAndValidateRefreshToken", "refresh-1").Return(refreshToken, nil)
+		mockAuth.On("RotateRefresh", mock.Anything, mock.Anything, "family-1").Return(nil)
+		mockRepository.On("GetById", mock.Anything, mock.Anything).Return(*dataUserResponse(), nil)
+		mockToken.On("CreateToken", mock.Anything, mock.MatchedBy(func(options modelAuth.TokenOptions) bool {
+			return options.ClientID == client.ID && options.Family == "family-1" && options.AuthTime.Equal(authTime)
+		})).Return(dataTokenDetails(), nil)
+		mockAuth.On("CreateAuth", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockAuth.On("SaveSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		form := url.Values{"grant_type": {"refresh_token"}, "refresh_token": {"refresh-1"}}
+		testLoginHandler.TokenHandler(response, newTokenRequest(client, "secret-1", form))
+		mockToken.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var token model.OAuthToken
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&token))
+		assert.Empty(tt, token.IDToken)
+		assert.Equal(tt, "foods:read", token.Scope)
+	})
+}
+
+func TestLoginRouter_OpenIDConfigurationHandler(t *testing.T) {
+	t.Run("OpenID Configuration Handler", func(tt *testing.T) {
+		jwks := modelAuth.JWKS{Keys: []modelAuth.JWK{{KeyID: "1", Algorithm: "EdDSA"}, {KeyID: "2", Algorithm: "EdDSA"}}}
+
+		request := httptest.NewRequest(http.MethodGet, "/.well-known/openid-configuration", nil)
+		response := httptest.NewRecorder()
+		mockToken := &authMock.TokenInterface{}
+
+		testLoginHandler := &application.LoginRouter{Token: mockToken}
+		mockToken.On("JWKS").Return(jwks)
+
+		testLoginHandler.OpenIDConfigurationHandler(response, request)
+		mockToken.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var body model.OpenIDConfiguration
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&body))
+		assert.Equal(tt, service.OAuthIssuer(), body.Issuer)
+		assert.Equal(tt, []string{"EdDSA"}, body.IDTokenSigningAlgValuesSupported)
+		assert.Equal(tt, []string{"S256"}, body.CodeChallengeMethodsSupported)
+	})
+}
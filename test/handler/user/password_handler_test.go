@@ -0,0 +1,195 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"food-api/domain/user/application"
+	responseUser "food-api/domain/user/application/v1/response"
+	"food-api/domain/user/domain/model"
+	repoMock "food-api/domain/user/domain/repository/mocks"
+	"food-api/domain/user/infrastructure/persistence"
+	authMock "food-api/infrastructure/auth/mocks"
+	"food-api/infrastructure/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// notifierTest keeps the tokens sent to the users
+type notifierTest struct {
+	mu            sync.Mutex
+	resets        map[string]string
+	verifications map[string]string
+}
+
+func newNotifierTest() *notifierTest {
+	return &notifierTest{resets: map[string]string{}, verifications: map[string]string{}}
+}
+
+func (nt *notifierTest) PasswordReset(ctx context.Context, user responseUser.UserResponse, token string) error {
+	nt.mu.Lock()
+	defer nt.mu.Unlock()
+
+	nt.resets[user.ID] = token
+	return nil
+}
+
+// reset returns the reset token sent to the user, the resets are sent in background
+func (nt *notifierTest) reset(userId string) string {
+	nt.mu.Lock()
+	defer nt.mu.Unlock()
+
+	return nt.resets[userId]
+}
+
+func (nt *notifierTest) VerifyEmail(ctx context.Context, user responseUser.UserResponse, token string) error {
+	nt.verifications[user.ID] = token
+	return nil
+}
+
+// newPasswordRequest returns a request with the body in JSON
+func newPasswordRequest(tt *testing.T, path string, body interface{}) *http.Request {
+	marshal, err := json.Marshal(body)
+	assert.NoError(tt, err)
+
+	return httptest.NewRequest(http.MethodPost, path, bytes.NewReader(marshal))
+}
+
+func TestLoginRouter_ForgotPasswordHandler(t *testing.T) {
+	user := dataUser()
+	userResponse := responseUser.UserResponse{ID: user.ID, Names: user.Names, LastNames: user.LastNames, Email: user.Email}
+
+	t.Run("Validate Forgot Password Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository}
+
+		testLoginHandler.ForgotPasswordHandler(response, newPasswordRequest(tt, "/api/forgot-password", map[string]string{"email": "email"}))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Unknown Email Forgot Password Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockResetTokens := &repoMock.ResetTokenRepository{}
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, ResetTokens: mockResetTokens}
+		mockRepository.On("GetByEmail", mock.Anything, user.Email).Return(responseUser.UserResponse{}, sql.ErrNoRows)
+
+		testLoginHandler.ForgotPasswordHandler(response, newPasswordRequest(tt, "/api/forgot-password", map[string]string{"email": user.Email}))
+		mockRepository.AssertExpectations(tt)
+		mockResetTokens.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+
+	t.Run("Error Save Token Forgot Password Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockResetTokens := &repoMock.ResetTokenRepository{}
+		notifier := newNotifierTest()
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, ResetTokens: mockResetTokens, Notifier: notifier}
+		mockRepository.On("GetByEmail", mock.Anything, user.Email).Return(userResponse, nil)
+		saved := make(chan struct{})
+		mockResetTokens.On("SaveResetToken", mock.Anything, user.ID, mock.Anything, time.Hour).Return(errors.New("error redis")).
+			Run(func(mock.Arguments) { close(saved) })
+
+		testLoginHandler.ForgotPasswordHandler(response, newPasswordRequest(tt, "/api/forgot-password", map[string]string{"email": user.Email}))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		select {
+		case <-saved:
+		case <-time.After(time.Second):
+			tt.Fatal("the reset token was not saved")
+		}
+
+		assert.Empty(tt, notifier.reset(user.ID))
+	})
+
+	t.Run("Forgot Password Successfully", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		notifier := newNotifierTest()
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, ResetTokens: persistence.NewResetTokenRepository(newTestRedis()), Notifier: notifier}
+		mockRepository.On("GetByEmail", mock.Anything, user.Email).Return(userResponse, nil)
+
+		testLoginHandler.ForgotPasswordHandler(response, newPasswordRequest(tt, "/api/forgot-password", map[string]string{"email": user.Email}))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		assert.Eventually(tt, func() bool { return len(notifier.reset(user.ID)) == 64 }, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestLoginRouter_ResetPasswordHandler(t *testing.T) {
+	user := dataUser()
+
+	t.Run("Validate Reset Password Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository}
+
+		testLoginHandler.ResetPasswordHandler(response, newPasswordRequest(tt, "/api/reset-password", model.PasswordReset{Password: "123"}))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Error Invalid Token Reset Password Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, ResetTokens: persistence.NewResetTokenRepository(newTestRedis())}
+
+		testLoginHandler.ResetPasswordHandler(response, newPasswordRequest(tt, "/api/reset-password", model.PasswordReset{Token: "token", Password: "654321"}))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Reset Password Successfully", func(tt *testing.T) {
+		ctx := context.Background()
+		resetTokens := persistence.NewResetTokenRepository(newTestRedis())
+		assert.NoError(tt, resetTokens.SaveResetToken(ctx, user.ID, "old", time.Hour))
+		assert.NoError(tt, resetTokens.SaveResetToken(ctx, user.ID, "token", time.Hour))
+
+		mockRepository := &repoMock.UserRepository{}
+		mockAuth := &authMock.InterfaceAuth{}
+		mockRedis := &database.RedisService{Auth: mockAuth}
+		mockAPIKeys := &repoMock.APIKeyRepository{}
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, ResetTokens: resetTokens, Redis: mockRedis, APIKeys: mockAPIKeys}
+		mockRepository.On("UpdatePassword", mock.Anything, user.ID, mock.MatchedBy(func(hash string) bool {
+			return model.User{PasswordHash: hash}.PasswordMatch("654321")
+		}), mock.Anything).Return(nil)
+		mockAuth.On("DeleteUserTokens", mock.Anything, user.ID).Return(nil)
+		mockAPIKeys.On("DeleteUserAPIKeys", mock.Anything, user.ID).Return(nil)
+
+		response := httptest.NewRecorder()
+		testLoginHandler.ResetPasswordHandler(response, newPasswordRequest(tt, "/api/reset-password", model.PasswordReset{Token: "old", Password: "654321"}))
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+
+		response = httptest.NewRecorder()
+		testLoginHandler.ResetPasswordHandler(response, newPasswordRequest(tt, "/api/reset-password", model.PasswordReset{Token: "token", Password: "654321"}))
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		// The token can only be used once
+		response = httptest.NewRecorder()
+		testLoginHandler.ResetPasswordHandler(response, newPasswordRequest(tt, "/api/reset-password", model.PasswordReset{Token: "token", Password: "654321"}))
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+
+		mockRepository.AssertExpectations(tt)
+		mockAuth.AssertExpectations(tt)
+		mockAPIKeys.AssertExpectations(tt)
+		mockRepository.AssertNumberOfCalls(tt, "UpdatePassword", 1)
+	})
+}
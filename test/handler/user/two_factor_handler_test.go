@@ -0,0 +1,193 @@
+package user
+
+import (
+	"bytes"
+	"encoding/json"
+	"food-api/domain/user/application"
+	responseUser "food-api/domain/user/application/v1/response"
+	"food-api/domain/user/domain/model"
+	repoMock "food-api/domain/user/domain/repository/mocks"
+	"food-api/domain/user/domain/service"
+	"food-api/domain/user/infrastructure/persistence"
+	authMock "food-api/infrastructure/auth/mocks"
+	modelAuth "food-api/infrastructure/auth/model"
+	"food-api/infrastructure/database"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// twoFactorSecret is the TOTP secret for test
+const twoFactorSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// newTwoFactorLoginRequest returns the second step of the login with the code
+func newTwoFactorLoginRequest(code string) *http.Request {
+	body, _ := json.Marshal(model.TwoFactorLogin{ChallengeToken: "challenge", TwoFactorCode: model.TwoFactorCode{Code: code}})
+
+	return httptest.NewRequest(http.MethodPost, "/api/login/2fa", bytes.NewReader(body))
+}
+
+// dataChallenge is data for test
+func dataChallenge(userId string) *model.TwoFactorChallenge {
+	return &model.TwoFactorChallenge{
+		UserID:     userId,
+		Email:      "daniel.delapava@jikkosoft.com",
+		Scope:      "foods:read",
+		RememberMe: true,
+		ExpiresAt:  time.Now().Add(time.Minute),
+	}
+}
+
+// dataTwoFactor is data for test
+func dataTwoFactor(userId string) *model.TwoFactor {
+	enabledAt := time.Now()
+
+	return &model.TwoFactor{UserID: userId, Secret: twoFactorSecret, EnabledAt: &enabledAt}
+}
+
+func TestLoginRouter_TwoFactorLoginHandler(t *testing.T) {
+	userId := uuid.New().String()
+
+	t.Run("Error Validate Two Factor Login", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockChallenges := &repoMock.TwoFactorChallengeRepository{}
+
+		testLoginHandler := &application.LoginRouter{Challenges: mockChallenges}
+
+		testLoginHandler.TwoFactorLoginHandler(response, httptest.NewRequest(http.MethodPost, "/api/login/2fa", bytes.NewReader([]byte(`{}`))))
+		mockChallenges.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Error Invalid Challenge", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockChallenges := &repoMock.TwoFactorChallengeRepository{}
+
+		testLoginHandler := &application.LoginRouter{Challenges: mockChallenges}
+		mockChallenges.On("GetChallenge", mock.Anything, "challenge").Return(nil, persistence.ErrTwoFactorChallengeInvalid)
+
+		testLoginHandler.TwoFactorLoginHandler(response, newTwoFactorLoginRequest("123456"))
+		mockChallenges.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
+	t.Run("Error Invalid Code", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockChallenges := &repoMock.TwoFactorChallengeRepository{}
+		mockTwoFactor := &repoMock.TwoFactorRepository{}
+		mockAttempts := &repoMock.LoginAttemptRepository{}
+		challenge := dataChallenge(userId)
+
+		testLoginHandler := &application.LoginRouter{Challenges: mockChallenges, TwoFactor: mockTwoFactor, Attempts: mockAttempts}
+		mockChallenges.On("GetChallenge", mock.Anything, "challenge").Return(challenge, nil)
+		mockChallenges.On("ConsumeChallenge", mock.Anything, "challenge").Return(challenge, nil)
+		mockAttempts.On("LockedFor", mock.Anything, mock.Anything).Return(time.Duration(0), nil)
+		mockTwoFactor.On("GetTwoFactor", mock.Anything, userId).Return(dataTwoFactor(userId), nil)
+		mockTwoFactor.On("UseRecoveryCode", mock.Anything, userId, "aaaaabbbbb", mock.Anything).Return(false, nil)
+		mockAttempts.On("Fail", mock.Anything, mock.Anything, mock.Anything).Return(time.Duration(0), nil)
+		mockChallenges.On("SaveChallenge", mock.Anything, "challenge", mock.MatchedBy(func(saved *model.TwoFactorChallenge) bool {
+			return saved.Attempts == 1
+		})).Return(nil)
+
+		testLoginHandler.TwoFactorLoginHandler(response, newTwoFactorLoginRequest("AAAAA-BBBBB"))
+		mockChallenges.AssertExpectations(tt)
+		mockTwoFactor.AssertExpectations(tt)
+		mockAttempts.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
+	t.Run("Error Last Attempt", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockChallenges := &repoMock.TwoFactorChallengeRepository{}
+		mockTwoFactor := &repoMock.TwoFactorRepository{}
+		mockAttempts := &repoMock.LoginAttemptRepository{}
+		challenge := dataChallenge(userId)
+		challenge.Attempts = service.MaxTwoFactorAttempts - 1
+
+		testLoginHandler := &application.LoginRouter{Challenges: mockChallenges, TwoFactor: mockTwoFactor, Attempts: mockAttempts}
+		mockChallenges.On("GetChallenge", mock.Anything, "challenge").Return(challenge, nil)
+		mockChallenges.On("ConsumeChallenge", mock.Anything, "challenge").Return(challenge, nil)
+		mockAttempts.On("LockedFor", mock.Anything, mock.Anything).Return(time.Duration(0), nil)
+		mockTwoFactor.On("GetTwoFactor", mock.Anything, userId).Return(dataTwoFactor(userId), nil)
+		mockAttempts.On("Fail", mock.Anything, mock.Anything, mock.Anything).Return(time.Duration(0), nil)
+
+		testLoginHandler.TwoFactorLoginHandler(response, newTwoFactorLoginRequest("000000"))
+		mockChallenges.AssertExpectations(tt)
+		mockChallenges.AssertNotCalled(tt, "SaveChallenge", mock.Anything, mock.Anything, mock.Anything)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
+	t.Run("Error Locked", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockChallenges := &repoMock.TwoFactorChallengeRepository{}
+		mockAttempts := &repoMock.LoginAttemptRepository{}
+
+		testLoginHandler := &application.LoginRouter{Challenges: mockChallenges, Attempts: mockAttempts}
+		mockChallenges.On("GetChallenge", mock.Anything, "challenge").Return(dataChallenge(userId), nil)
+		mockAttempts.On("LockedFor", mock.Anything, []string{"email:daniel.delapava@jikkosoft.com", "ip:192.0.2.1"}).Return(time.Minute, nil)
+
+		testLoginHandler.TwoFactorLoginHandler(response, newTwoFactorLoginRequest("123456"))
+		mockAttempts.AssertExpectations(tt)
+		mockChallenges.AssertNotCalled(tt, "ConsumeChallenge", mock.Anything, mock.Anything)
+		assert.Equal(tt, http.StatusTooManyRequests, response.Code)
+		assert.Equal(tt, "60", response.Header().Get("Retry-After"))
+	})
+
+	t.Run("Error Consumed Challenge", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockChallenges := &repoMock.TwoFactorChallengeRepository{}
+		mockAttempts := &repoMock.LoginAttemptRepository{}
+
+		testLoginHandler := &application.LoginRouter{Challenges: mockChallenges, Attempts: mockAttempts}
+		mockChallenges.On("GetChallenge", mock.Anything, "challenge").Return(dataChallenge(userId), nil)
+		mockAttempts.On("LockedFor", mock.Anything, mock.Anything).Return(time.Duration(0), nil)
+		mockChallenges.On("ConsumeChallenge", mock.Anything, "challenge").Return(nil, persistence.ErrTwoFactorChallengeInvalid)
+
+		testLoginHandler.TwoFactorLoginHandler(response, newTwoFactorLoginRequest("123456"))
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
+	t.Run("Two Factor Login Successfully", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockChallenges := &repoMock.TwoFactorChallengeRepository{}
+		mockTwoFactor := &repoMock.TwoFactorRepository{}
+		mockAttempts := &repoMock.LoginAttemptRepository{}
+		mockAuth := &authMock.InterfaceAuth{}
+		mockToken := &authMock.TokenInterface{}
+
+		now := time.Now()
+		code, err := service.TOTPCode(twoFactorSecret, service.TOTPStep(now))
+		assert.NoError(tt, err)
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Challenges: mockChallenges, TwoFactor: mockTwoFactor,
+			Attempts: mockAttempts, Redis: &database.RedisService{Auth: mockAuth}, Token: mockToken}
+		mockChallenges.On("GetChallenge", mock.Anything, "challenge").Return(dataChallenge(userId), nil)
+		mockChallenges.On("ConsumeChallenge", mock.Anything, "challenge").Return(dataChallenge(userId), nil)
+		mockAttempts.On("LockedFor", mock.Anything, mock.Anything).Return(time.Duration(0), nil)
+		mockTwoFactor.On("GetTwoFactor", mock.Anything, userId).Return(dataTwoFactor(userId), nil)
+		mockTwoFactor.On("UseStep", mock.Anything, userId, mock.Anything).Return(true, nil)
+		mockRepository.On("GetById", mock.Anything, userId).Return(responseUser.UserResponse{ID: userId, Role: modelAuth.RoleUser}, nil)
+		mockAttempts.On("Reset", mock.Anything, "email:daniel.delapava@jikkosoft.com").Return(nil)
+		mockToken.On("CreateToken", userId, modelAuth.TokenOptions{Role: modelAuth.RoleUser, Scopes: []string{modelAuth.ScopeFoodsRead}, RememberMe: true}).
+			Return(dataTokenDetails(), nil)
+		mockAuth.On("CreateAuth", mock.Anything, userId, mock.Anything).Return(nil)
+		mockAuth.On("SaveSession", mock.Anything, userId, mock.Anything, mock.Anything).Return(nil)
+
+		testLoginHandler.TwoFactorLoginHandler(response, newTwoFactorLoginRequest(code))
+		mockRepository.AssertExpectations(tt)
+		mockTwoFactor.AssertExpectations(tt)
+		mockAttempts.AssertExpectations(tt)
+		mockToken.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var dataLogin modelAuth.DataLogin
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&dataLogin))
+		assert.NotEmpty(tt, dataLogin.AccessToken)
+		assert.False(tt, dataLogin.TwoFactorRequired)
+	})
+}
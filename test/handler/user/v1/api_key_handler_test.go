@@ -0,0 +1,191 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	v1 "food-api/domain/user/application/v1"
+	"food-api/domain/user/domain/model"
+	repoMock "food-api/domain/user/domain/repository/mocks"
+	"food-api/domain/user/domain/service"
+	authMock "food-api/infrastructure/auth/mocks"
+	modelAuth "food-api/infrastructure/auth/model"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newAPIKeyRequest returns a request to the API key id
+func newAPIKeyRequest(method, id, body string) *http.Request {
+	request := httptest.NewRequest(method, "/api/v1/api-keys/{id}", strings.NewReader(body))
+
+	requestCtx := chi.NewRouteContext()
+	requestCtx.URLParams.Add("id", id)
+
+	return request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+}
+
+func TestUserRouter_GetAPIKeysHandler(t *testing.T) {
+	owner := &modelAuth.AccessDetails{UserId: uuid.New().String(), TokenUuid: uuid.New().String()}
+
+	response := httptest.NewRecorder()
+	mockAPIKeys := &repoMock.APIKeyRepository{}
+	mockToken := &authMock.TokenInterface{}
+
+	testUserHandler := &v1.UserRouter{APIKeys: mockAPIKeys, Token: mockToken}
+	mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+	mockAPIKeys.On("GetAPIKeys", mock.Anything, owner.UserId).
+		Return([]model.APIKey{{ID: "key-1", UserID: owner.UserId, Name: "Script", KeyHash: "hash"}}, nil)
+
+	testUserHandler.GetAPIKeysHandler(response, httptest.NewRequest(http.MethodGet, "/api/v1/api-keys", nil))
+	mockAPIKeys.AssertExpectations(t)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), `"name":"Script"`)
+	assert.NotContains(t, response.Body.String(), "hash")
+}
+
+func TestUserRouter_CreateAPIKeyHandler(t *testing.T) {
+	owner := &modelAuth.AccessDetails{UserId: uuid.New().String(), TokenUuid: uuid.New().String(),
+		Scopes: []string{modelAuth.ScopeFoodsRead, modelAuth.ScopeFoodsWrite}}
+
+	t.Run("Error Created With An API Key", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockAPIKeys := &repoMock.APIKeyRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{APIKeys: mockAPIKeys, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).
+			Return(&modelAuth.AccessDetails{UserId: owner.UserId, APIKeyID: "key-1"}, nil)
+
+		testUserHandler.CreateAPIKeyHandler(response, newAPIKeyRequest(http.MethodPost, "", `{"name":"Script"}`))
+		mockAPIKeys.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("Error Scope Not In The Login", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockAPIKeys := &repoMock.APIKeyRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{APIKeys: mockAPIKeys, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+
+		testUserHandler.CreateAPIKeyHandler(response, newAPIKeyRequest(http.MethodPost, "",
+			`{"name":"Script","scope":"users:write"}`))
+		mockAPIKeys.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Error Expired Create Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockAPIKeys := &repoMock.APIKeyRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{APIKeys: mockAPIKeys, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+
+		body := `{"name":"Script","expires_at":"` + time.Now().Add(-time.Hour).Format(time.RFC3339) + `"}`
+		testUserHandler.CreateAPIKeyHandler(response, newAPIKeyRequest(http.MethodPost, "", body))
+		mockAPIKeys.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Create Successfully", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockAPIKeys := &repoMock.APIKeyRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{APIKeys: mockAPIKeys, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockAPIKeys.On("CreateAPIKey", mock.Anything, mock.MatchedBy(func(key *model.APIKey) bool {
+			return key.UserID == owner.UserId && key.Scope == "foods:read" && len(key.KeyHash) == 64
+		})).Return(nil)
+
+		testUserHandler.CreateAPIKeyHandler(response, newAPIKeyRequest(http.MethodPost, "",
+			`{"name":"Script","scope":"foods:read"}`))
+		mockAPIKeys.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusCreated, response.Code)
+
+		var created model.CreatedAPIKey
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&created))
+		assert.True(tt, strings.HasPrefix(created.Key, "fapi_"+created.Prefix+"_"))
+		prefix, ok := service.APIKeyPrefix(created.Key)
+		assert.True(tt, ok)
+		assert.Equal(tt, created.Prefix, prefix)
+	})
+}
+
+func TestUserRouter_UpdateAPIKeyHandler(t *testing.T) {
+	owner := &modelAuth.AccessDetails{UserId: uuid.New().String(), TokenUuid: uuid.New().String(),
+		Scopes: []string{modelAuth.ScopeFoodsRead}}
+
+	t.Run("Error Not Found Update Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockAPIKeys := &repoMock.APIKeyRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{APIKeys: mockAPIKeys, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockAPIKeys.On("GetAPIKey", mock.Anything, owner.UserId, "key-1").Return(nil, sql.ErrNoRows)
+
+		testUserHandler.UpdateAPIKeyHandler(response, newAPIKeyRequest(http.MethodPut, "key-1", `{"name":"Renamed"}`))
+		mockAPIKeys.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Update Successfully", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockAPIKeys := &repoMock.APIKeyRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{APIKeys: mockAPIKeys, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockAPIKeys.On("GetAPIKey", mock.Anything, owner.UserId, "key-1").
+			Return(&model.APIKey{ID: "key-1", UserID: owner.UserId, Name: "Script"}, nil)
+		mockAPIKeys.On("UpdateAPIKey", mock.Anything, mock.MatchedBy(func(key *model.APIKey) bool {
+			return key.Name == "Renamed" && key.Scope == "foods:read"
+		})).Return(nil)
+
+		testUserHandler.UpdateAPIKeyHandler(response, newAPIKeyRequest(http.MethodPut, "key-1", `{"name":"Renamed"}`))
+		mockAPIKeys.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+}
+
+func TestUserRouter_DeleteAPIKeyHandler(t *testing.T) {
+	owner := &modelAuth.AccessDetails{UserId: uuid.New().String(), APIKeyID: "key-1"}
+
+	t.Run("Error Not Found Delete Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockAPIKeys := &repoMock.APIKeyRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{APIKeys: mockAPIKeys, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockAPIKeys.On("DeleteAPIKey", mock.Anything, owner.UserId, "key-2").Return(sql.ErrNoRows)
+
+		testUserHandler.DeleteAPIKeyHandler(response, newAPIKeyRequest(http.MethodDelete, "key-2", ""))
+		mockAPIKeys.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Revoke Itself Successfully", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockAPIKeys := &repoMock.APIKeyRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{APIKeys: mockAPIKeys, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockAPIKeys.On("DeleteAPIKey", mock.Anything, owner.UserId, "key-1").Return(nil)
+
+		testUserHandler.DeleteAPIKeyHandler(response, newAPIKeyRequest(http.MethodDelete, "key-1", ""))
+		mockAPIKeys.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+}
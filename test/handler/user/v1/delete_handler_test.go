@@ -0,0 +1,159 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	v1 "food-api/domain/user/application/v1"
+	"food-api/domain/user/domain/model"
+	repoMock "food-api/domain/user/domain/repository/mocks"
+	authMock "food-api/infrastructure/auth/mocks"
+	modelAuth "food-api/infrastructure/auth/model"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// newDeleteRequest returns a delete request for the user id
+func newDeleteRequest(id string) *http.Request {
+	request := httptest.NewRequest(http.MethodDelete, "/api/v1/users/{id}", nil)
+
+	requestCtx := chi.NewRouteContext()
+	requestCtx.URLParams.Add("id", id)
+
+	return request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+}
+
+func TestUserRouter_DeleteHandler(t *testing.T) {
+	owner := &modelAuth.AccessDetails{UserId: uuid.New().String(), TokenUuid: uuid.New().String()}
+
+	t.Run("Error Another User Delete Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockAuth := &authMock.InterfaceAuth{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Auth: mockAuth, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+
+		testUserHandler.DeleteHandler(response, newDeleteRequest(uuid.New().String()))
+		mockRepository.AssertExpectations(tt)
+		mockAuth.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("Error Policy Delete Handler", func(tt *testing.T) {
+		_ = os.Setenv("DELETED_USER_FOODS", model.FoodPolicyTransfer)
+		defer func() {
+			_ = os.Unsetenv("DELETED_USER_FOODS")
+		}()
+
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+
+		testUserHandler.DeleteHandler(response, newDeleteRequest(owner.UserId))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+	})
+
+	t.Run("Error Revoke Tokens Delete Handler", func(tt *testing.T) {
+		user := dataUserResponse()[0]
+		user.ID = owner.UserId
+
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockAuth := &authMock.InterfaceAuth{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Auth: mockAuth, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetById", mock.Anything, owner.UserId).Return(user, nil)
+		mockAuth.On("DeleteUserTokens", mock.Anything, owner.UserId).Return(errors.New("error redis"))
+
+		testUserHandler.DeleteHandler(response, newDeleteRequest(owner.UserId))
+		mockRepository.AssertNotCalled(tt, "DeleteUser", mock.Anything, mock.Anything)
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+	})
+
+	t.Run("Error Not Found Delete Handler", func(tt *testing.T) {
+		user := dataUserResponse()[0]
+		user.ID = owner.UserId
+
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockAuth := &authMock.InterfaceAuth{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Auth: mockAuth, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetById", mock.Anything, owner.UserId).Return(user, nil)
+		mockAuth.On("DeleteUserTokens", mock.Anything, owner.UserId).Return(nil)
+		mockRepository.On("DeleteUser", mock.Anything, mock.Anything).Return(sql.ErrNoRows)
+
+		testUserHandler.DeleteHandler(response, newDeleteRequest(owner.UserId))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Delete Handler", func(tt *testing.T) {
+		user := dataUserResponse()[0]
+		user.ID = owner.UserId
+
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockAuth := &authMock.InterfaceAuth{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Auth: mockAuth, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetById", mock.Anything, owner.UserId).Return(user, nil)
+		mockAuth.On("DeleteUserTokens", mock.Anything, owner.UserId).Return(nil)
+		mockRepository.On("DeleteUser", mock.Anything, mock.MatchedBy(func(deletion *model.Deletion) bool {
+			return deletion.UserID == owner.UserId && deletion.FoodPolicy == model.FoodPolicyAnonymize
+		})).Return(nil)
+
+		testUserHandler.DeleteHandler(response, newDeleteRequest(owner.UserId))
+		mockRepository.AssertExpectations(tt)
+		mockAuth.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNoContent, response.Code)
+	})
+
+	t.Run("Admin Transfer Delete Handler", func(tt *testing.T) {
+		users := dataUserResponse()
+		admin := &modelAuth.AccessDetails{UserId: uuid.New().String(), TokenUuid: uuid.New().String(), Role: modelAuth.RoleAdmin}
+		_ = os.Setenv("DELETED_USER_FOODS", model.FoodPolicyTransfer)
+		_ = os.Setenv("DELETED_USER_FOODS_TRANSFER_TO", users[1].ID)
+		defer func() {
+			_ = os.Unsetenv("DELETED_USER_FOODS")
+			_ = os.Unsetenv("DELETED_USER_FOODS_TRANSFER_TO")
+		}()
+
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockAuth := &authMock.InterfaceAuth{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Auth: mockAuth, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(admin, nil)
+		mockRepository.On("GetById", mock.Anything, users[0].ID).Return(users[0], nil)
+		mockRepository.On("GetById", mock.Anything, users[1].ID).Return(users[1], nil)
+		mockAuth.On("DeleteUserTokens", mock.Anything, users[0].ID).Return(nil)
+		mockRepository.On("DeleteUser", mock.Anything, mock.MatchedBy(func(deletion *model.Deletion) bool {
+			return deletion.FoodOwner() == users[1].ID
+		})).Return(nil)
+
+		testUserHandler.DeleteHandler(response, newDeleteRequest(users[0].ID))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNoContent, response.Code)
+	})
+}
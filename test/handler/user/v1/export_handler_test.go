@@ -0,0 +1,211 @@
+package v1
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	v1 "food-api/domain/user/application/v1"
+	responseUser "food-api/domain/user/application/v1/response"
+	"food-api/domain/user/domain/model"
+	repoMock "food-api/domain/user/domain/repository/mocks"
+	"food-api/domain/user/infrastructure/persistence"
+	authMock "food-api/infrastructure/auth/mocks"
+	modelAuth "food-api/infrastructure/auth/model"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// newExportRequest returns a request with the id and job params
+func newExportRequest(target, id, job string) *http.Request {
+	request := httptest.NewRequest(http.MethodGet, target, nil)
+
+	requestCtx := chi.NewRouteContext()
+	requestCtx.URLParams.Add("id", id)
+	if job != "" {
+		requestCtx.URLParams.Add("job", job)
+	}
+
+	return request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+}
+
+// pngImage is the signature of a PNG image for test
+const pngImage = "\x89PNG\r\n\x1a\nimage"
+
+// stubImages returns the images of the URLs, any other URL cannot be downloaded
+type stubImages map[string]string
+
+func (si stubImages) Fetch(_ context.Context, url string) (string, []byte, error) {
+	image, ok := si[url]
+	if !ok {
+		return "", nil, errors.New("the image responded 404")
+	}
+
+	return "png", []byte(image), nil
+}
+
+// dataUserExport is data for test, the foods have an image linked by URL
+func dataUserExport(userId string) *responseUser.UserExportResponse {
+	now := time.Now()
+
+	return &responseUser.UserExportResponse{
+		Profile: responseUser.ProfileResponse{ID: userId, Names: "Daniel", Email: "daniel.delapava@jikkosoft.com", CreatedAt: now, UpdatedAt: now},
+		Foods: []responseUser.ExportFoodResponse{
+			{ID: "pancakes", Title: "Pancakes", FoodImage: "https://example.com/pancakes.png", Visibility: "private", CreatedAt: now, UpdatedAt: now},
+			{ID: "curry", Title: "Curry", FoodImage: "https://example.com/curry.jpg", Visibility: "public", CreatedAt: now, UpdatedAt: now},
+		},
+		Preferences: []responseUser.ExportPreferenceResponse{{FoodID: "curry", Favorite: true, Rating: 4}},
+		Followers:   []responseUser.ExportFollowResponse{},
+		Following:   []responseUser.ExportFollowResponse{},
+	}
+}
+
+// readArchive returns the files of a ZIP archive
+func readArchive(tt *testing.T, body []byte) map[string][]byte {
+	reader, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	assert.NoError(tt, err)
+
+	files := make(map[string][]byte)
+	for _, file := range reader.File {
+		content, err := file.Open()
+		assert.NoError(tt, err)
+
+		files[file.Name], err = ioutil.ReadAll(content)
+		assert.NoError(tt, err)
+		_ = content.Close()
+	}
+
+	return files
+}
+
+func TestUserRouter_ExportHandler(t *testing.T) {
+	owner := &modelAuth.AccessDetails{UserId: uuid.New().String(), TokenUuid: uuid.New().String()}
+
+	t.Run("Error Another User Export Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+
+		testUserHandler.ExportHandler(response, newExportRequest("/api/v1/users/{id}/export", uuid.New().String(), ""))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("Error SQL Export Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockExports := &repoMock.ExportRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Exports: mockExports, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetById", mock.Anything, owner.UserId).Return(responseUser.UserResponse{ID: owner.UserId}, nil)
+		mockExports.On("CountUserFoods", mock.Anything, owner.UserId).Return(0, errors.New("error sql"))
+
+		testUserHandler.ExportHandler(response, newExportRequest("/api/v1/users/{id}/export", owner.UserId, ""))
+		mockExports.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+	})
+
+	t.Run("Export Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockExports := &repoMock.ExportRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		images := stubImages{"https://example.com/pancakes.png": pngImage}
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Exports: mockExports, Images: images, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetById", mock.Anything, owner.UserId).Return(responseUser.UserResponse{ID: owner.UserId}, nil)
+		mockExports.On("CountUserFoods", mock.Anything, owner.UserId).Return(2, nil)
+		mockExports.On("GetUserExport", mock.Anything, owner.UserId).Return(dataUserExport(owner.UserId), nil)
+
+		testUserHandler.ExportHandler(response, newExportRequest("/api/v1/users/{id}/export", owner.UserId, ""))
+		mockExports.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		assert.Equal(tt, "application/zip", response.Header().Get("Content-Type"))
+
+		files := readArchive(tt, response.Body.Bytes())
+		assert.Contains(tt, files, "profile.json")
+		assert.Contains(tt, files, "preferences.json")
+		assert.Contains(tt, files, "followers.json")
+		assert.Contains(tt, files, "following.json")
+		assert.Equal(tt, pngImage, string(files["images/pancakes.png"]))
+
+		// The image that cannot be downloaded is kept as a URL
+		var foods []responseUser.ExportFoodResponse
+		assert.NoError(tt, json.Unmarshal(files["foods.json"], &foods))
+		assert.Equal(tt, "images/pancakes.png", foods[0].FoodImage)
+		assert.Equal(tt, "https://example.com/curry.jpg", foods[1].FoodImage)
+
+		var manifest []map[string]string
+		assert.NoError(tt, json.Unmarshal(files["images.json"], &manifest))
+		assert.Equal(tt, []map[string]string{
+			{"food_id": "pancakes", "url": "https://example.com/pancakes.png", "file": "images/pancakes.png"},
+			{"food_id": "curry", "url": "https://example.com/curry.jpg", "error": "the image responded 404"},
+		}, manifest)
+	})
+
+	t.Run("Async Export Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockExports := &repoMock.ExportRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Exports: mockExports, ExportJobs: persistence.NewExportJobRepository(), Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetById", mock.Anything, owner.UserId).Return(responseUser.UserResponse{ID: owner.UserId}, nil)
+		mockExports.On("CountUserFoods", mock.Anything, owner.UserId).Return(2, nil)
+		mockExports.On("GetUserExport", mock.Anything, owner.UserId).Return(dataUserExport(owner.UserId), nil)
+
+		exportPath := fmt.Sprintf("/api/v1/users/%s/export", owner.UserId)
+		testUserHandler.ExportHandler(response, newExportRequest(exportPath+"?async=true", owner.UserId, ""))
+		assert.Equal(tt, http.StatusAccepted, response.Code)
+
+		var job responseUser.ExportJobResponse
+		assert.NoError(tt, json.Unmarshal(response.Body.Bytes(), &job))
+		assert.Equal(tt, exportPath+"/"+job.ID, response.Header().Get("Location"))
+
+		// wait for the job to complete
+		for i := 0; i < 100 && job.Status != model.ExportStatusCompleted; i++ {
+			time.Sleep(10 * time.Millisecond)
+
+			response = httptest.NewRecorder()
+			testUserHandler.GetExportJobHandler(response, newExportRequest(exportPath+"/"+job.ID, owner.UserId, job.ID))
+			assert.Equal(tt, http.StatusOK, response.Code)
+			assert.NoError(tt, json.Unmarshal(response.Body.Bytes(), &job))
+		}
+
+		assert.Equal(tt, model.ExportStatusCompleted, job.Status)
+		assert.NotNil(tt, job.ExpiresAt)
+
+		download, err := url.Parse(job.DownloadURL)
+		assert.NoError(tt, err)
+
+		response = httptest.NewRecorder()
+		testUserHandler.DownloadExportHandler(response, newExportRequest(exportPath+"/"+job.ID+"/download?token=wrong", owner.UserId, job.ID))
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+
+		response = httptest.NewRecorder()
+		testUserHandler.DownloadExportHandler(response, newExportRequest(download.RequestURI(), owner.UserId, job.ID))
+		assert.Equal(tt, http.StatusOK, response.Code)
+		assert.Contains(tt, readArchive(tt, response.Body.Bytes()), "foods.json")
+	})
+}
@@ -0,0 +1,157 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	v1 "food-api/domain/user/application/v1"
+	responseUser "food-api/domain/user/application/v1/response"
+	repoMock "food-api/domain/user/domain/repository/mocks"
+	authMock "food-api/infrastructure/auth/mocks"
+	modelAuth "food-api/infrastructure/auth/model"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newFollowRequest returns a follow request for the user id
+func newFollowRequest(method, id string) *http.Request {
+	request := httptest.NewRequest(method, "/api/v1/users/{id}/follow", nil)
+
+	requestCtx := chi.NewRouteContext()
+	requestCtx.URLParams.Add("id", id)
+
+	return request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+}
+
+func TestUserRouter_FollowHandler(t *testing.T) {
+	follower := &modelAuth.AccessDetails{UserId: uuid.New().String(), TokenUuid: uuid.New().String()}
+
+	t.Run("Error Token Follow Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(nil, errors.New("error token"))
+
+		testUserHandler.FollowHandler(response, newFollowRequest(http.MethodPut, "1"))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
+	t.Run("Error Follow Yourself Follow Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(follower, nil)
+
+		testUserHandler.FollowHandler(response, newFollowRequest(http.MethodPut, follower.UserId))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Error Not Found Follow Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(follower, nil)
+		mockRepository.On("GetById", mock.Anything, "1").Return(responseUser.UserResponse{}, errors.New("not found"))
+
+		testUserHandler.FollowHandler(response, newFollowRequest(http.MethodPut, "1"))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Error SQL Follow Handler", func(tt *testing.T) {
+		user := dataUserResponse()[0]
+
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(follower, nil)
+		mockRepository.On("GetById", mock.Anything, user.ID).Return(user, nil)
+		mockRepository.On("FollowUser", mock.Anything, user.ID, follower.UserId).Return(errors.New("error sql"))
+
+		testUserHandler.FollowHandler(response, newFollowRequest(http.MethodPut, user.ID))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+	})
+
+	t.Run("Follow Handler", func(tt *testing.T) {
+		user := dataUserResponse()[0]
+
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(follower, nil)
+		mockRepository.On("GetById", mock.Anything, user.ID).Return(user, nil)
+		mockRepository.On("FollowUser", mock.Anything, user.ID, follower.UserId).Return(nil)
+
+		testUserHandler.FollowHandler(response, newFollowRequest(http.MethodPut, user.ID))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+}
+
+func TestUserRouter_UnfollowHandler(t *testing.T) {
+	follower := &modelAuth.AccessDetails{UserId: uuid.New().String(), TokenUuid: uuid.New().String()}
+
+	t.Run("Error Token Unfollow Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(nil, errors.New("error token"))
+
+		testUserHandler.UnfollowHandler(response, newFollowRequest(http.MethodDelete, "1"))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
+	t.Run("Error SQL Unfollow Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(follower, nil)
+		mockRepository.On("UnfollowUser", mock.Anything, "1", follower.UserId).Return(errors.New("error sql"))
+
+		testUserHandler.UnfollowHandler(response, newFollowRequest(http.MethodDelete, "1"))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+	})
+
+	t.Run("Unfollow Handler", func(tt *testing.T) {
+
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(follower, nil)
+		mockRepository.On("UnfollowUser", mock.Anything, "1", follower.UserId).Return(nil)
+
+		testUserHandler.UnfollowHandler(response, newFollowRequest(http.MethodDelete, "1"))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNoContent, response.Code)
+	})
+}
@@ -0,0 +1,92 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	v1 "food-api/domain/user/application/v1"
+	responseUser "food-api/domain/user/application/v1/response"
+	repoMock "food-api/domain/user/domain/repository/mocks"
+	authMock "food-api/infrastructure/auth/mocks"
+	modelAuth "food-api/infrastructure/auth/model"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newUnlockRequest returns a request to unlock the logins of the user id
+func newUnlockRequest(id, query string) *http.Request {
+	request := httptest.NewRequest(http.MethodDelete, "/api/v1/users/{id}/lock"+query, nil)
+
+	requestCtx := chi.NewRouteContext()
+	requestCtx.URLParams.Add("id", id)
+
+	return request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+}
+
+func TestUserRouter_UnlockHandler(t *testing.T) {
+	admin := &modelAuth.AccessDetails{UserId: uuid.New().String(), TokenUuid: uuid.New().String(), Role: modelAuth.RoleAdmin}
+	target := responseUser.UserResponse{ID: uuid.New().String(), Email: "Daniel.DeLaPava@jikkosoft.com"}
+
+	t.Run("Error Not Admin Unlock Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockAttempts := &repoMock.LoginAttemptRepository{}
+		mockToken := &authMock.TokenInterface{}
+		moderator := &modelAuth.AccessDetails{UserId: uuid.New().String(), Role: modelAuth.RoleModerator}
+
+		testUserHandler := &v1.UserRouter{Attempts: mockAttempts, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(moderator, nil)
+
+		testUserHandler.UnlockHandler(response, newUnlockRequest(target.ID, ""))
+		mockAttempts.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("Error Invalid IP Unlock Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(admin, nil)
+
+		testUserHandler.UnlockHandler(response, newUnlockRequest(target.ID, "?ip=localhost"))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Error Not Found Unlock Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(admin, nil)
+		mockRepository.On("GetById", mock.Anything, target.ID).Return(responseUser.UserResponse{}, sql.ErrNoRows)
+
+		testUserHandler.UnlockHandler(response, newUnlockRequest(target.ID, ""))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Unlock Successfully", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockAttempts := &repoMock.LoginAttemptRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Attempts: mockAttempts, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(admin, nil)
+		mockRepository.On("GetById", mock.Anything, target.ID).Return(target, nil)
+		mockAttempts.On("Reset", mock.Anything, "email:daniel.delapava@jikkosoft.com").Return(nil)
+		mockAttempts.On("Reset", mock.Anything, "ip:192.0.2.7").Return(nil)
+
+		testUserHandler.UnlockHandler(response, newUnlockRequest(target.ID, "?ip=192.0.2.7"))
+		mockRepository.AssertExpectations(tt)
+		mockAttempts.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+}
@@ -0,0 +1,440 @@
+package v1
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	v1 "food-api/domain/user/application/v1"
+	responseUser "food-api/domain/user/application/v1/response"
+	"food-api/domain/user/domain/model"
+	repoMock "food-api/domain/user/domain/repository/mocks"
+	"food-api/domain/user/domain/service"
+	authMock "food-api/infrastructure/auth/mocks"
+	modelAuth "food-api/infrastructure/auth/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// oauthCodeChallenge is the S256 code challenge of RFC 7636 appendix B
+const oauthCodeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+// dataOAuthClient is data for test
+func dataOAuthClient() *model.OAuthClient {
+	return &model.OAuthClient{ID: uuid.New().String(), UserID: uuid.New().String(), Name: "Meal Planner",
+		RedirectURIs: []string{"https://planner.example.com/callback"}, Scope: "openid email foods:read"}
+}
+
+// dataAuthorizationRequest is data for test
+func dataAuthorizationRequest(client *model.OAuthClient) model.AuthorizationRequest {
+	return model.AuthorizationRequest{
+		ResponseType:        "code",
+		ClientID:            client.ID,
+		RedirectURI:         client.RedirectURIs[0],
+		Scope:               "openid foods:read",
+		State:               "state-1",
+		Nonce:               "nonce-1",
+		CodeChallenge:       oauthCodeChallenge,
+		CodeChallengeMethod: "S256",
+	}
+}
+
+// newAuthorizeRequest returns the request of the consent screen with the query of the authorization request
+func newAuthorizeRequest(request model.AuthorizationRequest) *http.Request {
+	query := url.Values{}
+	query.Set("response_type", request.ResponseType)
+	query.Set("client_id", request.ClientID)
+	query.Set("redirect_uri", request.RedirectURI)
+	query.Set("scope", request.Scope)
+	query.Set("state", request.State)
+	query.Set("nonce", request.Nonce)
+	query.Set("code_challenge", request.CodeChallenge)
+	query.Set("code_challenge_method", request.CodeChallengeMethod)
+
+	return httptest.NewRequest(http.MethodGet, "/api/v1/oauth/authorize?"+query.Encode(), nil)
+}
+
+// newConsentRequest returns the decision of the user on the consent screen
+func newConsentRequest(request model.AuthorizationRequest, approve bool) *http.Request {
+	body, _ := json.Marshal(model.AuthorizationDecision{AuthorizationRequest: request, Approve: approve})
+
+	return httptest.NewRequest(http.MethodPost, "/api/v1/oauth/authorize", bytes.NewReader(body))
+}
+
+func TestUserRouter_AuthorizeHandler(t *testing.T) {
+	login := &modelAuth.AccessDetails{UserId: uuid.New().String(), TokenUuid: uuid.New().String(), Scopes: modelAuth.AllScopes}
+	client := dataOAuthClient()
+
+	t.Run("Error Approved With An API Key", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{OAuthClients: mockClients, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: login.UserId, APIKeyID: "key-1"}, nil)
+
+		testUserHandler.AuthorizeHandler(response, newAuthorizeRequest(dataAuthorizationRequest(client)))
+		mockClients.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("Error Unknown Client", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{OAuthClients: mockClients, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(login, nil)
+		mockClients.On("GetClient", mock.Anything, client.ID).Return(nil, sql.ErrNoRows)
+
+		testUserHandler.AuthorizeHandler(response, newAuthorizeRequest(dataAuthorizationRequest(client)))
+		mockClients.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Error Redirect URI Not Registered", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{OAuthClients: mockClients, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(login, nil)
+		mockClients.On("GetClient", mock.Anything, client.ID).Return(client, nil)
+
+		request := dataAuthorizationRequest(client)
+		request.RedirectURI = "https://evil.example.com/callback"
+		testUserHandler.AuthorizeHandler(response, newAuthorizeRequest(request))
+		mockClients.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Error Without PKCE", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{OAuthClients: mockClients, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(login, nil)
+		mockClients.On("GetClient", mock.Anything, client.ID).Return(client, nil)
+
+		request := dataAuthorizationRequest(client)
+		request.CodeChallengeMethod = "plain"
+		testUserHandler.AuthorizeHandler(response, newAuthorizeRequest(request))
+		mockClients.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var redirect model.OAuthRedirect
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&redirect))
+		uri, err := url.Parse(redirect.RedirectTo)
+		assert.NoError(tt, err)
+		assert.Equal(tt, "planner.example.com", uri.Host)
+		assert.Equal(tt, "invalid_request", uri.Query().Get("error"))
+		assert.Equal(tt, "state-1", uri.Query().Get("state"))
+	})
+
+	t.Run("Error Unsupported Response Type", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{OAuthClients: mockClients, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(login, nil)
+		mockClients.On("GetClient", mock.Anything, client.ID).Return(client, nil)
+
+		request := dataAuthorizationRequest(client)
+		request.ResponseType = "token"
+		testUserHandler.AuthorizeHandler(response, newAuthorizeRequest(request))
+		mockClients.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var redirect model.OAuthRedirect
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&redirect))
+		uri, err := url.Parse(redirect.RedirectTo)
+		assert.NoError(tt, err)
+		assert.Equal(tt, "unsupported_response_type", uri.Query().Get("error"))
+		assert.Equal(tt, "state-1", uri.Query().Get("state"))
+	})
+
+	t.Run("Error Scope Not In The Client", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{OAuthClients: mockClients, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(login, nil)
+		mockClients.On("GetClient", mock.Anything, client.ID).Return(client, nil)
+
+		request := dataAuthorizationRequest(client)
+		request.Scope = "openid users:write"
+		testUserHandler.AuthorizeHandler(response, newAuthorizeRequest(request))
+		mockClients.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Authorize Successfully", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{OAuthClients: mockClients, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(login, nil)
+		mockClients.On("GetClient", mock.Anything, client.ID).Return(client, nil)
+
+		testUserHandler.AuthorizeHandler(response, newAuthorizeRequest(dataAuthorizationRequest(client)))
+		mockClients.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var consent model.OAuthConsent
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&consent))
+		assert.Equal(tt, "Meal Planner", consent.ClientName)
+		assert.Equal(tt, []string{"openid", "foods:read"}, consent.Scopes)
+		assert.Equal(tt, "state-1", consent.State)
+	})
+}
+
+func TestUserRouter_ConsentHandler(t *testing.T) {
+	authTime := time.Now().Add(-time.Hour)
+	login := &modelAuth.AccessDetails{UserId: uuid.New().String(), TokenUuid: uuid.New().String(), Scopes: modelAuth.AllScopes,
+		AuthTime: authTime}
+	client := dataOAuthClient()
+
+	t.Run("Deny Consent", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+		mockCodes := &repoMock.AuthorizationCodeRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{OAuthClients: mockClients, AuthorizationCodes: mockCodes, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(login, nil)
+		mockClients.On("GetClient", mock.Anything, client.ID).Return(client, nil)
+
+		testUserHandler.ConsentHandler(response, newConsentRequest(dataAuthorizationRequest(client), false))
+		mockCodes.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var redirect model.OAuthRedirect
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&redirect))
+		uri, err := url.Parse(redirect.RedirectTo)
+		assert.NoError(tt, err)
+		assert.Equal(tt, "access_denied", uri.Query().Get("error"))
+		assert.Equal(tt, "state-1", uri.Query().Get("state"))
+		assert.Empty(tt, uri.Query().Get("code"))
+	})
+
+	t.Run("Approve Consent", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+		mockCodes := &repoMock.AuthorizationCodeRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{OAuthClients: mockClients, AuthorizationCodes: mockCodes, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(login, nil)
+		mockClients.On("GetClient", mock.Anything, client.ID).Return(client, nil)
+		mockCodes.On("SaveCode", mock.Anything, mock.Anything, mock.MatchedBy(func(code *model.AuthorizationCode) bool {
+			return code.UserID == login.UserId && code.ClientID == client.ID && code.Scope == "openid foods:read" &&
+				code.Nonce == "nonce-1" && code.CodeChallenge == oauthCodeChallenge && code.ExpiresAt.After(time.Now()) &&
+				code.AuthTime.Equal(authTime)
+		})).Return(nil)
+
+		testUserHandler.ConsentHandler(response, newConsentRequest(dataAuthorizationRequest(client), true))
+		mockCodes.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var redirect model.OAuthRedirect
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&redirect))
+		uri, err := url.Parse(redirect.RedirectTo)
+		assert.NoError(tt, err)
+		assert.Equal(tt, "planner.example.com", uri.Host)
+		assert.Len(tt, uri.Query().Get("code"), 64)
+		assert.Equal(tt, "state-1", uri.Query().Get("state"))
+	})
+}
+
+func TestUserRouter_UserInfoHandler(t *testing.T) {
+	userId := uuid.New().String()
+	user := responseUser.UserResponse{ID: userId, Names: "Daniel", LastNames: "De La Pava Suarez", Email: "daniel.delapava@jikkosoft.com"}
+
+	t.Run("Error Token Of A Login", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: userId}, nil)
+
+		testUserHandler.UserInfoHandler(response, httptest.NewRequest(http.MethodGet, "/api/v1/oauth/userinfo", nil))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("User Info Successfully", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: userId, ClientID: "client-1",
+			IdentityScopes: []string{modelAuth.ScopeOpenID, modelAuth.ScopeEmail}}, nil)
+		mockRepository.On("GetById", mock.Anything, userId).Return(user, nil)
+
+		testUserHandler.UserInfoHandler(response, httptest.NewRequest(http.MethodGet, "/api/v1/oauth/userinfo", nil))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var claims map[string]interface{}
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&claims))
+		assert.Equal(tt, userId, claims["sub"])
+		assert.Equal(tt, "daniel.delapava@jikkosoft.com", claims["email"])
+		assert.Equal(tt, false, claims["email_verified"])
+		assert.NotContains(tt, claims, "name")
+	})
+
+	t.Run("Verified User Info Successfully", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		verifiedAt := time.Now()
+		verified := user
+		verified.EmailVerifiedAt = &verifiedAt
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: userId, ClientID: "client-1",
+			IdentityScopes: []string{modelAuth.ScopeOpenID, modelAuth.ScopeEmail}}, nil)
+		mockRepository.On("GetById", mock.Anything, userId).Return(verified, nil)
+
+		testUserHandler.UserInfoHandler(response, httptest.NewRequest(http.MethodGet, "/api/v1/oauth/userinfo", nil))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var claims map[string]interface{}
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&claims))
+		assert.Equal(tt, true, claims["email_verified"])
+	})
+}
+
+func TestUserRouter_CreateOAuthClientHandler(t *testing.T) {
+	login := &modelAuth.AccessDetails{UserId: uuid.New().String(), TokenUuid: uuid.New().String(), Scopes: modelAuth.AllScopes}
+
+	t.Run("Error Created By An OAuth Client", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{OAuthClients: mockClients, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: login.UserId, ClientID: "client-1"}, nil)
+
+		body := `{"name":"Planner","redirect_uris":["https://planner.example.com/callback"],"scope":"openid"}`
+		testUserHandler.CreateOAuthClientHandler(response, newAPIKeyRequest(http.MethodPost, "", body))
+		mockClients.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("Error Invalid Redirect URI", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{OAuthClients: mockClients, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(login, nil)
+
+		body := `{"name":"Planner","redirect_uris":["http://planner.example.com/callback"],"scope":"openid"}`
+		testUserHandler.CreateOAuthClientHandler(response, newAPIKeyRequest(http.MethodPost, "", body))
+		mockClients.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Create Confidential Client Successfully", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{OAuthClients: mockClients, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(login, nil)
+		mockClients.On("CreateClient", mock.Anything, mock.MatchedBy(func(client *model.OAuthClient) bool {
+			return client.UserID == login.UserId && client.Confidential && len(client.SecretHash) == 64 &&
+				client.Scope == "openid foods:read"
+		})).Return(nil)
+
+		body := `{"name":"Planner","redirect_uris":["https://planner.example.com/callback"],"scope":"foods:read openid","confidential":true}`
+		testUserHandler.CreateOAuthClientHandler(response, newAPIKeyRequest(http.MethodPost, "", body))
+		mockClients.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusCreated, response.Code)
+
+		var created model.CreatedOAuthClient
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&created))
+		assert.NotEmpty(tt, created.ID)
+		assert.True(tt, service.VerifyClientSecret(&model.OAuthClient{Confidential: true,
+			SecretHash: service.HashClientSecret(created.Secret)}, created.Secret))
+		assert.Len(tt, created.Secret, 64)
+	})
+}
+
+func TestUserRouter_GetOAuthClientHandler(t *testing.T) {
+	client := dataOAuthClient()
+
+	t.Run("Error Client Of Another User", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{OAuthClients: mockClients, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: uuid.New().String()}, nil)
+		mockClients.On("GetClient", mock.Anything, client.ID).Return(client, nil)
+
+		testUserHandler.GetOAuthClientHandler(response, newAPIKeyRequest(http.MethodGet, client.ID, ""))
+		mockClients.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Get Client Successfully", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{OAuthClients: mockClients, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: client.UserID}, nil)
+		mockClients.On("GetClient", mock.Anything, client.ID).Return(client, nil)
+
+		testUserHandler.GetOAuthClientHandler(response, newAPIKeyRequest(http.MethodGet, client.ID, ""))
+		mockClients.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+}
+
+func TestUserRouter_DeleteOAuthClientHandler(t *testing.T) {
+	owner := &modelAuth.AccessDetails{UserId: uuid.New().String()}
+
+	t.Run("Error Not Found Delete Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{OAuthClients: mockClients, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockClients.On("DeleteClient", mock.Anything, owner.UserId, "client-1").Return(sql.ErrNoRows)
+
+		testUserHandler.DeleteOAuthClientHandler(response, newAPIKeyRequest(http.MethodDelete, "client-1", ""))
+		mockClients.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Delete Client Successfully", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockClients := &repoMock.OAuthClientRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{OAuthClients: mockClients, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockClients.On("DeleteClient", mock.Anything, owner.UserId, "client-1").Return(nil)
+
+		testUserHandler.DeleteOAuthClientHandler(response, newAPIKeyRequest(http.MethodDelete, "client-1", ""))
+		mockClients.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+}
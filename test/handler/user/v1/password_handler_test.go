@@ -0,0 +1,193 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	v1 "food-api/domain/user/application/v1"
+	"food-api/domain/user/domain/model"
+	repoMock "food-api/domain/user/domain/repository/mocks"
+	"food-api/domain/user/domain/service"
+	authMock "food-api/infrastructure/auth/mocks"
+	modelAuth "food-api/infrastructure/auth/model"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newChangePasswordRequest returns a change password request for the user id
+func newChangePasswordRequest(tt *testing.T, id string, change model.PasswordChange) *http.Request {
+	marshal, err := json.Marshal(change)
+	assert.NoError(tt, err)
+
+	request := httptest.NewRequest(http.MethodPut, "/api/v1/users/{id}/password", bytes.NewReader(marshal))
+
+	requestCtx := chi.NewRouteContext()
+	requestCtx.URLParams.Add("id", id)
+
+	return request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+}
+
+func TestUserRouter_ChangePasswordHandler(t *testing.T) {
+	owner := &modelAuth.AccessDetails{UserId: uuid.New().String(), TokenUuid: uuid.New().String()}
+	change := model.PasswordChange{CurrentPassword: "123456", Password: "654321"}
+
+	user := dataUserResponse()[0]
+	user.ID = owner.UserId
+
+	t.Run("Error Another User Change Password Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+
+		testUserHandler.ChangePasswordHandler(response, newChangePasswordRequest(tt, uuid.New().String(), change))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("Error API Key Change Password Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: owner.UserId, APIKeyID: "key-1"}, nil)
+
+		testUserHandler.ChangePasswordHandler(response, newChangePasswordRequest(tt, owner.UserId, change))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("Error OAuth Client Change Password Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: owner.UserId, ClientID: "client-1"}, nil)
+
+		testUserHandler.ChangePasswordHandler(response, newChangePasswordRequest(tt, owner.UserId, change))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("Error Locked Change Password Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+		mockAttempts := &repoMock.LoginAttemptRepository{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken, Attempts: mockAttempts}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetById", mock.Anything, owner.UserId).Return(user, nil)
+		mockAttempts.On("LockedFor", mock.Anything, []string{model.AccountSubject(user.Email)}).Return(time.Minute, nil)
+
+		testUserHandler.ChangePasswordHandler(response, newChangePasswordRequest(tt, owner.UserId, change))
+		mockRepository.AssertNotCalled(tt, "GetUserByEmailAndPassword", mock.Anything, mock.Anything)
+		assert.Equal(tt, http.StatusTooManyRequests, response.Code)
+		assert.Equal(tt, "60", response.Header().Get("Retry-After"))
+	})
+
+	t.Run("Error Lockout Change Password Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+		mockAttempts := &repoMock.LoginAttemptRepository{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken, Attempts: mockAttempts}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetById", mock.Anything, owner.UserId).Return(user, nil)
+		mockAttempts.On("LockedFor", mock.Anything, mock.Anything).Return(time.Duration(0), nil)
+		mockRepository.On("GetUserByEmailAndPassword", mock.Anything, mock.Anything).Return(nil, errors.New("password does not match"))
+		mockAttempts.On("Fail", mock.Anything, model.AccountSubject(user.Email), mock.Anything).Return(time.Minute, nil)
+
+		testUserHandler.ChangePasswordHandler(response, newChangePasswordRequest(tt, owner.UserId, change))
+		mockAttempts.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusTooManyRequests, response.Code)
+	})
+
+	t.Run("Validate Change Password Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+
+		testUserHandler.ChangePasswordHandler(response, newChangePasswordRequest(tt, owner.UserId, model.PasswordChange{Password: "123"}))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Error Current Password Change Password Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+		mockAttempts := &repoMock.LoginAttemptRepository{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken, Attempts: mockAttempts}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetById", mock.Anything, owner.UserId).Return(user, nil)
+		mockAttempts.On("LockedFor", mock.Anything, []string{model.AccountSubject(user.Email)}).Return(time.Duration(0), nil)
+		mockRepository.On("GetUserByEmailAndPassword", mock.Anything, &model.User{Email: user.Email, Password: change.CurrentPassword}).
+			Return(nil, errors.New("password does not match"))
+		mockAttempts.On("Fail", mock.Anything, model.AccountSubject(user.Email), service.AccountLockoutPolicy()).Return(time.Duration(0), nil)
+
+		testUserHandler.ChangePasswordHandler(response, newChangePasswordRequest(tt, owner.UserId, change))
+		mockRepository.AssertExpectations(tt)
+		mockAttempts.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("Error Not Found Change Password Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+		mockAttempts := &repoMock.LoginAttemptRepository{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken, Attempts: mockAttempts}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetById", mock.Anything, owner.UserId).Return(user, nil)
+		mockAttempts.On("LockedFor", mock.Anything, []string{model.AccountSubject(user.Email)}).Return(time.Duration(0), nil)
+		mockRepository.On("GetUserByEmailAndPassword", mock.Anything, mock.Anything).Return(&user, nil)
+		mockRepository.On("UpdatePassword", mock.Anything, owner.UserId, mock.Anything, mock.Anything).Return(sql.ErrNoRows)
+
+		testUserHandler.ChangePasswordHandler(response, newChangePasswordRequest(tt, owner.UserId, change))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Change Password Successfully", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockAuth := &authMock.InterfaceAuth{}
+		mockToken := &authMock.TokenInterface{}
+		mockAttempts := &repoMock.LoginAttemptRepository{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Auth: mockAuth, Token: mockToken, Attempts: mockAttempts}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetById", mock.Anything, owner.UserId).Return(user, nil)
+		mockAttempts.On("LockedFor", mock.Anything, []string{model.AccountSubject(user.Email)}).Return(time.Duration(0), nil)
+		mockRepository.On("GetUserByEmailAndPassword", mock.Anything, mock.Anything).Return(&user, nil)
+		mockRepository.On("UpdatePassword", mock.Anything, owner.UserId, mock.MatchedBy(func(hash string) bool {
+			return model.User{PasswordHash: hash}.PasswordMatch(change.Password)
+		}), mock.Anything).Return(nil)
+		mockAuth.On("DeleteUserTokens", mock.Anything, owner.UserId, owner.TokenUuid, fmt.Sprintf("%s++%s", owner.TokenUuid, owner.UserId)).Return(nil)
+
+		testUserHandler.ChangePasswordHandler(response, newChangePasswordRequest(tt, owner.UserId, change))
+		mockRepository.AssertExpectations(tt)
+		mockAuth.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+}
@@ -0,0 +1,104 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	v1 "food-api/domain/user/application/v1"
+	repoMock "food-api/domain/user/domain/repository/mocks"
+	authMock "food-api/infrastructure/auth/mocks"
+	modelAuth "food-api/infrastructure/auth/model"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newRoleRequest returns a request to change the role of the user id
+func newRoleRequest(id, body string) *http.Request {
+	request := httptest.NewRequest(http.MethodPut, "/api/v1/users/{id}/role", strings.NewReader(body))
+
+	requestCtx := chi.NewRouteContext()
+	requestCtx.URLParams.Add("id", id)
+
+	return request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+}
+
+func TestUserRouter_RoleHandler(t *testing.T) {
+	admin := &modelAuth.AccessDetails{UserId: uuid.New().String(), TokenUuid: uuid.New().String(), Role: modelAuth.RoleAdmin}
+	target := uuid.New().String()
+
+	t.Run("Error Not Admin Role Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+		moderator := &modelAuth.AccessDetails{UserId: uuid.New().String(), Role: modelAuth.RoleModerator}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(moderator, nil)
+
+		testUserHandler.RoleHandler(response, newRoleRequest(target, `{"role":"admin"}`))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("Error Invalid Role Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(admin, nil)
+
+		testUserHandler.RoleHandler(response, newRoleRequest(target, `{"role":"root"}`))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Error Own Role Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(admin, nil)
+
+		testUserHandler.RoleHandler(response, newRoleRequest(admin.UserId, `{"role":"user"}`))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusConflict, response.Code)
+	})
+
+	t.Run("Error Not Found Role Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(admin, nil)
+		mockRepository.On("UpdateRole", mock.Anything, target, modelAuth.RoleModerator, mock.Anything).Return(sql.ErrNoRows)
+
+		testUserHandler.RoleHandler(response, newRoleRequest(target, `{"role":"moderator"}`))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Role Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockAuth := &authMock.InterfaceAuth{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Auth: mockAuth, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(admin, nil)
+		mockRepository.On("UpdateRole", mock.Anything, target, modelAuth.RoleModerator, mock.Anything).Return(nil)
+		mockAuth.On("DeleteUserTokens", mock.Anything, target).Return(nil)
+
+		testUserHandler.RoleHandler(response, newRoleRequest(target, `{"role":"moderator"}`))
+		mockRepository.AssertExpectations(tt)
+		mockAuth.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+}
@@ -0,0 +1,117 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	v1 "food-api/domain/user/application/v1"
+	"food-api/infrastructure/auth"
+	authMock "food-api/infrastructure/auth/mocks"
+	modelAuth "food-api/infrastructure/auth/model"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newSessionRequest returns a request for the session id
+func newSessionRequest(method, id string) *http.Request {
+	request := httptest.NewRequest(method, "/api/v1/sessions/{id}", nil)
+
+	requestCtx := chi.NewRouteContext()
+	requestCtx.URLParams.Add("id", id)
+
+	return request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+}
+
+func TestUserRouter_GetSessionsHandler(t *testing.T) {
+	owner := &modelAuth.AccessDetails{UserId: uuid.New().String(), TokenUuid: uuid.New().String(), Family: "family-1"}
+
+	t.Run("Error List Sessions Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockAuth := &authMock.InterfaceAuth{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Auth: mockAuth, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockAuth.On("ListSessions", mock.Anything, owner.UserId).Return(nil, errors.New("error redis"))
+
+		testUserHandler.GetSessionsHandler(response, httptest.NewRequest(http.MethodGet, "/api/v1/sessions", nil))
+		mockAuth.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusInternalServerError, response.Code)
+	})
+
+	t.Run("Get Sessions Handler", func(tt *testing.T) {
+		now := time.Now().UTC().Truncate(time.Second)
+		response := httptest.NewRecorder()
+		mockAuth := &authMock.InterfaceAuth{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Auth: mockAuth, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockAuth.On("ListSessions", mock.Anything, owner.UserId).Return([]modelAuth.Session{
+			{ID: "family-2", CreatedAt: now, LastUsedAt: now, IP: "10.0.0.2"},
+			{ID: "family-1", CreatedAt: now, LastUsedAt: now, IP: "10.0.0.1"},
+		}, nil)
+
+		testUserHandler.GetSessionsHandler(response, httptest.NewRequest(http.MethodGet, "/api/v1/sessions", nil))
+		mockAuth.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var sessions []modelAuth.Session
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&sessions))
+		assert.Len(tt, sessions, 2)
+		assert.False(tt, sessions[0].Current)
+		assert.True(tt, sessions[1].Current)
+	})
+}
+
+func TestUserRouter_DeleteSessionHandler(t *testing.T) {
+	owner := &modelAuth.AccessDetails{UserId: uuid.New().String(), TokenUuid: uuid.New().String(), Family: "family-1"}
+
+	t.Run("Error Not Found Delete Session Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockAuth := &authMock.InterfaceAuth{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Auth: mockAuth, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockAuth.On("DeleteSession", mock.Anything, owner.UserId, "family-2").Return(auth.ErrSessionNotFound)
+
+		testUserHandler.DeleteSessionHandler(response, newSessionRequest(http.MethodDelete, "family-2"))
+		mockAuth.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Delete Session Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockAuth := &authMock.InterfaceAuth{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Auth: mockAuth, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockAuth.On("DeleteSession", mock.Anything, owner.UserId, "family-2").Return(nil)
+
+		testUserHandler.DeleteSessionHandler(response, newSessionRequest(http.MethodDelete, "family-2"))
+		mockAuth.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+
+	t.Run("Delete Sessions Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockAuth := &authMock.InterfaceAuth{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Auth: mockAuth, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockAuth.On("DeleteUserTokens", mock.Anything, owner.UserId).Return(nil)
+
+		testUserHandler.DeleteSessionsHandler(response, httptest.NewRequest(http.MethodDelete, "/api/v1/sessions", nil))
+		mockAuth.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+}
@@ -0,0 +1,308 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	v1 "food-api/domain/user/application/v1"
+	responseUser "food-api/domain/user/application/v1/response"
+	"food-api/domain/user/domain/model"
+	repoMock "food-api/domain/user/domain/repository/mocks"
+	"food-api/domain/user/domain/service"
+	"food-api/domain/user/infrastructure/persistence"
+	authMock "food-api/infrastructure/auth/mocks"
+	modelAuth "food-api/infrastructure/auth/model"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// twoFactorSecret is the TOTP secret for test
+const twoFactorSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// newTwoFactorRequest returns a request to the two-factor authentication of the user id
+func newTwoFactorRequest(method, id, body string) *http.Request {
+	request := httptest.NewRequest(method, "/api/v1/users/{id}/2fa", strings.NewReader(body))
+
+	requestCtx := chi.NewRouteContext()
+	requestCtx.URLParams.Add("id", id)
+
+	return request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+}
+
+// currentCode returns the TOTP code of the secret for test
+func currentCode(tt *testing.T) string {
+	code, err := service.TOTPCode(twoFactorSecret, service.TOTPStep(time.Now()))
+	assert.NoError(tt, err)
+
+	return `{"code":"` + code + `"}`
+}
+
+func TestUserRouter_EnrollTwoFactorHandler(t *testing.T) {
+	owner := &modelAuth.AccessDetails{UserId: uuid.New().String(), TokenUuid: uuid.New().String()}
+	user := responseUser.UserResponse{ID: owner.UserId, Email: "daniel.delapava@jikkosoft.com"}
+
+	t.Run("Error Another User Enroll Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockTwoFactor := &repoMock.TwoFactorRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{TwoFactor: mockTwoFactor, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+
+		testUserHandler.EnrollTwoFactorHandler(response, newTwoFactorRequest(http.MethodPost, uuid.New().String(), ""))
+		mockTwoFactor.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("Error API Key Enroll Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockTwoFactor := &repoMock.TwoFactorRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{TwoFactor: mockTwoFactor, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: owner.UserId, APIKeyID: "key-1"}, nil)
+
+		testUserHandler.EnrollTwoFactorHandler(response, newTwoFactorRequest(http.MethodPost, owner.UserId, ""))
+		mockTwoFactor.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("Error OAuth Client Enroll Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockTwoFactor := &repoMock.TwoFactorRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{TwoFactor: mockTwoFactor, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: owner.UserId, ClientID: "client-1"}, nil)
+
+		testUserHandler.EnrollTwoFactorHandler(response, newTwoFactorRequest(http.MethodPost, owner.UserId, ""))
+		mockTwoFactor.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("Error Already Enabled Enroll Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockTwoFactor := &repoMock.TwoFactorRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, TwoFactor: mockTwoFactor, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetById", mock.Anything, owner.UserId).Return(user, nil)
+		mockTwoFactor.On("SaveSecret", mock.Anything, owner.UserId, mock.Anything, mock.Anything).Return(persistence.ErrTwoFactorEnabled)
+
+		testUserHandler.EnrollTwoFactorHandler(response, newTwoFactorRequest(http.MethodPost, owner.UserId, ""))
+		mockTwoFactor.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusConflict, response.Code)
+	})
+
+	t.Run("Enroll Successfully", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockTwoFactor := &repoMock.TwoFactorRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, TwoFactor: mockTwoFactor, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetById", mock.Anything, owner.UserId).Return(user, nil)
+		mockTwoFactor.On("SaveSecret", mock.Anything, owner.UserId, mock.Anything, mock.Anything).Return(nil)
+
+		testUserHandler.EnrollTwoFactorHandler(response, newTwoFactorRequest(http.MethodPost, owner.UserId, ""))
+		mockTwoFactor.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var enrollment model.TwoFactorEnrollment
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&enrollment))
+		assert.Len(tt, enrollment.Secret, 32)
+		assert.True(tt, strings.HasPrefix(enrollment.URI, "otpauth://totp/food-api:daniel.delapava@jikkosoft.com?"))
+		assert.Contains(tt, enrollment.URI, "secret="+enrollment.Secret)
+	})
+}
+
+func TestUserRouter_ConfirmTwoFactorHandler(t *testing.T) {
+	owner := &modelAuth.AccessDetails{UserId: uuid.New().String(), TokenUuid: uuid.New().String()}
+	user := responseUser.UserResponse{ID: owner.UserId, Email: "daniel.delapava@jikkosoft.com"}
+	pending := &model.TwoFactor{UserID: owner.UserId, Secret: twoFactorSecret}
+
+	t.Run("Error Not Enrolled Confirm Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockTwoFactor := &repoMock.TwoFactorRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, TwoFactor: mockTwoFactor, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetById", mock.Anything, owner.UserId).Return(user, nil)
+		mockTwoFactor.On("GetTwoFactor", mock.Anything, owner.UserId).Return(nil, sql.ErrNoRows)
+
+		testUserHandler.ConfirmTwoFactorHandler(response, newTwoFactorRequest(http.MethodPost, owner.UserId, currentCode(tt)))
+		mockTwoFactor.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Error Invalid Code Confirm Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockTwoFactor := &repoMock.TwoFactorRepository{}
+		mockAttempts := &repoMock.LoginAttemptRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, TwoFactor: mockTwoFactor, Attempts: mockAttempts, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetById", mock.Anything, owner.UserId).Return(user, nil)
+		mockTwoFactor.On("GetTwoFactor", mock.Anything, owner.UserId).Return(pending, nil)
+		mockAttempts.On("LockedFor", mock.Anything, []string{"email:daniel.delapava@jikkosoft.com"}).Return(time.Duration(0), nil)
+		mockAttempts.On("Fail", mock.Anything, "email:daniel.delapava@jikkosoft.com", mock.Anything).Return(time.Duration(0), nil)
+
+		testUserHandler.ConfirmTwoFactorHandler(response, newTwoFactorRequest(http.MethodPost, owner.UserId, `{"code":"aaaaa-bbbbb"}`))
+		mockTwoFactor.AssertExpectations(tt)
+		mockAttempts.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("Error Locked Confirm Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockTwoFactor := &repoMock.TwoFactorRepository{}
+		mockAttempts := &repoMock.LoginAttemptRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, TwoFactor: mockTwoFactor, Attempts: mockAttempts, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetById", mock.Anything, owner.UserId).Return(user, nil)
+		mockTwoFactor.On("GetTwoFactor", mock.Anything, owner.UserId).Return(pending, nil)
+		mockAttempts.On("LockedFor", mock.Anything, mock.Anything).Return(2*time.Minute, nil)
+
+		testUserHandler.ConfirmTwoFactorHandler(response, newTwoFactorRequest(http.MethodPost, owner.UserId, currentCode(tt)))
+		mockTwoFactor.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusTooManyRequests, response.Code)
+		assert.Equal(tt, "120", response.Header().Get("Retry-After"))
+	})
+
+	t.Run("Confirm Successfully", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockTwoFactor := &repoMock.TwoFactorRepository{}
+		mockAttempts := &repoMock.LoginAttemptRepository{}
+		mockAuth := &authMock.InterfaceAuth{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, TwoFactor: mockTwoFactor, Attempts: mockAttempts, Auth: mockAuth, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetById", mock.Anything, owner.UserId).Return(user, nil)
+		mockTwoFactor.On("GetTwoFactor", mock.Anything, owner.UserId).Return(pending, nil)
+		mockAttempts.On("LockedFor", mock.Anything, mock.Anything).Return(time.Duration(0), nil)
+		mockTwoFactor.On("UseStep", mock.Anything, owner.UserId, mock.Anything).Return(true, nil)
+		mockTwoFactor.On("Enable", mock.Anything, owner.UserId, mock.MatchedBy(func(codes []string) bool {
+			return len(codes) == 10 && len(codes[0]) == 10
+		}), mock.Anything).Return(nil)
+		mockAuth.On("DeleteUserTokens", mock.Anything, owner.UserId, owner.TokenUuid, mock.Anything).Return(nil)
+
+		testUserHandler.ConfirmTwoFactorHandler(response, newTwoFactorRequest(http.MethodPost, owner.UserId, currentCode(tt)))
+		mockTwoFactor.AssertExpectations(tt)
+		mockAuth.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		var recoveryCodes model.RecoveryCodes
+		assert.NoError(tt, json.NewDecoder(response.Body).Decode(&recoveryCodes))
+		assert.Len(tt, recoveryCodes.Codes, 10)
+	})
+}
+
+func TestUserRouter_DisableTwoFactorHandler(t *testing.T) {
+	owner := &modelAuth.AccessDetails{UserId: uuid.New().String(), TokenUuid: uuid.New().String()}
+	user := responseUser.UserResponse{ID: owner.UserId, Email: "daniel.delapava@jikkosoft.com"}
+	enabledAt := time.Now()
+	enabled := &model.TwoFactor{UserID: owner.UserId, Secret: twoFactorSecret, EnabledAt: &enabledAt}
+
+	t.Run("Error Not Enabled Disable Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockTwoFactor := &repoMock.TwoFactorRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, TwoFactor: mockTwoFactor, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetById", mock.Anything, owner.UserId).Return(user, nil)
+		mockTwoFactor.On("GetTwoFactor", mock.Anything, owner.UserId).Return(&model.TwoFactor{UserID: owner.UserId, Secret: twoFactorSecret}, nil)
+
+		testUserHandler.DisableTwoFactorHandler(response, newTwoFactorRequest(http.MethodDelete, owner.UserId, currentCode(tt)))
+		mockTwoFactor.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Disable With Recovery Code Successfully", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockTwoFactor := &repoMock.TwoFactorRepository{}
+		mockAttempts := &repoMock.LoginAttemptRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, TwoFactor: mockTwoFactor, Attempts: mockAttempts, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetById", mock.Anything, owner.UserId).Return(user, nil)
+		mockTwoFactor.On("GetTwoFactor", mock.Anything, owner.UserId).Return(enabled, nil)
+		mockAttempts.On("LockedFor", mock.Anything, mock.Anything).Return(time.Duration(0), nil)
+		mockTwoFactor.On("UseRecoveryCode", mock.Anything, owner.UserId, "aaaaabbbbb", mock.Anything).Return(true, nil)
+		mockTwoFactor.On("Disable", mock.Anything, owner.UserId).Return(nil)
+
+		testUserHandler.DisableTwoFactorHandler(response, newTwoFactorRequest(http.MethodDelete, owner.UserId, `{"code":"AAAAA-BBBBB"}`))
+		mockTwoFactor.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+}
+
+func TestUserRouter_RecoveryCodesHandler(t *testing.T) {
+	owner := &modelAuth.AccessDetails{UserId: uuid.New().String(), TokenUuid: uuid.New().String()}
+	user := responseUser.UserResponse{ID: owner.UserId, Email: "daniel.delapava@jikkosoft.com"}
+	enabledAt := time.Now()
+
+	t.Run("Error Replayed Code Recovery Codes Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockTwoFactor := &repoMock.TwoFactorRepository{}
+		mockAttempts := &repoMock.LoginAttemptRepository{}
+		mockToken := &authMock.TokenInterface{}
+		used := &model.TwoFactor{UserID: owner.UserId, Secret: twoFactorSecret, EnabledAt: &enabledAt, LastStep: service.TOTPStep(time.Now()) + 1}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, TwoFactor: mockTwoFactor, Attempts: mockAttempts, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetById", mock.Anything, owner.UserId).Return(user, nil)
+		mockTwoFactor.On("GetTwoFactor", mock.Anything, owner.UserId).Return(used, nil)
+		mockAttempts.On("LockedFor", mock.Anything, mock.Anything).Return(time.Duration(0), nil)
+		mockAttempts.On("Fail", mock.Anything, mock.Anything, mock.Anything).Return(time.Duration(0), nil)
+
+		testUserHandler.RecoveryCodesHandler(response, newTwoFactorRequest(http.MethodPost, owner.UserId, currentCode(tt)))
+		mockTwoFactor.AssertExpectations(tt)
+		mockAttempts.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("Recovery Codes Successfully", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		mockTwoFactor := &repoMock.TwoFactorRepository{}
+		mockAttempts := &repoMock.LoginAttemptRepository{}
+		mockToken := &authMock.TokenInterface{}
+		enabled := &model.TwoFactor{UserID: owner.UserId, Secret: twoFactorSecret, EnabledAt: &enabledAt}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, TwoFactor: mockTwoFactor, Attempts: mockAttempts, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(owner, nil)
+		mockRepository.On("GetById", mock.Anything, owner.UserId).Return(user, nil)
+		mockTwoFactor.On("GetTwoFactor", mock.Anything, owner.UserId).Return(enabled, nil)
+		mockAttempts.On("LockedFor", mock.Anything, mock.Anything).Return(time.Duration(0), nil)
+		mockTwoFactor.On("UseStep", mock.Anything, owner.UserId, mock.Anything).Return(true, nil)
+		mockTwoFactor.On("ReplaceRecoveryCodes", mock.Anything, owner.UserId, mock.Anything, mock.Anything).Return(nil)
+
+		testUserHandler.RecoveryCodesHandler(response, newTwoFactorRequest(http.MethodPost, owner.UserId, currentCode(tt)))
+		mockTwoFactor.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+}
@@ -3,12 +3,15 @@ package v1
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	v1 "food-api/domain/user/application/v1"
 	responseUser "food-api/domain/user/application/v1/response"
 	"food-api/domain/user/domain/model"
 	repoMock "food-api/domain/user/domain/repository/mocks"
+	authMock "food-api/infrastructure/auth/mocks"
+	modelAuth "food-api/infrastructure/auth/model"
 	"github.com/go-chi/chi"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -64,6 +67,24 @@ func dataUserResponse() []responseUser.UserResponse {
 	}
 }
 
+// notifierTest keeps the tokens sent to the users
+type notifierTest struct {
+	verifications map[string]string
+}
+
+func (nt *notifierTest) PasswordReset(ctx context.Context, user responseUser.UserResponse, token string) error {
+	return nil
+}
+
+func (nt *notifierTest) VerifyEmail(ctx context.Context, user responseUser.UserResponse, token string) error {
+	if nt.verifications == nil {
+		nt.verifications = map[string]string{}
+	}
+
+	nt.verifications[user.ID] = token
+	return nil
+}
+
 func TestUserRouter_GetAllUser(t *testing.T) {
 
 	t.Run("Error Get All User Handler", func(tt *testing.T) {
@@ -204,11 +225,19 @@ func TestUserRouter_CreateHandler(t *testing.T) {
 		response := httptest.NewRecorder()
 		mockRepository := &repoMock.UserRepository{}
 
-		testUserHandler := &v1.UserRouter{Repo: mockRepository}
-		mockRepository.On("CreateUser", mock.Anything, mock.Anything).Return(&dataUserResponse()[0], nil)
+		mockVerificationTokens := &repoMock.VerificationTokenRepository{}
+		notifier := &notifierTest{}
+		user := dataUserResponse()[0]
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, VerificationTokens: mockVerificationTokens, Notifier: notifier}
+		mockRepository.On("CreateUser", mock.Anything, mock.Anything).Return(&user, nil)
+		mockVerificationTokens.On("SaveVerificationToken", mock.Anything, user.ID, user.Email, mock.Anything, 24*time.Hour).Return(nil)
 
 		testUserHandler.CreateHandler(response, request)
 		mockRepository.AssertExpectations(tt)
+		mockVerificationTokens.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusCreated, response.Code)
+		assert.Len(tt, notifier.verifications[user.ID], 64)
 	})
 }
 
@@ -237,10 +266,14 @@ func TestUserRouter_UpdateHandler(t *testing.T) {
 		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
 
 		mockRepository := &repoMock.UserRepository{}
-		testUserHandler := &v1.UserRouter{Repo: mockRepository}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: "1"}, nil)
 
 		testUserHandler.UpdateHandler(response, request)
 		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
 	})
 
 	t.Run("Error SQL Update Handler", func(tt *testing.T) {
@@ -257,7 +290,11 @@ func TestUserRouter_UpdateHandler(t *testing.T) {
 		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
 		mockRepository := &repoMock.UserRepository{}
 
-		testUserHandler := &v1.UserRouter{Repo: mockRepository}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: "1"}, nil)
+		mockRepository.On("GetById", mock.Anything, "1").Return(responseUser.UserResponse{ID: "1", Email: dataUser()[0].Email}, nil)
 		mockRepository.On("UpdateUser", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error sql")).Once()
 
 		testUserHandler.UpdateHandler(response, request)
@@ -280,13 +317,66 @@ func TestUserRouter_UpdateHandler(t *testing.T) {
 
 		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
 		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
 
-		testUserHandler := &v1.UserRouter{Repo: mockRepository}
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: "1"}, nil)
+
+		testUserHandler.UpdateHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Error Another User Update Handler", func(tt *testing.T) {
+
+		marshal, err := json.Marshal(dataUser()[0])
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPut, "/api/v1/users/{id}", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("id", "1")
+
+		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: "2", Role: modelAuth.RoleUser}, nil)
+
+		testUserHandler.UpdateHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		mockRepository.AssertNotCalled(tt, "UpdateUser", mock.Anything, mock.Anything, mock.Anything)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("Admin Update Handler", func(tt *testing.T) {
+
+		marshal, err := json.Marshal(dataUser()[0])
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPut, "/api/v1/users/{id}", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("id", "1")
+
+		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: "2", Role: modelAuth.RoleAdmin}, nil)
+		mockRepository.On("GetById", mock.Anything, "1").Return(responseUser.UserResponse{ID: "1", Email: dataUser()[0].Email}, nil)
+		mockRepository.On("UpdateUser", mock.Anything, "1", mock.Anything).Return(nil).Once()
 
 		testUserHandler.UpdateHandler(response, request)
 		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
 	})
 
+
 	t.Run("Update Handler", func(tt *testing.T) {
 
 		marshal, err := json.Marshal(dataUser()[0])
@@ -301,10 +391,116 @@ func TestUserRouter_UpdateHandler(t *testing.T) {
 		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
 		mockRepository := &repoMock.UserRepository{}
 
-		testUserHandler := &v1.UserRouter{Repo: mockRepository}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: "1"}, nil)
+		mockRepository.On("GetById", mock.Anything, "1").Return(responseUser.UserResponse{ID: "1", Email: dataUser()[0].Email}, nil)
 		mockRepository.On("UpdateUser", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
 
 		testUserHandler.UpdateHandler(response, request)
 		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
 	})
-}
\ No newline at end of file
+
+	t.Run("Error Not Found Update Handler", func(tt *testing.T) {
+
+		marshal, err := json.Marshal(dataUser()[0])
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPut, "/api/v1/users/{id}", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("id", "1")
+
+		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: "1"}, nil)
+		mockRepository.On("GetById", mock.Anything, "1").Return(responseUser.UserResponse{}, sql.ErrNoRows)
+
+		testUserHandler.UpdateHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Change Email Update Handler", func(tt *testing.T) {
+
+		marshal, err := json.Marshal(dataUser()[0])
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPut, "/api/v1/users/{id}", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("id", "1")
+
+		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		mockRepository := &repoMock.UserRepository{}
+		mockVerificationTokens := &repoMock.VerificationTokenRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, VerificationTokens: mockVerificationTokens, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: "1"}, nil)
+		mockRepository.On("GetById", mock.Anything, "1").Return(responseUser.UserResponse{ID: "1", Email: "old@jikkosoft.com"}, nil)
+		mockRepository.On("UpdateUser", mock.Anything, "1", mock.Anything).Return(nil).Once()
+		mockVerificationTokens.On("DeleteVerificationToken", mock.Anything, "1").Return(nil).Once()
+
+		testUserHandler.UpdateHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		mockVerificationTokens.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+
+	t.Run("Error API Key Change Email Update Handler", func(tt *testing.T) {
+
+		marshal, err := json.Marshal(dataUser()[0])
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPut, "/api/v1/users/{id}", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("id", "1")
+
+		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: "1", APIKeyID: "key-1"}, nil)
+		mockRepository.On("GetById", mock.Anything, "1").Return(responseUser.UserResponse{ID: "1", Email: "old@jikkosoft.com"}, nil)
+
+		testUserHandler.UpdateHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("OAuth Client Same Email Update Handler", func(tt *testing.T) {
+
+		marshal, err := json.Marshal(dataUser()[0])
+		assert.NoError(tt, err)
+
+		request := httptest.NewRequest(http.MethodPut, "/api/v1/users/{id}", bytes.NewReader(marshal))
+		response := httptest.NewRecorder()
+
+		requestCtx := chi.NewRouteContext()
+		requestCtx.URLParams.Add("id", "1")
+
+		request = request.WithContext(context.WithValue(request.Context(), chi.RouteCtxKey, requestCtx))
+		mockRepository := &repoMock.UserRepository{}
+		mockToken := &authMock.TokenInterface{}
+
+		testUserHandler := &v1.UserRouter{Repo: mockRepository, Token: mockToken}
+		mockToken.On("ExtractTokenMetadata", mock.Anything).Return(&modelAuth.AccessDetails{UserId: "1", ClientID: "client-1"}, nil)
+		mockRepository.On("GetById", mock.Anything, "1").Return(responseUser.UserResponse{ID: "1", Email: dataUser()[0].Email}, nil)
+		mockRepository.On("UpdateUser", mock.Anything, "1", mock.Anything).Return(nil).Once()
+
+		testUserHandler.UpdateHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+	})
+}
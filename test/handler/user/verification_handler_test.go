@@ -0,0 +1,142 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"food-api/domain/user/application"
+	responseUser "food-api/domain/user/application/v1/response"
+	repoMock "food-api/domain/user/domain/repository/mocks"
+	"food-api/domain/user/infrastructure/persistence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLoginRouter_VerifyEmailHandler(t *testing.T) {
+	user := dataUser()
+
+	t.Run("Error Token Required Verify Email Handler", func(tt *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/api/verify-email", nil)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository}
+
+		testLoginHandler.VerifyEmailHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Error Deleted User Verify Email Handler", func(tt *testing.T) {
+		verificationTokens := persistence.NewVerificationTokenRepository(newTestRedis())
+		assert.NoError(tt, verificationTokens.SaveVerificationToken(context.Background(), user.ID, user.Email, "token", time.Hour))
+
+		request := httptest.NewRequest(http.MethodGet, "/api/verify-email?token=token", nil)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, VerificationTokens: verificationTokens}
+		mockRepository.On("VerifyEmail", mock.Anything, user.ID, user.Email, mock.Anything).Return(sql.ErrNoRows)
+
+		testLoginHandler.VerifyEmailHandler(response, request)
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Error Email Changed Verify Email Handler", func(tt *testing.T) {
+		verificationTokens := persistence.NewVerificationTokenRepository(newTestRedis())
+		assert.NoError(tt, verificationTokens.SaveVerificationToken(context.Background(), user.ID, user.Email, "token", time.Hour))
+		assert.NoError(tt, verificationTokens.DeleteVerificationToken(context.Background(), user.ID))
+
+		request := httptest.NewRequest(http.MethodGet, "/api/verify-email?token=token", nil)
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, VerificationTokens: verificationTokens}
+
+		testLoginHandler.VerifyEmailHandler(response, request)
+		mockRepository.AssertNotCalled(tt, "VerifyEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Verify Email Successfully", func(tt *testing.T) {
+		verificationTokens := persistence.NewVerificationTokenRepository(newTestRedis())
+		assert.NoError(tt, verificationTokens.SaveVerificationToken(context.Background(), user.ID, user.Email, "token", time.Hour))
+
+		mockRepository := &repoMock.UserRepository{}
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, VerificationTokens: verificationTokens}
+		mockRepository.On("VerifyEmail", mock.Anything, user.ID, user.Email, mock.Anything).Return(nil)
+
+		response := httptest.NewRecorder()
+		testLoginHandler.VerifyEmailHandler(response, httptest.NewRequest(http.MethodGet, "/api/verify-email?token=token", nil))
+		assert.Equal(tt, http.StatusOK, response.Code)
+
+		// The token can only be used once
+		response = httptest.NewRecorder()
+		testLoginHandler.VerifyEmailHandler(response, httptest.NewRequest(http.MethodGet, "/api/verify-email?token=token", nil))
+		assert.Equal(tt, http.StatusBadRequest, response.Code)
+
+		mockRepository.AssertExpectations(tt)
+		mockRepository.AssertNumberOfCalls(tt, "VerifyEmail", 1)
+	})
+}
+
+func TestLoginRouter_ResendVerificationHandler(t *testing.T) {
+	user := dataUser()
+	userResponse := responseUser.UserResponse{ID: user.ID, Names: user.Names, LastNames: user.LastNames, Email: user.Email}
+
+	t.Run("Validate Resend Verification Handler", func(tt *testing.T) {
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository}
+
+		testLoginHandler.ResendVerificationHandler(response, newPasswordRequest(tt, "/api/verify-email/resend", map[string]string{"email": ""}))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Verified User Resend Verification Handler", func(tt *testing.T) {
+		verifiedAt := time.Now()
+		verified := userResponse
+		verified.EmailVerifiedAt = &verifiedAt
+
+		response := httptest.NewRecorder()
+		mockRepository := &repoMock.UserRepository{}
+		notifier := newNotifierTest()
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Notifier: notifier,
+			VerificationTokens: persistence.NewVerificationTokenRepository(newTestRedis())}
+		mockRepository.On("GetByEmail", mock.Anything, user.Email).Return(verified, nil)
+
+		testLoginHandler.ResendVerificationHandler(response, newPasswordRequest(tt, "/api/verify-email/resend", map[string]string{"email": user.Email}))
+		mockRepository.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		assert.Empty(tt, notifier.verifications)
+	})
+
+	t.Run("Resend Verification Throttled", func(tt *testing.T) {
+		mockRepository := &repoMock.UserRepository{}
+		notifier := newNotifierTest()
+
+		testLoginHandler := &application.LoginRouter{Repo: mockRepository, Notifier: notifier,
+			VerificationTokens: persistence.NewVerificationTokenRepository(newTestRedis())}
+		mockRepository.On("GetByEmail", mock.Anything, user.Email).Return(userResponse, nil)
+
+		response := httptest.NewRecorder()
+		testLoginHandler.ResendVerificationHandler(response, newPasswordRequest(tt, "/api/verify-email/resend", map[string]string{"email": user.Email}))
+		assert.Equal(tt, http.StatusOK, response.Code)
+		assert.Len(tt, notifier.verifications[user.ID], 64)
+
+		response = httptest.NewRecorder()
+		testLoginHandler.ResendVerificationHandler(response, newPasswordRequest(tt, "/api/verify-email/resend", map[string]string{"email": user.Email}))
+		assert.Equal(tt, http.StatusTooManyRequests, response.Code)
+		assert.Equal(tt, "60", response.Header().Get("Retry-After"))
+
+		mockRepository.AssertExpectations(tt)
+		mockRepository.AssertNumberOfCalls(tt, "GetByEmail", 1)
+	})
+}
@@ -0,0 +1,238 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"food-api/infrastructure/mail"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net"
+	netMail "net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const from = "Food API <no-reply@food-api.local>"
+
+// dataMessage is data for test
+func dataMessage() mail.Message {
+	return mail.Message{
+		To:      []string{"Daniel <daniel.delapava@jikkosoft.com>"},
+		Subject: "Reset your password ✓",
+		Text:    "Open the link",
+		HTML:    "<p>Open the link</p>",
+	}
+}
+
+// readMessage parses an email and returns its subject and its parts by content type
+func readMessage(tt *testing.T, data []byte) (string, map[string]string) {
+	message, err := netMail.ReadMessage(strings.NewReader(string(data)))
+	assert.NoError(tt, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	assert.NoError(tt, err)
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	assert.NoError(tt, err)
+	assert.Equal(tt, "multipart/alternative", mediaType)
+
+	parts := map[string]string{}
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+
+		assert.NoError(tt, err)
+		content, err := ioutil.ReadAll(part)
+		assert.NoError(tt, err)
+
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[contentType] = string(content)
+	}
+
+	return subject, parts
+}
+
+func TestTemplate_Render(t *testing.T) {
+
+	t.Run("Error Template", func(tt *testing.T) {
+		template, err := mail.NewTemplate("test", "{{.Subject", "text", "")
+		assert.Error(tt, err)
+		assert.Nil(tt, template)
+	})
+
+	t.Run("Render Successfully", func(tt *testing.T) {
+		template := mail.MustTemplate("test", "Hi\n{{.Names}}", "Hi {{.Names}}", "<p>Hi {{.Names}}</p>")
+
+		message, err := template.Render(map[string]string{"Names": "<b>Daniel</b>"}, "daniel.delapava@jikkosoft.com")
+		assert.NoError(tt, err)
+		assert.Equal(tt, []string{"daniel.delapava@jikkosoft.com"}, message.To)
+		assert.Equal(tt, "Hi <b>Daniel</b>", message.Subject)
+		assert.Equal(tt, "Hi <b>Daniel</b>", message.Text)
+		assert.Equal(tt, "<p>Hi &lt;b&gt;Daniel&lt;/b&gt;</p>", message.HTML)
+	})
+}
+
+func TestMessage_Bytes(t *testing.T) {
+	now := time.Now()
+
+	t.Run("Error Header Injection", func(tt *testing.T) {
+		message := dataMessage()
+		message.From = from
+		message.Subject = "Hi\r\nBcc: someone@example.com"
+
+		data, err := message.Bytes(now)
+		assert.Equal(tt, mail.ErrInvalidHeader, err)
+		assert.Nil(tt, data)
+	})
+
+	t.Run("Error No Recipients", func(tt *testing.T) {
+		message := dataMessage()
+		message.From = from
+		message.To = nil
+
+		data, err := message.Bytes(now)
+		assert.Equal(tt, mail.ErrNoRecipients, err)
+		assert.Nil(tt, data)
+	})
+
+	t.Run("Bytes Successfully", func(tt *testing.T) {
+		message := dataMessage()
+		message.From = from
+
+		data, err := message.Bytes(now)
+		assert.NoError(tt, err)
+
+		subject, parts := readMessage(tt, data)
+		assert.Equal(tt, message.Subject, subject)
+		assert.Equal(tt, map[string]string{"text/plain": message.Text, "text/html": message.HTML}, parts)
+	})
+}
+
+func TestMemoryMailer_Send(t *testing.T) {
+	mailer := mail.NewMemoryMailer(from)
+
+	message := dataMessage()
+	message.To = []string{"not an email"}
+	assert.Error(t, mailer.Send(context.Background(), message))
+
+	assert.NoError(t, mailer.Send(context.Background(), dataMessage()))
+
+	messages := mailer.Messages()
+	assert.Len(t, messages, 1)
+	assert.Equal(t, from, messages[0].From)
+}
+
+func TestFileMailer_Send(t *testing.T) {
+	path, err := ioutil.TempDir("", "mail")
+	assert.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	mailer := mail.NewFileMailer(filepath.Join(path, "outbox"), from)
+	assert.NoError(t, mailer.Send(context.Background(), dataMessage()))
+
+	files, err := filepath.Glob(filepath.Join(path, "outbox", "*.eml"))
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	data, err := ioutil.ReadFile(files[0])
+	assert.NoError(t, err)
+
+	subject, parts := readMessage(t, data)
+	assert.Equal(t, dataMessage().Subject, subject)
+	assert.Equal(t, dataMessage().Text, parts["text/plain"])
+}
+
+// smtpServer is an SMTP server that accepts one message, it returns the address and the
+// commands and the data received
+func smtpServer(tt *testing.T) (string, <-chan []string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(tt, err)
+
+	received := make(chan []string, 1)
+	go func() {
+		defer listener.Close()
+
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		var lines []string
+		reader := bufio.NewReader(conn)
+		_, _ = fmt.Fprint(conn, "220 localhost ESMTP\r\n")
+
+		data := false
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				break
+			}
+
+			line = strings.TrimRight(line, "\r\n")
+			lines = append(lines, line)
+
+			switch {
+			case data && line == ".":
+				data = false
+				_, _ = fmt.Fprint(conn, "250 OK\r\n")
+			case data:
+			case strings.HasPrefix(line, "EHLO"):
+				_, _ = fmt.Fprint(conn, "250-localhost\r\n250 8BITMIME\r\n")
+			case line == "DATA":
+				data = true
+				_, _ = fmt.Fprint(conn, "354 Go ahead\r\n")
+			case line == "QUIT":
+				_, _ = fmt.Fprint(conn, "221 Bye\r\n")
+				received <- lines
+				return
+			default:
+				_, _ = fmt.Fprint(conn, "250 OK\r\n")
+			}
+		}
+
+		received <- lines
+	}()
+
+	return listener.Addr().String(), received
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+
+	t.Run("Error Connection", func(tt *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(tt, err)
+		host, port, _ := net.SplitHostPort(listener.Addr().String())
+		_ = listener.Close()
+
+		mailer := mail.NewSMTPMailer(host, port, "", "", from)
+		assert.Error(tt, mailer.Send(context.Background(), dataMessage()))
+	})
+
+	t.Run("Send Successfully", func(tt *testing.T) {
+		address, received := smtpServer(tt)
+		host, port, _ := net.SplitHostPort(address)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		mailer := mail.NewSMTPMailer(host, port, "", "", from)
+		assert.NoError(tt, mailer.Send(ctx, dataMessage()))
+
+		lines := <-received
+		assert.Contains(tt, lines, "MAIL FROM:<no-reply@food-api.local> BODY=8BITMIME")
+		assert.Contains(tt, lines, "RCPT TO:<daniel.delapava@jikkosoft.com>")
+		assert.Contains(tt, lines, "To: Daniel <daniel.delapava@jikkosoft.com>")
+	})
+}
@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"context"
+	"food-api/infrastructure/auth"
+	authMock "food-api/infrastructure/auth/mocks"
+	"food-api/infrastructure/auth/model"
+	"food-api/infrastructure/middleware"
+	"github.com/alicebob/miniredis"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestAuth initialize a redis server in memory for the tokens
+func newTestAuth() (*miniredis.Miniredis, auth.InterfaceAuth) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+
+	return mr, auth.NewAuth(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+}
+
+// okHandler responses the user id of the access details in the context
+func okHandler(tt *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		details, ok := auth.FromContext(r.Context())
+		assert.True(tt, ok)
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(details.UserId))
+	})
+}
+
+func newAuthRequest(accessToken string) *http.Request {
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/foods", nil)
+	request.Header.Set("Authorization", "Bearer "+accessToken)
+
+	return request
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	token := auth.NewToken()
+	ctx := context.Background()
+
+	t.Run("Error Without Token", func(tt *testing.T) {
+		mr, store := newTestAuth()
+		defer mr.Close()
+
+		response := httptest.NewRecorder()
+		middleware.AuthMiddleware(token, store, nil)(okHandler(tt)).
+			ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/api/v1/foods", nil))
+
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
+	t.Run("Error Revoked Token", func(tt *testing.T) {
+		mr, store := newTestAuth()
+		defer mr.Close()
+
+		details, err := token.CreateToken("user-1", model.TokenOptions{Role: model.RoleUser, Scopes: model.AllScopes})
+		assert.NoError(tt, err)
+		assert.NoError(tt, store.CreateAuth(ctx, "user-1", details))
+		assert.NoError(tt, store.DeleteTokens(ctx, &model.AccessDetails{TokenUuid: details.TokenUuid, UserId: "user-1"}))
+
+		response := httptest.NewRecorder()
+		middleware.AuthMiddleware(token, store, nil)(okHandler(tt)).ServeHTTP(response, newAuthRequest(details.AccessToken))
+
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
+	t.Run("Error Token Of Another User", func(tt *testing.T) {
+		mr, store := newTestAuth()
+		defer mr.Close()
+
+		details, err := token.CreateToken("user-1", model.TokenOptions{Role: model.RoleUser, Scopes: model.AllScopes})
+		assert.NoError(tt, err)
+		assert.NoError(tt, store.CreateAuth(ctx, "user-2", details))
+
+		response := httptest.NewRecorder()
+		middleware.AuthMiddleware(token, store, nil)(okHandler(tt)).ServeHTTP(response, newAuthRequest(details.AccessToken))
+
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
+	t.Run("Auth Middleware", func(tt *testing.T) {
+		mr, store := newTestAuth()
+		defer mr.Close()
+
+		details, err := token.CreateToken("user-1", model.TokenOptions{Role: model.RoleUser, Scopes: model.AllScopes})
+		assert.NoError(tt, err)
+		assert.NoError(tt, store.CreateAuth(ctx, "user-1", details))
+
+		response := httptest.NewRecorder()
+		middleware.AuthMiddleware(token, store, nil)(okHandler(tt)).ServeHTTP(response, newAuthRequest(details.AccessToken))
+
+		assert.Equal(tt, http.StatusOK, response.Code)
+		assert.Equal(tt, "user-1", response.Body.String())
+	})
+
+	t.Run("Require Role And Scope", func(tt *testing.T) {
+		mr, store := newTestAuth()
+		defer mr.Close()
+
+		details, err := token.CreateToken("user-1", model.TokenOptions{Role: model.RoleModerator, Scopes: []string{model.ScopeFoodsRead}})
+		assert.NoError(tt, err)
+		assert.NoError(tt, store.CreateAuth(ctx, "user-1", details))
+
+		cases := []struct {
+			name       string
+			middleware func(http.Handler) http.Handler
+			code       int
+		}{
+			{"moderator", middleware.RequireRole(model.RoleModerator), http.StatusOK},
+			{"admin", middleware.RequireRole(model.RoleAdmin), http.StatusForbidden},
+			{"foods:read", middleware.RequireScope(model.ScopeFoodsRead), http.StatusOK},
+			{"foods:write", middleware.RequireScope(model.ScopeFoodsWrite), http.StatusForbidden},
+		}
+
+		for _, c := range cases {
+			response := httptest.NewRecorder()
+			middleware.AuthMiddleware(token, store, nil)(c.middleware(okHandler(tt))).
+				ServeHTTP(response, newAuthRequest(details.AccessToken))
+
+			assert.Equal(tt, c.code, response.Code, c.name)
+		}
+	})
+}
+
+func TestAuthMiddleware_APIKey(t *testing.T) {
+	token := auth.NewToken()
+	key := "fapi_0a1b2c3d_secret"
+	details := &model.AccessDetails{UserId: "user-1", Role: model.RoleUser, Scopes: []string{model.ScopeFoodsRead}, APIKeyID: "key-1"}
+
+	t.Run("Error Without Verifier", func(tt *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods", nil)
+		request.Header.Set(auth.APIKeyHeader, key)
+
+		response := httptest.NewRecorder()
+		middleware.AuthMiddleware(token, nil, nil)(okHandler(tt)).ServeHTTP(response, request)
+
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
+	t.Run("Error Invalid API Key", func(tt *testing.T) {
+		mockAPIKeys := &authMock.APIKeyVerifier{}
+		mockAPIKeys.On("VerifyAPIKey", mock.Anything, key).Return(nil, auth.ErrAPIKeyInvalid)
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods", nil)
+		request.Header.Set(auth.APIKeyHeader, key)
+
+		response := httptest.NewRecorder()
+		middleware.AuthMiddleware(token, nil, mockAPIKeys)(okHandler(tt)).ServeHTTP(response, request)
+
+		mockAPIKeys.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusUnauthorized, response.Code)
+	})
+
+	t.Run("API Key Header", func(tt *testing.T) {
+		mockAPIKeys := &authMock.APIKeyVerifier{}
+		mockAPIKeys.On("VerifyAPIKey", mock.Anything, key).Return(details, nil)
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods", nil)
+		request.Header.Set(auth.APIKeyHeader, key)
+
+		response := httptest.NewRecorder()
+		middleware.AuthMiddleware(token, nil, mockAPIKeys)(middleware.RequireScope(model.ScopeFoodsRead)(okHandler(tt))).
+			ServeHTTP(response, request)
+
+		mockAPIKeys.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusOK, response.Code)
+		assert.Equal(tt, "user-1", response.Body.String())
+	})
+
+	t.Run("API Key Authorization Scheme", func(tt *testing.T) {
+		mockAPIKeys := &authMock.APIKeyVerifier{}
+		mockAPIKeys.On("VerifyAPIKey", mock.Anything, key).Return(details, nil)
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/foods", nil)
+		request.Header.Set("Authorization", "ApiKey "+key)
+
+		response := httptest.NewRecorder()
+		middleware.AuthMiddleware(token, nil, mockAPIKeys)(middleware.RequireScope(model.ScopeFoodsWrite)(okHandler(tt))).
+			ServeHTTP(response, request)
+
+		mockAPIKeys.AssertExpectations(tt)
+		assert.Equal(tt, http.StatusForbidden, response.Code)
+	})
+}
@@ -0,0 +1,62 @@
+package user
+
+import (
+	"context"
+	"food-api/domain/user/application/v1/response"
+	"food-api/domain/user/infrastructure/notification"
+	"food-api/infrastructure/mail"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+// dataUserResponse is data for test
+func dataUserResponse() response.UserResponse {
+	return response.UserResponse{
+		ID:        uuid.New().String(),
+		Names:     "Daniel <b>",
+		LastNames: "De La Pava Suarez",
+		Email:     "daniel.delapava@jikkosoft.com",
+	}
+}
+
+func Test_mailNotifier_PasswordReset(t *testing.T) {
+	_ = os.Setenv("PASSWORD_RESET_URL", "https://food.example.com/reset")
+	_ = os.Setenv("PASSWORD_RESET_TTL", "30m")
+	defer func() {
+		_ = os.Unsetenv("PASSWORD_RESET_URL")
+		_ = os.Unsetenv("PASSWORD_RESET_TTL")
+	}()
+
+	mailer := mail.NewMemoryMailer("no-reply@food-api.local")
+	notifier := notification.NewMailNotifier(mailer)
+
+	user := dataUserResponse()
+	assert.NoError(t, notifier.PasswordReset(context.Background(), user, "token"))
+
+	messages := mailer.Messages()
+	assert.Len(t, messages, 1)
+	assert.Equal(t, []string{user.Email}, messages[0].To)
+	assert.Contains(t, messages[0].Text, "https://food.example.com/reset?token=token")
+	assert.Contains(t, messages[0].Text, "expires in 30 minutes")
+	assert.Contains(t, messages[0].HTML, `href="https://food.example.com/reset?token=token"`)
+	assert.Contains(t, messages[0].HTML, "Daniel &lt;b&gt;")
+}
+
+func Test_mailNotifier_VerifyEmail(t *testing.T) {
+	mailer := mail.NewMemoryMailer("no-reply@food-api.local")
+	notifier := notification.NewMailNotifier(mailer)
+
+	user := dataUserResponse()
+	assert.NoError(t, notifier.VerifyEmail(context.Background(), user, "token"))
+
+	messages := mailer.Messages()
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "Verify your Food API email", messages[0].Subject)
+	assert.Contains(t, messages[0].Text, "http://localhost:8888/api/verify-email?token=token")
+	assert.Contains(t, messages[0].Text, "expires in 24 hours")
+
+	user.Email = "not an email"
+	assert.Error(t, notifier.VerifyEmail(context.Background(), user, "token"))
+}
@@ -3,6 +3,8 @@ package food
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	responseFood "food-api/domain/food/application/v1/response"
 	"food-api/domain/food/domain/model"
 	"food-api/domain/food/domain/repository"
@@ -10,6 +12,7 @@ import (
 	"food-api/infrastructure/database"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"log"
 	"testing"
@@ -55,22 +58,34 @@ func dataFood() []model.Food {
 
 	return []model.Food{
 		{
-			ID:          uuid.New().String(),
-			UserID:      userId,
-			Title:       "Title",
-			Description: "Description",
-			FoodImage:   "/profile-photos/food_api/309-3092053_gopher-link-transparent-cartoons-gopher-link.png",
-			CreatedAt:   now,
-			UpdatedAt:   now,
+			ID:           uuid.New().String(),
+			UserID:       userId,
+			Title:        "Title",
+			Description:  "Description",
+			FoodImage:    "/profile-photos/food_api/309-3092053_gopher-link-transparent-cartoons-gopher-link.png",
+			Ingredients:  []string{"Flour", "Water"},
+			Instructions: []string{"Mix", "Bake"},
+			Tags:         []string{"bread"},
+			Visibility:   "private",
+			Language:     "es",
+			Slug:         "title",
+			CreatedAt:    now,
+			UpdatedAt:    now,
 		},
 		{
-			ID:          uuid.New().String(),
-			UserID:      userId,
-			Title:       "Title",
-			Description: "Description",
-			FoodImage:   "/profile-photos/food_api/309-3092053_gopher-link-transparent-cartoons-gopher-link.png",
-			CreatedAt:   now,
-			UpdatedAt:   now,
+			ID:           uuid.New().String(),
+			UserID:       userId,
+			Title:        "Title",
+			Description:  "Description",
+			FoodImage:    "/profile-photos/food_api/309-3092053_gopher-link-transparent-cartoons-gopher-link.png",
+			Ingredients:  []string{"Flour", "Water"},
+			Instructions: []string{"Mix", "Bake"},
+			Tags:         []string{"bread"},
+			Visibility:   "private",
+			Language:     "es",
+			Slug:         "title",
+			CreatedAt:    now,
+			UpdatedAt:    now,
 		},
 	}
 }
@@ -81,22 +96,36 @@ func dataFoodResponse() []responseFood.FoodResponse {
 
 	return []responseFood.FoodResponse{
 		{
-			ID:          uuid.New().String(),
-			UserID:      userId,
-			Title:       "Title",
-			Description: "Description",
-			FoodImage:   "/profile-photos/food_api/309-3092053_gopher-link-transparent-cartoons-gopher-link.png",
+			ID:           uuid.New().String(),
+			UserID:       userId,
+			Title:        "Title",
+			Slug:         "title",
+			Description:  "Description",
+			FoodImage:    "/profile-photos/food_api/309-3092053_gopher-link-transparent-cartoons-gopher-link.png",
+			Ingredients:  []string{"Flour", "Water"},
+			Instructions: []string{"Mix", "Bake"},
+			Visibility:   "private",
 		},
 		{
-			ID:          uuid.New().String(),
-			UserID:      userId,
-			Title:       "Title",
-			Description: "Description",
-			FoodImage:   "/profile-photos/food_api/309-3092053_gopher-link-transparent-cartoons-gopher-link.png",
+			ID:           uuid.New().String(),
+			UserID:       userId,
+			Title:        "Title",
+			Slug:         "title-2",
+			Description:  "Description",
+			FoodImage:    "/profile-photos/food_api/309-3092053_gopher-link-transparent-cartoons-gopher-link.png",
+			Ingredients:  []string{"Flour", "Water"},
+			Instructions: []string{"Mix", "Bake"},
+			Visibility:   "public",
 		},
 	}
 }
 
+// textArrayValue returns the value of a text[] column as it is read from the database
+func textArrayValue(values []string) driver.Value {
+	value, _ := pq.Array(values).Value()
+	return value
+}
+
 func Test_sqlFoodRepo_GetAllFood(t *testing.T) {
 
 	t.Run("Error SQL", func(tt *testing.T) {
@@ -109,7 +138,7 @@ func Test_sqlFoodRepo_GetAllFood(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		users, err := foodRepositoryMock.GetAllFood(ctx)
+		users, err := foodRepositoryMock.GetAllFood(ctx, "")
 		assert.Error(tt, err)
 		assert.Nil(tt, users)
 	})
@@ -121,16 +150,16 @@ func Test_sqlFoodRepo_GetAllFood(t *testing.T) {
 		}()
 
 		foodsData := dataFoodResponse()
-		rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "food_image"}).
-			AddRow(foodsData[0].ID, foodsData[0].UserID, foodsData[0].Title, foodsData[0].Description, foodsData[0].FoodImage).
-			AddRow(foodsData[1].ID, foodsData[0].UserID, foodsData[1].Title, foodsData[1].Description, foodsData[1].FoodImage)
+		rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "food_image", "ingredients", "instructions", "tags", "slug", "visibility", "language"}).
+			AddRow(foodsData[0].ID, foodsData[0].UserID, foodsData[0].Title, foodsData[0].Description, foodsData[0].FoodImage, textArrayValue(foodsData[0].Ingredients), textArrayValue(foodsData[0].Instructions), textArrayValue(foodsData[0].Tags), foodsData[0].Slug, foodsData[0].Visibility, foodsData[0].Language).
+			AddRow(foodsData[1].ID, foodsData[0].UserID, foodsData[1].Title, foodsData[1].Description, foodsData[1].FoodImage, textArrayValue(foodsData[1].Ingredients), textArrayValue(foodsData[1].Instructions), textArrayValue(foodsData[1].Tags), foodsData[1].Slug, foodsData[1].Visibility, foodsData[1].Language)
 
-		mock.ExpectQuery(selectAllFoodTest).WillReturnRows(rows)
+		mock.ExpectQuery(selectAllFoodTest).WithArgs(foodsData[0].UserID).WillReturnRows(rows)
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		foods, err := foodRepositoryMock.GetAllFood(ctx)
+		foods, err := foodRepositoryMock.GetAllFood(ctx, foodsData[0].UserID)
 		assert.NotEmpty(tt, foods)
 		assert.NoError(tt, err)
 		assert.Len(tt, foods, 2)
@@ -147,15 +176,15 @@ func Test_sqlFoodRepo_GetFoodById(t *testing.T) {
 			CloseMockFood()
 		}()
 
-		row := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "food_image"}).
-			AddRow(foodTest.ID, foodTest.UserID, foodTest.Title, foodTest.Description, foodTest.FoodImage)
+		row := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "food_image", "ingredients", "instructions", "tags", "slug", "visibility", "language"}).
+			AddRow(foodTest.ID, foodTest.UserID, foodTest.Title, foodTest.Description, foodTest.FoodImage, textArrayValue(foodTest.Ingredients), textArrayValue(foodTest.Instructions), textArrayValue(foodTest.Tags), foodTest.Slug, foodTest.Visibility, foodTest.Language)
 
-		mock.ExpectQuery(selectFoodByIdTest).WithArgs(nil).WillReturnRows(row)
+		mock.ExpectQuery(selectFoodByIdTest).WithArgs(nil, nil).WillReturnRows(row)
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		foodResult, err := foodRepositoryMock.GetFoodById(ctx, foodTest.ID)
+		foodResult, err := foodRepositoryMock.GetFoodById(ctx, foodTest.ID, "")
 		assert.Error(tt, err)
 		assert.NotNil(tt, foodResult)
 	})
@@ -165,21 +194,138 @@ func Test_sqlFoodRepo_GetFoodById(t *testing.T) {
 		defer func() {
 			CloseMockFood()
 		}()
-		row := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "food_image"}).
-			AddRow(foodTest.ID, foodTest.UserID, foodTest.Title, foodTest.Description, foodTest.FoodImage)
+		row := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "food_image", "ingredients", "instructions", "tags", "slug", "visibility", "language"}).
+			AddRow(foodTest.ID, foodTest.UserID, foodTest.Title, foodTest.Description, foodTest.FoodImage, textArrayValue(foodTest.Ingredients), textArrayValue(foodTest.Instructions), textArrayValue(foodTest.Tags), foodTest.Slug, foodTest.Visibility, foodTest.Language)
 
-		mock.ExpectQuery(selectFoodByIdTest).WithArgs(foodTest.ID).WillReturnRows(row)
+		mock.ExpectQuery(selectFoodByIdTest).WithArgs(foodTest.ID, foodTest.UserID).WillReturnRows(row)
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		foodResult, err := foodRepositoryMock.GetFoodById(ctx, foodTest.ID)
+		foodResult, err := foodRepositoryMock.GetFoodById(ctx, foodTest.ID, foodTest.UserID)
 		assert.NoError(tt, err)
 		assert.NotNil(tt, foodResult)
 	})
 
 }
 
+func Test_sqlFoodRepo_GetFoodsByIds(t *testing.T) {
+	foodsTest := dataFoodResponse()
+	ids := []string{foodsTest[0].ID, foodsTest[1].ID}
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock := NewMockFood()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		mock.ExpectQuery(selectFoodsByIdsTest).WithArgs(pq.Array(ids), nil).WillReturnError(errors.New("error sql"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		foodResult, err := foodRepositoryMock.GetFoodsByIds(ctx, ids, "")
+		assert.Error(tt, err)
+		assert.Nil(tt, foodResult)
+	})
+
+	t.Run("Get Foods By Ids Successful", func(tt *testing.T) {
+		mock := NewMockFood()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "food_image", "ingredients", "instructions", "tags", "slug", "visibility", "language"})
+		for _, foodTest := range foodsTest[:2] {
+			rows.AddRow(foodTest.ID, foodTest.UserID, foodTest.Title, foodTest.Description, foodTest.FoodImage, textArrayValue(foodTest.Ingredients), textArrayValue(foodTest.Instructions), textArrayValue(foodTest.Tags), foodTest.Slug, foodTest.Visibility, foodTest.Language)
+		}
+
+		mock.ExpectQuery(selectFoodsByIdsTest).WithArgs(pq.Array(ids), foodsTest[0].UserID).WillReturnRows(rows)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		foodResult, err := foodRepositoryMock.GetFoodsByIds(ctx, ids, foodsTest[0].UserID)
+		assert.NoError(tt, err)
+		assert.Len(tt, foodResult, 2)
+		assert.Equal(tt, foodsTest[1].ID, foodResult[1].ID)
+	})
+}
+
+func Test_sqlFoodRepo_GetFoodBySlug(t *testing.T) {
+	foodTest := dataFoodResponse()[0]
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock := NewMockFood()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		mock.ExpectQuery(selectFoodBySlugTest).WithArgs(foodTest.Slug, nil).WillReturnError(sql.ErrNoRows)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		foodResult, err := foodRepositoryMock.GetFoodBySlug(ctx, foodTest.Slug, "")
+		assert.Error(tt, err)
+		assert.NotNil(tt, foodResult)
+	})
+
+	t.Run("Get Food By Slug Successful", func(tt *testing.T) {
+		mock := NewMockFood()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		row := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "food_image", "ingredients", "instructions", "tags", "slug", "visibility", "language"}).
+			AddRow(foodTest.ID, foodTest.UserID, foodTest.Title, foodTest.Description, foodTest.FoodImage, textArrayValue(foodTest.Ingredients), textArrayValue(foodTest.Instructions), textArrayValue(foodTest.Tags), foodTest.Slug, foodTest.Visibility, foodTest.Language)
+
+		mock.ExpectQuery(selectFoodBySlugTest).WithArgs(foodTest.Slug, foodTest.UserID).WillReturnRows(row)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		foodResult, err := foodRepositoryMock.GetFoodBySlug(ctx, foodTest.Slug, foodTest.UserID)
+		assert.NoError(tt, err)
+		assert.Equal(tt, &foodTest, foodResult)
+	})
+}
+
+func Test_sqlFoodRepo_GetSlugByHistory(t *testing.T) {
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock := NewMockFood()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		mock.ExpectQuery(selectSlugByHistoryTest).WithArgs("old-title", nil).WillReturnError(sql.ErrNoRows)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		slug, err := foodRepositoryMock.GetSlugByHistory(ctx, "old-title", "")
+		assert.Error(tt, err)
+		assert.Empty(tt, slug)
+	})
+
+	t.Run("Get Slug By History Successful", func(tt *testing.T) {
+		mock := NewMockFood()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		mock.ExpectQuery(selectSlugByHistoryTest).WithArgs("old-title", "user").WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("new-title"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		slug, err := foodRepositoryMock.GetSlugByHistory(ctx, "old-title", "user")
+		assert.NoError(tt, err)
+		assert.Equal(tt, "new-title", slug)
+	})
+}
+
 func Test_sqlFoodRepo_GetFoodByUserId(t *testing.T) {
 	foodTest := dataFoodResponse()[0]
 
@@ -189,15 +335,15 @@ func Test_sqlFoodRepo_GetFoodByUserId(t *testing.T) {
 			CloseMockFood()
 		}()
 
-		row := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "food_image"}).
-			AddRow(foodTest.ID, foodTest.UserID, foodTest.Title, foodTest.Description, foodTest.FoodImage)
+		row := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "food_image", "ingredients", "instructions", "tags", "slug", "visibility", "language"}).
+			AddRow(foodTest.ID, foodTest.UserID, foodTest.Title, foodTest.Description, foodTest.FoodImage, textArrayValue(foodTest.Ingredients), textArrayValue(foodTest.Instructions), textArrayValue(foodTest.Tags), foodTest.Slug, foodTest.Visibility, foodTest.Language)
 
-		mock.ExpectQuery(selectFoodByUserIdTest).WithArgs(nil).WillReturnRows(row)
+		mock.ExpectQuery(selectFoodByUserIdTest).WithArgs(nil, nil).WillReturnRows(row)
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		foodResult, err := foodRepositoryMock.GetFoodByUserId(ctx, foodTest.ID)
+		foodResult, err := foodRepositoryMock.GetFoodByUserId(ctx, foodTest.ID, "")
 		assert.Error(tt, err)
 		assert.NotNil(tt, foodResult)
 	})
@@ -208,37 +354,165 @@ func Test_sqlFoodRepo_GetFoodByUserId(t *testing.T) {
 			CloseMockFood()
 		}()
 
-		row := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "food_image"}).
-			AddRow(foodTest.ID, foodTest.UserID, foodTest.Title, foodTest.Description, foodTest.FoodImage)
+		row := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "food_image", "ingredients", "instructions", "tags", "slug", "visibility", "language"}).
+			AddRow(foodTest.ID, foodTest.UserID, foodTest.Title, foodTest.Description, foodTest.FoodImage, textArrayValue(foodTest.Ingredients), textArrayValue(foodTest.Instructions), textArrayValue(foodTest.Tags), foodTest.Slug, foodTest.Visibility, foodTest.Language)
 
-		mock.ExpectQuery(selectFoodByUserIdTest).WithArgs(foodTest.UserID).WillReturnRows(row)
+		mock.ExpectQuery(selectFoodByUserIdTest).WithArgs(foodTest.UserID, nil).WillReturnRows(row)
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		foodResult, err := foodRepositoryMock.GetFoodByUserId(ctx, foodTest.UserID)
+		foodResult, err := foodRepositoryMock.GetFoodByUserId(ctx, foodTest.UserID, "")
 		assert.NoError(tt, err)
 		assert.NotNil(tt, foodResult)
 	})
 }
 
+func Test_sqlFoodRepo_GetAllFoodByUserId(t *testing.T) {
+	foodTest := dataFoodResponse()
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock := NewMockFood()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		mock.ExpectQuery(selectAllFoodByUserIdTest).WithArgs(foodTest[0].UserID).WillReturnError(errors.New("error sql"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		foods, err := foodRepositoryMock.GetAllFoodByUserId(ctx, foodTest[0].UserID)
+		assert.Error(tt, err)
+		assert.Nil(tt, foods)
+	})
+
+	t.Run("Get All Food By User Successful", func(tt *testing.T) {
+		mock := NewMockFood()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "food_image", "ingredients", "instructions", "tags", "slug", "visibility", "language"}).
+			AddRow(foodTest[0].ID, foodTest[0].UserID, foodTest[0].Title, foodTest[0].Description, foodTest[0].FoodImage, textArrayValue(foodTest[0].Ingredients), textArrayValue(foodTest[0].Instructions), textArrayValue(foodTest[0].Tags), foodTest[0].Slug, foodTest[0].Visibility, foodTest[0].Language).
+			AddRow(foodTest[1].ID, foodTest[1].UserID, foodTest[1].Title, foodTest[1].Description, foodTest[1].FoodImage, textArrayValue(foodTest[1].Ingredients), textArrayValue(foodTest[1].Instructions), textArrayValue(foodTest[1].Tags), foodTest[1].Slug, foodTest[1].Visibility, foodTest[1].Language)
+
+		mock.ExpectQuery(selectAllFoodByUserIdTest).WithArgs(foodTest[0].UserID).WillReturnRows(rows)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		foods, err := foodRepositoryMock.GetAllFoodByUserId(ctx, foodTest[0].UserID)
+		assert.NoError(tt, err)
+		assert.Equal(tt, foodTest, foods)
+	})
+}
+
+func Test_sqlFoodRepo_StreamFoodByUserId(t *testing.T) {
+	foodTest := dataFoodResponse()
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock := NewMockFood()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		mock.ExpectQuery(selectAllFoodByUserIdTest).WithArgs(foodTest[0].UserID).WillReturnError(errors.New("error sql"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := foodRepositoryMock.StreamFoodByUserId(ctx, foodTest[0].UserID, func(food responseFood.FoodResponse) error {
+			return nil
+		})
+		assert.Error(tt, err)
+	})
+
+	t.Run("Error Callback", func(tt *testing.T) {
+		mock := NewMockFood()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "food_image", "ingredients", "instructions", "tags", "slug", "visibility", "language"}).
+			AddRow(foodTest[0].ID, foodTest[0].UserID, foodTest[0].Title, foodTest[0].Description, foodTest[0].FoodImage, textArrayValue(foodTest[0].Ingredients), textArrayValue(foodTest[0].Instructions), textArrayValue(foodTest[0].Tags), foodTest[0].Slug, foodTest[0].Visibility, foodTest[0].Language).
+			AddRow(foodTest[1].ID, foodTest[1].UserID, foodTest[1].Title, foodTest[1].Description, foodTest[1].FoodImage, textArrayValue(foodTest[1].Ingredients), textArrayValue(foodTest[1].Instructions), textArrayValue(foodTest[1].Tags), foodTest[1].Slug, foodTest[1].Visibility, foodTest[1].Language)
+
+		mock.ExpectQuery(selectAllFoodByUserIdTest).WithArgs(foodTest[0].UserID).WillReturnRows(rows)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		calls := 0
+		err := foodRepositoryMock.StreamFoodByUserId(ctx, foodTest[0].UserID, func(food responseFood.FoodResponse) error {
+			calls++
+			return errors.New("error write")
+		})
+		assert.Error(tt, err)
+		assert.Equal(tt, 1, calls)
+	})
+
+	t.Run("Stream Food By User Successful", func(tt *testing.T) {
+		mock := NewMockFood()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "food_image", "ingredients", "instructions", "tags", "slug", "visibility", "language"}).
+			AddRow(foodTest[0].ID, foodTest[0].UserID, foodTest[0].Title, foodTest[0].Description, foodTest[0].FoodImage, textArrayValue(foodTest[0].Ingredients), textArrayValue(foodTest[0].Instructions), textArrayValue(foodTest[0].Tags), foodTest[0].Slug, foodTest[0].Visibility, foodTest[0].Language).
+			AddRow(foodTest[1].ID, foodTest[1].UserID, foodTest[1].Title, foodTest[1].Description, foodTest[1].FoodImage, textArrayValue(foodTest[1].Ingredients), textArrayValue(foodTest[1].Instructions), textArrayValue(foodTest[1].Tags), foodTest[1].Slug, foodTest[1].Visibility, foodTest[1].Language)
+
+		mock.ExpectQuery(selectAllFoodByUserIdTest).WithArgs(foodTest[0].UserID).WillReturnRows(rows)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var foods []responseFood.FoodResponse
+		err := foodRepositoryMock.StreamFoodByUserId(ctx, foodTest[0].UserID, func(food responseFood.FoodResponse) error {
+			foods = append(foods, food)
+			return nil
+		})
+		assert.NoError(tt, err)
+		assert.Len(tt, foods, 2)
+	})
+}
+
 func Test_sqlFoodRepo_SaveFood(t *testing.T) {
 
+	t.Run("Error Slug SQL", func(tt *testing.T) {
+		mock := NewMockFood()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		dataTest := dataFood()[0]
+		mock.ExpectQuery(selectFoodSlugsTest).WithArgs("title", "title-%", dataTest.ID).WillReturnError(errors.New("error sql"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		foodResult, err := foodRepositoryMock.SaveFood(ctx, &dataTest)
+		assert.Error(tt, err)
+		assert.NotNil(tt, foodResult)
+	})
+
 	t.Run("Error SQL", func(tt *testing.T) {
 		mock := NewMockFood()
 		defer func() {
 			CloseMockFood()
 		}()
 
+		dataTest := dataFood()[0]
+		mock.ExpectQuery(selectFoodSlugsTest).WithArgs("title", "title-%", dataTest.ID).WillReturnRows(sqlmock.NewRows([]string{"slug"}))
 		prep := mock.ExpectPrepare("insertFoodTest")
 		prep.ExpectExec().
-			WithArgs(dataFood()[0].ID, dataFood()[0].UserID, dataFood()[0].Title, dataFood()[0].Description, dataFood()[0].FoodImage, dataFood()[0].CreatedAt, dataFood()[0].UpdatedAt).
+			WithArgs(dataTest.ID, dataTest.UserID, dataTest.Title, dataTest.Description, dataTest.FoodImage, pq.Array(dataTest.Ingredients), pq.Array(dataTest.Instructions), pq.Array(dataTest.Tags), dataTest.Slug, dataTest.Visibility, dataTest.Language, dataTest.CreatedAt, dataTest.UpdatedAt).
 			WillReturnResult(sqlmock.NewResult(0, 0))
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		foodResult, err := foodRepositoryMock.SaveFood(ctx, &dataFood()[0])
+		foodResult, err := foodRepositoryMock.SaveFood(ctx, &dataTest)
 		assert.Error(tt, err)
 		assert.NotNil(tt, foodResult)
 	})
@@ -249,15 +523,17 @@ func Test_sqlFoodRepo_SaveFood(t *testing.T) {
 			CloseMockFood()
 		}()
 
+		dataTest := dataFood()[0]
+		mock.ExpectQuery(selectFoodSlugsTest).WithArgs("title", "title-%", dataTest.ID).WillReturnRows(sqlmock.NewRows([]string{"slug"}))
 		prep := mock.ExpectPrepare(insertFoodTest)
 		prep.ExpectQuery().
-			WithArgs(dataFood()[0].ID, dataFood()[0].UserID, dataFood()[0].Title, dataFood()[0].Description, dataFood()[0].FoodImage, dataFood()[0].CreatedAt, dataFood()[0].UpdatedAt).
+			WithArgs(dataTest.ID, dataTest.UserID, dataTest.Title, dataTest.Description, dataTest.FoodImage, pq.Array(dataTest.Ingredients), pq.Array(dataTest.Instructions), pq.Array(dataTest.Tags), dataTest.Slug, dataTest.Visibility, dataTest.Language, dataTest.CreatedAt, dataTest.UpdatedAt).
 			WillReturnRows(sqlmock.NewRows([]string{"first_name"}).AddRow("Error"))
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		foodResult, err := foodRepositoryMock.SaveFood(ctx, &dataFood()[0])
+		foodResult, err := foodRepositoryMock.SaveFood(ctx, &dataTest)
 		assert.Error(tt, err)
 		assert.NotNil(tt, foodResult)
 	})
@@ -269,33 +545,32 @@ func Test_sqlFoodRepo_SaveFood(t *testing.T) {
 		}()
 
 		dataTest := dataFood()[0]
+		mock.ExpectQuery(selectFoodSlugsTest).WithArgs("title", "title-%", dataTest.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("title").AddRow("title-2"))
 		prep := mock.ExpectPrepare(insertFoodTest)
 		prep.ExpectQuery().
-			WithArgs(dataTest.ID, dataTest.UserID, dataTest.Title, dataTest.Description, dataTest.FoodImage, dataTest.CreatedAt, dataTest.UpdatedAt).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "description", "food_image"}).
-				AddRow(dataTest.ID, dataTest.UserID, dataTest.Title, dataTest.Description, dataTest.FoodImage))
+			WithArgs(dataTest.ID, dataTest.UserID, dataTest.Title, dataTest.Description, dataTest.FoodImage, pq.Array(dataTest.Ingredients), pq.Array(dataTest.Instructions), pq.Array(dataTest.Tags), "title-3", dataTest.Visibility, dataTest.Language, dataTest.CreatedAt, dataTest.UpdatedAt).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "description", "food_image", "ingredients", "instructions", "tags", "slug", "visibility", "language"}).
+				AddRow(dataTest.ID, dataTest.UserID, dataTest.Title, dataTest.Description, dataTest.FoodImage, textArrayValue(dataTest.Ingredients), textArrayValue(dataTest.Instructions), textArrayValue(dataTest.Tags), "title-3", dataTest.Visibility, dataTest.Language))
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
 		foodResult, err := foodRepositoryMock.SaveFood(ctx, &dataTest)
 		assert.NoError(tt, err)
-		assert.NotNil(tt, foodResult)
+		assert.Equal(tt, "title-3", foodResult.Slug)
 	})
 }
 
 func Test_sqlFoodRepo_UpdateFood(t *testing.T) {
 
-	t.Run("Error SQL", func(tt *testing.T) {
+	t.Run("Error Begin Transaction", func(tt *testing.T) {
 		mock := NewMockFood()
 		defer func() {
 			CloseMockFood()
 		}()
 
-		prep := mock.ExpectPrepare("updateFoodTest")
-		prep.ExpectExec().
-			WithArgs(dataFood()[0].Title, dataFood()[0].Description, dataFood()[0].FoodImage, dataFood()[0].UpdatedAt, dataFood()[0].ID).
-			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectBegin().WillReturnError(errors.New("error begin"))
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
@@ -304,22 +579,67 @@ func Test_sqlFoodRepo_UpdateFood(t *testing.T) {
 		assert.Error(tt, err)
 	})
 
-	t.Run("Error Statement SQL", func(tt *testing.T) {
+	t.Run("Error Not Found", func(tt *testing.T) {
 		mock := NewMockFood()
 		defer func() {
 			CloseMockFood()
 		}()
 
-		prep := mock.ExpectPrepare(updateFoodTest)
-		prep.ExpectExec().
-			WithArgs(dataFood()[0].Title, dataFood()[0].Description, dataFood()[0].FoodImage, dataFood()[0].UpdatedAt, nil).
-			WillReturnResult(sqlmock.NewResult(1, 1))
+		dataTest := dataFood()[0]
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectFoodSlugForUpdateTest).WithArgs(dataTest.ID).WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		err := foodRepositoryMock.UpdateFood(ctx, dataFood()[0].ID, &dataFood()[0])
+		err := foodRepositoryMock.UpdateFood(ctx, dataTest.ID, &dataTest)
+		assert.Error(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock := NewMockFood()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		dataTest := dataFood()[0]
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectFoodSlugForUpdateTest).WithArgs(dataTest.ID).WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("title"))
+		mock.ExpectQuery(updateFoodTest).
+			WithArgs(dataTest.Title, dataTest.Description, dataTest.FoodImage, pq.Array(dataTest.Ingredients), pq.Array(dataTest.Instructions), pq.Array(dataTest.Tags), "title", dataTest.Visibility, dataTest.Language, dataTest.UpdatedAt, dataTest.ID, dataTest.UserID).
+			WillReturnError(errors.New("error sql"))
+		mock.ExpectRollback()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := foodRepositoryMock.UpdateFood(ctx, dataTest.ID, &dataTest)
 		assert.Error(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error Another Owner", func(tt *testing.T) {
+		mock := NewMockFood()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		dataTest := dataFood()[0]
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectFoodSlugForUpdateTest).WithArgs(dataTest.ID).WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("title"))
+		mock.ExpectQuery(updateFoodTest).
+			WithArgs(dataTest.Title, dataTest.Description, dataTest.FoodImage, pq.Array(dataTest.Ingredients), pq.Array(dataTest.Instructions), pq.Array(dataTest.Tags), "title", dataTest.Visibility, dataTest.Language, dataTest.UpdatedAt, dataTest.ID, dataTest.UserID).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := foodRepositoryMock.UpdateFood(ctx, dataTest.ID, &dataTest)
+		assert.Equal(tt, sql.ErrNoRows, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
 	})
 
 	t.Run("Update Food Successful", func(tt *testing.T) {
@@ -329,16 +649,49 @@ func Test_sqlFoodRepo_UpdateFood(t *testing.T) {
 		}()
 
 		dataTest := dataFood()[0]
-		prep := mock.ExpectPrepare(updateFoodTest)
-		prep.ExpectExec().
-			WithArgs(dataTest.Title, dataTest.Description, dataTest.FoodImage, dataTest.UpdatedAt, dataTest.ID).
-			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectFoodSlugForUpdateTest).WithArgs(dataTest.ID).WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("title-2"))
+		mock.ExpectQuery(updateFoodTest).
+			WithArgs(dataTest.Title, dataTest.Description, dataTest.FoodImage, pq.Array(dataTest.Ingredients), pq.Array(dataTest.Instructions), pq.Array(dataTest.Tags), "title-2", dataTest.Visibility, dataTest.Language, dataTest.UpdatedAt, dataTest.ID, dataTest.UserID).
+			WillReturnRows(sqlmock.NewRows([]string{"visibility", "language"}).AddRow("private", "es"))
+		mock.ExpectCommit()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := foodRepositoryMock.UpdateFood(ctx, dataTest.ID, &dataTest)
+		assert.NoError(tt, err)
+		assert.Equal(tt, "es", dataTest.Language)
+		assert.Equal(tt, "title-2", dataTest.Slug)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Rename Food Successful", func(tt *testing.T) {
+		mock := NewMockFood()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		dataTest := dataFood()[0]
+		dataTest.Title = "Spicy Chicken Curry"
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectFoodSlugForUpdateTest).WithArgs(dataTest.ID).WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("title"))
+		mock.ExpectQuery(selectFoodSlugsTest).WithArgs("spicy-chicken-curry", "spicy-chicken-curry-%", dataTest.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("spicy-chicken-curry"))
+		mock.ExpectExec(insertFoodSlugHistoryTest).WithArgs("title", dataTest.ID, dataTest.UpdatedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(deleteFoodSlugHistoryTest).WithArgs("spicy-chicken-curry-2").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(updateFoodTest).
+			WithArgs(dataTest.Title, dataTest.Description, dataTest.FoodImage, pq.Array(dataTest.Ingredients), pq.Array(dataTest.Instructions), pq.Array(dataTest.Tags), "spicy-chicken-curry-2", dataTest.Visibility, dataTest.Language, dataTest.UpdatedAt, dataTest.ID, dataTest.UserID).
+			WillReturnRows(sqlmock.NewRows([]string{"visibility", "language"}).AddRow("private", "es"))
+		mock.ExpectCommit()
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
 		err := foodRepositoryMock.UpdateFood(ctx, dataTest.ID, &dataTest)
 		assert.NoError(tt, err)
+		assert.Equal(tt, "spicy-chicken-curry-2", dataTest.Slug)
+		assert.NoError(tt, mock.ExpectationsWereMet())
 	})
 
 }
@@ -402,3 +755,83 @@ func Test_sqlFoodRepo_DeleteFood(t *testing.T) {
 	})
 
 }
+
+func Test_sqlFoodRepo_SaveFoodBatch(t *testing.T) {
+
+	t.Run("Error Begin Transaction", func(tt *testing.T) {
+		mock := NewMockFood()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		mock.ExpectBegin().WillReturnError(errors.New("error begin"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		rowErrors, err := foodRepositoryMock.SaveFoodBatch(ctx, dataFood(), false)
+		assert.Error(tt, err)
+		assert.Len(tt, rowErrors, 2)
+	})
+
+	t.Run("Error SQL Atomic Rollback", func(tt *testing.T) {
+		mock := NewMockFood()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		dataTest := dataFood()
+		mock.ExpectBegin()
+		prep := mock.ExpectPrepare(insertFoodTest)
+		mock.ExpectQuery(selectFoodSlugsTest).WithArgs("title", "title-%", dataTest[0].ID).WillReturnRows(sqlmock.NewRows([]string{"slug"}))
+		prep.ExpectExec().
+			WithArgs(dataTest[0].ID, dataTest[0].UserID, dataTest[0].Title, dataTest[0].Description, dataTest[0].FoodImage, pq.Array(dataTest[0].Ingredients), pq.Array(dataTest[0].Instructions), pq.Array(dataTest[0].Tags), "title", dataTest[0].Visibility, dataTest[0].Language, dataTest[0].CreatedAt, dataTest[0].UpdatedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(selectFoodSlugsTest).WithArgs("title", "title-%", dataTest[1].ID).WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("title"))
+		prep.ExpectExec().
+			WithArgs(dataTest[1].ID, dataTest[1].UserID, dataTest[1].Title, dataTest[1].Description, dataTest[1].FoodImage, pq.Array(dataTest[1].Ingredients), pq.Array(dataTest[1].Instructions), pq.Array(dataTest[1].Tags), "title-2", dataTest[1].Visibility, dataTest[1].Language, dataTest[1].CreatedAt, dataTest[1].UpdatedAt).
+			WillReturnError(errors.New("error sql"))
+		mock.ExpectRollback()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		rowErrors, err := foodRepositoryMock.SaveFoodBatch(ctx, dataTest, false)
+		assert.Error(tt, err)
+		assert.NoError(tt, rowErrors[0])
+		assert.Error(tt, rowErrors[1])
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Save Food Batch Partial Successful", func(tt *testing.T) {
+		mock := NewMockFood()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		dataTest := dataFood()
+		mock.ExpectBegin()
+		prep := mock.ExpectPrepare(insertFoodTest)
+		mock.ExpectExec(savepointFoodImportTest).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(selectFoodSlugsTest).WithArgs("title", "title-%", dataTest[0].ID).WillReturnRows(sqlmock.NewRows([]string{"slug"}))
+		prep.ExpectExec().
+			WithArgs(dataTest[0].ID, dataTest[0].UserID, dataTest[0].Title, dataTest[0].Description, dataTest[0].FoodImage, pq.Array(dataTest[0].Ingredients), pq.Array(dataTest[0].Instructions), pq.Array(dataTest[0].Tags), "title", dataTest[0].Visibility, dataTest[0].Language, dataTest[0].CreatedAt, dataTest[0].UpdatedAt).
+			WillReturnError(errors.New("error sql"))
+		mock.ExpectExec(rollbackFoodImportTest).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(savepointFoodImportTest).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(selectFoodSlugsTest).WithArgs("title", "title-%", dataTest[1].ID).WillReturnRows(sqlmock.NewRows([]string{"slug"}))
+		prep.ExpectExec().
+			WithArgs(dataTest[1].ID, dataTest[1].UserID, dataTest[1].Title, dataTest[1].Description, dataTest[1].FoodImage, pq.Array(dataTest[1].Ingredients), pq.Array(dataTest[1].Instructions), pq.Array(dataTest[1].Tags), "title", dataTest[1].Visibility, dataTest[1].Language, dataTest[1].CreatedAt, dataTest[1].UpdatedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		rowErrors, err := foodRepositoryMock.SaveFoodBatch(ctx, dataTest, true)
+		assert.NoError(tt, err)
+		assert.Error(tt, rowErrors[0])
+		assert.NoError(tt, rowErrors[1])
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,205 @@
+package food
+
+import (
+	"context"
+	"errors"
+	"food-api/domain/food/domain/model"
+	"food-api/domain/food/domain/repository"
+	"food-api/domain/food/domain/service"
+	"food-api/domain/food/infrastructure/persistence"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// NewMockPreference initialize mock connection to database for the favorites and ratings
+func NewMockPreference() (sqlmock.Sqlmock, repository.PreferenceRepository) {
+	mock := NewMockFood()
+	return mock, persistence.NewPreferenceRepository(&connMockFood)
+}
+
+// dataPreference is data for test
+func dataPreference() model.FoodPreference {
+	now := time.Now()
+
+	return model.FoodPreference{
+		UserID:    uuid.New().String(),
+		FoodID:    uuid.New().String(),
+		Favorite:  true,
+		Rating:    4,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func Test_sqlPreferenceRepo_SaveFavorite(t *testing.T) {
+	dataTest := dataPreference()
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock, preferenceRepository := NewMockPreference()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		mock.ExpectPrepare(insertFoodFavoriteTest).
+			ExpectExec().
+			WithArgs(dataTest.UserID, dataTest.FoodID, dataTest.Favorite, dataTest.CreatedAt, dataTest.UpdatedAt).
+			WillReturnError(errors.New("error sql"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := preferenceRepository.SaveFavorite(ctx, &dataTest)
+		assert.Error(tt, err)
+	})
+
+	t.Run("Save Favorite Successful", func(tt *testing.T) {
+		mock, preferenceRepository := NewMockPreference()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		mock.ExpectPrepare(insertFoodFavoriteTest).
+			ExpectExec().
+			WithArgs(dataTest.UserID, dataTest.FoodID, dataTest.Favorite, dataTest.CreatedAt, dataTest.UpdatedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := preferenceRepository.SaveFavorite(ctx, &dataTest)
+		assert.NoError(tt, err)
+	})
+}
+
+func Test_sqlPreferenceRepo_SaveRating(t *testing.T) {
+	dataTest := dataPreference()
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock, preferenceRepository := NewMockPreference()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		mock.ExpectPrepare(insertFoodRatingTest).
+			ExpectExec().
+			WithArgs(dataTest.UserID, dataTest.FoodID, dataTest.Rating, dataTest.CreatedAt, dataTest.UpdatedAt).
+			WillReturnError(errors.New("error sql"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := preferenceRepository.SaveRating(ctx, &dataTest)
+		assert.Error(tt, err)
+	})
+
+	t.Run("Save Rating Successful", func(tt *testing.T) {
+		mock, preferenceRepository := NewMockPreference()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		mock.ExpectPrepare(insertFoodRatingTest).
+			ExpectExec().
+			WithArgs(dataTest.UserID, dataTest.FoodID, dataTest.Rating, dataTest.CreatedAt, dataTest.UpdatedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := preferenceRepository.SaveRating(ctx, &dataTest)
+		assert.NoError(tt, err)
+	})
+}
+
+func Test_sqlPreferenceRepo_GetPreferencesByUserId(t *testing.T) {
+	dataTest := dataPreference()
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock, preferenceRepository := NewMockPreference()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		mock.ExpectQuery(selectFoodPreferencesTest).WithArgs(dataTest.UserID).WillReturnError(errors.New("error sql"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		preferences, err := preferenceRepository.GetPreferencesByUserId(ctx, dataTest.UserID)
+		assert.Error(tt, err)
+		assert.Nil(tt, preferences)
+	})
+
+	t.Run("Get Preferences Successful", func(tt *testing.T) {
+		mock, preferenceRepository := NewMockPreference()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		rows := sqlmock.NewRows([]string{"food_id", "favorite", "rating"}).
+			AddRow(dataTest.FoodID, true, 0).
+			AddRow(uuid.New().String(), false, 2)
+
+		mock.ExpectQuery(selectFoodPreferencesTest).WithArgs(dataTest.UserID).WillReturnRows(rows)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		preferences, err := preferenceRepository.GetPreferencesByUserId(ctx, dataTest.UserID)
+		assert.NoError(tt, err)
+		assert.Len(tt, preferences, 2)
+		assert.True(tt, preferences[0].Favorite)
+		assert.Equal(tt, 2, preferences[1].Rating)
+		assert.Equal(tt, dataTest.UserID, preferences[1].UserID)
+	})
+}
+
+func Test_sqlPreferenceRepo_GetPopularFood(t *testing.T) {
+	since := time.Now().Add(-24 * time.Hour)
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock, preferenceRepository := NewMockPreference()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		mock.ExpectQuery(selectPopularFoodTest).
+			WithArgs(since, service.FavoriteWeight, service.RatingWeight, 10).
+			WillReturnError(errors.New("error sql"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		scores, err := preferenceRepository.GetPopularFood(ctx, since, 10)
+		assert.Error(tt, err)
+		assert.Nil(tt, scores)
+	})
+
+	t.Run("Get Popular Food Successful", func(tt *testing.T) {
+		mock, preferenceRepository := NewMockPreference()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		popularId := uuid.New().String()
+		rows := sqlmock.NewRows([]string{"food_id", "score"}).
+			AddRow(popularId, 5.0).
+			AddRow(uuid.New().String(), 2.0)
+
+		mock.ExpectQuery(selectPopularFoodTest).
+			WithArgs(since, service.FavoriteWeight, service.RatingWeight, 10).
+			WillReturnRows(rows)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		scores, err := preferenceRepository.GetPopularFood(ctx, since, 10)
+		assert.NoError(tt, err)
+		assert.Len(tt, scores, 2)
+		assert.Equal(tt, popularId, scores[0].FoodID)
+		assert.Equal(tt, 5.0, scores[0].Score)
+	})
+}
@@ -0,0 +1,160 @@
+package food
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"food-api/domain/food/domain/model"
+	"food-api/domain/food/domain/repository"
+	"food-api/domain/food/infrastructure/persistence"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// NewMockTranslation initialize mock connection to database for the translations
+func NewMockTranslation() (sqlmock.Sqlmock, repository.TranslationRepository) {
+	mock := NewMockFood()
+	return mock, persistence.NewTranslationRepository(&connMockFood)
+}
+
+// dataTranslation is data for test
+func dataTranslation() model.FoodTranslation {
+	now := time.Now()
+
+	return model.FoodTranslation{
+		FoodID:       uuid.New().String(),
+		Language:     "es",
+		Title:        "Título",
+		Description:  "Descripción",
+		Instructions: []string{"Mezclar", "Hornear"},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+func Test_sqlTranslationRepo_SaveTranslation(t *testing.T) {
+	dataTest := dataTranslation()
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock, translationRepository := NewMockTranslation()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		mock.ExpectPrepare(insertFoodTranslationTest).
+			ExpectQuery().
+			WithArgs(dataTest.FoodID, dataTest.Language, dataTest.Title, dataTest.Description, pq.Array(dataTest.Instructions), dataTest.CreatedAt, dataTest.UpdatedAt).
+			WillReturnError(errors.New("error sql"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_, err := translationRepository.SaveTranslation(ctx, &dataTest)
+		assert.Error(tt, err)
+	})
+
+	t.Run("Save Translation Successful", func(tt *testing.T) {
+		mock, translationRepository := NewMockTranslation()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		mock.ExpectPrepare(insertFoodTranslationTest).
+			ExpectQuery().
+			WithArgs(dataTest.FoodID, dataTest.Language, dataTest.Title, dataTest.Description, pq.Array(dataTest.Instructions), dataTest.CreatedAt, dataTest.UpdatedAt).
+			WillReturnRows(sqlmock.NewRows([]string{"food_id", "language", "title", "description", "instructions"}).
+				AddRow(dataTest.FoodID, dataTest.Language, dataTest.Title, dataTest.Description, textArrayValue(dataTest.Instructions)))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		translationResult, err := translationRepository.SaveTranslation(ctx, &dataTest)
+		assert.NoError(tt, err)
+		assert.Equal(tt, dataTest.Title, translationResult.Title)
+		assert.Equal(tt, dataTest.Instructions, translationResult.Instructions)
+	})
+}
+
+func Test_sqlTranslationRepo_GetTranslationsByFoodIds(t *testing.T) {
+	dataTest := dataTranslation()
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock, translationRepository := NewMockTranslation()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		mock.ExpectQuery(selectFoodTranslationsTest).WillReturnError(errors.New("error sql"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		translations, err := translationRepository.GetTranslationsByFoodIds(ctx, []string{dataTest.FoodID})
+		assert.Error(tt, err)
+		assert.Nil(tt, translations)
+	})
+
+	t.Run("Get Translations Successful", func(tt *testing.T) {
+		mock, translationRepository := NewMockTranslation()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		rows := sqlmock.NewRows([]string{"food_id", "language", "title", "description", "instructions"}).
+			AddRow(dataTest.FoodID, "en", "Title", "Description", textArrayValue(nil)).
+			AddRow(dataTest.FoodID, dataTest.Language, dataTest.Title, dataTest.Description, textArrayValue(dataTest.Instructions))
+
+		mock.ExpectQuery(selectFoodTranslationsTest).WithArgs(pq.Array([]string{dataTest.FoodID})).WillReturnRows(rows)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		translations, err := translationRepository.GetTranslationsByFoodIds(ctx, []string{dataTest.FoodID})
+		assert.NoError(tt, err)
+		assert.Len(tt, translations, 2)
+		assert.Equal(tt, "es", translations[1].Language)
+	})
+}
+
+func Test_sqlTranslationRepo_DeleteTranslation(t *testing.T) {
+	dataTest := dataTranslation()
+
+	t.Run("Error Not Found", func(tt *testing.T) {
+		mock, translationRepository := NewMockTranslation()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		mock.ExpectExec(deleteFoodTranslationTest).
+			WithArgs(dataTest.FoodID, dataTest.Language).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := translationRepository.DeleteTranslation(ctx, dataTest.FoodID, dataTest.Language)
+		assert.Equal(tt, sql.ErrNoRows, err)
+	})
+
+	t.Run("Delete Translation Successful", func(tt *testing.T) {
+		mock, translationRepository := NewMockTranslation()
+		defer func() {
+			CloseMockFood()
+		}()
+
+		mock.ExpectExec(deleteFoodTranslationTest).
+			WithArgs(dataTest.FoodID, dataTest.Language).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := translationRepository.DeleteTranslation(ctx, dataTest.FoodID, dataTest.Language)
+		assert.NoError(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
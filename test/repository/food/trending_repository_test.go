@@ -0,0 +1,72 @@
+package food
+
+import (
+	"context"
+	"food-api/domain/food/domain/model"
+	"food-api/domain/food/domain/repository"
+	"food-api/domain/food/infrastructure/persistence"
+	"github.com/alicebob/miniredis"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// NewMockTrending initialize a redis server in memory for the trending counters
+func NewMockTrending() (*miniredis.Miniredis, repository.TrendingRepository) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr: mr.Addr(),
+	})
+
+	return mr, persistence.NewTrendingRepository(client)
+}
+
+func Test_redisTrendingRepo_GetTrending(t *testing.T) {
+	now := time.Date(2021, 1, 10, 12, 30, 0, 0, time.UTC)
+
+	t.Run("Error Window", func(tt *testing.T) {
+		mr, trendingRepository := NewMockTrending()
+		defer mr.Close()
+
+		scores, err := trendingRepository.GetTrending(context.Background(), "1y", now, 10)
+		assert.Error(tt, err)
+		assert.Nil(tt, scores)
+	})
+
+	t.Run("Recent Events Weigh More", func(tt *testing.T) {
+		mr, trendingRepository := NewMockTrending()
+		defer mr.Close()
+
+		ctx := context.Background()
+		assert.NoError(tt, trendingRepository.AddEvent(ctx, "old", 3, now.Add(-12*time.Hour)))
+		assert.NoError(tt, trendingRepository.AddEvent(ctx, "new", 1, now))
+		assert.NoError(tt, trendingRepository.AddEvent(ctx, "new", 1, now))
+		assert.NoError(tt, trendingRepository.AddEvent(ctx, "expired", 10, now.Add(-2*24*time.Hour)))
+
+		scores, err := trendingRepository.GetTrending(ctx, model.TrendingWindowDay, now, 10)
+		assert.NoError(tt, err)
+		assert.Equal(tt, []model.FoodScore{{FoodID: "new", Score: 2}, {FoodID: "old", Score: 0.75}}, scores)
+
+		scores, err = trendingRepository.GetTrending(ctx, model.TrendingWindowWeek, now, 1)
+		assert.NoError(tt, err)
+		assert.Len(tt, scores, 1)
+		assert.Equal(tt, "expired", scores[0].FoodID)
+	})
+
+	t.Run("Error Redis Unavailable", func(tt *testing.T) {
+		mr, trendingRepository := NewMockTrending()
+		mr.Close()
+
+		ctx := context.Background()
+		assert.Error(tt, trendingRepository.AddEvent(ctx, "food", 1, now))
+
+		scores, err := trendingRepository.GetTrending(ctx, model.TrendingWindowDay, now, 10)
+		assert.Equal(tt, persistence.ErrTrendingUnavailable, err)
+		assert.Nil(tt, scores)
+	})
+}
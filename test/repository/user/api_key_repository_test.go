@@ -0,0 +1,193 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"food-api/domain/user/domain/model"
+	"food-api/domain/user/domain/repository"
+	"food-api/domain/user/infrastructure/persistence"
+	"food-api/infrastructure/database"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"testing"
+	"time"
+)
+
+// NewMockAPIKeys initialize mock connection to database for the API keys
+func NewMockAPIKeys() (*sql.DB, sqlmock.Sqlmock, repository.APIKeyRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	return db, mock, persistence.NewAPIKeyRepository(&database.Data{DB: db})
+}
+
+func Test_sqlAPIKeyRepo_CreateAPIKey(t *testing.T) {
+	db, mock, apiKeyRepository := NewMockAPIKeys()
+	defer db.Close()
+
+	now := time.Now()
+	key := &model.APIKey{ID: uuid.New().String(), UserID: uuid.New().String(), Name: "Script", Prefix: "0a1b2c3d",
+		KeyHash: "hash", Scope: "foods:read", CreatedAt: now, UpdatedAt: now}
+
+	mock.ExpectExec(insertAPIKeyTest).WithArgs(key.ID, key.UserID, key.Name, key.Prefix, key.KeyHash, key.Scope,
+		key.ExpiresAt, now).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := apiKeyRepository.CreateAPIKey(context.Background(), key)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_sqlAPIKeyRepo_GetAPIKeys(t *testing.T) {
+	db, mock, apiKeyRepository := NewMockAPIKeys()
+	defer db.Close()
+
+	userId := uuid.New().String()
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "user_id", "name", "prefix", "scope", "expires_at", "last_used_at",
+		"created_at", "updated_at"}).
+		AddRow("key-2", userId, "Deploy", "11111111", "", nil, now, now, now).
+		AddRow("key-1", userId, "Script", "22222222", "foods:read", now, nil, now, now)
+	mock.ExpectQuery(selectAPIKeysTest).WithArgs(userId).WillReturnRows(rows)
+
+	keys, err := apiKeyRepository.GetAPIKeys(context.Background(), userId)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.Equal(t, "key-2", keys[0].ID)
+	assert.Nil(t, keys[0].ExpiresAt)
+	assert.Nil(t, keys[1].LastUsedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_sqlAPIKeyRepo_GetAPIKey(t *testing.T) {
+	userId := uuid.New().String()
+
+	t.Run("Error Not Found", func(tt *testing.T) {
+		db, mock, apiKeyRepository := NewMockAPIKeys()
+		defer db.Close()
+
+		mock.ExpectQuery(selectAPIKeyTest).WithArgs(userId, "key-1").WillReturnError(sql.ErrNoRows)
+
+		key, err := apiKeyRepository.GetAPIKey(context.Background(), userId, "key-1")
+		assert.Equal(tt, sql.ErrNoRows, err)
+		assert.Nil(tt, key)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Get API Key Successfully", func(tt *testing.T) {
+		db, mock, apiKeyRepository := NewMockAPIKeys()
+		defer db.Close()
+
+		now := time.Now()
+		rows := sqlmock.NewRows([]string{"id", "user_id", "name", "prefix", "scope", "expires_at", "last_used_at",
+			"created_at", "updated_at"}).
+			AddRow("key-1", userId, "Script", "22222222", "foods:read", nil, nil, now, now)
+		mock.ExpectQuery(selectAPIKeyTest).WithArgs(userId, "key-1").WillReturnRows(rows)
+
+		key, err := apiKeyRepository.GetAPIKey(context.Background(), userId, "key-1")
+		assert.NoError(tt, err)
+		assert.Equal(tt, "Script", key.Name)
+		assert.Empty(tt, key.KeyHash)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_sqlAPIKeyRepo_GetAPIKeyByPrefix(t *testing.T) {
+	db, mock, apiKeyRepository := NewMockAPIKeys()
+	defer db.Close()
+
+	userId := uuid.New().String()
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "user_id", "name", "prefix", "key_hash", "scope", "expires_at",
+		"last_used_at", "created_at", "updated_at", "role"}).
+		AddRow("key-1", userId, "Script", "22222222", "hash", "foods:read", nil, nil, now, now, "admin")
+	mock.ExpectQuery(selectAPIKeyByPrefixTest).WithArgs("22222222").WillReturnRows(rows)
+
+	key, err := apiKeyRepository.GetAPIKeyByPrefix(context.Background(), "22222222")
+	assert.NoError(t, err)
+	assert.Equal(t, "hash", key.KeyHash)
+	assert.Equal(t, "admin", key.Role)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_sqlAPIKeyRepo_UpdateAPIKey(t *testing.T) {
+	now := time.Now()
+	key := &model.APIKey{ID: "key-1", UserID: uuid.New().String(), Name: "Renamed", Scope: "", UpdatedAt: now}
+
+	t.Run("Error Not Found", func(tt *testing.T) {
+		db, mock, apiKeyRepository := NewMockAPIKeys()
+		defer db.Close()
+
+		mock.ExpectExec(updateAPIKeyTest).WithArgs(key.Name, key.Scope, now, key.UserID, key.ID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := apiKeyRepository.UpdateAPIKey(context.Background(), key)
+		assert.Equal(tt, sql.ErrNoRows, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Update API Key Successfully", func(tt *testing.T) {
+		db, mock, apiKeyRepository := NewMockAPIKeys()
+		defer db.Close()
+
+		mock.ExpectExec(updateAPIKeyTest).WithArgs(key.Name, key.Scope, now, key.UserID, key.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := apiKeyRepository.UpdateAPIKey(context.Background(), key)
+		assert.NoError(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_sqlAPIKeyRepo_DeleteAPIKey(t *testing.T) {
+	userId := uuid.New().String()
+
+	t.Run("Error Not Found", func(tt *testing.T) {
+		db, mock, apiKeyRepository := NewMockAPIKeys()
+		defer db.Close()
+
+		mock.ExpectExec(deleteAPIKeyTest).WithArgs(userId, "key-1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := apiKeyRepository.DeleteAPIKey(context.Background(), userId, "key-1")
+		assert.Equal(tt, sql.ErrNoRows, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Delete API Key Successfully", func(tt *testing.T) {
+		db, mock, apiKeyRepository := NewMockAPIKeys()
+		defer db.Close()
+
+		mock.ExpectExec(deleteAPIKeyTest).WithArgs(userId, "key-1").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := apiKeyRepository.DeleteAPIKey(context.Background(), userId, "key-1")
+		assert.NoError(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_sqlAPIKeyRepo_DeleteUserAPIKeys(t *testing.T) {
+	db, mock, apiKeyRepository := NewMockAPIKeys()
+	defer db.Close()
+
+	userId := uuid.New().String()
+	mock.ExpectExec(deleteUserAPIKeysTest).WithArgs(userId).WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := apiKeyRepository.DeleteUserAPIKeys(context.Background(), userId)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_sqlAPIKeyRepo_TouchAPIKey(t *testing.T) {
+	db, mock, apiKeyRepository := NewMockAPIKeys()
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectExec(touchAPIKeyTest).WithArgs(now, "key-1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := apiKeyRepository.TouchAPIKey(context.Background(), "key-1", now)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
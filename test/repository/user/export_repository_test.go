@@ -0,0 +1,123 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"food-api/domain/user/domain/repository"
+	"food-api/domain/user/infrastructure/persistence"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// NewMockExport initialize mock connection to database for the personal data export
+func NewMockExport() (sqlmock.Sqlmock, repository.ExportRepository) {
+	mock := NewMockUser()
+	return mock, persistence.NewExportRepository(&connMockUser)
+}
+
+func Test_sqlExportRepo_CountUserFoods(t *testing.T) {
+	userId := uuid.New().String()
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock, exportRepository := NewMockExport()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		mock.ExpectQuery(countUserFoodsTest).WithArgs(userId).WillReturnError(errors.New("error sql"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_, err := exportRepository.CountUserFoods(ctx, userId)
+		assert.Error(tt, err)
+	})
+
+	t.Run("Count User Foods Successful", func(tt *testing.T) {
+		mock, exportRepository := NewMockExport()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		mock.ExpectQuery(countUserFoodsTest).WithArgs(userId).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		count, err := exportRepository.CountUserFoods(ctx, userId)
+		assert.NoError(tt, err)
+		assert.Equal(tt, 12, count)
+	})
+}
+
+func Test_sqlExportRepo_GetUserExport(t *testing.T) {
+	user := dataUser()[0]
+
+	t.Run("Error Not Found", func(tt *testing.T) {
+		mock, exportRepository := NewMockExport()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectUserProfileTest).WithArgs(user.ID).WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		export, err := exportRepository.GetUserExport(ctx, user.ID)
+		assert.Equal(tt, sql.ErrNoRows, err)
+		assert.Nil(tt, export)
+	})
+
+	t.Run("Get User Export Successful", func(tt *testing.T) {
+		mock, exportRepository := NewMockExport()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		foodId := uuid.New().String()
+		followerId := uuid.New().String()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectUserProfileTest).WithArgs(user.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "names", "last_names", "email", "created_at", "updated_at"}).
+				AddRow(user.ID, user.Names, user.LastNames, user.Email, user.CreatedAt, user.UpdatedAt))
+		mock.ExpectQuery(selectUserFoodsTest).WithArgs(user.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "food_image", "ingredients", "instructions", "tags", "slug", "visibility", "language", "created_at", "updated_at"}).
+				AddRow(foodId, "Pancakes", "Sweet", "", "{Flour,Milk}", "{Mix,Cook}", "{breakfast}", "pancakes", "private", "en", user.CreatedAt, user.UpdatedAt))
+		mock.ExpectQuery(selectUserFoodTranslationsTest).WithArgs(user.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"food_id", "language", "title", "description", "instructions"}).
+				AddRow(foodId, "es", "Panqueques", "Dulce", "{Mezclar}").
+				AddRow(uuid.New().String(), "es", "Otro", "", "{}"))
+		mock.ExpectQuery(selectUserPreferencesTest).WithArgs(user.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"food_id", "favorite", "rating", "created_at", "updated_at"}).
+				AddRow(foodId, true, 5, user.CreatedAt, user.UpdatedAt))
+		mock.ExpectQuery(selectUserFollowersTest).WithArgs(user.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"follower_id", "created_at"}).AddRow(followerId, user.CreatedAt))
+		mock.ExpectQuery(selectUserFollowingTest).WithArgs(user.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id", "created_at"}))
+		mock.ExpectCommit()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		export, err := exportRepository.GetUserExport(ctx, user.ID)
+		assert.NoError(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+		assert.Equal(tt, user.Email, export.Profile.Email)
+		assert.Len(tt, export.Foods, 1)
+		assert.Equal(tt, []string{"Flour", "Milk"}, export.Foods[0].Ingredients)
+		assert.Len(tt, export.Foods[0].Translations, 1)
+		assert.Equal(tt, "Panqueques", export.Foods[0].Translations[0].Title)
+		assert.Equal(tt, 5, export.Preferences[0].Rating)
+		assert.Equal(tt, followerId, export.Followers[0].UserID)
+		assert.NotNil(tt, export.Following)
+		assert.Empty(tt, export.Following)
+	})
+}
@@ -0,0 +1,133 @@
+package user
+
+import (
+	"context"
+	"food-api/domain/user/domain/model"
+	"food-api/domain/user/domain/repository"
+	"food-api/domain/user/infrastructure/persistence"
+	"github.com/alicebob/miniredis"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// NewMockLoginAttempts initialize a redis server in memory for the failed logins
+func NewMockLoginAttempts() (*miniredis.Miniredis, repository.LoginAttemptRepository) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr: mr.Addr(),
+	})
+
+	return mr, persistence.NewLoginAttemptRepository(client)
+}
+
+// dataLockoutPolicy is data for test
+func dataLockoutPolicy() model.LockoutPolicy {
+	return model.LockoutPolicy{MaxAttempts: 3, Base: time.Minute, Max: 5 * time.Minute, Window: 15 * time.Minute}
+}
+
+func TestLockoutPolicy_Lockout(t *testing.T) {
+	policy := dataLockoutPolicy()
+
+	assert.Equal(t, time.Duration(0), policy.Lockout(2))
+	assert.Equal(t, time.Minute, policy.Lockout(3))
+	assert.Equal(t, 2*time.Minute, policy.Lockout(4))
+	assert.Equal(t, 4*time.Minute, policy.Lockout(5))
+	assert.Equal(t, 5*time.Minute, policy.Lockout(6))
+	assert.Equal(t, 5*time.Minute, policy.Lockout(100))
+}
+
+func Test_redisLoginAttemptRepo_Fail(t *testing.T) {
+	ctx := context.Background()
+	account := model.AccountSubject("daniel.delapava@jikkosoft.com")
+	ip := model.IPSubject("192.0.2.1")
+
+	t.Run("Lock After Max Attempts", func(tt *testing.T) {
+		mr, attempts := NewMockLoginAttempts()
+		defer mr.Close()
+
+		for i := 0; i < 2; i++ {
+			lockout, err := attempts.Fail(ctx, account, dataLockoutPolicy())
+			assert.NoError(tt, err)
+			assert.Equal(tt, time.Duration(0), lockout)
+		}
+
+		locked, err := attempts.LockedFor(ctx, []string{account, ip})
+		assert.NoError(tt, err)
+		assert.Equal(tt, time.Duration(0), locked)
+
+		lockout, err := attempts.Fail(ctx, account, dataLockoutPolicy())
+		assert.NoError(tt, err)
+		assert.Equal(tt, time.Minute, lockout)
+
+		locked, err = attempts.LockedFor(ctx, []string{account, ip})
+		assert.NoError(tt, err)
+		assert.Equal(tt, time.Minute, locked)
+
+		for _, key := range mr.Keys() {
+			assert.NotContains(tt, key, "daniel.delapava")
+		}
+	})
+
+	t.Run("Backoff After The Lock", func(tt *testing.T) {
+		mr, attempts := NewMockLoginAttempts()
+		defer mr.Close()
+
+		for i := 0; i < 3; i++ {
+			_, err := attempts.Fail(ctx, account, dataLockoutPolicy())
+			assert.NoError(tt, err)
+		}
+
+		mr.FastForward(time.Minute)
+		locked, err := attempts.LockedFor(ctx, []string{account})
+		assert.NoError(tt, err)
+		assert.Equal(tt, time.Duration(0), locked)
+
+		lockout, err := attempts.Fail(ctx, account, dataLockoutPolicy())
+		assert.NoError(tt, err)
+		assert.Equal(tt, 2*time.Minute, lockout)
+	})
+
+	t.Run("Window Expired", func(tt *testing.T) {
+		mr, attempts := NewMockLoginAttempts()
+		defer mr.Close()
+
+		for i := 0; i < 2; i++ {
+			_, err := attempts.Fail(ctx, account, dataLockoutPolicy())
+			assert.NoError(tt, err)
+		}
+
+		mr.FastForward(16 * time.Minute)
+		lockout, err := attempts.Fail(ctx, account, dataLockoutPolicy())
+		assert.NoError(tt, err)
+		assert.Equal(tt, time.Duration(0), lockout)
+	})
+}
+
+func Test_redisLoginAttemptRepo_Reset(t *testing.T) {
+	ctx := context.Background()
+	account := model.AccountSubject("daniel.delapava@jikkosoft.com")
+
+	mr, attempts := NewMockLoginAttempts()
+	defer mr.Close()
+
+	for i := 0; i < 3; i++ {
+		_, err := attempts.Fail(ctx, account, dataLockoutPolicy())
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, attempts.Reset(ctx, account))
+
+	locked, err := attempts.LockedFor(ctx, []string{account})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), locked)
+
+	lockout, err := attempts.Fail(ctx, account, dataLockoutPolicy())
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), lockout)
+}
@@ -0,0 +1,201 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"food-api/domain/user/domain/model"
+	"food-api/domain/user/domain/repository"
+	"food-api/domain/user/infrastructure/persistence"
+	"food-api/infrastructure/database"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"testing"
+	"time"
+)
+
+// NewMockOAuthClients initialize mock connection to database for the OAuth clients
+func NewMockOAuthClients() (*sql.DB, sqlmock.Sqlmock, repository.OAuthClientRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	return db, mock, persistence.NewOAuthClientRepository(&database.Data{DB: db})
+}
+
+// NewMockAuthorizationCodes initialize a redis server in memory for the authorization codes
+func NewMockAuthorizationCodes() (*miniredis.Miniredis, repository.AuthorizationCodeRepository) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr: mr.Addr(),
+	})
+
+	return mr, persistence.NewAuthorizationCodeRepository(client)
+}
+
+// oauthClientColumns are the columns of the OAuth clients for test
+var oauthClientColumns = []string{"id", "user_id", "name", "secret_hash", "redirect_uris", "scope", "created_at", "updated_at"}
+
+func Test_sqlOAuthClientRepo_CreateClient(t *testing.T) {
+	now := time.Now()
+
+	t.Run("Create Public Client", func(tt *testing.T) {
+		db, mock, clientRepository := NewMockOAuthClients()
+		defer db.Close()
+
+		client := &model.OAuthClient{ID: uuid.New().String(), UserID: uuid.New().String(), Name: "Mobile",
+			RedirectURIs: []string{"com.example.app:/oauth"}, Scope: "openid", CreatedAt: now}
+		mock.ExpectExec(insertOAuthClientTest).WithArgs(client.ID, client.UserID, client.Name, nil,
+			pq.Array(client.RedirectURIs), client.Scope, now).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := clientRepository.CreateClient(context.Background(), client)
+		assert.NoError(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Create Confidential Client", func(tt *testing.T) {
+		db, mock, clientRepository := NewMockOAuthClients()
+		defer db.Close()
+
+		client := &model.OAuthClient{ID: uuid.New().String(), UserID: uuid.New().String(), Name: "Web",
+			RedirectURIs: []string{"https://app.example.com/callback"}, Scope: "openid", Confidential: true,
+			SecretHash: "hash", CreatedAt: now}
+		mock.ExpectExec(insertOAuthClientTest).WithArgs(client.ID, client.UserID, client.Name, "hash",
+			pq.Array(client.RedirectURIs), client.Scope, now).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := clientRepository.CreateClient(context.Background(), client)
+		assert.NoError(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_sqlOAuthClientRepo_GetClients(t *testing.T) {
+	db, mock, clientRepository := NewMockOAuthClients()
+	defer db.Close()
+
+	userId := uuid.New().String()
+	now := time.Now()
+	rows := sqlmock.NewRows(oauthClientColumns).
+		AddRow("client-2", userId, "Web", "hash", "{https://app.example.com/callback}", "openid", now, now).
+		AddRow("client-1", userId, "Mobile", nil, "{com.example.app:/oauth,http://localhost/cb}", "openid", now, now)
+	mock.ExpectQuery(selectOAuthClientsTest).WithArgs(userId).WillReturnRows(rows)
+
+	clients, err := clientRepository.GetClients(context.Background(), userId)
+	assert.NoError(t, err)
+	assert.Len(t, clients, 2)
+	assert.True(t, clients[0].Confidential)
+	assert.False(t, clients[1].Confidential)
+	assert.Equal(t, []string{"com.example.app:/oauth", "http://localhost/cb"}, clients[1].RedirectURIs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_sqlOAuthClientRepo_GetClient(t *testing.T) {
+	id := uuid.New().String()
+
+	t.Run("Error Not A Client Id", func(tt *testing.T) {
+		db, mock, clientRepository := NewMockOAuthClients()
+		defer db.Close()
+
+		client, err := clientRepository.GetClient(context.Background(), "not-an-id")
+		assert.Equal(tt, sql.ErrNoRows, err)
+		assert.Nil(tt, client)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error Not Found", func(tt *testing.T) {
+		db, mock, clientRepository := NewMockOAuthClients()
+		defer db.Close()
+
+		mock.ExpectQuery(selectOAuthClientTest).WithArgs(id).WillReturnError(sql.ErrNoRows)
+
+		client, err := clientRepository.GetClient(context.Background(), id)
+		assert.Equal(tt, sql.ErrNoRows, err)
+		assert.Nil(tt, client)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Get Client Successfully", func(tt *testing.T) {
+		db, mock, clientRepository := NewMockOAuthClients()
+		defer db.Close()
+
+		now := time.Now()
+		rows := sqlmock.NewRows(oauthClientColumns).
+			AddRow(id, uuid.New().String(), "Web", "hash", "{https://app.example.com/callback}", "openid email", now, now)
+		mock.ExpectQuery(selectOAuthClientTest).WithArgs(id).WillReturnRows(rows)
+
+		client, err := clientRepository.GetClient(context.Background(), id)
+		assert.NoError(tt, err)
+		assert.Equal(tt, "hash", client.SecretHash)
+		assert.True(tt, client.HasRedirectURI("https://app.example.com/callback"))
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_sqlOAuthClientRepo_DeleteClient(t *testing.T) {
+	userId, id := uuid.New().String(), uuid.New().String()
+
+	t.Run("Error Not Found", func(tt *testing.T) {
+		db, mock, clientRepository := NewMockOAuthClients()
+		defer db.Close()
+
+		mock.ExpectExec(deleteOAuthClientTest).WithArgs(userId, id).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := clientRepository.DeleteClient(context.Background(), userId, id)
+		assert.Equal(tt, sql.ErrNoRows, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Delete Client Successfully", func(tt *testing.T) {
+		db, mock, clientRepository := NewMockOAuthClients()
+		defer db.Close()
+
+		mock.ExpectExec(deleteOAuthClientTest).WithArgs(userId, id).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := clientRepository.DeleteClient(context.Background(), userId, id)
+		assert.NoError(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_redisAuthorizationCodeRepo(t *testing.T) {
+	ctx := context.Background()
+	authorization := &model.AuthorizationCode{ClientID: "client-1", UserID: "user-1", RedirectURI: "https://app.example.com/cb",
+		Scope: "openid", CodeChallenge: "challenge", AuthTime: time.Now().UTC().Truncate(time.Second),
+		ExpiresAt: time.Now().Add(time.Minute).UTC().Truncate(time.Second)}
+
+	t.Run("Consume Code Once", func(tt *testing.T) {
+		mr, codeRepository := NewMockAuthorizationCodes()
+		defer mr.Close()
+
+		assert.NoError(tt, codeRepository.SaveCode(ctx, "code", authorization))
+		assert.Len(tt, mr.Keys(), 1)
+		assert.NotContains(tt, mr.Keys()[0], "code:code")
+
+		saved, err := codeRepository.ConsumeCode(ctx, "code")
+		assert.NoError(tt, err)
+		assert.Equal(tt, authorization, saved)
+
+		_, err = codeRepository.ConsumeCode(ctx, "code")
+		assert.Equal(tt, persistence.ErrAuthorizationCodeInvalid, err)
+	})
+
+	t.Run("Error Expired Code", func(tt *testing.T) {
+		mr, codeRepository := NewMockAuthorizationCodes()
+		defer mr.Close()
+
+		assert.NoError(tt, codeRepository.SaveCode(ctx, "code", authorization))
+		mr.FastForward(2 * time.Minute)
+
+		_, err := codeRepository.ConsumeCode(ctx, "code")
+		assert.Equal(tt, persistence.ErrAuthorizationCodeInvalid, err)
+	})
+}
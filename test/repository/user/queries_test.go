@@ -3,17 +3,25 @@ package user
 const(
 
 	// selectAllUserTest is a query that selects all rows in the user table
-	selectAllUserTest = "SELECT id, names, last_names, email FROM \"user\" WHERE deleted_at IS NULL ORDER BY created_at DESC;"
+	selectAllUserTest = "SELECT id, names, last_names, email, role FROM \"user\" WHERE deleted_at IS NULL ORDER BY created_at DESC;"
 
-	// selectUserByIdTest is a query that selects a row from the user table based off of the given id.
+	// selectUserByIdTest is a query that selects a row from the user table based off of the given id,
+	// the deleted users are not found.
 	// You must escape the code and to escape the code use
 	// https://regex-escape.com/preg_quote-online.php
-	selectUserByIdTest = "SELECT id, names, last_names, email FROM \"user\" WHERE id \\= \\$1;"
+	selectUserByIdTest = "SELECT id, names, last_names, email, email_verified_at, role FROM \"user\" WHERE id \\= \\$1 AND deleted_at IS NULL;"
 
-	// selectUserByEmail is a query that selects a row from the user table based off of the given email.
+	// selectUserByEmail is a query that selects a row from the user table based off of the given email,
+	// the deleted users cannot log in.
 	// You must escape the code and to escape the code use
 	// https://regex-escape.com/preg_quote-online.php
-	selectUserByEmailTest = "SELECT id, names, last_names, email, \"password\", created_at, updated_at FROM \"user\" WHERE email \\= \\$1;"
+	selectUserByEmailTest = "SELECT id, names, last_names, email, \"password\", email_verified_at, role, created_at, updated_at FROM \"user\" WHERE email \\= \\$1 AND deleted_at IS NULL;"
+
+	// selectUserInfoByEmailTest is a query that selects a row from the user table based off of the given email,
+	// without the password.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	selectUserInfoByEmailTest = "SELECT id, names, last_names, email, email_verified_at FROM \"user\" WHERE email \\= \\$1 AND deleted_at IS NULL;"
 
 	// insertUserTest is a query that inserts a new row in the user table using the values
 	// given in order for id, names, last_names, username, email, password, created_at and updated_at.
@@ -22,13 +30,198 @@ const(
 	insertUserTest = "INSERT INTO \"user\" \\(id, names, last_names, email, \"password\", created_at, updated_at\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7\\) RETURNING id, names, last_names, email;"
 
 	// updateUserTest is a query that updates a row in the user table based off of id.
-	// The values able to be updated are names, last_names, email and updated_at.
+	// The values able to be updated are names, last_names, email and updated_at, a new email is not verified.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	updateUserTest = "UPDATE \"user\" SET names\\=\\$1, last_names\\=\\$2, email\\=\\$3, updated_at\\=\\$4, email_verified_at\\=CASE WHEN email\\=\\$3 THEN email_verified_at END WHERE id\\=\\$5;"
+
+	// verifyUserEmailTest is a query that marks the email of the user $2 as verified at $1 while it is still $3, verifying it again
+	// keeps the first date.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	verifyUserEmailTest = "UPDATE \"user\" SET email_verified_at\\=COALESCE\\(email_verified_at, \\$1\\), updated_at\\=\\$1 WHERE id\\=\\$2 AND email\\=\\$3 AND deleted_at IS NULL;"
+
+	// updateUserPasswordTest is a query that replaces the password hash of the user given as $3.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	updateUserPasswordTest = "UPDATE \"user\" SET \"password\"\\=\\$1, updated_at\\=\\$2 WHERE id\\=\\$3 AND deleted_at IS NULL;"
+
+	// updateUserRoleTest is a query that sets the role $1 to the user $3.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	updateUserRoleTest = "UPDATE \"user\" SET role\\=\\$1, updated_at\\=\\$2 WHERE id\\=\\$3 AND deleted_at IS NULL;"
+
+	// promoteAdminsTest is a query that sets the admin role to the users with the verified emails $1.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	promoteAdminsTest = "UPDATE \"user\" SET role\\='admin', updated_at\\=\\$2 WHERE email \\= ANY\\(\\$1\\) AND email_verified_at IS NOT NULL AND deleted_at IS NULL AND role \\<\\> 'admin';"
+
+	// deleteUserTest is a query that soft deletes a row in the user table given a id and scrubs its
+	// personal data, the deleted_at and updated_at are $1.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	deleteUserTest = "UPDATE \"user\" SET names\\='Deleted', last_names\\='User', email\\='deleted\\+' \\|\\| id \\|\\| '@food\\-api\\.invalid', \"password\"\\='', email_verified_at\\=NULL, deleted_at\\=\\$1, updated_at\\=\\$1 WHERE id\\=\\$2 AND deleted_at IS NULL;"
+
+	// deleteUserFollowersTest is a query that removes the followers of the user and the users the user follows.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	deleteUserFollowersTest = "DELETE FROM user_follower WHERE user_id\\=\\$1 OR follower_id\\=\\$1;"
+
+	// transferUserFoodsTest is a query that moves the foods of the user $3 to the user $1.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	transferUserFoodsTest = "UPDATE food SET user_id\\=\\$1, updated_at\\=\\$2 WHERE user_id\\=\\$3;"
+
+	// deleteUserFoodsTest is a query that deletes the foods of the user, their translations, preferences and
+	// slug history are deleted in cascade.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	deleteUserFoodsTest = "DELETE FROM food WHERE user_id\\=\\$1;"
+
+	// insertFollowerTest is a query that makes follower_id a follower of user_id, following twice is ignored.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	insertFollowerTest = "INSERT INTO user_follower \\(user_id, follower_id, created_at\\) VALUES \\(\\$1, \\$2, \\$3\\) ON CONFLICT \\(user_id, follower_id\\) DO NOTHING;"
+
+	// deleteFollowerTest is a query that removes follower_id from the followers of user_id.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	deleteFollowerTest = "DELETE FROM user_follower WHERE user_id\\=\\$1 AND follower_id\\=\\$2;"
+
+	// selectUserProfileTest is a query that selects the profile of the user for the personal data export.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	selectUserProfileTest = "SELECT id, names, last_names, email, created_at, updated_at FROM \"user\" WHERE id \\= \\$1 AND deleted_at IS NULL;"
+
+	// countUserFoodsTest is a query that counts the foods of the user.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	countUserFoodsTest = "SELECT COUNT\\(\\*\\) FROM food WHERE user_id \\= \\$1;"
+
+	// selectUserFoodsTest is a query that selects every food of the user, whatever its visibility.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	selectUserFoodsTest = "SELECT id, title, description, COALESCE\\(food_image, ''\\), ingredients, instructions, tags, slug, visibility, language, created_at, updated_at FROM food WHERE user_id \\= \\$1 ORDER BY created_at, id;"
+
+	// selectUserFoodTranslationsTest is a query that selects the translations of the foods of the user.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	selectUserFoodTranslationsTest = "SELECT ft\\.food_id, ft\\.language, ft\\.title, ft\\.description, ft\\.instructions FROM food_translation ft JOIN food f ON f\\.id \\= ft\\.food_id WHERE f\\.user_id \\= \\$1 ORDER BY ft\\.food_id, ft\\.language;"
+
+	// selectUserPreferencesTest is a query that selects the favorites and the ratings of the user, a food without
+	// rating has a rating of 0.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	selectUserPreferencesTest = "SELECT food_id, favorite, COALESCE\\(rating, 0\\), created_at, updated_at FROM food_preference WHERE user_id \\= \\$1 ORDER BY food_id;"
+
+	// selectUserFollowersTest is a query that selects the followers of the user.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	selectUserFollowersTest = "SELECT follower_id, created_at FROM user_follower WHERE user_id \\= \\$1 ORDER BY created_at, follower_id;"
+
+	// selectUserFollowingTest is a query that selects the users the user follows.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	selectUserFollowingTest = "SELECT user_id, created_at FROM user_follower WHERE follower_id \\= \\$1 ORDER BY created_at, user_id;"
+
+	// selectTwoFactorTest is a query that selects the TOTP secret of the user.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	selectTwoFactorTest = "SELECT user_id, secret, enabled_at, last_step FROM user_two_factor WHERE user_id \\= \\$1;"
+
+	// upsertTwoFactorSecretTest is a query that saves the pending secret $2 of the user $1, the secret of an
+	// enabled two-factor authentication is not replaced.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	upsertTwoFactorSecretTest = "INSERT INTO user_two_factor \\(user_id, secret, created_at, updated_at\\) VALUES \\(\\$1, \\$2, \\$3, \\$3\\) ON CONFLICT \\(user_id\\) DO UPDATE SET secret\\=\\$2, last_step\\=0, updated_at\\=\\$3 WHERE user_two_factor\\.enabled_at IS NULL;"
+
+	// enableTwoFactorTest is a query that enables the pending secret of the user $2 at $1.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	enableTwoFactorTest = "UPDATE user_two_factor SET enabled_at\\=\\$1, updated_at\\=\\$1 WHERE user_id\\=\\$2 AND enabled_at IS NULL;"
+
+	// deleteTwoFactorTest is a query that removes the secret of the user.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	deleteTwoFactorTest = "DELETE FROM user_two_factor WHERE user_id\\=\\$1;"
+
+	// updateTwoFactorStepTest is a query that records the time step $1 of the code used by the user $2.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	updateTwoFactorStepTest = "UPDATE user_two_factor SET last_step\\=\\$1 WHERE user_id\\=\\$2 AND last_step \\< \\$1;"
+
+	// insertRecoveryCodeTest is a query that inserts the hash $2 of a recovery code of the user $1.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	insertRecoveryCodeTest = "INSERT INTO user_recovery_code \\(user_id, code_hash, created_at\\) VALUES \\(\\$1, \\$2, \\$3\\);"
+
+	// deleteRecoveryCodesTest is a query that removes the recovery codes of the user.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	deleteRecoveryCodesTest = "DELETE FROM user_recovery_code WHERE user_id\\=\\$1;"
+
+	// useRecoveryCodeTest is a query that marks the recovery code $3 of the user $2 as used at $1.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	useRecoveryCodeTest = "UPDATE user_recovery_code SET used_at\\=\\$1 WHERE user_id\\=\\$2 AND code_hash\\=\\$3 AND used_at IS NULL;"
+
+	// insertAPIKeyTest is a query that inserts a new row in the api_key table.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	insertAPIKeyTest = "INSERT INTO api_key \\(id, user_id, name, prefix, key_hash, scope, expires_at, created_at, updated_at\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7, \\$8, \\$8\\);"
+
+	// selectAPIKeysTest is a query that selects the API keys of the user, the newest first.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	selectAPIKeysTest = "SELECT id, user_id, name, prefix, scope, expires_at, last_used_at, created_at, updated_at FROM api_key WHERE user_id \\= \\$1 ORDER BY created_at DESC, id;"
+
+	// selectAPIKeyTest is a query that selects the API key $2 of the user $1.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	selectAPIKeyTest = "SELECT id, user_id, name, prefix, scope, expires_at, last_used_at, created_at, updated_at FROM api_key WHERE user_id \\= \\$1 AND id \\= \\$2;"
+
+	// selectAPIKeyByPrefixTest is a query that selects the API key of the prefix with the role of its user.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	selectAPIKeyByPrefixTest = "SELECT k.id, k.user_id, k.name, k.prefix, k.key_hash, k.scope, k.expires_at, k.last_used_at, k.created_at, k.updated_at, u.role FROM api_key k JOIN \"user\" u ON u.id \\= k.user_id WHERE k.prefix \\= \\$1 AND u.deleted_at IS NULL;"
+
+	// updateAPIKeyTest is a query that sets the name $1 and the scope $2 of the API key $5 of the user $4.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	updateAPIKeyTest = "UPDATE api_key SET name\\=\\$1, scope\\=\\$2, updated_at\\=\\$3 WHERE user_id\\=\\$4 AND id\\=\\$5;"
+
+	// deleteAPIKeyTest is a query that removes the API key $2 of the user $1.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	deleteAPIKeyTest = "DELETE FROM api_key WHERE user_id\\=\\$1 AND id\\=\\$2;"
+
+	// deleteUserAPIKeysTest is a query that removes all the API keys of the user $1.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	deleteUserAPIKeysTest = "DELETE FROM api_key WHERE user_id\\=\\$1;"
+
+	// touchAPIKeyTest is a query that records the last use $1 of the API key $2.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	touchAPIKeyTest = "UPDATE api_key SET last_used_at\\=\\$1 WHERE id\\=\\$2 AND \\(last_used_at IS NULL OR last_used_at \\< \\$1 \\- interval '1 minute'\\);"
+
+	// insertOAuthClientTest is a query that inserts a new row in the oauth_client table.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	insertOAuthClientTest = "INSERT INTO oauth_client \\(id, user_id, name, secret_hash, redirect_uris, scope, created_at, updated_at\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7, \\$7\\);"
+
+	// selectOAuthClientsTest is a query that selects the OAuth clients of the user, the newest first.
+	// You must escape the code and to escape the code use
+	// https://regex-escape.com/preg_quote-online.php
+	selectOAuthClientsTest = "SELECT id, user_id, name, secret_hash, redirect_uris, scope, created_at, updated_at FROM oauth_client WHERE user_id \\= \\$1 ORDER BY created_at DESC, id;"
+
+	// selectOAuthClientTest is a query that selects the OAuth client by id.
 	// You must escape the code and to escape the code use
 	// https://regex-escape.com/preg_quote-online.php
-	updateUserTest = "UPDATE \"user\" SET names\\=\\$1, last_names\\=\\$2, email\\=\\$3, updated_at\\=\\$4 WHERE id\\=\\$5;"
+	selectOAuthClientTest = "SELECT c.id, c.user_id, c.name, c.secret_hash, c.redirect_uris, c.scope, c.created_at, c.updated_at FROM oauth_client c JOIN \"user\" u ON u.id \\= c.user_id WHERE c.id \\= \\$1 AND u.deleted_at IS NULL;"
 
-	// deleteUserTest is a query that deletes a row in the user table given a id.
+	// deleteOAuthClientTest is a query that removes the OAuth client $2 of the user $1.
 	// You must escape the code and to escape the code use
 	// https://regex-escape.com/preg_quote-online.php
-	deleteUserTest = "DELETE FROM \"user\" WHERE id\\=\\$1;"
+	deleteOAuthClientTest = "DELETE FROM oauth_client WHERE user_id\\=\\$1 AND id\\=\\$2;"
 )
@@ -0,0 +1,265 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"food-api/domain/user/domain/model"
+	"food-api/domain/user/domain/repository"
+	"food-api/domain/user/infrastructure/persistence"
+	"food-api/infrastructure/database"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"testing"
+	"time"
+)
+
+// NewMockTwoFactor initialize mock connection to database for the two-factor authentication
+func NewMockTwoFactor() (*sql.DB, sqlmock.Sqlmock, repository.TwoFactorRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	return db, mock, persistence.NewTwoFactorRepository(&database.Data{DB: db})
+}
+
+// NewMockTwoFactorChallenges initialize a redis server in memory for the challenges of the logins
+func NewMockTwoFactorChallenges() (*miniredis.Miniredis, repository.TwoFactorChallengeRepository) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr: mr.Addr(),
+	})
+
+	return mr, persistence.NewTwoFactorChallengeRepository(client)
+}
+
+func Test_sqlTwoFactorRepo_GetTwoFactor(t *testing.T) {
+	userId := uuid.New().String()
+
+	t.Run("Error Not Enrolled", func(tt *testing.T) {
+		db, mock, twoFactorRepository := NewMockTwoFactor()
+		defer db.Close()
+
+		mock.ExpectQuery(selectTwoFactorTest).WithArgs(userId).WillReturnError(sql.ErrNoRows)
+
+		twoFactor, err := twoFactorRepository.GetTwoFactor(context.Background(), userId)
+		assert.Equal(tt, sql.ErrNoRows, err)
+		assert.Nil(tt, twoFactor)
+		assert.False(tt, twoFactor.Enabled())
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Get Two Factor Successfully", func(tt *testing.T) {
+		db, mock, twoFactorRepository := NewMockTwoFactor()
+		defer db.Close()
+
+		enabledAt := time.Now()
+		rows := sqlmock.NewRows([]string{"user_id", "secret", "enabled_at", "last_step"}).
+			AddRow(userId, "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", enabledAt, 41152263)
+		mock.ExpectQuery(selectTwoFactorTest).WithArgs(userId).WillReturnRows(rows)
+
+		twoFactor, err := twoFactorRepository.GetTwoFactor(context.Background(), userId)
+		assert.NoError(tt, err)
+		assert.True(tt, twoFactor.Enabled())
+		assert.Equal(tt, int64(41152263), twoFactor.LastStep)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_sqlTwoFactorRepo_SaveSecret(t *testing.T) {
+	userId := uuid.New().String()
+	now := time.Now()
+
+	t.Run("Error Already Enabled", func(tt *testing.T) {
+		db, mock, twoFactorRepository := NewMockTwoFactor()
+		defer db.Close()
+
+		mock.ExpectExec(upsertTwoFactorSecretTest).WithArgs(userId, "secret", now).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := twoFactorRepository.SaveSecret(context.Background(), userId, "secret", now)
+		assert.Equal(tt, persistence.ErrTwoFactorEnabled, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Save Secret Successfully", func(tt *testing.T) {
+		db, mock, twoFactorRepository := NewMockTwoFactor()
+		defer db.Close()
+
+		mock.ExpectExec(upsertTwoFactorSecretTest).WithArgs(userId, "secret", now).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(tt, twoFactorRepository.SaveSecret(context.Background(), userId, "secret", now))
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_sqlTwoFactorRepo_Enable(t *testing.T) {
+	userId := uuid.New().String()
+	now := time.Now()
+	codes := []string{"aaaaabbbbb", "cccccddddd"}
+
+	t.Run("Error Not Pending", func(tt *testing.T) {
+		db, mock, twoFactorRepository := NewMockTwoFactor()
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(enableTwoFactorTest).WithArgs(now, userId).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := twoFactorRepository.Enable(context.Background(), userId, codes, now)
+		assert.Equal(tt, sql.ErrNoRows, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error SQL Recovery Codes", func(tt *testing.T) {
+		db, mock, twoFactorRepository := NewMockTwoFactor()
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(enableTwoFactorTest).WithArgs(now, userId).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(deleteRecoveryCodesTest).WithArgs(userId).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(insertRecoveryCodeTest).WillReturnError(errors.New("error sql"))
+		mock.ExpectRollback()
+
+		err := twoFactorRepository.Enable(context.Background(), userId, codes, now)
+		assert.Error(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Enable Successfully", func(tt *testing.T) {
+		db, mock, twoFactorRepository := NewMockTwoFactor()
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(enableTwoFactorTest).WithArgs(now, userId).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(deleteRecoveryCodesTest).WithArgs(userId).WillReturnResult(sqlmock.NewResult(0, 0))
+		// The codes are stored hashed
+		mock.ExpectExec(insertRecoveryCodeTest).
+			WithArgs(userId, "ed74b5c9ceaa577420d8ec549a9e55c9480ea02f6718c4095687f67e4ab220d4", now).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(insertRecoveryCodeTest).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(tt, twoFactorRepository.Enable(context.Background(), userId, codes, now))
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_sqlTwoFactorRepo_Disable(t *testing.T) {
+	userId := uuid.New().String()
+
+	db, mock, twoFactorRepository := NewMockTwoFactor()
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(deleteRecoveryCodesTest).WithArgs(userId).WillReturnResult(sqlmock.NewResult(0, 10))
+	mock.ExpectExec(deleteTwoFactorTest).WithArgs(userId).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, twoFactorRepository.Disable(context.Background(), userId))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_sqlTwoFactorRepo_UseStep(t *testing.T) {
+	userId := uuid.New().String()
+
+	t.Run("Replayed Step", func(tt *testing.T) {
+		db, mock, twoFactorRepository := NewMockTwoFactor()
+		defer db.Close()
+
+		mock.ExpectExec(updateTwoFactorStepTest).WithArgs(int64(41152263), userId).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		used, err := twoFactorRepository.UseStep(context.Background(), userId, 41152263)
+		assert.NoError(tt, err)
+		assert.False(tt, used)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Use Step Successfully", func(tt *testing.T) {
+		db, mock, twoFactorRepository := NewMockTwoFactor()
+		defer db.Close()
+
+		mock.ExpectExec(updateTwoFactorStepTest).WithArgs(int64(41152263), userId).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		used, err := twoFactorRepository.UseStep(context.Background(), userId, 41152263)
+		assert.NoError(tt, err)
+		assert.True(tt, used)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_sqlTwoFactorRepo_UseRecoveryCode(t *testing.T) {
+	userId := uuid.New().String()
+	now := time.Now()
+
+	db, mock, twoFactorRepository := NewMockTwoFactor()
+	defer db.Close()
+
+	mock.ExpectExec(useRecoveryCodeTest).WithArgs(now, userId, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	used, err := twoFactorRepository.UseRecoveryCode(context.Background(), userId, "aaaaabbbbb", now)
+	assert.NoError(t, err)
+	assert.False(t, used)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_redisTwoFactorChallengeRepo(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Error Invalid Challenge", func(tt *testing.T) {
+		mr, challenges := NewMockTwoFactorChallenges()
+		defer mr.Close()
+
+		challenge, err := challenges.ConsumeChallenge(ctx, "challenge")
+		assert.Equal(tt, persistence.ErrTwoFactorChallengeInvalid, err)
+		assert.Nil(tt, challenge)
+	})
+
+	t.Run("Error Expired Challenge", func(tt *testing.T) {
+		mr, challenges := NewMockTwoFactorChallenges()
+		defer mr.Close()
+
+		err := challenges.SaveChallenge(ctx, "challenge", &model.TwoFactorChallenge{UserID: "user", ExpiresAt: time.Now().Add(-time.Second)})
+		assert.Equal(tt, persistence.ErrTwoFactorChallengeInvalid, err)
+	})
+
+	t.Run("Consume Challenge Once", func(tt *testing.T) {
+		mr, challenges := NewMockTwoFactorChallenges()
+		defer mr.Close()
+
+		saved := &model.TwoFactorChallenge{UserID: "user", Email: "daniel.delapava@jikkosoft.com", Scope: "foods:read",
+			RememberMe: true, Attempts: 1, ExpiresAt: time.Now().Add(time.Minute).Truncate(time.Second)}
+		assert.NoError(tt, challenges.SaveChallenge(ctx, "challenge", saved))
+
+		for _, key := range mr.Keys() {
+			assert.NotContains(tt, key, "challenge")
+		}
+
+		// Getting the challenge does not use it
+		challenge, err := challenges.GetChallenge(ctx, "challenge")
+		assert.NoError(tt, err)
+		assert.Equal(tt, saved.Email, challenge.Email)
+
+		challenge, err = challenges.ConsumeChallenge(ctx, "challenge")
+		assert.NoError(tt, err)
+		assert.Equal(tt, saved.UserID, challenge.UserID)
+		assert.Equal(tt, saved.Scope, challenge.Scope)
+		assert.True(tt, challenge.RememberMe)
+		assert.Equal(tt, 1, challenge.Attempts)
+		assert.True(tt, saved.ExpiresAt.Equal(challenge.ExpiresAt))
+
+		_, err = challenges.ConsumeChallenge(ctx, "challenge")
+		assert.Equal(tt, persistence.ErrTwoFactorChallengeInvalid, err)
+
+		_, err = challenges.GetChallenge(ctx, "challenge")
+		assert.Equal(tt, persistence.ErrTwoFactorChallengeInvalid, err)
+	})
+}
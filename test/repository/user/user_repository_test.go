@@ -3,6 +3,7 @@ package user
 import (
 	"context"
 	"database/sql"
+	"errors"
 	responseUser "food-api/domain/user/application/v1/response"
 	"food-api/domain/user/domain/model"
 	"food-api/domain/user/domain/repository"
@@ -10,6 +11,7 @@ import (
 	"food-api/infrastructure/database"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"log"
 	"testing"
@@ -117,9 +119,9 @@ func Test_sqlUserRepo_GetAllUser(t *testing.T) {
 		}()
 
 		usersData := dataUserResponse()
-		rows := sqlmock.NewRows([]string{"id", "names", "last_names", "email"}).
-			AddRow(usersData[0].ID, usersData[0].Names, usersData[0].LastNames, usersData[0].Email).
-			AddRow(usersData[1].ID, usersData[1].Names, usersData[1].LastNames, usersData[1].Email)
+		rows := sqlmock.NewRows([]string{"id", "names", "last_names", "email", "role"}).
+			AddRow(usersData[0].ID, usersData[0].Names, usersData[0].LastNames, usersData[0].Email, "admin").
+			AddRow(usersData[1].ID, usersData[1].Names, usersData[1].LastNames, usersData[1].Email, "user")
 
 		mock.ExpectQuery(selectAllUserTest).WillReturnRows(rows)
 
@@ -143,8 +145,8 @@ func Test_sqlUserRepo_GetById(t *testing.T) {
 			CloseMockUser()
 		}()
 
-		row := sqlmock.NewRows([]string{"id", "names", "last_names", "email"}).
-			AddRow(userTest.ID, userTest.Names, userTest.LastNames, userTest.Email)
+		row := sqlmock.NewRows([]string{"id", "names", "last_names", "email", "email_verified_at", "role"}).
+			AddRow(userTest.ID, userTest.Names, userTest.LastNames, userTest.Email, nil, "user")
 
 		mock.ExpectQuery(selectUserByIdTest).WithArgs(nil).WillReturnRows(row)
 
@@ -162,8 +164,9 @@ func Test_sqlUserRepo_GetById(t *testing.T) {
 			CloseMockUser()
 		}()
 
-		row := sqlmock.NewRows([]string{"id", "names", "last_names", "email"}).
-			AddRow(userTest.ID, userTest.Names, userTest.LastNames, userTest.Email)
+		verifiedAt := time.Now()
+		row := sqlmock.NewRows([]string{"id", "names", "last_names", "email", "email_verified_at", "role"}).
+			AddRow(userTest.ID, userTest.Names, userTest.LastNames, userTest.Email, verifiedAt, "user")
 
 		mock.ExpectQuery(selectUserByIdTest).WithArgs(userTest.ID).WillReturnRows(row)
 
@@ -173,6 +176,49 @@ func Test_sqlUserRepo_GetById(t *testing.T) {
 		userResult, err := userRepositoryMock.GetById(ctx, userTest.ID)
 		assert.NoError(tt, err)
 		assert.NotNil(tt, userResult)
+		assert.True(tt, verifiedAt.Equal(*userResult.EmailVerifiedAt))
+	})
+}
+
+func Test_sqlUserRepo_GetByEmail(t *testing.T) {
+	userTest := dataUserResponse()[0]
+
+	t.Run("Error Not Found", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		mock.ExpectQuery(selectUserInfoByEmailTest).WithArgs(userTest.Email).WillReturnError(sql.ErrNoRows)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		userResult, err := userRepositoryMock.GetByEmail(ctx, userTest.Email)
+		assert.Equal(tt, sql.ErrNoRows, err)
+		assert.Empty(tt, userResult.ID)
+	})
+
+	t.Run("Get User By Email Successful", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		verifiedAt := time.Now()
+		userTest.EmailVerifiedAt = &verifiedAt
+
+		row := sqlmock.NewRows([]string{"id", "names", "last_names", "email", "email_verified_at"}).
+			AddRow(userTest.ID, userTest.Names, userTest.LastNames, userTest.Email, verifiedAt)
+
+		mock.ExpectQuery(selectUserInfoByEmailTest).WithArgs(userTest.Email).WillReturnRows(row)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		userResult, err := userRepositoryMock.GetByEmail(ctx, userTest.Email)
+		assert.NoError(tt, err)
+		assert.Equal(tt, userTest, userResult)
 	})
 }
 
@@ -204,8 +250,8 @@ func Test_sqlUserRepo_GetUserByEmailAndPassword(t *testing.T) {
 			CloseMockUser()
 		}()
 
-		row := sqlmock.NewRows([]string{"id", "names", "last_names", "email", "password", "created_at", "updated_at"}).
-			AddRow(userTest.ID, userTest.Names, userTest.LastNames, userTest.Email, userTest.PasswordHash, userTest.CreatedAt, userTest.UpdatedAt)
+		row := sqlmock.NewRows([]string{"id", "names", "last_names", "email", "password", "email_verified_at", "role", "created_at", "updated_at"}).
+			AddRow(userTest.ID, userTest.Names, userTest.LastNames, userTest.Email, userTest.PasswordHash, nil, "user", userTest.CreatedAt, userTest.UpdatedAt)
 
 		mock.ExpectQuery(selectUserByEmailTest).WithArgs(userTest.Email).WillReturnRows(row)
 
@@ -228,8 +274,8 @@ func Test_sqlUserRepo_GetUserByEmailAndPassword(t *testing.T) {
 			assert.Error(tt, err)
 		}
 
-		row := sqlmock.NewRows([]string{"id", "names", "last_names", "email", "password", "created_at", "updated_at"}).
-			AddRow(userTest.ID, userTest.Names, userTest.LastNames, userTest.Email, userTest.PasswordHash, userTest.CreatedAt, userTest.UpdatedAt)
+		row := sqlmock.NewRows([]string{"id", "names", "last_names", "email", "password", "email_verified_at", "role", "created_at", "updated_at"}).
+			AddRow(userTest.ID, userTest.Names, userTest.LastNames, userTest.Email, userTest.PasswordHash, nil, "user", userTest.CreatedAt, userTest.UpdatedAt)
 
 		mock.ExpectQuery(selectUserByEmailTest).WithArgs(userTest.Email).WillReturnRows(row)
 
@@ -368,3 +414,341 @@ func Test_sqlUserRepo_UpdateUser(t *testing.T) {
 	})
 
 }
+
+func Test_sqlUserRepo_FollowUser(t *testing.T) {
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		prep := mock.ExpectPrepare(insertFollowerTest)
+		prep.ExpectExec().
+			WithArgs(dataUser()[0].ID, dataUser()[1].ID, sqlmock.AnyArg()).
+			WillReturnError(errors.New("error sql"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := userRepositoryMock.FollowUser(ctx, dataUser()[0].ID, dataUser()[1].ID)
+		assert.Error(tt, err)
+	})
+
+	t.Run("Follow User Successful", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		dataTest := dataUser()
+		prep := mock.ExpectPrepare(insertFollowerTest)
+		prep.ExpectExec().
+			WithArgs(dataTest[0].ID, dataTest[1].ID, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := userRepositoryMock.FollowUser(ctx, dataTest[0].ID, dataTest[1].ID)
+		assert.NoError(tt, err)
+	})
+}
+
+func Test_sqlUserRepo_UnfollowUser(t *testing.T) {
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		prep := mock.ExpectPrepare(deleteFollowerTest)
+		prep.ExpectExec().
+			WithArgs(dataUser()[0].ID, dataUser()[1].ID).
+			WillReturnError(errors.New("error sql"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := userRepositoryMock.UnfollowUser(ctx, dataUser()[0].ID, dataUser()[1].ID)
+		assert.Error(tt, err)
+	})
+
+	t.Run("Unfollow User Successful", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		dataTest := dataUser()
+		prep := mock.ExpectPrepare(deleteFollowerTest)
+		prep.ExpectExec().
+			WithArgs(dataTest[0].ID, dataTest[1].ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := userRepositoryMock.UnfollowUser(ctx, dataTest[0].ID, dataTest[1].ID)
+		assert.NoError(tt, err)
+	})
+}
+
+func Test_sqlUserRepo_UpdatePassword(t *testing.T) {
+	userTest := dataUser()[0]
+	now := time.Now()
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		prep := mock.ExpectPrepare(updateUserPasswordTest)
+		prep.ExpectExec().WithArgs("hash", now, userTest.ID).WillReturnError(errors.New("error sql"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := userRepositoryMock.UpdatePassword(ctx, userTest.ID, "hash", now)
+		assert.Error(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error Not Found", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		prep := mock.ExpectPrepare(updateUserPasswordTest)
+		prep.ExpectExec().WithArgs("hash", now, userTest.ID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := userRepositoryMock.UpdatePassword(ctx, userTest.ID, "hash", now)
+		assert.Equal(tt, sql.ErrNoRows, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Update Password Successful", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		prep := mock.ExpectPrepare(updateUserPasswordTest)
+		prep.ExpectExec().WithArgs("hash", now, userTest.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := userRepositoryMock.UpdatePassword(ctx, userTest.ID, "hash", now)
+		assert.NoError(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_sqlUserRepo_VerifyEmail(t *testing.T) {
+	userTest := dataUser()[0]
+	now := time.Now()
+
+	t.Run("Error Not Found", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		prep := mock.ExpectPrepare(verifyUserEmailTest)
+		prep.ExpectExec().WithArgs(now, userTest.ID, userTest.Email).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := userRepositoryMock.VerifyEmail(ctx, userTest.ID, userTest.Email, now)
+		assert.Equal(tt, sql.ErrNoRows, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Verify Email Successful", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		prep := mock.ExpectPrepare(verifyUserEmailTest)
+		prep.ExpectExec().WithArgs(now, userTest.ID, userTest.Email).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := userRepositoryMock.VerifyEmail(ctx, userTest.ID, userTest.Email, now)
+		assert.NoError(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_sqlUserRepo_UpdateRole(t *testing.T) {
+	userTest := dataUser()[0]
+	now := time.Now()
+
+	t.Run("Error Not Found", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		prep := mock.ExpectPrepare(updateUserRoleTest)
+		prep.ExpectExec().WithArgs("moderator", now, userTest.ID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := userRepositoryMock.UpdateRole(ctx, userTest.ID, "moderator", now)
+		assert.Equal(tt, sql.ErrNoRows, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Update Role Successful", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		prep := mock.ExpectPrepare(updateUserRoleTest)
+		prep.ExpectExec().WithArgs("moderator", now, userTest.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := userRepositoryMock.UpdateRole(ctx, userTest.ID, "moderator", now)
+		assert.NoError(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_sqlUserRepo_DeleteUser(t *testing.T) {
+
+	t.Run("Error Not Found", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		deletion := &model.Deletion{UserID: dataUser()[0].ID, FoodPolicy: model.FoodPolicyAnonymize, DeletedAt: time.Now()}
+		mock.ExpectBegin()
+		mock.ExpectExec(deleteUserTest).WithArgs(deletion.DeletedAt, deletion.UserID).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := userRepositoryMock.DeleteUser(ctx, deletion)
+		assert.Equal(tt, sql.ErrNoRows, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error SQL Foods", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		deletion := &model.Deletion{UserID: dataUser()[0].ID, FoodPolicy: model.FoodPolicyCascade, DeletedAt: time.Now()}
+		mock.ExpectBegin()
+		mock.ExpectExec(deleteUserTest).WithArgs(deletion.DeletedAt, deletion.UserID).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(deleteUserFollowersTest).WithArgs(deletion.UserID).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(deleteUserFoodsTest).WithArgs(deletion.UserID).WillReturnError(errors.New("error sql"))
+		mock.ExpectRollback()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := userRepositoryMock.DeleteUser(ctx, deletion)
+		assert.Error(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Delete User Anonymize Foods Successful", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		deletion := &model.Deletion{UserID: dataUser()[0].ID, FoodPolicy: model.FoodPolicyAnonymize, DeletedAt: time.Now()}
+		mock.ExpectBegin()
+		mock.ExpectExec(deleteUserTest).WithArgs(deletion.DeletedAt, deletion.UserID).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(deleteUserFollowersTest).WithArgs(deletion.UserID).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(transferUserFoodsTest).WithArgs(model.DeletedUserID, deletion.DeletedAt, deletion.UserID).WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectCommit()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := userRepositoryMock.DeleteUser(ctx, deletion)
+		assert.NoError(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Delete User Transfer Foods Successful", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		dataTest := dataUser()
+		deletion := &model.Deletion{UserID: dataTest[0].ID, FoodPolicy: model.FoodPolicyTransfer, TransferTo: dataTest[1].ID, DeletedAt: time.Now()}
+		mock.ExpectBegin()
+		mock.ExpectExec(deleteUserTest).WithArgs(deletion.DeletedAt, deletion.UserID).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(deleteUserFollowersTest).WithArgs(deletion.UserID).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(transferUserFoodsTest).WithArgs(dataTest[1].ID, deletion.DeletedAt, deletion.UserID).WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectCommit()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := userRepositoryMock.DeleteUser(ctx, deletion)
+		assert.NoError(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_sqlUserRepo_PromoteAdmins(t *testing.T) {
+	now := time.Now()
+	emails := []string{"admin@example.com"}
+
+	t.Run("Error SQL", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		mock.ExpectExec(promoteAdminsTest).WithArgs(pq.Array(emails), now).WillReturnError(errors.New("error sql"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_, err := userRepositoryMock.PromoteAdmins(ctx, emails, now)
+		assert.Error(tt, err)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Promote Admins Successful", func(tt *testing.T) {
+		mock := NewMockUser()
+		defer func() {
+			CloseMockUser()
+		}()
+
+		mock.ExpectExec(promoteAdminsTest).WithArgs(pq.Array(emails), now).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		promoted, err := userRepositoryMock.PromoteAdmins(ctx, emails, now)
+		assert.NoError(tt, err)
+		assert.Equal(tt, int64(1), promoted)
+		assert.NoError(tt, mock.ExpectationsWereMet())
+	})
+}
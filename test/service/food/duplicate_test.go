@@ -0,0 +1,86 @@
+package food
+
+import (
+	"food-api/domain/food/application/v1/response"
+	"food-api/domain/food/domain/model"
+	"food-api/domain/food/domain/service"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormalizeTitle(t *testing.T) {
+
+	t.Run("Normalize Title", func(tt *testing.T) {
+		assert.Equal(tt, "spaghetti carbonara 2", service.NormalizeTitle("  Spaghetti-Carbonara (#2)! "))
+		assert.Equal(tt, "", service.NormalizeTitle(" -- "))
+	})
+}
+
+func TestTitleSimilarity(t *testing.T) {
+
+	t.Run("Same Title", func(tt *testing.T) {
+		assert.Equal(tt, 1.0, service.TitleSimilarity("Chicken Curry", "chicken curry!"))
+	})
+
+	t.Run("Similar Title", func(tt *testing.T) {
+		similarity := service.TitleSimilarity("Chicken Curry", "Chiken Curry")
+		assert.True(tt, similarity >= service.TitleSimilarityThreshold)
+		assert.True(tt, similarity < 1)
+	})
+
+	t.Run("Different Title", func(tt *testing.T) {
+		assert.True(tt, service.TitleSimilarity("Chicken Curry", "Apple Pie") < service.TitleSimilarityThreshold)
+	})
+
+	t.Run("Empty Title", func(tt *testing.T) {
+		assert.Equal(tt, 0.0, service.TitleSimilarity("", "Apple Pie"))
+	})
+}
+
+func TestSameIngredients(t *testing.T) {
+
+	t.Run("Same Ingredients", func(tt *testing.T) {
+		assert.True(tt, service.SameIngredients([]string{"Flour", "Water", "water"}, []string{" water", "FLOUR"}))
+	})
+
+	t.Run("Different Ingredients", func(tt *testing.T) {
+		assert.False(tt, service.SameIngredients([]string{"Flour", "Water"}, []string{"Flour", "Milk"}))
+		assert.False(tt, service.SameIngredients([]string{"Flour", "Water"}, []string{"Flour"}))
+	})
+
+	t.Run("Empty Ingredients", func(tt *testing.T) {
+		assert.False(tt, service.SameIngredients(nil, []string{}))
+	})
+}
+
+func TestFindDuplicates(t *testing.T) {
+	candidates := []response.FoodResponse{
+		{ID: "1", Title: "Apple Pie", Ingredients: []string{"Apple", "Flour"}},
+		{ID: "2", Title: "Chiken Curry"},
+		{ID: "3", Title: "Sunday dinner", Ingredients: []string{"chicken", "curry paste"}},
+		{ID: "4", Title: "Chicken Curry"},
+	}
+
+	t.Run("Find Duplicates", func(tt *testing.T) {
+		food := model.Food{Title: "Chicken curry", Ingredients: []string{"Curry paste", "Chicken"}}
+
+		duplicates := service.FindDuplicates(food, candidates)
+		assert.Len(tt, duplicates, 3)
+		assert.Equal(tt, "4", duplicates[0].Food.ID)
+		assert.Equal(tt, "2", duplicates[1].Food.ID)
+		assert.Equal(tt, "3", duplicates[2].Food.ID)
+		assert.True(tt, duplicates[2].SameIngredients)
+	})
+
+	t.Run("Skip Same Food", func(tt *testing.T) {
+		food := model.Food{ID: "4", Title: "Chicken Curry"}
+
+		duplicates := service.FindDuplicates(food, candidates)
+		assert.Len(tt, duplicates, 1)
+		assert.Equal(tt, "2", duplicates[0].Food.ID)
+	})
+
+	t.Run("No Duplicates", func(tt *testing.T) {
+		assert.Empty(tt, service.FindDuplicates(model.Food{Title: "Ramen"}, candidates))
+	})
+}
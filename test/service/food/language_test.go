@@ -0,0 +1,53 @@
+package food
+
+import (
+	"food-api/domain/food/domain/model"
+	"food-api/domain/food/domain/service"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormalizeLanguage(t *testing.T) {
+
+	t.Run("Valid Language", func(tt *testing.T) {
+		for input, expected := range map[string]string{"es": "es", "en-US": "en-us", " es_MX ": "es-mx", "es-419": "es-419"} {
+			language, ok := model.NormalizeLanguage(input)
+			assert.True(tt, ok, input)
+			assert.Equal(tt, expected, language)
+		}
+	})
+
+	t.Run("Invalid Language", func(tt *testing.T) {
+		for _, input := range []string{"", "*", "e", "english", "es-", "1s", "es-toolongtag"} {
+			_, ok := model.NormalizeLanguage(input)
+			assert.False(tt, ok, input)
+		}
+	})
+}
+
+func TestNegotiateLanguage(t *testing.T) {
+	available := []string{"en", "es", "es-mx"}
+
+	t.Run("Exact Match", func(tt *testing.T) {
+		assert.Equal(tt, "es-mx", service.NegotiateLanguage("es-MX", available))
+	})
+
+	t.Run("Quality Order", func(tt *testing.T) {
+		assert.Equal(tt, "es", service.NegotiateLanguage("en;q=0.5, es;q=0.9", available))
+	})
+
+	t.Run("Language Family", func(tt *testing.T) {
+		assert.Equal(tt, "es", service.NegotiateLanguage("es-AR", available))
+		assert.Equal(tt, "en", service.NegotiateLanguage("fr, en-GB;q=0.8", available))
+		assert.Equal(tt, "es-mx", service.NegotiateLanguage("es", []string{"en", "es-mx"}))
+	})
+
+	t.Run("Wildcard", func(tt *testing.T) {
+		assert.Equal(tt, "en", service.NegotiateLanguage("fr, *;q=0.1", available))
+	})
+
+	t.Run("Not Acceptable", func(tt *testing.T) {
+		assert.Equal(tt, "", service.NegotiateLanguage("fr, es;q=0", available))
+		assert.Equal(tt, "", service.NegotiateLanguage("", available))
+	})
+}
@@ -0,0 +1,152 @@
+package food
+
+import (
+	"food-api/domain/food/application/v1/response"
+	"food-api/domain/food/domain/service"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// dataSimilarFoods is data for test, the foods are compared with curry
+func dataSimilarFoods() (response.FoodResponse, []response.FoodResponse) {
+	curry := response.FoodResponse{
+		ID:          "curry",
+		Title:       "Spicy chicken curry",
+		Description: "Chicken in a spicy sauce",
+		Ingredients: []string{"Chicken", "Curry paste", "Rice"},
+		Tags:        []string{"spicy", "indian", "dinner"},
+	}
+
+	return curry, []response.FoodResponse{
+		curry,
+		{
+			ID:          "tikka",
+			Title:       "Chicken tikka masala",
+			Description: "Chicken in a creamy sauce",
+			Ingredients: []string{"chicken", "yogurt", "rice"},
+			Tags:        []string{"Indian", "dinner"},
+		},
+		{
+			ID:          "vindaloo",
+			Title:       "Pork vindaloo",
+			Description: "Pork in a spicy sauce",
+			Ingredients: []string{"Pork", "Curry paste", "Rice"},
+			Tags:        []string{"spicy", "indian", "dinner"},
+		},
+		{
+			ID:          "pancakes",
+			Title:       "Pancakes",
+			Description: "Sweet breakfast",
+			Ingredients: []string{"Flour", "Milk", "Eggs"},
+			Tags:        []string{"breakfast"},
+		},
+	}
+}
+
+func TestWeightedScorer(t *testing.T) {
+
+	t.Run("Same Food", func(tt *testing.T) {
+		curry, _ := dataSimilarFoods()
+		assert.InDelta(tt, 1, service.NewScorer().Score(curry, curry), 0.0001)
+	})
+
+	t.Run("Nothing In Common", func(tt *testing.T) {
+		curry, foods := dataSimilarFoods()
+		assert.True(tt, service.NewScorer().Score(curry, foods[3]) < service.MinScore)
+	})
+
+	t.Run("Only Tags", func(tt *testing.T) {
+		curry, foods := dataSimilarFoods()
+		scorer := service.WeightedScorer{Tags: 1}
+		assert.InDelta(tt, 2.0/3.0, scorer.Score(curry, foods[1]), 0.0001)
+		assert.InDelta(tt, 1, scorer.Score(curry, foods[2]), 0.0001)
+	})
+
+	t.Run("Only Ingredients", func(tt *testing.T) {
+		curry, foods := dataSimilarFoods()
+		scorer := service.WeightedScorer{Ingredients: 1}
+		assert.InDelta(tt, 2.0/4.0, scorer.Score(curry, foods[1]), 0.0001)
+		assert.InDelta(tt, 2.0/4.0, scorer.Score(curry, foods[2]), 0.0001)
+	})
+
+	t.Run("Target Without Tags", func(tt *testing.T) {
+		curry, foods := dataSimilarFoods()
+		curry.Tags = nil
+		scorer := service.WeightedScorer{Tags: 1, Ingredients: 1}
+		assert.InDelta(tt, 2.0/4.0, scorer.Score(curry, foods[2]), 0.0001)
+	})
+}
+
+// fixedScorer scores the candidates with a fixed value by id
+type fixedScorer map[string]float64
+
+func (fs fixedScorer) Score(target, candidate response.FoodResponse) float64 {
+	return fs[target.ID+">"+candidate.ID]
+}
+
+func TestRankSimilar(t *testing.T) {
+
+	t.Run("Rank Similar Foods", func(tt *testing.T) {
+		curry, foods := dataSimilarFoods()
+
+		ranked := service.RankSimilar(service.NewScorer(), curry, foods, 10)
+		assert.Len(tt, ranked, 2)
+		assert.Equal(tt, "vindaloo", ranked[0].ID)
+		assert.Equal(tt, "tikka", ranked[1].ID)
+		assert.True(tt, ranked[0].Score > ranked[1].Score)
+	})
+
+	t.Run("Ties And Limit", func(tt *testing.T) {
+		curry, foods := dataSimilarFoods()
+		scorer := fixedScorer{"curry>tikka": 0.5, "curry>vindaloo": 0.5, "curry>pancakes": 0.5}
+
+		ranked := service.RankSimilar(scorer, curry, foods, 2)
+		assert.Len(tt, ranked, 2)
+		assert.Equal(tt, "pancakes", ranked[0].ID)
+		assert.Equal(tt, "tikka", ranked[1].ID)
+	})
+}
+
+func TestRecommend(t *testing.T) {
+
+	t.Run("Recommend From Seeds", func(tt *testing.T) {
+		curry, foods := dataSimilarFoods()
+		scorer := fixedScorer{
+			"curry>tikka": 0.8, "curry>vindaloo": 0.3, "curry>pancakes": 0.1,
+			"pancakes>tikka": 0.1, "pancakes>vindaloo": 0.9,
+		}
+
+		seeds := []service.Seed{
+			{Food: curry, Weight: service.PreferenceWeight(true, 5)},
+			{Food: foods[3], Weight: service.PreferenceWeight(false, 1)},
+		}
+
+		ranked := service.Recommend(scorer, seeds, foods, nil, 10)
+		assert.Len(tt, ranked, 1)
+		assert.Equal(tt, "tikka", ranked[0].ID)
+		assert.InDelta(tt, (2*0.8-1*0.1)/3, ranked[0].Score, 0.0001)
+	})
+
+	t.Run("Exclude Foods", func(tt *testing.T) {
+		curry, foods := dataSimilarFoods()
+		scorer := fixedScorer{"curry>tikka": 0.8, "curry>vindaloo": 0.6}
+
+		seeds := []service.Seed{{Food: curry, Weight: 1}}
+
+		ranked := service.Recommend(scorer, seeds, foods, map[string]bool{"tikka": true}, 10)
+		assert.Len(tt, ranked, 1)
+		assert.Equal(tt, "vindaloo", ranked[0].ID)
+	})
+
+	t.Run("Without Seeds", func(tt *testing.T) {
+		_, foods := dataSimilarFoods()
+		assert.Empty(tt, service.Recommend(service.NewScorer(), nil, foods, nil, 10))
+	})
+}
+
+func TestPreferenceWeight(t *testing.T) {
+	assert.Equal(t, 1.0, service.PreferenceWeight(true, 0))
+	assert.Equal(t, 2.0, service.PreferenceWeight(true, 5))
+	assert.Equal(t, -1.0, service.PreferenceWeight(false, 1))
+	assert.Equal(t, 0.0, service.PreferenceWeight(false, 3))
+}
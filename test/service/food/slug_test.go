@@ -0,0 +1,52 @@
+package food
+
+import (
+	"food-api/domain/food/domain/service"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+
+	t.Run("Slugify Title", func(tt *testing.T) {
+		assert.Equal(tt, "spicy-chicken-curry", service.Slugify("Spicy Chicken Curry!"))
+		assert.Equal(tt, "arroz-con-pina-y-jalapeno", service.Slugify("  Arroz con piña y jalapeño!  "))
+		assert.Equal(tt, "creme-brulee-2", service.Slugify("Crème brûlée #2"))
+	})
+
+	t.Run("Slugify Without Letters", func(tt *testing.T) {
+		assert.Equal(tt, "food", service.Slugify("!!! ---"))
+	})
+
+	t.Run("Slugify Long Title", func(tt *testing.T) {
+		slug := service.Slugify(strings.Repeat("a", 100) + " " + strings.Repeat("b", 100))
+		assert.True(tt, len(slug) <= service.SlugMaxLength)
+		assert.False(tt, strings.HasSuffix(slug, "-"))
+	})
+}
+
+func TestUniqueSlug(t *testing.T) {
+
+	t.Run("Free Slug", func(tt *testing.T) {
+		assert.Equal(tt, "curry", service.UniqueSlug("curry", map[string]bool{"curry-2": true}))
+	})
+
+	t.Run("Taken Slug", func(tt *testing.T) {
+		taken := map[string]bool{"curry": true, "curry-2": true, "curry-4": true}
+		assert.Equal(tt, "curry-3", service.UniqueSlug("curry", taken))
+	})
+}
+
+func TestSlugMatchesTitle(t *testing.T) {
+
+	t.Run("Slug Matches Title", func(tt *testing.T) {
+		assert.True(tt, service.SlugMatchesTitle("chicken-curry", "Chicken curry"))
+		assert.True(tt, service.SlugMatchesTitle("chicken-curry-3", "Chicken Curry"))
+	})
+
+	t.Run("Slug Does Not Match Title", func(tt *testing.T) {
+		assert.False(tt, service.SlugMatchesTitle("chicken-curry", "Spicy Chicken Curry"))
+		assert.False(tt, service.SlugMatchesTitle("chicken-curry-hot", "Chicken Curry"))
+	})
+}
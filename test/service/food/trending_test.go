@@ -0,0 +1,50 @@
+package food
+
+import (
+	"food-api/domain/food/application/v1/response"
+	"food-api/domain/food/domain/model"
+	"food-api/domain/food/domain/service"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDecayWeight(t *testing.T) {
+
+	t.Run("Decay Weight Halves Every Half Life", func(tt *testing.T) {
+		assert.Equal(tt, 1.0, service.DecayWeight(0, 6*time.Hour))
+		assert.Equal(tt, 0.5, service.DecayWeight(6*time.Hour, 6*time.Hour))
+		assert.Equal(tt, 0.25, service.DecayWeight(12*time.Hour, 6*time.Hour))
+	})
+
+	t.Run("Decay Weight Without Half Life", func(tt *testing.T) {
+		assert.Equal(tt, 1.0, service.DecayWeight(time.Hour, 0))
+	})
+}
+
+func TestTrendingDuration(t *testing.T) {
+	assert.Equal(t, 24*time.Hour, service.TrendingDuration(model.TrendingWindowDay))
+	assert.Equal(t, 7*24*time.Hour, service.TrendingDuration(model.TrendingWindowWeek))
+}
+
+func TestRankTrending(t *testing.T) {
+	foods := []response.FoodResponse{{ID: "curry"}, {ID: "pancakes"}, {ID: "tikka"}}
+
+	t.Run("Rank Trending Skips Hidden Foods", func(tt *testing.T) {
+		scores := []model.FoodScore{{FoodID: "hidden", Score: 9}, {FoodID: "tikka", Score: 3}, {FoodID: "curry", Score: 3}, {FoodID: "pancakes", Score: 1.23456}}
+
+		ranked := service.RankTrending(scores, foods, 10)
+		assert.Len(tt, ranked, 3)
+		assert.Equal(tt, "curry", ranked[0].ID)
+		assert.Equal(tt, "tikka", ranked[1].ID)
+		assert.Equal(tt, 1.2346, ranked[2].Score)
+	})
+
+	t.Run("Rank Trending Limit", func(tt *testing.T) {
+		scores := []model.FoodScore{{FoodID: "curry", Score: 3}, {FoodID: "pancakes", Score: 2}, {FoodID: "tikka", Score: 0}}
+
+		ranked := service.RankTrending(scores, foods, 1)
+		assert.Len(tt, ranked, 1)
+		assert.Equal(tt, "curry", ranked[0].ID)
+	})
+}
@@ -0,0 +1,124 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"food-api/domain/user/domain/model"
+	repoMock "food-api/domain/user/domain/repository/mocks"
+	"food-api/domain/user/domain/service"
+	"food-api/infrastructure/auth"
+	authModel "food-api/infrastructure/auth/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	prefix, key, err := service.NewAPIKey()
+	assert.NoError(t, err)
+	assert.Len(t, prefix, 16)
+	assert.True(t, strings.HasPrefix(key, "fapi_"+prefix+"_"))
+
+	parsed, ok := service.APIKeyPrefix(key)
+	assert.True(t, ok)
+	assert.Equal(t, prefix, parsed)
+
+	_, ok = service.APIKeyPrefix("Bearer token")
+	assert.False(t, ok)
+
+	_, ok = service.APIKeyPrefix("fapi__secret")
+	assert.False(t, ok)
+
+	assert.Len(t, service.HashAPIKey(key), 64)
+	assert.NotEqual(t, service.HashAPIKey(key), service.HashAPIKey(key+"x"))
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+	tokenScopes := []string{authModel.ScopeFoodsRead, authModel.ScopeFoodsWrite}
+
+	t.Run("Scopes Of The Token", func(tt *testing.T) {
+		scopes, err := service.APIKeyScopes("", tokenScopes)
+		assert.NoError(tt, err)
+		assert.Equal(tt, tokenScopes, scopes)
+	})
+
+	t.Run("Subset Of The Token", func(tt *testing.T) {
+		scopes, err := service.APIKeyScopes("foods:read", tokenScopes)
+		assert.NoError(tt, err)
+		assert.Equal(tt, []string{authModel.ScopeFoodsRead}, scopes)
+	})
+
+	t.Run("Error Scope Not In The Token", func(tt *testing.T) {
+		_, err := service.APIKeyScopes("foods:read users:write", tokenScopes)
+		assert.Error(tt, err)
+	})
+
+	t.Run("Error Invalid Scope", func(tt *testing.T) {
+		_, err := service.APIKeyScopes("foods:delete", tokenScopes)
+		assert.Error(tt, err)
+	})
+}
+
+func TestAPIKeyAuthenticator_VerifyAPIKey(t *testing.T) {
+	ctx := context.Background()
+	prefix, key, err := service.NewAPIKey()
+	assert.NoError(t, err)
+
+	dataAPIKey := func() *model.APIKey {
+		return &model.APIKey{ID: "key-1", UserID: "user-1", Prefix: prefix, Scope: "foods:read", KeyHash: service.HashAPIKey(key),
+			Role: authModel.RoleModerator}
+	}
+
+	t.Run("Error Invalid Format", func(tt *testing.T) {
+		mockAPIKeys := &repoMock.APIKeyRepository{}
+
+		_, err := service.NewAPIKeyAuthenticator(mockAPIKeys).VerifyAPIKey(ctx, "not-a-key")
+		assert.Equal(tt, auth.ErrAPIKeyInvalid, err)
+		mockAPIKeys.AssertExpectations(tt)
+	})
+
+	t.Run("Error Unknown Prefix", func(tt *testing.T) {
+		mockAPIKeys := &repoMock.APIKeyRepository{}
+		mockAPIKeys.On("GetAPIKeyByPrefix", mock.Anything, prefix).Return(nil, sql.ErrNoRows)
+
+		_, err := service.NewAPIKeyAuthenticator(mockAPIKeys).VerifyAPIKey(ctx, key)
+		assert.Equal(tt, auth.ErrAPIKeyInvalid, err)
+		mockAPIKeys.AssertExpectations(tt)
+	})
+
+	t.Run("Error Wrong Secret", func(tt *testing.T) {
+		mockAPIKeys := &repoMock.APIKeyRepository{}
+		mockAPIKeys.On("GetAPIKeyByPrefix", mock.Anything, prefix).Return(dataAPIKey(), nil)
+
+		_, err := service.NewAPIKeyAuthenticator(mockAPIKeys).VerifyAPIKey(ctx, key+"x")
+		assert.Equal(tt, auth.ErrAPIKeyInvalid, err)
+		mockAPIKeys.AssertExpectations(tt)
+	})
+
+	t.Run("Error Expired", func(tt *testing.T) {
+		expired := dataAPIKey()
+		expiresAt := time.Now().Add(-time.Minute)
+		expired.ExpiresAt = &expiresAt
+
+		mockAPIKeys := &repoMock.APIKeyRepository{}
+		mockAPIKeys.On("GetAPIKeyByPrefix", mock.Anything, prefix).Return(expired, nil)
+
+		_, err := service.NewAPIKeyAuthenticator(mockAPIKeys).VerifyAPIKey(ctx, key)
+		assert.Equal(tt, auth.ErrAPIKeyInvalid, err)
+		mockAPIKeys.AssertExpectations(tt)
+	})
+
+	t.Run("Verify API Key Successfully", func(tt *testing.T) {
+		mockAPIKeys := &repoMock.APIKeyRepository{}
+		mockAPIKeys.On("GetAPIKeyByPrefix", mock.Anything, prefix).Return(dataAPIKey(), nil)
+		mockAPIKeys.On("TouchAPIKey", mock.Anything, "key-1", mock.Anything).Return(nil)
+
+		details, err := service.NewAPIKeyAuthenticator(mockAPIKeys).VerifyAPIKey(ctx, key)
+		assert.NoError(tt, err)
+		assert.Equal(tt, &authModel.AccessDetails{UserId: "user-1", Role: authModel.RoleModerator,
+			Scopes: []string{authModel.ScopeFoodsRead}, APIKeyID: "key-1"}, details)
+		mockAPIKeys.AssertExpectations(tt)
+	})
+}
@@ -0,0 +1,127 @@
+package user
+
+import (
+	responseUser "food-api/domain/user/application/v1/response"
+	"food-api/domain/user/domain/model"
+	"food-api/domain/user/domain/service"
+	authModel "food-api/infrastructure/auth/model"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+)
+
+// RFC 7636 appendix B
+const (
+	codeVerifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	codeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+)
+
+func TestVerifyCodeVerifier(t *testing.T) {
+	assert.True(t, service.VerifyCodeVerifier(codeVerifier, codeChallenge))
+	assert.False(t, service.VerifyCodeVerifier(codeVerifier+"x", codeChallenge))
+	assert.False(t, service.VerifyCodeVerifier("short", codeChallenge))
+	assert.False(t, service.VerifyCodeVerifier("", ""))
+}
+
+func TestCheckAuthorizationRequest(t *testing.T) {
+	request := model.AuthorizationRequest{ResponseType: "code", CodeChallenge: codeChallenge, CodeChallengeMethod: "S256"}
+	assert.NoError(t, service.CheckAuthorizationRequest(request))
+
+	token := request
+	token.ResponseType = "token"
+	assert.Equal(t, service.ErrUnsupportedResponseType, service.CheckAuthorizationRequest(token))
+
+	plain := request
+	plain.CodeChallengeMethod = "plain"
+	assert.Equal(t, service.ErrCodeChallengeRequired, service.CheckAuthorizationRequest(plain))
+
+	missing := request
+	missing.CodeChallenge = ""
+	assert.Equal(t, service.ErrCodeChallengeRequired, service.CheckAuthorizationRequest(missing))
+}
+
+func TestVerifyClientSecret(t *testing.T) {
+	client := &model.OAuthClient{Confidential: true, SecretHash: service.HashClientSecret("secret")}
+	assert.True(t, service.VerifyClientSecret(client, "secret"))
+	assert.False(t, service.VerifyClientSecret(client, "other"))
+	assert.False(t, service.VerifyClientSecret(&model.OAuthClient{}, ""))
+}
+
+func TestClientScopes(t *testing.T) {
+	client := &model.OAuthClient{Scope: "openid email foods:read"}
+	loginScopes := []string{authModel.ScopeFoodsRead, authModel.ScopeFoodsWrite}
+
+	t.Run("Scopes Of The Client", func(tt *testing.T) {
+		scopes, err := service.ClientScopes(client, "", loginScopes)
+		assert.NoError(tt, err)
+		assert.Equal(tt, []string{"openid", "email", "foods:read"}, scopes)
+	})
+
+	t.Run("Subset Of The Client", func(tt *testing.T) {
+		scopes, err := service.ClientScopes(client, "openid", loginScopes)
+		assert.NoError(tt, err)
+		assert.Equal(tt, []string{"openid"}, scopes)
+	})
+
+	t.Run("Error Scope Not In The Client", func(tt *testing.T) {
+		_, err := service.ClientScopes(client, "openid profile", loginScopes)
+		assert.Error(tt, err)
+	})
+
+	t.Run("Error Scope Not In The Login", func(tt *testing.T) {
+		_, err := service.ClientScopes(client, "foods:read", []string{authModel.ScopeUsersRead})
+		assert.Error(tt, err)
+	})
+}
+
+func TestValidRedirectURI(t *testing.T) {
+	valid := []string{"https://app.example.com/callback", "http://localhost:3000/callback", "http://127.0.0.1/cb",
+		"com.example.app:/oauth"}
+	for _, uri := range valid {
+		assert.True(t, model.ValidRedirectURI(uri), uri)
+	}
+
+	invalid := []string{"http://app.example.com/callback", "https://app.example.com/callback#token", "javascript:alert(1)",
+		"/callback", "https://"}
+	for _, uri := range invalid {
+		assert.False(t, model.ValidRedirectURI(uri), uri)
+	}
+}
+
+func TestAuthorizationRedirect(t *testing.T) {
+	redirect := service.AuthorizationRedirect("https://app.example.com/callback?tenant=1", url.Values{"code": {"abc"}, "state": {"x y"}})
+
+	uri, err := url.Parse(redirect)
+	assert.NoError(t, err)
+	assert.Equal(t, "app.example.com", uri.Host)
+	assert.Equal(t, "1", uri.Query().Get("tenant"))
+	assert.Equal(t, "abc", uri.Query().Get("code"))
+	assert.Equal(t, "x y", uri.Query().Get("state"))
+}
+
+func TestUserClaims(t *testing.T) {
+	verifiedAt := time.Now()
+	user := responseUser.UserResponse{ID: "user-1", Names: "Daniel", LastNames: "De La Pava Suarez",
+		Email: "daniel.delapava@jikkosoft.com", EmailVerifiedAt: &verifiedAt}
+
+	assert.Empty(t, service.UserClaims(user, []string{authModel.ScopeOpenID}))
+
+	claims := service.UserClaims(user, authModel.IdentityScopes)
+	assert.Equal(t, "Daniel De La Pava Suarez", claims["name"])
+	assert.Equal(t, "daniel.delapava@jikkosoft.com", claims["email"])
+	assert.Equal(t, true, claims["email_verified"])
+}
+
+func TestNewOpenIDConfiguration(t *testing.T) {
+	_ = os.Setenv("OAUTH_CONSENT_URL", "https://food.example.com/consent")
+	defer os.Unsetenv("OAUTH_CONSENT_URL")
+
+	configuration := service.NewOpenIDConfiguration("https://api.example.com", []string{"RS256"})
+	assert.Equal(t, "https://food.example.com/consent", configuration.AuthorizationEndpoint)
+	assert.Equal(t, "https://api.example.com/api/oauth/token", configuration.TokenEndpoint)
+	assert.Equal(t, "https://api.example.com/.well-known/jwks.json", configuration.JWKSURI)
+	assert.Equal(t, []string{"S256"}, configuration.CodeChallengeMethodsSupported)
+	assert.Contains(t, configuration.ScopesSupported, "openid")
+}
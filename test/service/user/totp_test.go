@@ -0,0 +1,138 @@
+package user
+
+import (
+	"context"
+	"food-api/domain/user/domain/model"
+	repoMock "food-api/domain/user/domain/repository/mocks"
+	"food-api/domain/user/domain/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the secret of the SHA1 test vectors of RFC 6238, "12345678901234567890" in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for seconds, expected := range vectors {
+		code, err := service.TOTPCode(rfcSecret, service.TOTPStep(time.Unix(seconds, 0)))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, code)
+	}
+
+	_, err := service.TOTPCode("not base32!", 1)
+	assert.Error(t, err)
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	at := time.Unix(1234567890, 0)
+
+	t.Run("Current Step", func(tt *testing.T) {
+		step, ok := service.VerifyTOTP(rfcSecret, "005924", at)
+		assert.True(tt, ok)
+		assert.Equal(tt, service.TOTPStep(at), step)
+	})
+
+	t.Run("Clock Drift", func(tt *testing.T) {
+		step, ok := service.VerifyTOTP(rfcSecret, "005924", at.Add(30*time.Second))
+		assert.True(tt, ok)
+		assert.Equal(tt, service.TOTPStep(at), step)
+	})
+
+	t.Run("Expired Code", func(tt *testing.T) {
+		_, ok := service.VerifyTOTP(rfcSecret, "005924", at.Add(2*time.Minute))
+		assert.False(tt, ok)
+	})
+
+	t.Run("Invalid Code", func(tt *testing.T) {
+		_, ok := service.VerifyTOTP(rfcSecret, "5924", at)
+		assert.False(tt, ok)
+	})
+}
+
+func TestNewTOTPSecret(t *testing.T) {
+	secret, err := service.NewTOTPSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	uri, err := url.Parse(service.TOTPURI("daniel.delapava@jikkosoft.com", secret))
+	assert.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/food-api:daniel.delapava@jikkosoft.com", uri.Path)
+	assert.Equal(t, secret, uri.Query().Get("secret"))
+	assert.Equal(t, "food-api", uri.Query().Get("issuer"))
+}
+
+func TestNewRecoveryCodes(t *testing.T) {
+	codes, err := service.NewRecoveryCodes()
+	assert.NoError(t, err)
+	assert.Len(t, codes, 10)
+
+	unique := make(map[string]bool)
+	for _, code := range codes {
+		assert.Regexp(t, "^[a-z2-7]{5}-[a-z2-7]{5}$", code)
+		unique[code] = true
+	}
+
+	assert.Len(t, unique, 10)
+	assert.Equal(t, "abcdefghij", service.NormalizeRecoveryCode("ABCDE-FGHIJ"))
+}
+
+func TestCheckTwoFactorCode(t *testing.T) {
+	ctx := context.Background()
+	at := time.Unix(1234567890, 0)
+	enabledAt := at
+	twoFactor := &model.TwoFactor{UserID: "user", Secret: rfcSecret, EnabledAt: &enabledAt}
+
+	t.Run("Error Without Secret", func(tt *testing.T) {
+		mockTwoFactor := &repoMock.TwoFactorRepository{}
+
+		err := service.CheckTwoFactorCode(ctx, mockTwoFactor, &model.TwoFactor{UserID: "user"}, "005924", at)
+		assert.Equal(tt, service.ErrTwoFactorCodeInvalid, err)
+		mockTwoFactor.AssertExpectations(tt)
+	})
+
+	t.Run("Error Replayed Code", func(tt *testing.T) {
+		mockTwoFactor := &repoMock.TwoFactorRepository{}
+		mockTwoFactor.On("UseStep", mock.Anything, "user", service.TOTPStep(at)).Return(false, nil)
+
+		err := service.CheckTwoFactorCode(ctx, mockTwoFactor, twoFactor, "005924", at)
+		assert.Equal(tt, service.ErrTwoFactorCodeInvalid, err)
+		mockTwoFactor.AssertExpectations(tt)
+	})
+
+	t.Run("Error Recovery Code Of Pending Secret", func(tt *testing.T) {
+		mockTwoFactor := &repoMock.TwoFactorRepository{}
+
+		err := service.CheckTwoFactorCode(ctx, mockTwoFactor, &model.TwoFactor{UserID: "user", Secret: rfcSecret}, "aaaaa-bbbbb", at)
+		assert.Equal(tt, service.ErrTwoFactorCodeInvalid, err)
+		mockTwoFactor.AssertExpectations(tt)
+	})
+
+	t.Run("TOTP Code", func(tt *testing.T) {
+		mockTwoFactor := &repoMock.TwoFactorRepository{}
+		mockTwoFactor.On("UseStep", mock.Anything, "user", service.TOTPStep(at)).Return(true, nil)
+
+		assert.NoError(tt, service.CheckTwoFactorCode(ctx, mockTwoFactor, twoFactor, "005924", at))
+		mockTwoFactor.AssertExpectations(tt)
+	})
+
+	t.Run("Recovery Code", func(tt *testing.T) {
+		mockTwoFactor := &repoMock.TwoFactorRepository{}
+		mockTwoFactor.On("UseRecoveryCode", mock.Anything, "user", "aaaaabbbbb", at).Return(true, nil)
+
+		assert.NoError(tt, service.CheckTwoFactorCode(ctx, mockTwoFactor, twoFactor, "AAAAA-BBBBB", at))
+		mockTwoFactor.AssertExpectations(tt)
+	})
+}